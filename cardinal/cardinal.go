@@ -20,6 +20,13 @@ var (
 	ErrEntityMustHaveAtLeastOneComponent = gamestate.ErrEntityMustHaveAtLeastOneComponent
 	ErrComponentNotOnEntity              = gamestate.ErrComponentNotOnEntity
 	ErrComponentAlreadyOnEntity          = gamestate.ErrComponentAlreadyOnEntity
+	ErrRelationshipCycle                 = gamestate.ErrRelationshipCycle
+	ErrResourceNotRegistered             = types.ErrResourceNotRegistered
+	ErrComponentAccessNotDeclared        = errors.New("component access was not declared by the system")
+	ErrStructuralChangeNotAllowed        = errors.New(
+		"systems registered with component access cannot create or remove entities or components",
+	)
+	ErrSystemOrderCycle = errors.New("system ordering constraints contain a cycle")
 )
 
 // FilterFunction wrap your component filter function of func(comp T) bool inside FilterFunction to use
@@ -44,7 +51,39 @@ func RegisterSystems(w *World, sys ...System) error {
 			worldstage.Init,
 		)
 	}
-	return w.SystemManager.registerSystems(false, sys...)
+	return w.SystemManager.registerSystems(false, nil, sys...)
+}
+
+// RegisterSystem registers a system with options that control when it runs, e.g. its phase and the systems it must
+// run before or after. The order of all systems is resolved when the game starts, and StartGame returns an error if
+// the ordering constraints can't be satisfied.
+//
+// Usage:
+//
+// cardinal.RegisterSystem(world, MoveSystem, cardinal.After(cardinal.SystemName(InputSystem)))
+func RegisterSystem(w *World, sys System, opts ...SystemOption) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"world state is %s, expected %s to register systems",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	return w.SystemManager.registerSystems(false, opts, sys)
+}
+
+// RegisterSystemsWithAccess registers systems that only read and write the components declared in access. Consecutively
+// registered systems whose access does not conflict are run concurrently within a tick. See SystemAccess for the
+// restrictions placed on these systems.
+func RegisterSystemsWithAccess(w *World, access SystemAccess, sys ...System) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"world state is %s, expected %s to register systems",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	return w.SystemManager.registerSystems(false, []SystemOption{WithComponentAccess(access)}, sys...)
 }
 
 func RegisterInitSystems(w *World, sys ...System) error {
@@ -55,10 +94,12 @@ func RegisterInitSystems(w *World, sys ...System) error {
 			worldstage.Init,
 		)
 	}
-	return w.SystemManager.registerSystems(true, sys...)
+	return w.SystemManager.registerSystems(true, nil, sys...)
 }
 
-func RegisterComponent[T types.Component](w *World) error {
+// RegisterComponent registers the component T with the world. Options like component.WithCodec change how the values
+// of the component are stored.
+func RegisterComponent[T types.Component](w *World, opts ...component.Option[T]) error {
 	if w.worldStage.Current() != worldstage.Init {
 		return eris.Errorf(
 			"world state is %s, expected %s to register component",
@@ -67,10 +108,13 @@ func RegisterComponent[T types.Component](w *World) error {
 		)
 	}
 
-	compMetadata, err := component.NewComponentMetadata[T]()
+	compMetadata, err := component.NewComponentMetadata[T](opts...)
 	if err != nil {
 		return err
 	}
+	if _, ok := w.resources[compMetadata.Name()]; ok {
+		return eris.Errorf("component %q has the same name as a registered resource", compMetadata.Name())
+	}
 
 	err = w.RegisterComponent(compMetadata)
 	if err != nil {
@@ -80,8 +124,8 @@ func RegisterComponent[T types.Component](w *World) error {
 	return nil
 }
 
-func MustRegisterComponent[T types.Component](w *World) {
-	err := RegisterComponent[T](w)
+func MustRegisterComponent[T types.Component](w *World, opts ...component.Option[T]) {
+	err := RegisterComponent[T](w, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -171,6 +215,10 @@ func CreateMany(wCtx WorldContext, num int, components ...types.Component) (enti
 		return nil, ErrEntityMutationOnReadOnly
 	}
 
+	if err = wCtx.componentAccess().checkStructuralChange(); err != nil {
+		return nil, err
+	}
+
 	if !wCtx.isWorldReady() {
 		return nil, ErrEntitiesCreatedBeforeReady
 	}
@@ -219,8 +267,12 @@ func SetComponent[T types.Component](wCtx WorldContext, id types.EntityID, compo
 		return ErrEntityMutationOnReadOnly
 	}
 
-	// Get the component metadata
 	var t T
+	if err = wCtx.componentAccess().checkWrite(t.Name()); err != nil {
+		return err
+	}
+
+	// Get the component metadata
 	c, err := wCtx.getComponentByName(t.Name())
 	if err != nil {
 		return err
@@ -246,8 +298,12 @@ func SetComponent[T types.Component](wCtx WorldContext, id types.EntityID, compo
 func GetComponent[T types.Component](wCtx WorldContext, id types.EntityID) (comp *T, err error) {
 	defer func() { panicOnFatalError(wCtx, err) }()
 
-	// Get the component metadata
 	var t T
+	if err = wCtx.componentAccess().checkRead(t.Name()); err != nil {
+		return nil, err
+	}
+
+	// Get the component metadata
 	c, err := wCtx.getComponentByName(t.Name())
 	if err != nil {
 		return nil, err
@@ -307,6 +363,10 @@ func AddComponentTo[T types.Component](wCtx WorldContext, id types.EntityID) (er
 		return ErrEntityMutationOnReadOnly
 	}
 
+	if err = wCtx.componentAccess().checkStructuralChange(); err != nil {
+		return err
+	}
+
 	// Get the component metadata
 	var t T
 	c, err := wCtx.getComponentByName(t.Name())
@@ -332,6 +392,10 @@ func RemoveComponentFrom[T types.Component](wCtx WorldContext, id types.EntityID
 		return ErrEntityMutationOnReadOnly
 	}
 
+	if err = wCtx.componentAccess().checkStructuralChange(); err != nil {
+		return err
+	}
+
 	// Get the component metadata
 	var t T
 	c, err := wCtx.getComponentByName(t.Name())
@@ -357,6 +421,10 @@ func Remove(wCtx WorldContext, id types.EntityID) (err error) {
 		return ErrEntityMutationOnReadOnly
 	}
 
+	if err = wCtx.componentAccess().checkStructuralChange(); err != nil {
+		return err
+	}
+
 	err = wCtx.storeManager().RemoveEntity(id)
 	if err != nil {
 		return err
@@ -2,17 +2,20 @@ package cardinal_test
 
 import (
 	"bytes"
+	"crypto/ecdsa"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fasthttp/websocket"
 	"github.com/golang/mock/gomock"
@@ -20,8 +23,10 @@ import (
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
 	"pkg.world.dev/world-engine/cardinal/filter"
+	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/router/mocks"
 	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/txpool"
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/sign"
 )
@@ -108,6 +113,164 @@ func TestForEachTransaction(t *testing.T) {
 	}
 }
 
+func TestTransactionsAreDeferredWhenTickIsFull(t *testing.T) {
+	type BidMsg struct {
+		Fee int64
+	}
+	tf := cardinal.NewTestFixture(t, nil,
+		cardinal.WithMaxTxsPerTick(2),
+		cardinal.WithPersonaTxRateLimit(3),
+		cardinal.WithTxPriority(func(_ types.Message, _ *sign.Transaction, msg any) int64 {
+			bid, _ := msg.(BidMsg)
+			return bid.Fee
+		}),
+	)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterMessage[BidMsg, BidMsg](world, "bid"))
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx cardinal.WorldContext) error {
+		return cardinal.EachMessage[BidMsg, BidMsg](wCtx, func(tx cardinal.TxData[BidMsg]) (BidMsg, error) {
+			return tx.Msg, nil
+		})
+	}))
+	tf.StartWorld()
+	bid, ok := world.GetMessageByFullName("game.bid")
+	assert.True(t, ok)
+
+	submit := func(fee int64) types.TxHash {
+		_, txHash, err := world.SubmitTransaction(bid.ID(), BidMsg{Fee: fee}, testutils.UniqueSignature())
+		assert.NilError(t, err)
+		return txHash
+	}
+	low := submit(1)
+	high := submit(3)
+	mid := submit(2)
+	// The persona is rate limited until the next tick.
+	_, _, err := world.SubmitTransaction(bid.ID(), BidMsg{Fee: 4}, testutils.UniqueSignature())
+	assert.ErrorIs(t, err, txpool.ErrRateLimited)
+
+	tf.DoTick()
+	receipts, err := world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+	assert.NilError(t, err)
+	results := map[types.TxHash]receipt.Receipt{}
+	for _, rec := range receipts {
+		results[rec.TxHash] = rec
+	}
+	assert.Equal(t, 3, len(results))
+	assert.Equal(t, BidMsg{Fee: 3}, results[high].Result)
+	assert.Equal(t, BidMsg{Fee: 2}, results[mid].Result)
+	assert.Check(t, results[low].Deferred)
+	assert.Check(t, results[low].Result == nil)
+
+	// The deferred transaction is handled in the next tick.
+	tf.DoTick()
+	receipts, err = world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(receipts))
+	assert.Equal(t, low, receipts[0].TxHash)
+	assert.Check(t, !receipts[0].Deferred)
+	assert.Equal(t, BidMsg{Fee: 1}, receipts[0].Result)
+}
+
+func TestTxPoolIsRestoredAfterRestart(t *testing.T) {
+	type EchoMsg struct {
+		Value int
+	}
+	path := filepath.Join(t.TempDir(), "txpool.log")
+	newFixture := func(redis *miniredis.Miniredis) *cardinal.TestFixture {
+		tf := cardinal.NewTestFixture(t, redis, cardinal.WithTxPoolWAL(path))
+		assert.NilError(t, cardinal.RegisterMessage[EchoMsg, EchoMsg](tf.World, "echo"))
+		assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx cardinal.WorldContext) error {
+			return cardinal.EachMessage[EchoMsg, EchoMsg](wCtx, func(tx cardinal.TxData[EchoMsg]) (EchoMsg, error) {
+				return tx.Msg, nil
+			})
+		}))
+		tf.StartWorld()
+		return tf
+	}
+	privateKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	// The logged transactions are decoded from their body, so the body must hold the message.
+	submit := func(world *cardinal.World, privateKey *ecdsa.PrivateKey, value int) types.TxHash {
+		echo, ok := world.GetMessageByFullName("game.echo")
+		assert.True(t, ok)
+		tx, err := sign.NewTransaction(privateKey, "alice", world.Namespace(), EchoMsg{Value: value})
+		assert.NilError(t, err)
+		_, txHash, err := world.SubmitTransaction(echo.ID(), EchoMsg{Value: value}, tx)
+		assert.NilError(t, err)
+		return txHash
+	}
+
+	tf := newFixture(nil)
+	tf.CreatePersona("alice", crypto.PubkeyToAddress(privateKey.PublicKey).Hex())
+	submit(tf.World, privateKey, 1)
+	tf.DoTick()
+	// These transactions are accepted, but the shard stops before the next tick.
+	txHash := submit(tf.World, privateKey, 2)
+	// The logged transactions are validated again when they are restored, so a transaction that is not signed by the
+	// signer of the persona is dropped.
+	otherKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	submit(tf.World, otherKey, 3)
+
+	tf2 := newFixture(tf.Redis)
+	assert.Equal(t, uint64(2), tf2.World.CurrentTick())
+	tf2.DoTick()
+	receipts, err := tf2.World.GetTransactionReceiptsForTick(2)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(receipts))
+	assert.Equal(t, txHash, receipts[0].TxHash)
+	assert.Equal(t, EchoMsg{Value: 2}, receipts[0].Result)
+}
+
+func TestReceiptsAreStoredAcrossRestarts(t *testing.T) {
+	type EchoMsg struct {
+		Value int
+	}
+	newFixture := func(redis *miniredis.Miniredis) *cardinal.TestFixture {
+		tf := cardinal.NewTestFixture(t, redis, cardinal.WithReceiptHistorySize(1), cardinal.WithReceiptStore(100))
+		assert.NilError(t, cardinal.RegisterMessage[EchoMsg, EchoMsg](tf.World, "echo"))
+		assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx cardinal.WorldContext) error {
+			return cardinal.EachMessage[EchoMsg, EchoMsg](wCtx, func(tx cardinal.TxData[EchoMsg]) (EchoMsg, error) {
+				return tx.Msg, nil
+			})
+		}))
+		tf.StartWorld()
+		return tf
+	}
+
+	tf := newFixture(nil)
+	echo, ok := tf.World.GetMessageByFullName("game.echo")
+	assert.True(t, ok)
+	_, txHash := tf.World.AddTransaction(echo.ID(), EchoMsg{Value: 1}, testutils.UniqueSignature())
+	tf.DoTick()
+	tf.DoTick()
+	tf.DoTick()
+
+	// The receipt is no longer kept in memory, but it is still in the receipt store.
+	rec, tick, err := tf.World.GetTransactionReceipt(txHash)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), tick)
+	assert.Equal(t, `{"Value":1}`, string(rec.Result.(json.RawMessage)))
+
+	tf2 := newFixture(tf.Redis)
+	rec, tick, err = tf2.World.GetTransactionReceipt(txHash)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), tick)
+	assert.Equal(t, `{"Value":1}`, string(rec.Result.(json.RawMessage)))
+	receipts, err := tf2.World.GetTransactionReceiptsForTick(0)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(receipts))
+	firstTick, ticks, err := tf2.World.GetTransactionReceiptsForTicks(0, tf2.World.CurrentTick())
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), firstTick)
+	assert.Equal(t, 1, len(ticks))
+	assert.Equal(t, uint64(0), ticks[0].Tick)
+	assert.Equal(t, txHash, ticks[0].Receipts[0].TxHash)
+
+	_, _, err = tf2.World.GetTransactionReceipt("unknown")
+	assert.ErrorIs(t, err, receipt.ErrReceiptNotFound)
+}
+
 type CounterComponent struct {
 	Count int
 }
@@ -861,7 +1024,7 @@ func TestCallsRegisterGameShardOnStartup(t *testing.T) {
 
 	rtr.EXPECT().Start().Times(1)
 	rtr.EXPECT().RegisterGameShard(gomock.Any()).Times(1)
-	rtr.EXPECT().SubmitTxBlob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	rtr.EXPECT().SubmitTxBlob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 	tf.DoTick()
 }
 
@@ -0,0 +1,54 @@
+package cardinal
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/filter"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// RemovedComponents returns the IDs of the entities that had the component T removed in the current tick, including
+// entities that were removed, sorted by ID. Only changes made by the systems that ran before the calling system are
+// seen, so systems that react to them should run after the systems that make them, e.g. in the PostUpdate phase.
+func RemovedComponents[T types.Component](wCtx WorldContext) (ids []types.EntityID, err error) {
+	defer func() { panicOnFatalError(wCtx, err) }()
+
+	var t T
+	if _, err = wCtx.getComponentByName(t.Name()); err != nil {
+		return nil, err
+	}
+	tracker, err := changeTracker(wCtx, t.Name())
+	if err != nil {
+		return nil, err
+	}
+	return tracker.RemovedComponents(t.Name()), nil
+}
+
+// changedEntities returns the sorted IDs of the entities matched by the change filter in the current tick.
+func changedEntities(wCtx WorldContext, changeFilter filter.ChangeFilter) ([]types.EntityID, error) {
+	name := changeFilter.ChangedComponent().Name()
+	tracker, err := changeTracker(wCtx, name)
+	if err != nil {
+		return nil, err
+	}
+	switch changeFilter.Kind() {
+	case filter.ComponentAdded:
+		return tracker.AddedComponents(name), nil
+	case filter.ComponentChanged:
+		return tracker.ChangedComponents(name), nil
+	default:
+		return nil, eris.Errorf("unknown change filter kind %d", changeFilter.Kind())
+	}
+}
+
+func changeTracker(wCtx WorldContext, componentName string) (gamestate.ComponentChangeTracker, error) {
+	if err := wCtx.componentAccess().checkRead(componentName); err != nil {
+		return nil, err
+	}
+	tracker, ok := wCtx.storeReader().(gamestate.ComponentChangeTracker)
+	if !ok {
+		return nil, eris.New("component changes are only tracked within a tick, not in read only contexts")
+	}
+	return tracker, nil
+}
@@ -0,0 +1,47 @@
+package codec
+
+import (
+	"reflect"
+
+	"github.com/rotisserie/eris"
+	"google.golang.org/protobuf/proto"
+)
+
+// Protobuf is a binary codec for values that are pointers to generated protobuf messages, e.g. a component type
+// declared as *pb.Inventory. Messages are encoded with deterministic marshaling, so map fields are sorted.
+var Protobuf Codec = protobufCodec{}
+
+type protobufCodec struct{}
+
+func (protobufCodec) Name() string {
+	return "protobuf"
+}
+
+func (protobufCodec) Encode(v any) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, eris.Errorf("%T is not a protobuf message", v)
+	}
+	bz, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	return bz, nil
+}
+
+func (protobufCodec) Decode(bz []byte, v any) error {
+	// v is a pointer to a pointer to the message, so a new message is allocated and stored in it.
+	ptr := reflect.ValueOf(v)
+	if ptr.Kind() != reflect.Pointer || ptr.Elem().Kind() != reflect.Pointer {
+		return eris.Errorf("cannot decode a protobuf message into %T", v)
+	}
+	msg, ok := reflect.New(ptr.Elem().Type().Elem()).Interface().(proto.Message)
+	if !ok {
+		return eris.Errorf("%s is not a protobuf message", ptr.Elem().Type())
+	}
+	if err := proto.Unmarshal(bz, msg); err != nil {
+		return eris.Wrap(err, "")
+	}
+	ptr.Elem().Set(reflect.ValueOf(msg))
+	return nil
+}
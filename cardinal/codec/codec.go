@@ -21,3 +21,35 @@ func Encode(comp any) ([]byte, error) {
 	}
 	return bz, nil
 }
+
+// Codec encodes and decodes values. Components use the JSON codec unless another one is set with
+// component.WithCodec.
+type Codec interface {
+	// Name returns the name of the codec.
+	Name() string
+	Encode(v any) ([]byte, error)
+	// Decode decodes bz into v, which must be a pointer.
+	Decode(bz []byte, v any) error
+}
+
+// JSON is the default codec. Its encoded values can be read directly by clients.
+var JSON Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+func (jsonCodec) Encode(v any) ([]byte, error) {
+	return Encode(v)
+}
+
+func (jsonCodec) Decode(bz []byte, v any) error {
+	return eris.Wrap(json.Unmarshal(bz, v), "")
+}
+
+// IsJSON returns true if values encoded by c are JSON.
+func IsJSON(c Codec) bool {
+	return c == nil || c.Name() == JSON.Name()
+}
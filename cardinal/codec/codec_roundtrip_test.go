@@ -0,0 +1,263 @@
+package codec_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/codec"
+)
+
+type Leaf struct {
+	Label string
+	Tags  []string `json:"tags"`
+}
+
+type Node struct {
+	Nums     [4]int
+	Int8     int8
+	Uint64   uint64
+	Negative int64
+	Ratio    float64
+	Small    float32
+	Enabled  bool
+	Name     string `json:"name"`
+	Ignored  string `json:"-"`
+	Data     []byte
+	Counts   map[string]int
+	ByID     map[int]Leaf
+	Leaves   []Leaf
+	Left     *Node
+	Right    *Node
+	Any      any
+}
+
+func newTestNode() Node {
+	return Node{
+		Nums:     [4]int{1, -2, 300, math.MaxInt32 + 1},
+		Int8:     math.MinInt8,
+		Uint64:   math.MaxUint64,
+		Negative: math.MinInt64,
+		Ratio:    0.25,
+		Small:    1.5,
+		Enabled:  true,
+		Name:     "root with a name longer than thirty two characters",
+		Ignored:  "",
+		Data:     []byte{0, 1, 2, 255},
+		Counts:   map[string]int{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6, "g": 7, "h": 8},
+		ByID:     map[int]Leaf{-1: {Label: "minus one", Tags: nil}, 7: {Label: "seven", Tags: []string{"x", "y"}}},
+		Leaves:   []Leaf{{Label: "first", Tags: []string{}}},
+		Left:     &Node{Name: "left", Nums: [4]int{4}},
+		Right:    nil,
+		Any:      map[string]any{"nested": []any{"text", true}},
+	}
+}
+
+func TestCodecsRoundTrip(t *testing.T) {
+	for _, c := range []codec.Codec{codec.JSON, codec.MsgPack} {
+		t.Run(c.Name(), func(t *testing.T) {
+			want := newTestNode()
+			bz, err := c.Encode(want)
+			assert.NilError(t, err)
+
+			var got Node
+			assert.NilError(t, c.Decode(bz, &got))
+			// Values in interfaces are decoded into generic types.
+			assert.DeepEqual(t, map[string]any{"nested": []any{"text", true}}, got.Any)
+			got.Any = want.Any
+			assert.DeepEqual(t, want, got)
+
+			// Decoding into a pointer field allocates the value.
+			var ptr *Node
+			assert.NilError(t, c.Decode(bz, &ptr))
+			assert.Equal(t, want.Name, ptr.Name)
+		})
+	}
+}
+
+func TestCodecsAreDeterministic(t *testing.T) {
+	for _, c := range []codec.Codec{codec.MsgPack, codec.Protobuf} {
+		t.Run(c.Name(), func(t *testing.T) {
+			var value any = newTestNode()
+			if c == codec.Protobuf {
+				msg, err := structpb.NewStruct(map[string]any{
+					"a": 1, "b": "two", "c": true, "d": []any{1, 2}, "e": map[string]any{"x": 1, "y": 2, "z": 3},
+				})
+				assert.NilError(t, err)
+				value = msg
+			}
+			first, err := c.Encode(value)
+			assert.NilError(t, err)
+			// Maps are iterated in a random order, so encode the value enough times to notice if the order leaks.
+			for i := 0; i < 20; i++ {
+				bz, err := c.Encode(value)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, first, bz)
+			}
+		})
+	}
+}
+
+func TestProtobufRoundTrip(t *testing.T) {
+	want, err := structpb.NewStruct(map[string]any{"name": "sword", "damage": 12.5, "tags": []any{"sharp"}})
+	assert.NilError(t, err)
+	bz, err := codec.Protobuf.Encode(want)
+	assert.NilError(t, err)
+
+	var got *structpb.Struct
+	assert.NilError(t, codec.Protobuf.Decode(bz, &got))
+	assert.Check(t, proto.Equal(want, got))
+
+	_, err = codec.Protobuf.Encode(Leaf{Label: "not a message", Tags: nil})
+	assert.ErrorContains(t, err, "is not a protobuf message")
+	assert.ErrorContains(t, codec.Protobuf.Decode(bz, &Leaf{}), "cannot decode a protobuf message")
+}
+
+func TestMsgPackRejectsInvalidData(t *testing.T) {
+	bz, err := codec.MsgPack.Encode(newTestNode())
+	assert.NilError(t, err)
+
+	var node Node
+	assert.ErrorContains(t, codec.MsgPack.Decode(bz[:len(bz)-1], &node), "unexpected end of msgpack data")
+	assert.ErrorContains(t, codec.MsgPack.Decode(append(bz, 0), &node), "unexpected 1 bytes after msgpack value")
+
+	var small struct{ Int8 int8 }
+	assert.ErrorContains(t, codec.MsgPack.Decode([]byte{0x81, 0xa4, 'I', 'n', 't', '8', 0xcc, 200}, &small),
+		"overflows int8")
+	var text struct{ Name string }
+	assert.ErrorContains(t, codec.MsgPack.Decode([]byte{0x81, 0xa4, 'N', 'a', 'm', 'e', 0x01}, &text),
+		"cannot decode msgpack uint into string")
+}
+
+// Position keeps its state in unexported fields and encodes it with encoding.BinaryMarshaler.
+type Position struct {
+	x, y int32
+}
+
+func (p Position) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, uint32(p.x)), uint32(p.y)), nil
+}
+
+func (p *Position) UnmarshalBinary(bz []byte) error {
+	if len(bz) != 8 { //nolint:gomnd // two uint32
+		return errors.New("invalid position")
+	}
+	p.x, p.y = int32(binary.BigEndian.Uint32(bz)), int32(binary.BigEndian.Uint32(bz[4:]))
+	return nil
+}
+
+// Level encodes itself with json.Marshaler only.
+type Level struct {
+	value int
+}
+
+func (l Level) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(l.value)), nil
+}
+
+func (l *Level) UnmarshalJSON(bz []byte) error {
+	value, err := strconv.Atoi(string(bz))
+	l.value = value
+	return err
+}
+
+type Base struct {
+	ID   int
+	Name string
+}
+
+type Extra struct {
+	Note string `json:"note"`
+}
+
+type embeddedLeaf struct {
+	Label string
+}
+
+type Item struct {
+	Base
+	*Extra
+	embeddedLeaf
+	Name     string
+	Created  time.Time
+	Amount   *big.Int
+	Balance  big.Int
+	Position Position
+	Level    Level
+	Levels   map[Level]int
+}
+
+func TestMsgPackRoundTripsMarshalersAndEmbeddedStructs(t *testing.T) {
+	want := Item{
+		Base:         Base{ID: 7, Name: "hidden by Item.Name"},
+		Extra:        &Extra{Note: "embedded pointer"},
+		embeddedLeaf: embeddedLeaf{Label: "unexported embedded"},
+		Name:         "item",
+		Created:      time.Date(2024, 2, 29, 12, 30, 15, 123456789, time.FixedZone("UTC+2", 2*60*60)),
+		Amount:       new(big.Int).Lsh(big.NewInt(1), 100),
+		Balance:      *big.NewInt(-42),
+		Position:     Position{x: -3, y: 4},
+		Level:        Level{value: 9},
+		Levels:       map[Level]int{{value: 1}: 10, {value: 2}: 20},
+	}
+	bz, err := codec.MsgPack.Encode(want)
+	assert.NilError(t, err)
+
+	var got Item
+	assert.NilError(t, codec.MsgPack.Decode(bz, &got))
+	assert.Equal(t, want.ID, got.ID)
+	assert.Equal(t, want.Note, got.Note)
+	assert.Equal(t, want.Label, got.Label)
+	assert.Equal(t, want.Name, got.Name)
+	// The field of the embedded struct is hidden by the field of the outer struct with the same name.
+	assert.Equal(t, "", got.Base.Name)
+	assert.Check(t, want.Created.Equal(got.Created))
+	_, wantOffset := want.Created.Zone()
+	_, gotOffset := got.Created.Zone()
+	assert.Equal(t, wantOffset, gotOffset)
+	assert.Equal(t, 0, want.Amount.Cmp(got.Amount))
+	assert.Equal(t, 0, want.Balance.Cmp(&got.Balance))
+	assert.Equal(t, want.Position, got.Position)
+	assert.Equal(t, want.Level, got.Level)
+	assert.DeepEqual(t, want.Levels, got.Levels)
+
+	// The fields of a nil embedded pointer are left out, and a pointer to a marshaler type can be nil.
+	bz, err = codec.MsgPack.Encode(Item{Name: "empty"})
+	assert.NilError(t, err)
+	got = Item{}
+	assert.NilError(t, codec.MsgPack.Decode(bz, &got))
+	assert.Check(t, got.Extra == nil)
+	assert.Check(t, got.Amount == nil)
+	assert.Equal(t, "empty", got.Name)
+
+	// Marshalers are also used for values that are not addressable.
+	bz, err = codec.MsgPack.Encode(*big.NewInt(5))
+	assert.NilError(t, err)
+	var amount *big.Int
+	assert.NilError(t, codec.MsgPack.Decode(bz, &amount))
+	assert.Equal(t, int64(5), amount.Int64())
+}
+
+func TestMsgPackRejectsStructsWithoutEncodableFields(t *testing.T) {
+	type opaque struct {
+		secret int
+	}
+	_, err := codec.MsgPack.Encode(opaque{secret: 1})
+	assert.ErrorContains(t, err, "has no exported fields")
+	_, err = codec.MsgPack.Encode(map[string]opaque{"a": {secret: 1}})
+	assert.ErrorContains(t, err, "has no exported fields")
+	assert.ErrorContains(t, codec.MsgPack.Decode([]byte{0x80}, &opaque{}), "has no exported fields")
+
+	// A struct without fields has nothing to lose.
+	bz, err := codec.MsgPack.Encode(struct{}{})
+	assert.NilError(t, err)
+	assert.NilError(t, codec.MsgPack.Decode(bz, &struct{}{}))
+}
@@ -0,0 +1,929 @@
+package codec
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rotisserie/eris"
+)
+
+// MsgPack is a binary codec that encodes values with MessagePack. It is more compact and faster than JSON for large
+// values like arrays and trees. The encoding is deterministic: struct fields are encoded in declaration order, map
+// entries are sorted, and numbers use their shortest representation, so equal values always have equal encodings.
+// Like JSON, only exported fields are encoded, fields are named after their json tag if they have one, and the fields
+// of embedded structs are encoded as fields of the outer struct. Values that implement encoding.BinaryMarshaler are
+// encoded as binary data, and values that implement encoding.TextMarshaler or json.Marshaler as strings, in this order
+// of preference, so types like time.Time and *big.Int keep their value. Encoding a struct that has unexported fields
+// but no encoded field fails, since its value would be lost.
+var MsgPack Codec = msgpackCodec{}
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string {
+	return "msgpack"
+}
+
+func (msgpackCodec) Encode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeMsgPack(&buf, reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (msgpackCodec) Decode(bz []byte, v any) error {
+	ptr := reflect.ValueOf(v)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return eris.Errorf("cannot decode msgpack into %T, a non-nil pointer is required", v)
+	}
+	d := &msgpackDecoder{bz: bz, pos: 0}
+	if err := d.decode(ptr.Elem()); err != nil {
+		return err
+	}
+	if d.pos != len(bz) {
+		return eris.Errorf("unexpected %d bytes after msgpack value", len(bz)-d.pos)
+	}
+	return nil
+}
+
+// MessagePack format bytes, see https://github.com/msgpack/msgpack/blob/master/spec.md.
+const (
+	mpNil      byte = 0xc0
+	mpFalse    byte = 0xc2
+	mpTrue     byte = 0xc3
+	mpBin8     byte = 0xc4
+	mpBin16    byte = 0xc5
+	mpBin32    byte = 0xc6
+	mpFloat32  byte = 0xca
+	mpFloat64  byte = 0xcb
+	mpUint8    byte = 0xcc
+	mpUint16   byte = 0xcd
+	mpUint32   byte = 0xce
+	mpUint64   byte = 0xcf
+	mpInt8     byte = 0xd0
+	mpInt16    byte = 0xd1
+	mpInt32    byte = 0xd2
+	mpInt64    byte = 0xd3
+	mpStr8     byte = 0xd9
+	mpStr16    byte = 0xda
+	mpStr32    byte = 0xdb
+	mpArray16  byte = 0xdc
+	mpArray32  byte = 0xdd
+	mpMap16    byte = 0xde
+	mpMap32    byte = 0xdf
+	mpFixMap   byte = 0x80
+	mpFixArray byte = 0x90
+	mpFixStr   byte = 0xa0
+)
+
+//nolint:gocyclo,cyclop // one case per kind
+func encodeMsgPack(buf *bytes.Buffer, v reflect.Value) error {
+	if !v.IsValid() {
+		buf.WriteByte(mpNil)
+		return nil
+	}
+	if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			buf.WriteByte(mpNil)
+			return nil
+		}
+		return encodeMsgPack(buf, v.Elem())
+	}
+	if kind := msgpackMarshalerOf(v.Type()); kind != noMarshaler {
+		return encodeMsgPackMarshaler(buf, v, kind)
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			buf.WriteByte(mpTrue)
+		} else {
+			buf.WriteByte(mpFalse)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeMsgPackInt(buf, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeMsgPackUint(buf, v.Uint())
+	case reflect.Float32:
+		buf.WriteByte(mpFloat32)
+		buf.Write(binary.BigEndian.AppendUint32(nil, math.Float32bits(float32(v.Float()))))
+	case reflect.Float64:
+		buf.WriteByte(mpFloat64)
+		buf.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(v.Float())))
+	case reflect.String:
+		writeMsgPackHeader(buf, v.Len(), mpFixStr, 32, mpStr8, mpStr16, mpStr32)
+		buf.WriteString(v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			buf.WriteByte(mpNil)
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			writeMsgPackHeader(buf, v.Len(), 0, 0, mpBin8, mpBin16, mpBin32)
+			buf.Write(v.Bytes())
+			return nil
+		}
+		return encodeMsgPackArray(buf, v)
+	case reflect.Array:
+		return encodeMsgPackArray(buf, v)
+	case reflect.Map:
+		if v.IsNil() {
+			buf.WriteByte(mpNil)
+			return nil
+		}
+		return encodeMsgPackMap(buf, v)
+	case reflect.Struct:
+		return encodeMsgPackStruct(buf, v)
+	default:
+		return eris.Errorf("cannot encode %s with msgpack", v.Type())
+	}
+	return nil
+}
+
+// encodeMsgPackStruct encodes a struct as a map from the names of its fields to their values. The fields of an embedded
+// struct pointer that is nil are left out.
+func encodeMsgPackStruct(buf *bytes.Buffer, v reflect.Value) error {
+	info := msgpackStructOf(v.Type())
+	if info.opaque {
+		return eris.Errorf("cannot encode %s with msgpack: it has no exported fields", v.Type())
+	}
+	names := make([]string, 0, len(info.fields))
+	values := make([]reflect.Value, 0, len(info.fields))
+	for _, field := range info.fields {
+		value, ok := fieldByIndex(v, field.index)
+		if !ok {
+			continue
+		}
+		names = append(names, field.name)
+		values = append(values, value)
+	}
+	writeMsgPackHeader(buf, len(names), mpFixMap, 16, 0, mpMap16, mpMap32)
+	for i, name := range names {
+		writeMsgPackHeader(buf, len(name), mpFixStr, 32, mpStr8, mpStr16, mpStr32)
+		buf.WriteString(name)
+		if err := encodeMsgPack(buf, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldByIndex returns the field of v with the given index path. ok is false if the path goes through a nil embedded
+// pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// encodeMsgPackMarshaler encodes v with the marshaler of the given kind that its type implements, with either a value
+// or a pointer receiver.
+func encodeMsgPackMarshaler(buf *bytes.Buffer, v reflect.Value, kind msgpackMarshaler) error {
+	if !v.CanInterface() {
+		return eris.Errorf("cannot encode unexported %s with msgpack", v.Type())
+	}
+	m := v
+	if !msgpackImplements(v.Type(), kind) {
+		// The marshaler has a pointer receiver.
+		if v.CanAddr() {
+			m = v.Addr()
+		} else {
+			m = reflect.New(v.Type())
+			m.Elem().Set(v)
+		}
+	}
+	var bz []byte
+	var err error
+	switch kind {
+	case binaryMarshaler:
+		bz, err = m.Interface().(encoding.BinaryMarshaler).MarshalBinary() //nolint:errcheck // checked by kind
+	case textMarshaler:
+		bz, err = m.Interface().(encoding.TextMarshaler).MarshalText() //nolint:errcheck // checked by kind
+	case jsonMarshaler:
+		bz, err = m.Interface().(json.Marshaler).MarshalJSON() //nolint:errcheck // checked by kind
+	}
+	if err != nil {
+		return eris.Wrapf(err, "failed to marshal %s", v.Type())
+	}
+	if kind == binaryMarshaler {
+		writeMsgPackHeader(buf, len(bz), 0, 0, mpBin8, mpBin16, mpBin32)
+	} else {
+		writeMsgPackHeader(buf, len(bz), mpFixStr, 32, mpStr8, mpStr16, mpStr32)
+	}
+	buf.Write(bz)
+	return nil
+}
+
+func encodeMsgPackArray(buf *bytes.Buffer, v reflect.Value) error {
+	writeMsgPackHeader(buf, v.Len(), mpFixArray, 16, 0, mpArray16, mpArray32)
+	for i := 0; i < v.Len(); i++ {
+		if err := encodeMsgPack(buf, v.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeMsgPackMap encodes the entries of a map sorted by their encoded key, so the encoding does not depend on the
+// iteration order of the map.
+func encodeMsgPackMap(buf *bytes.Buffer, v reflect.Value) error {
+	type entry struct {
+		key   []byte
+		value reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		var key bytes.Buffer
+		if err := encodeMsgPack(&key, iter.Key()); err != nil {
+			return err
+		}
+		entries = append(entries, entry{key: key.Bytes(), value: iter.Value()})
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return bytes.Compare(a.key, b.key)
+	})
+	writeMsgPackHeader(buf, len(entries), mpFixMap, 16, 0, mpMap16, mpMap32)
+	for _, e := range entries {
+		buf.Write(e.key)
+		if err := encodeMsgPack(buf, e.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeMsgPackHeader writes the format and length of a string, binary, array or map. fix is the format of the fixed
+// size variant, which is used for lengths below fixLimit, and the 8 bit variant is skipped if format8 is 0.
+func writeMsgPackHeader(buf *bytes.Buffer, n int, fix byte, fixLimit int, format8, format16, format32 byte) {
+	switch {
+	case n < fixLimit:
+		buf.WriteByte(fix | byte(n))
+	case format8 != 0 && n <= math.MaxUint8:
+		buf.WriteByte(format8)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(format16)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		buf.WriteByte(format32)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+}
+
+func writeMsgPackUint(buf *bytes.Buffer, n uint64) {
+	switch {
+	case n <= math.MaxInt8:
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(mpUint8)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(mpUint16)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	case n <= math.MaxUint32:
+		buf.WriteByte(mpUint32)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	default:
+		buf.WriteByte(mpUint64)
+		buf.Write(binary.BigEndian.AppendUint64(nil, n))
+	}
+}
+
+// writeMsgPackInt writes n in its shortest form. Non-negative numbers are written as unsigned numbers, so a value
+// has the same encoding whether it is stored in a signed or an unsigned field.
+func writeMsgPackInt(buf *bytes.Buffer, n int64) {
+	switch {
+	case n >= 0:
+		writeMsgPackUint(buf, uint64(n))
+	case n >= -32:
+		buf.WriteByte(byte(n))
+	case n >= math.MinInt8:
+		buf.WriteByte(mpInt8)
+		buf.WriteByte(byte(n))
+	case n >= math.MinInt16:
+		buf.WriteByte(mpInt16)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	case n >= math.MinInt32:
+		buf.WriteByte(mpInt32)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	default:
+		buf.WriteByte(mpInt64)
+		buf.Write(binary.BigEndian.AppendUint64(nil, uint64(n)))
+	}
+}
+
+// msgpackMarshaler is the kind of marshaler a type implements, see msgpackMarshalerOf.
+type msgpackMarshaler int
+
+const (
+	noMarshaler msgpackMarshaler = iota
+	binaryMarshaler
+	textMarshaler
+	jsonMarshaler
+)
+
+var (
+	binaryMarshalerType   = reflect.TypeFor[encoding.BinaryMarshaler]()
+	binaryUnmarshalerType = reflect.TypeFor[encoding.BinaryUnmarshaler]()
+	textMarshalerType     = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType   = reflect.TypeFor[encoding.TextUnmarshaler]()
+	jsonMarshalerType     = reflect.TypeFor[json.Marshaler]()
+	jsonUnmarshalerType   = reflect.TypeFor[json.Unmarshaler]()
+)
+
+// msgpackMarshalerCache maps types to the kind of marshaler they implement.
+var msgpackMarshalerCache sync.Map
+
+// msgpackMarshalerOf returns the marshaler that is used to encode the values of a type, which is not a pointer or an
+// interface: encoding.BinaryMarshaler, then encoding.TextMarshaler, then json.Marshaler, with either a value or a
+// pointer receiver.
+func msgpackMarshalerOf(t reflect.Type) msgpackMarshaler {
+	if cached, ok := msgpackMarshalerCache.Load(t); ok {
+		return cached.(msgpackMarshaler) //nolint:errcheck // only marshaler kinds are stored
+	}
+	kind := noMarshaler
+	for _, k := range []msgpackMarshaler{binaryMarshaler, textMarshaler, jsonMarshaler} {
+		if msgpackImplements(t, k) || msgpackImplements(reflect.PointerTo(t), k) {
+			kind = k
+			break
+		}
+	}
+	msgpackMarshalerCache.Store(t, kind)
+	return kind
+}
+
+func msgpackImplements(t reflect.Type, kind msgpackMarshaler) bool {
+	switch kind {
+	case binaryMarshaler:
+		return t.Implements(binaryMarshalerType)
+	case textMarshaler:
+		return t.Implements(textMarshalerType)
+	case jsonMarshaler:
+		return t.Implements(jsonMarshalerType)
+	}
+	return false
+}
+
+type msgpackField struct {
+	name string
+	// index is the index path of the field, which goes through the embedded structs the field is promoted from.
+	index []int
+}
+
+type msgpackStruct struct {
+	fields []msgpackField
+	// opaque is true if the struct has unexported fields but no encoded field, like a struct that keeps its state in
+	// unexported fields. Its value can not be encoded.
+	opaque bool
+}
+
+// msgpackStructCache maps struct types to their msgpackStruct.
+var msgpackStructCache sync.Map
+
+// msgpackStructOf returns the fields of a struct type that are encoded: its exported fields and the exported fields
+// promoted from its embedded structs, named after their json tag if they have one. Like with JSON, a promoted field is
+// hidden by a field with the same name that is less deeply embedded, and fields with the same name at the same depth
+// are all left out, unless only one of them is named by a json tag.
+func msgpackStructOf(t reflect.Type) *msgpackStruct {
+	if cached, ok := msgpackStructCache.Load(t); ok {
+		return cached.(*msgpackStruct) //nolint:errcheck // only msgpackStructs are stored
+	}
+	type candidate struct {
+		msgpackField
+		tagged bool
+	}
+	var candidates []candidate
+	unexported := false
+	var walk func(t reflect.Type, index []int, visited map[reflect.Type]bool)
+	walk = func(t reflect.Type, index []int, visited map[reflect.Type]bool) {
+		visited[t] = true
+		defer delete(visited, t)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tagName := ""
+			if tag, ok := f.Tag.Lookup("json"); ok {
+				tagName, _, _ = strings.Cut(tag, ",")
+				if tagName == "-" {
+					continue
+				}
+			}
+			path := append(slices.Clone(index), i)
+			if f.Anonymous && tagName == "" {
+				ft := f.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				// A pointer to an unexported struct can not be allocated when it is decoded.
+				promoted := ft.Kind() == reflect.Struct && (f.IsExported() || f.Type.Kind() != reflect.Pointer)
+				if promoted {
+					if !visited[ft] {
+						walk(ft, path, visited)
+					}
+					continue
+				}
+			}
+			if !f.IsExported() {
+				unexported = true
+				continue
+			}
+			name := f.Name
+			if tagName != "" {
+				name = tagName
+			}
+			candidates = append(candidates, candidate{msgpackField{name: name, index: path}, tagName != ""})
+		}
+	}
+	walk(t, nil, map[reflect.Type]bool{})
+
+	// Keep the dominant field of each name, in the order of the fields in the struct.
+	byName := map[string][]candidate{}
+	for _, c := range candidates {
+		byName[c.name] = append(byName[c.name], c)
+	}
+	fields := make([]msgpackField, 0, len(candidates))
+	for _, c := range candidates {
+		same := byName[c.name]
+		depth := len(same[0].index)
+		for _, other := range same {
+			depth = min(depth, len(other.index))
+		}
+		var dominant []candidate
+		for _, other := range same {
+			if len(other.index) == depth {
+				dominant = append(dominant, other)
+			}
+		}
+		if len(dominant) > 1 {
+			tagged := slices.DeleteFunc(slices.Clone(dominant), func(other candidate) bool { return !other.tagged })
+			dominant = tagged
+		}
+		if len(dominant) == 1 && slices.Equal(dominant[0].index, c.index) {
+			fields = append(fields, c.msgpackField)
+		}
+	}
+	info := &msgpackStruct{fields: fields, opaque: len(fields) == 0 && unexported}
+	msgpackStructCache.Store(t, info)
+	return info
+}
+
+type msgpackDecoder struct {
+	bz  []byte
+	pos int
+}
+
+func (d *msgpackDecoder) read(n int) ([]byte, error) {
+	if n < 0 || n > len(d.bz)-d.pos {
+		return nil, eris.New("unexpected end of msgpack data")
+	}
+	bz := d.bz[d.pos : d.pos+n]
+	d.pos += n
+	return bz, nil
+}
+
+func (d *msgpackDecoder) readByte() (byte, error) {
+	bz, err := d.read(1)
+	if err != nil {
+		return 0, err
+	}
+	return bz[0], nil
+}
+
+func (d *msgpackDecoder) readUint(size int) (uint64, error) {
+	bz, err := d.read(size)
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(bz[0]), nil
+	case 2: //nolint:mnd // size in bytes
+		return uint64(binary.BigEndian.Uint16(bz)), nil
+	case 4: //nolint:mnd // size in bytes
+		return uint64(binary.BigEndian.Uint32(bz)), nil
+	default:
+		return binary.BigEndian.Uint64(bz), nil
+	}
+}
+
+// readLength reads the length of a string, binary, array or map whose format byte is b. The length must not be
+// larger than the remaining data, since every element takes at least one byte.
+func (d *msgpackDecoder) readLength(b byte) (int, error) {
+	var n uint64
+	var err error
+	switch b {
+	case mpStr8, mpBin8:
+		n, err = d.readUint(1)
+	case mpStr16, mpBin16, mpArray16, mpMap16:
+		n, err = d.readUint(2) //nolint:mnd // size in bytes
+	case mpStr32, mpBin32, mpArray32, mpMap32:
+		n, err = d.readUint(4) //nolint:mnd // size in bytes
+	default:
+		return 0, eris.Errorf("unexpected msgpack format 0x%x", b)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(d.bz)-d.pos) {
+		return 0, eris.New("unexpected end of msgpack data")
+	}
+	return int(n), nil
+}
+
+type msgpackKind int
+
+const (
+	mpKindNil msgpackKind = iota
+	mpKindBool
+	mpKindInt
+	mpKindUint
+	mpKindFloat
+	mpKindString
+	mpKindBinary
+	mpKindArray
+	mpKindMap
+)
+
+// msgpackToken is the header of the next value: its kind, and its value or length.
+type msgpackToken struct {
+	kind   msgpackKind
+	b      bool
+	i      int64
+	u      uint64
+	f      float64
+	length int
+}
+
+//nolint:gocyclo,cyclop // one case per format
+func (d *msgpackDecoder) next() (msgpackToken, error) {
+	b, err := d.readByte()
+	if err != nil {
+		return msgpackToken{}, err
+	}
+	switch {
+	case b <= 0x7f:
+		return msgpackToken{kind: mpKindUint, u: uint64(b)}, nil
+	case b >= 0xe0:
+		return msgpackToken{kind: mpKindInt, i: int64(int8(b))}, nil
+	case b&0xf0 == mpFixMap:
+		return msgpackToken{kind: mpKindMap, length: int(b & 0x0f)}, nil
+	case b&0xf0 == mpFixArray:
+		return msgpackToken{kind: mpKindArray, length: int(b & 0x0f)}, nil
+	case b&0xe0 == mpFixStr:
+		return msgpackToken{kind: mpKindString, length: int(b & 0x1f)}, nil
+	}
+	switch b {
+	case mpNil:
+		return msgpackToken{kind: mpKindNil}, nil
+	case mpFalse, mpTrue:
+		return msgpackToken{kind: mpKindBool, b: b == mpTrue}, nil
+	case mpUint8, mpUint16, mpUint32, mpUint64:
+		u, err := d.readUint(1 << (b - mpUint8))
+		return msgpackToken{kind: mpKindUint, u: u}, err
+	case mpInt8, mpInt16, mpInt32, mpInt64:
+		size := 1 << (b - mpInt8)
+		u, err := d.readUint(size)
+		// Sign extend the value.
+		shift := 64 - 8*size
+		return msgpackToken{kind: mpKindInt, i: int64(u<<shift) >> shift}, err //nolint:gosec // intended conversion
+	case mpFloat32:
+		u, err := d.readUint(4) //nolint:mnd // size in bytes
+		return msgpackToken{kind: mpKindFloat, f: float64(math.Float32frombits(uint32(u)))}, err
+	case mpFloat64:
+		u, err := d.readUint(8) //nolint:mnd // size in bytes
+		return msgpackToken{kind: mpKindFloat, f: math.Float64frombits(u)}, err
+	case mpStr8, mpStr16, mpStr32:
+		n, err := d.readLength(b)
+		return msgpackToken{kind: mpKindString, length: n}, err
+	case mpBin8, mpBin16, mpBin32:
+		n, err := d.readLength(b)
+		return msgpackToken{kind: mpKindBinary, length: n}, err
+	case mpArray16, mpArray32:
+		n, err := d.readLength(b)
+		return msgpackToken{kind: mpKindArray, length: n}, err
+	case mpMap16, mpMap32:
+		n, err := d.readLength(b)
+		return msgpackToken{kind: mpKindMap, length: n}, err
+	}
+	return msgpackToken{}, eris.Errorf("unsupported msgpack format 0x%x", b)
+}
+
+//nolint:gocyclo,cyclop // one case per kind
+func (d *msgpackDecoder) decode(v reflect.Value) error {
+	if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
+		value, err := d.decodeAny()
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			v.SetZero()
+		} else {
+			v.Set(reflect.ValueOf(value))
+		}
+		return nil
+	}
+
+	if v.Kind() == reflect.Pointer {
+		if d.pos < len(d.bz) && d.bz[d.pos] == mpNil {
+			d.pos++
+			v.SetZero()
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decode(v.Elem())
+	}
+	if kind := msgpackMarshalerOf(v.Type()); kind != noMarshaler && v.Kind() != reflect.Interface {
+		return d.decodeMarshaler(v, kind)
+	}
+
+	tok, err := d.next()
+	if err != nil {
+		return err
+	}
+	if tok.kind == mpKindNil {
+		v.SetZero()
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if tok.kind != mpKindBool {
+			return d.typeError(tok, v)
+		}
+		v.SetBool(tok.b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		switch {
+		case tok.kind == mpKindInt:
+			n = tok.i
+		case tok.kind == mpKindUint && tok.u <= math.MaxInt64:
+			n = int64(tok.u)
+		default:
+			return d.typeError(tok, v)
+		}
+		if v.OverflowInt(n) {
+			return eris.Errorf("msgpack number %d overflows %s", n, v.Type())
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if tok.kind != mpKindUint {
+			return d.typeError(tok, v)
+		}
+		if v.OverflowUint(tok.u) {
+			return eris.Errorf("msgpack number %d overflows %s", tok.u, v.Type())
+		}
+		v.SetUint(tok.u)
+	case reflect.Float32, reflect.Float64:
+		switch tok.kind {
+		case mpKindFloat:
+			v.SetFloat(tok.f)
+		case mpKindInt:
+			v.SetFloat(float64(tok.i))
+		case mpKindUint:
+			v.SetFloat(float64(tok.u))
+		default:
+			return d.typeError(tok, v)
+		}
+	case reflect.String:
+		if tok.kind != mpKindString {
+			return d.typeError(tok, v)
+		}
+		bz, err := d.read(tok.length)
+		if err != nil {
+			return err
+		}
+		v.SetString(string(bz))
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 && (tok.kind == mpKindBinary || tok.kind == mpKindString) {
+			bz, err := d.read(tok.length)
+			if err != nil {
+				return err
+			}
+			v.SetBytes(slices.Clone(bz))
+			return nil
+		}
+		if tok.kind != mpKindArray {
+			return d.typeError(tok, v)
+		}
+		v.Set(reflect.MakeSlice(v.Type(), tok.length, tok.length))
+		for i := 0; i < tok.length; i++ {
+			if err := d.decode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Array:
+		if tok.kind != mpKindArray {
+			return d.typeError(tok, v)
+		}
+		if tok.length > v.Len() {
+			return eris.Errorf("cannot decode msgpack array of length %d into %s", tok.length, v.Type())
+		}
+		v.SetZero()
+		for i := 0; i < tok.length; i++ {
+			if err := d.decode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if tok.kind != mpKindMap {
+			return d.typeError(tok, v)
+		}
+		v.Set(reflect.MakeMapWithSize(v.Type(), tok.length))
+		for i := 0; i < tok.length; i++ {
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := d.decode(key); err != nil {
+				return err
+			}
+			value := reflect.New(v.Type().Elem()).Elem()
+			if err := d.decode(value); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, value)
+		}
+	case reflect.Struct:
+		if tok.kind != mpKindMap {
+			return d.typeError(tok, v)
+		}
+		info := msgpackStructOf(v.Type())
+		if info.opaque {
+			return eris.Errorf("cannot decode msgpack into %s: it has no exported fields", v.Type())
+		}
+		v.SetZero()
+		for i := 0; i < tok.length; i++ {
+			var name string
+			if err := d.decode(reflect.ValueOf(&name).Elem()); err != nil {
+				return err
+			}
+			index := slices.IndexFunc(info.fields, func(f msgpackField) bool { return f.name == name })
+			if index == -1 {
+				// Unknown fields are skipped, like with JSON.
+				if _, err := d.decodeAny(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := d.decode(allocFieldByIndex(v, info.fields[index].index)); err != nil {
+				return err
+			}
+		}
+	default:
+		return eris.Errorf("cannot decode msgpack into %s", v.Type())
+	}
+	return nil
+}
+
+// allocFieldByIndex returns the field of v with the given index path, allocating the nil embedded pointers on the way.
+func allocFieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// decodeMarshaler decodes a value that was encoded with the marshaler of the given kind, with the matching unmarshaler
+// of its type.
+func (d *msgpackDecoder) decodeMarshaler(v reflect.Value, kind msgpackMarshaler) error {
+	tok, err := d.next()
+	if err != nil {
+		return err
+	}
+	if tok.kind == mpKindNil {
+		v.SetZero()
+		return nil
+	}
+	want := mpKindString
+	if kind == binaryMarshaler {
+		want = mpKindBinary
+	}
+	if tok.kind != want {
+		return d.typeError(tok, v)
+	}
+	bz, err := d.read(tok.length)
+	if err != nil {
+		return err
+	}
+	if !v.CanAddr() || !v.Addr().CanInterface() {
+		return eris.Errorf("cannot decode msgpack into unexported %s", v.Type())
+	}
+	target := v.Addr().Interface()
+	// The unmarshaler may keep the data, which belongs to the decoder.
+	bz = slices.Clone(bz)
+	switch kind {
+	case binaryMarshaler:
+		if u, ok := target.(encoding.BinaryUnmarshaler); ok {
+			err = u.UnmarshalBinary(bz)
+		} else {
+			return eris.Errorf("cannot decode msgpack into %s: it does not implement %s", v.Type(), binaryUnmarshalerType)
+		}
+	case textMarshaler:
+		if u, ok := target.(encoding.TextUnmarshaler); ok {
+			err = u.UnmarshalText(bz)
+		} else {
+			return eris.Errorf("cannot decode msgpack into %s: it does not implement %s", v.Type(), textUnmarshalerType)
+		}
+	case jsonMarshaler:
+		if u, ok := target.(json.Unmarshaler); ok {
+			err = u.UnmarshalJSON(bz)
+		} else {
+			return eris.Errorf("cannot decode msgpack into %s: it does not implement %s", v.Type(), jsonUnmarshalerType)
+		}
+	}
+	return eris.Wrapf(err, "failed to unmarshal %s", v.Type())
+}
+
+// decodeAny decodes the next value into nil, bool, int64, uint64, float64, string, []byte, []any, or map[string]any
+// (map[any]any if a key is not a string).
+//
+//nolint:gocyclo,cyclop // one case per kind
+func (d *msgpackDecoder) decodeAny() (any, error) {
+	tok, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+	switch tok.kind {
+	case mpKindNil:
+		return nil, nil
+	case mpKindBool:
+		return tok.b, nil
+	case mpKindInt:
+		return tok.i, nil
+	case mpKindUint:
+		return tok.u, nil
+	case mpKindFloat:
+		return tok.f, nil
+	case mpKindString:
+		bz, err := d.read(tok.length)
+		return string(bz), err
+	case mpKindBinary:
+		bz, err := d.read(tok.length)
+		return slices.Clone(bz), err
+	case mpKindArray:
+		values := make([]any, tok.length)
+		for i := range values {
+			if values[i], err = d.decodeAny(); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	case mpKindMap:
+		keys := make([]any, tok.length)
+		values := make([]any, tok.length)
+		stringKeys := true
+		for i := range keys {
+			if keys[i], err = d.decodeAny(); err != nil {
+				return nil, err
+			}
+			if values[i], err = d.decodeAny(); err != nil {
+				return nil, err
+			}
+			if _, ok := keys[i].(string); !ok {
+				stringKeys = false
+			}
+		}
+		if stringKeys {
+			m := make(map[string]any, len(keys))
+			for i, key := range keys {
+				m[key.(string)] = values[i] //nolint:errcheck // all keys are strings
+			}
+			return m, nil
+		}
+		m := make(map[any]any, len(keys))
+		for i, key := range keys {
+			if key != nil && !reflect.TypeOf(key).Comparable() {
+				return nil, eris.Errorf("cannot use msgpack %T as a map key", key)
+			}
+			m[key] = values[i]
+		}
+		return m, nil
+	}
+	return nil, eris.New("unexpected msgpack value")
+}
+
+func (d *msgpackDecoder) typeError(tok msgpackToken, v reflect.Value) error {
+	names := map[msgpackKind]string{
+		mpKindNil: "nil", mpKindBool: "bool", mpKindInt: "int", mpKindUint: "uint", mpKindFloat: "float",
+		mpKindString: "string", mpKindBinary: "binary", mpKindArray: "array", mpKindMap: "map",
+	}
+	return eris.Errorf("cannot decode msgpack %s into %s", names[tok.kind], v.Type())
+}
@@ -28,6 +28,7 @@ type componentMetadata[T types.Component] struct {
 	name       string
 	schema     []byte
 	defaultVal types.Component
+	codec      codec.Codec
 }
 
 // NewComponentMetadata creates a new component type.
@@ -47,6 +48,7 @@ func NewComponentMetadata[T types.Component](opts ...Option[T]) (
 		compType: compType,
 		name:     t.Name(),
 		schema:   schema,
+		codec:    codec.JSON,
 	}
 	for _, opt := range opts {
 		opt(compMetadata)
@@ -92,17 +94,27 @@ func (c *componentMetadata[T]) ID() types.ComponentID {
 
 func (c *componentMetadata[T]) New() ([]byte, error) {
 	if c.defaultVal != nil {
-		return codec.Encode(c.defaultVal)
+		return c.codec.Encode(c.defaultVal)
 	}
-	return codec.Encode(c.compType)
+	var t T
+	return c.codec.Encode(t)
 }
 
 func (c *componentMetadata[T]) Encode(v any) ([]byte, error) {
-	return codec.Encode(v)
+	return c.codec.Encode(v)
 }
 
 func (c *componentMetadata[T]) Decode(bz []byte) (types.Component, error) {
-	return codec.Decode[T](bz)
+	comp := new(T)
+	if err := c.codec.Decode(bz, comp); err != nil {
+		return *comp, err
+	}
+	return *comp, nil
+}
+
+// Codec returns the codec used to encode the values of the component in storage.
+func (c *componentMetadata[T]) Codec() codec.Codec {
+	return c.codec
 }
 
 func (c *componentMetadata[T]) ValidateAgainstSchema(targetSchema []byte) error {
@@ -131,3 +143,13 @@ func WithDefault[T types.Component](defaultVal T) Option[T] {
 		c.validateDefaultVal()
 	}
 }
+
+// WithCodec sets the codec used to encode the values of the component in storage, e.g. codec.MsgPack for large
+// components. Values are still shown as JSON by the debug endpoints and CQL. The codec is stored with the schema of the
+// component, and registering the component with another codec once values have been stored fails with
+// types.ErrComponentCodecMismatch.
+func WithCodec[T types.Component](c codec.Codec) Option[T] {
+	return func(comp *componentMetadata[T]) {
+		comp.codec = c
+	}
+}
@@ -1,10 +1,15 @@
 package component_test
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/component"
 	"pkg.world.dev/world-engine/cardinal/filter"
 	"pkg.world.dev/world-engine/cardinal/gamestate"
 	"pkg.world.dev/world-engine/cardinal/types"
@@ -387,3 +392,90 @@ func TestGetMessageByName(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, weightComp.Name(), "weight")
 }
+
+func TestRegisterComponentMigration(t *testing.T) {
+	tf1 := cardinal.NewTestFixture(t, nil)
+	world := tf1.World
+	assert.NilError(t, cardinal.RegisterComponent[OldComponent](world))
+	tf1.StartWorld()
+	wCtx := cardinal.NewWorldContext(world)
+	first, err := cardinal.Create(wCtx, OldComponent{Val: 3})
+	assert.NilError(t, err)
+	second, err := cardinal.Create(wCtx, OldComponent{Val: 5})
+	assert.NilError(t, err)
+	tf1.DoTick()
+
+	// The migration turns the mismatch into a pending migration
+	tf2 := cardinal.NewTestFixture(t, tf1.Redis)
+	world = tf2.World
+	assert.NilError(t, cardinal.RegisterComponentMigration(world, func(old OldComponent) (NewComponent, error) {
+		return NewComponent{Val: old.Val * 2, NewFieldToScrewUpSchema: 1}, nil
+	}))
+	assert.NilError(t, cardinal.RegisterComponent[NewComponent](world))
+
+	reports, err := cardinal.DryRunComponentMigrations(world)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []cardinal.ComponentMigrationReport{{Component: "OldComponent", Steps: 1, Values: 2}}, reports)
+	// A dry run can be repeated since nothing was saved
+	reports, err = cardinal.DryRunComponentMigrations(world)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, reports[0].Values)
+
+	tf2.StartWorld()
+	wCtx = cardinal.NewWorldContext(world)
+	for id, want := range map[types.EntityID]int{first: 6, second: 10} {
+		got, err := cardinal.GetComponent[NewComponent](wCtx, id)
+		assert.NilError(t, err)
+		assert.Equal(t, NewComponent{Val: want, NewFieldToScrewUpSchema: 1}, *got)
+	}
+
+	// The stored schema was updated, so the new component no longer needs a migration
+	tf3 := cardinal.NewTestFixture(t, tf1.Redis)
+	assert.NilError(t, cardinal.RegisterComponent[NewComponent](tf3.World))
+	reports, err = cardinal.DryRunComponentMigrations(tf3.World)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(reports))
+}
+
+func TestRegisterComponent_WithCodec(t *testing.T) {
+	tf := cardinal.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Height](world, component.WithCodec[Height](codec.MsgPack)))
+	tf.StartWorld()
+
+	wCtx := cardinal.NewWorldContext(world)
+	id, err := cardinal.Create(wCtx, Height{Inches: 70})
+	assert.NilError(t, err)
+	tf.DoTick()
+
+	// The value is stored with the binary codec
+	heightComp, err := world.GetComponentByName(Height{}.Name())
+	assert.NilError(t, err)
+	stored, err := tf.Redis.Get(fmt.Sprintf("ECB:COMPONENT-VALUE:TYPE-ID-%d:ENTITY-ID-%d", heightComp.ID(), id))
+	assert.NilError(t, err)
+	assert.Check(t, !json.Valid([]byte(stored)))
+
+	height, err := cardinal.GetComponent[Height](cardinal.NewReadOnlyWorldContext(world), id)
+	assert.NilError(t, err)
+	assert.Equal(t, 70, height.Inches)
+
+	// The debug state still shows the value as JSON
+	state, err := world.GetDebugState()
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(state))
+	assert.Equal(t, `{"Inches":70}`, string(state[0].Components["height"]))
+}
+
+func TestRegisterComponent_ErrorOnCodecMismatch(t *testing.T) {
+	tf1 := cardinal.NewTestFixture(t, nil)
+	assert.NilError(t, cardinal.RegisterComponent[Height](tf1.World, component.WithCodec[Height](codec.MsgPack)))
+
+	// The stored values can't be read with another codec
+	tf2 := cardinal.NewTestFixture(t, tf1.Redis)
+	err := cardinal.RegisterComponent[Height](tf2.World)
+	assert.Check(t, errors.Is(err, types.ErrComponentCodecMismatch))
+
+	// Registering the component with the same codec still works
+	tf3 := cardinal.NewTestFixture(t, tf1.Redis)
+	assert.NilError(t, cardinal.RegisterComponent[Height](tf3.World, component.WithCodec[Height](codec.MsgPack)))
+}
@@ -1,10 +1,12 @@
 package component
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/rotisserie/eris"
 
+	"pkg.world.dev/world-engine/cardinal/codec"
 	"pkg.world.dev/world-engine/cardinal/storage/redis"
 	"pkg.world.dev/world-engine/cardinal/types"
 )
@@ -15,6 +17,8 @@ type manager struct {
 	registeredComponents map[string]types.ComponentMetadata
 	nextComponentID      types.ComponentID
 	schemaStorage        SchemaStorage
+	migrations           map[string][]Migration
+	pendingMigrations    map[string]PendingMigration
 }
 
 //nolint:revive // reason: we want this name for World which will take on the name of the manager as a prop
@@ -22,6 +26,9 @@ type ComponentManager interface {
 	RegisterComponent(compMetadata types.ComponentMetadata) error
 	GetComponents() []types.ComponentMetadata
 	GetComponentByName(name string) (types.ComponentMetadata, error)
+	RegisterMigration(migration Migration) error
+	PendingMigrations() []PendingMigration
+	CompleteMigration(componentName string) error
 }
 
 // NewManager creates a new component manager.
@@ -30,6 +37,8 @@ func NewManager(schemaStorage SchemaStorage) ComponentManager {
 		registeredComponents: make(map[string]types.ComponentMetadata),
 		nextComponentID:      1,
 		schemaStorage:        schemaStorage,
+		migrations:           make(map[string][]Migration),
+		pendingMigrations:    make(map[string]PendingMigration),
 	}
 }
 
@@ -52,11 +61,35 @@ func (m *manager) RegisterComponent(compMetadata types.ComponentMetadata) error
 
 	//nolint:nestif // Comments for nested if statements provided for clarity
 	if storedSchema != nil {
+		// The values stored with one codec can not be read with another one, so the codecs must match before the
+		// schemas are compared.
+		var storedCodec string
+		if storedSchema, storedCodec, err = splitStoredSchema(storedSchema); err != nil {
+			return err
+		}
+		if codecName := codecName(compMetadata.Codec()); storedCodec != codecName {
+			return eris.Wrapf(types.ErrComponentCodecMismatch,
+				"component %q is stored with the %q codec, but is registered with the %q codec",
+				compMetadata.Name(), storedCodec, codecName)
+		}
+
 		// If there is a schema stored in storage, check if it matches the current schema of the component.
-		// If it does not match or schema validation failed, return an error.
+		// If it does not match, the registered migrations must lead from the stored schema to the current one,
+		// otherwise return an error.
 		// If it does match, our job here is done.
 		if err := compMetadata.ValidateAgainstSchema(storedSchema); err != nil {
 			if eris.Is(err, types.ErrComponentSchemaMismatch) {
+				steps, ok, pathErr := m.findMigrationPath(compMetadata, storedSchema)
+				if pathErr != nil {
+					return pathErr
+				}
+				if ok {
+					if err := m.register(compMetadata); err != nil {
+						return err
+					}
+					m.pendingMigrations[compMetadata.Name()] = PendingMigration{Component: compMetadata, Steps: steps}
+					return nil
+				}
 				return eris.Wrap(err,
 					fmt.Sprintf("component %q does not match the schema stored in storage", compMetadata.Name()),
 				)
@@ -65,14 +98,27 @@ func (m *manager) RegisterComponent(compMetadata types.ComponentMetadata) error
 		}
 	} else {
 		// If there is no schema stored in storage, store the schema of the component in storage.
-		if err := m.schemaStorage.SetSchema(compMetadata.Name(), compMetadata.GetSchema()); err != nil {
+		if err := m.setSchema(compMetadata); err != nil {
 			return err
 		}
 	}
 
-	// Set the component ID and register the component.
-	// We do this after the schema validation and storage operations to ensure that the component is only registered
-	// if the schema validation and storage operations are successful.
+	return m.register(compMetadata)
+}
+
+// setSchema stores the schema of the component, along with the name of its codec.
+func (m *manager) setSchema(compMetadata types.ComponentMetadata) error {
+	schema, err := joinStoredSchema(compMetadata.GetSchema(), codecName(compMetadata.Codec()))
+	if err != nil {
+		return err
+	}
+	return m.schemaStorage.SetSchema(compMetadata.Name(), schema)
+}
+
+// register sets the component ID and registers the component.
+// This is done after the schema validation and storage operations to ensure that the component is only registered
+// if the schema validation and storage operations are successful.
+func (m *manager) register(compMetadata types.ComponentMetadata) error {
 	if err := compMetadata.SetID(m.nextComponentID); err != nil {
 		return err
 	}
@@ -109,3 +155,55 @@ func (m *manager) isComponentNameUnique(compMetadata types.ComponentMetadata) er
 	}
 	return nil
 }
+
+// schemaCodecKey is the property of a stored component schema that holds the name of the codec the values of the
+// component are stored with. It is left out for the JSON codec, so the schemas stored before components could have
+// another codec are still valid.
+const schemaCodecKey = "x-codec"
+
+// joinStoredSchema returns the schema to store for a component with the given schema and codec.
+func joinStoredSchema(schema []byte, codecName string) ([]byte, error) {
+	if codecName == codec.JSON.Name() {
+		return schema, nil
+	}
+	properties := map[string]json.RawMessage{}
+	if err := json.Unmarshal(schema, &properties); err != nil {
+		return nil, eris.Wrap(err, "failed to parse component schema")
+	}
+	bz, err := json.Marshal(codecName)
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	properties[schemaCodecKey] = bz
+	stored, err := json.Marshal(properties)
+	return stored, eris.Wrap(err, "")
+}
+
+// splitStoredSchema returns the schema and the name of the codec of a stored component schema.
+func splitStoredSchema(stored []byte) ([]byte, string, error) {
+	properties := map[string]json.RawMessage{}
+	if err := json.Unmarshal(stored, &properties); err != nil {
+		return nil, "", eris.Wrap(err, "failed to parse stored component schema")
+	}
+	bz, ok := properties[schemaCodecKey]
+	if !ok {
+		return stored, codec.JSON.Name(), nil
+	}
+	var codecName string
+	if err := json.Unmarshal(bz, &codecName); err != nil {
+		return nil, "", eris.Wrap(err, "failed to parse the codec of the stored component schema")
+	}
+	delete(properties, schemaCodecKey)
+	schema, err := json.Marshal(properties)
+	if err != nil {
+		return nil, "", eris.Wrap(err, "")
+	}
+	return schema, codecName, nil
+}
+
+func codecName(c codec.Codec) string {
+	if c == nil {
+		return codec.JSON.Name()
+	}
+	return c.Name()
+}
@@ -0,0 +1,169 @@
+package component
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+	"github.com/rotisserie/eris"
+	"github.com/wI2L/jsondiff"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// Migration rewrites the stored values of a component from one schema to another.
+type Migration struct {
+	Component  string
+	FromSchema []byte
+	ToSchema   []byte
+	// Decode decodes a stored value with FromSchema using the codec of the component.
+	Decode func(c codec.Codec, bz []byte) (any, error)
+	// Migrate converts a value with FromSchema into a value with ToSchema.
+	Migrate func(value any) (any, error)
+}
+
+// NewMigration creates a migration from the component type Old to the component type New, which must have the same
+// name. Old is usually a copy of the previous version of the component struct.
+func NewMigration[Old, New types.Component](migrate func(Old) (New, error)) (Migration, error) {
+	var oldComp Old
+	var newComp New
+	if oldComp.Name() != newComp.Name() {
+		return Migration{}, eris.Errorf("cannot migrate component %q to component %q, they must have the same name",
+			oldComp.Name(), newComp.Name())
+	}
+	fromSchema, err := jsonschema.ReflectFromType(reflect.TypeOf(oldComp)).MarshalJSON()
+	if err != nil {
+		return Migration{}, eris.Wrap(err, "component must be json serializable")
+	}
+	toSchema, err := jsonschema.ReflectFromType(reflect.TypeOf(newComp)).MarshalJSON()
+	if err != nil {
+		return Migration{}, eris.Wrap(err, "component must be json serializable")
+	}
+	return Migration{
+		Component:  newComp.Name(),
+		FromSchema: fromSchema,
+		ToSchema:   toSchema,
+		Decode: func(c codec.Codec, bz []byte) (any, error) {
+			oldValue := new(Old)
+			if err := c.Decode(bz, oldValue); err != nil {
+				return nil, err
+			}
+			return *oldValue, nil
+		},
+		Migrate: func(value any) (any, error) {
+			oldValue, ok := value.(Old)
+			if !ok {
+				// The previous migration returned another type with the same schema, convert it through JSON.
+				bz, err := codec.Encode(value)
+				if err != nil {
+					return nil, err
+				}
+				if oldValue, err = codec.Decode[Old](bz); err != nil {
+					return nil, err
+				}
+			}
+			return migrate(oldValue)
+		},
+	}, nil
+}
+
+// PendingMigration is the list of migrations that bring the stored values of a component to its registered schema.
+type PendingMigration struct {
+	Component types.ComponentMetadata
+	Steps     []Migration
+}
+
+// Migrate runs all the migration steps on a stored value.
+func (p PendingMigration) Migrate(bz []byte) ([]byte, error) {
+	value, err := p.Steps[0].Decode(p.Component.Codec(), bz)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to decode component %q", p.Component.Name())
+	}
+	for _, step := range p.Steps {
+		if value, err = step.Migrate(value); err != nil {
+			return nil, eris.Wrapf(err, "failed to migrate component %q", p.Component.Name())
+		}
+	}
+	return p.Component.Encode(value)
+}
+
+// RegisterMigration adds a migration that is used when the schema of a component stored in storage does not match the
+// schema it is registered with. Migrations must be registered before the component.
+func (m *manager) RegisterMigration(migration Migration) error {
+	if _, ok := m.registeredComponents[migration.Component]; ok {
+		return eris.Errorf("migrations of component %q must be registered before the component", migration.Component)
+	}
+	m.migrations[migration.Component] = append(m.migrations[migration.Component], migration)
+	return nil
+}
+
+// PendingMigrations returns the migrations needed by the registered components, sorted by component name.
+func (m *manager) PendingMigrations() []PendingMigration {
+	result := make([]PendingMigration, 0, len(m.pendingMigrations))
+	for _, pending := range m.pendingMigrations {
+		result = append(result, pending)
+	}
+	slices.SortFunc(result, func(a, b PendingMigration) int {
+		return strings.Compare(a.Component.Name(), b.Component.Name())
+	})
+	return result
+}
+
+// CompleteMigration stores the registered schema of a component once its values have been migrated.
+func (m *manager) CompleteMigration(componentName string) error {
+	pending, ok := m.pendingMigrations[componentName]
+	if !ok {
+		return eris.Errorf("component %q has no pending migration", componentName)
+	}
+	if err := m.setSchema(pending.Component); err != nil {
+		return err
+	}
+	delete(m.pendingMigrations, componentName)
+	return nil
+}
+
+// findMigrationPath returns the migrations that lead from the stored schema to the schema of the component, or false
+// if there are none.
+func (m *manager) findMigrationPath(comp types.ComponentMetadata, storedSchema []byte) ([]Migration, bool, error) {
+	var path []Migration
+	schema := storedSchema
+	// Every migration can be used at most once, which also prevents cycles.
+	used := make([]bool, len(m.migrations[comp.Name()]))
+	for {
+		match, err := schemasMatch(schema, comp.GetSchema())
+		if err != nil {
+			return nil, false, err
+		}
+		if match {
+			return path, true, nil
+		}
+		next := -1
+		for i, migration := range m.migrations[comp.Name()] {
+			if used[i] {
+				continue
+			}
+			if match, err = schemasMatch(schema, migration.FromSchema); err != nil {
+				return nil, false, err
+			} else if match {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			return nil, false, nil
+		}
+		used[next] = true
+		path = append(path, m.migrations[comp.Name()][next])
+		schema = m.migrations[comp.Name()][next].ToSchema
+	}
+}
+
+func schemasMatch(a, b []byte) (bool, error) {
+	diff, err := jsondiff.CompareJSON(a, b)
+	if err != nil {
+		return false, eris.Wrap(err, "failed to compare component schema")
+	}
+	return diff.String() == "", nil
+}
@@ -1,6 +1,7 @@
 package cardinal_test
 
 import (
+	"context"
 	"errors"
 	"strconv"
 	"testing"
@@ -358,7 +359,8 @@ func TestWithoutRegistration(t *testing.T) {
 func TestTransactionsSentToRouterAfterTick(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	rtr := mocks.NewMockRouter(ctrl)
-	tf := cardinal.NewTestFixture(t, nil, cardinal.WithCustomRouter(rtr))
+	// State roots are only submitted to the base shard if they are enabled.
+	tf := cardinal.NewTestFixture(t, nil, cardinal.WithCustomRouter(rtr), cardinal.WithStateRoots(1))
 	world := tf.World
 
 	type fooMsg struct {
@@ -395,6 +397,7 @@ func TestTransactionsSentToRouterAfterTick(t *testing.T) {
 			},
 			world.CurrentTick(),
 			gomock.Any(),
+			gomock.Any(),
 		).
 		Return(nil).
 		Times(1)
@@ -403,19 +406,30 @@ func TestTransactionsSentToRouterAfterTick(t *testing.T) {
 	tf.StartWorld()
 	tf.DoTick()
 
-	// Expect that ticks with no transactions are also submitted
+	// Expect that ticks with no transactions are also submitted, along with the state root of the tick
+	tick := world.CurrentTick()
+	var stateRoot []byte
 	rtr.
 		EXPECT().
 		SubmitTxBlob(
 			gomock.Any(),
 			txpool.TxMap{},
-			world.CurrentTick(),
+			tick,
+			gomock.Any(),
 			gomock.Any(),
 		).
-		Return(nil).
+		DoAndReturn(func(_ context.Context, _ txpool.TxMap, _, _ uint64, root []byte) error {
+			stateRoot = root
+			return nil
+		}).
 		Times(1)
 	rtr.EXPECT().Start().AnyTimes()
 	tf.DoTick()
+
+	expectedRoot, err := world.StateRoot(tick)
+	assert.NilError(t, err)
+	assert.Check(t, len(stateRoot) > 0)
+	assert.DeepEqual(t, stateRoot, expectedRoot)
 }
 
 // setEnvToCardinalRollupMode sets a bunch of environment variables that are required
@@ -0,0 +1,115 @@
+package filter
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// ChangeKind is the kind of component change matched by a ChangeFilter.
+type ChangeKind int
+
+const (
+	// ComponentAdded matches components that were added to an entity, including components of new entities.
+	ComponentAdded ChangeKind = iota
+	// ComponentChanged matches components that were set or added.
+	ComponentChanged
+)
+
+// ChangeFilter matches the entities whose component was added or changed in the current tick. Archetypes are matched
+// like Contains, the change itself is checked by the search for each entity. A system only sees the changes made by
+// the systems that ran before it in the tick.
+type ChangeFilter interface {
+	ComponentFilter
+	// ChangedComponent returns the component whose changes are matched.
+	ChangedComponent() types.Component
+	// Kind returns the kind of change that is matched.
+	Kind() ChangeKind
+}
+
+type changed struct {
+	component types.Component
+	kind      ChangeKind
+}
+
+// Added matches entities that had the component T added in the current tick, either when the entity was created or
+// with AddComponentTo. It can be combined with other filters using And, but not Or or Not.
+//
+//revive:disable-next-line:unexported-return
+func Added[T types.Component]() ChangeFilter {
+	var t T
+	return &changed{component: t, kind: ComponentAdded}
+}
+
+// Changed matches entities whose component T was set or added in the current tick. It can be combined with other
+// filters using And, but not Or or Not.
+//
+//revive:disable-next-line:unexported-return
+func Changed[T types.Component]() ChangeFilter {
+	var t T
+	return &changed{component: t, kind: ComponentChanged}
+}
+
+func (f *changed) MatchesComponents(components []types.Component) bool {
+	return CreateComponentMatcher(components)(f.component)
+}
+
+func (f *changed) ChangedComponent() types.Component {
+	return f.component
+}
+
+func (f *changed) Kind() ChangeKind {
+	return f.kind
+}
+
+// ChangeFilters returns the change filters that every entity matched by the given filter must pass. An error is
+// returned if a change filter is used inside Or or Not, where it can not be evaluated per archetype.
+func ChangeFilters(f ComponentFilter) ([]ChangeFilter, error) {
+	switch f := f.(type) {
+	case ChangeFilter:
+		return []ChangeFilter{f}, nil
+	case *and:
+		var result []ChangeFilter
+		for _, filter := range f.filters {
+			changeFilters, err := ChangeFilters(filter)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, changeFilters...)
+		}
+		return result, nil
+	case *or:
+		for _, filter := range f.filters {
+			if hasChangeFilter(filter) {
+				return nil, eris.New("Added and Changed filters cannot be used inside Or")
+			}
+		}
+	case *not:
+		if hasChangeFilter(f.filter) {
+			return nil, eris.New("Added and Changed filters cannot be used inside Not")
+		}
+	}
+	return nil, nil
+}
+
+func hasChangeFilter(f ComponentFilter) bool {
+	switch f := f.(type) {
+	case ChangeFilter:
+		return true
+	case *and:
+		for _, filter := range f.filters {
+			if hasChangeFilter(filter) {
+				return true
+			}
+		}
+	case *or:
+		for _, filter := range f.filters {
+			if hasChangeFilter(filter) {
+				return true
+			}
+		}
+	case *not:
+		return hasChangeFilter(f.filter)
+	}
+	return false
+}
@@ -299,6 +299,44 @@ func TestCanGetArchetypeFromEntity(t *testing.T) {
 	assert.Equal(t, count2, wantCount)
 }
 
+func TestChangeFilters(t *testing.T) {
+	tf := cardinal.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Alpha](world))
+	assert.NilError(t, cardinal.RegisterComponent[Beta](world))
+	tf.StartWorld()
+
+	wCtx := cardinal.NewWorldContext(world)
+	collect := func(f filter.ComponentFilter) []types.EntityID {
+		ids, err := cardinal.NewSearch().Entity(f).Collect(wCtx)
+		assert.NilError(t, err)
+		return ids
+	}
+	ids, err := cardinal.CreateMany(wCtx, 2, Alpha{}, Beta{})
+	assert.NilError(t, err)
+	alphaID, err := cardinal.Create(wCtx, Alpha{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ids, collect(filter.Added[Beta]()))
+	assert.DeepEqual(t, append(ids, alphaID), collect(filter.Changed[Alpha]()))
+
+	tf.DoTick()
+	assert.Equal(t, 0, len(collect(filter.Changed[Alpha]())))
+
+	assert.NilError(t, cardinal.SetComponent[Alpha](wCtx, ids[0], &Alpha{}))
+	assert.NilError(t, cardinal.AddComponentTo[Beta](wCtx, alphaID))
+	assert.NilError(t, cardinal.RemoveComponentFrom[Beta](wCtx, ids[1]))
+	assert.DeepEqual(t, []types.EntityID{ids[0]}, collect(filter.Changed[Alpha]()))
+	assert.DeepEqual(t, []types.EntityID{alphaID}, collect(filter.Added[Beta]()))
+	assert.DeepEqual(t, []types.EntityID{ids[0]},
+		collect(filter.And(filter.Changed[Alpha](), filter.Contains(filter.Component[Beta]()))))
+	removed, err := cardinal.RemovedComponents[Beta](wCtx)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{ids[1]}, removed)
+
+	_, err = filter.ChangeFilters(filter.Or(filter.Changed[Alpha](), filter.Added[Beta]()))
+	assert.ErrorContains(t, err, "cannot be used inside Or")
+}
+
 func BenchmarkEntityCreation(b *testing.B) {
 	zerolog.SetGlobalLevel(zerolog.Disabled)
 	for i := 0; i < b.N; i++ {
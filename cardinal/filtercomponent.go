@@ -16,6 +16,9 @@ type FilterFn func(wCtx WorldContext, id types.EntityID) (bool, error)
 func ComponentFilter[T types.Component](f func(comp T) bool) FilterFn {
 	return func(wCtx WorldContext, id types.EntityID) (bool, error) {
 		var t T
+		if err := wCtx.componentAccess().checkRead(t.Name()); err != nil {
+			return false, err
+		}
 		c, err := wCtx.getComponentByName(t.Name())
 		if err != nil {
 			return false, err
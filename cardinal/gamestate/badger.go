@@ -0,0 +1,405 @@
+package gamestate
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	ddotel "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentelemetry"
+	ddtracer "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+)
+
+var _ PrimitiveStorage[string] = &BadgerStorage{}
+
+// BadgerStorage is an embedded, on-disk implementation of PrimitiveStorage backed by Badger. It allows a Cardinal
+// shard to run without an external Redis instance.
+//
+// Values are stored using the same textual encoding that Redis uses, so numbers written with Set can be read back with
+// GetInt, GetUInt64, etc. Missing keys are reported with redis.Nil, which is the "key not found" sentinel that the
+// EntityCommandBuffer currently expects from every PrimitiveStorage.
+//
+// A transaction that is too big for a single Badger transaction (see badger.ErrTxnTooBig) is committed through a
+// journal, see commitJournaled, so it is still applied atomically.
+type BadgerStorage struct {
+	db *badger.DB
+	// commitLock is shared by a BadgerStorage and its transactions. It is held for reading by every read and write, and
+	// for writing while a transaction is committed through the journal, so nobody sees it half applied.
+	commitLock *sync.RWMutex
+	// txn is only set on the BadgerStorage returned by StartTransaction. It is a read-only snapshot of the database when
+	// the transaction started, which the reads on the transaction see along with its own writes.
+	txn *badger.Txn
+	// writes holds the writes of a transaction until EndTransaction is called. A nil value deletes the key.
+	writes map[string][]byte
+	tracer trace.Tracer
+}
+
+// NewBadgerPrimitiveStorage opens (or creates) a Badger database in the given directory. If dir is empty, the
+// database is kept entirely in memory, which is useful for tests and CI.
+func NewBadgerPrimitiveStorage(dir string) (*BadgerStorage, error) {
+	opts := badger.DefaultOptions(dir).WithLogger(nil)
+	if dir == "" {
+		opts = opts.WithInMemory(true)
+	}
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to open badger database at %q", dir)
+	}
+	storage := &BadgerStorage{
+		db:         db,
+		commitLock: &sync.RWMutex{},
+		txn:        nil,
+		writes:     nil,
+		tracer:     otel.Tracer("badger"),
+	}
+	// Finish or discard a transaction that was being committed through the journal when the process stopped.
+	if err := storage.applyJournal(); err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+	return storage, nil
+}
+
+func (b *BadgerStorage) GetFloat64(ctx context.Context, key string) (float64, error) {
+	bz, err := b.GetBytes(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	res, err := strconv.ParseFloat(string(bz), 64)
+	return res, eris.Wrap(err, "")
+}
+
+func (b *BadgerStorage) GetFloat32(ctx context.Context, key string) (float32, error) {
+	bz, err := b.GetBytes(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	res, err := strconv.ParseFloat(string(bz), 32)
+	return float32(res), eris.Wrap(err, "")
+}
+
+func (b *BadgerStorage) GetUInt64(ctx context.Context, key string) (uint64, error) {
+	bz, err := b.GetBytes(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	res, err := strconv.ParseUint(string(bz), 10, 64)
+	return res, eris.Wrap(err, "")
+}
+
+func (b *BadgerStorage) GetInt64(ctx context.Context, key string) (int64, error) {
+	bz, err := b.GetBytes(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	res, err := strconv.ParseInt(string(bz), 10, 64)
+	return res, eris.Wrap(err, "")
+}
+
+func (b *BadgerStorage) GetInt(ctx context.Context, key string) (int, error) {
+	bz, err := b.GetBytes(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	res, err := strconv.Atoi(string(bz))
+	return res, eris.Wrap(err, "")
+}
+
+func (b *BadgerStorage) GetBool(ctx context.Context, key string) (bool, error) {
+	bz, err := b.GetBytes(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	res, err := strconv.ParseBool(string(bz))
+	return res, eris.Wrap(err, "")
+}
+
+func (b *BadgerStorage) GetBytes(_ context.Context, key string) ([]byte, error) {
+	var bz []byte
+	err := b.view(func(txn *badger.Txn) error {
+		var err error
+		bz, err = b.get(txn, key)
+		return err
+	})
+	if eris.Is(err, badger.ErrKeyNotFound) {
+		return nil, eris.Wrap(redis.Nil, "")
+	} else if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	return bz, nil
+}
+
+// Get returns the value at the given key as a string, which mirrors the behavior of RedisStorage.Get.
+func (b *BadgerStorage) Get(ctx context.Context, key string) (any, error) {
+	bz, err := b.GetBytes(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return string(bz), nil
+}
+
+func (b *BadgerStorage) Set(_ context.Context, key string, value any) error {
+	bz, err := encodeBadgerValue(value)
+	if err != nil {
+		return err
+	}
+	if bz == nil {
+		bz = []byte{}
+	}
+	return eris.Wrap(b.update(func(txn *badger.Txn) error {
+		return b.set(txn, key, bz)
+	}), "")
+}
+
+func (b *BadgerStorage) Incr(_ context.Context, key string) error {
+	return b.addToInt(key, 1)
+}
+
+func (b *BadgerStorage) Decr(_ context.Context, key string) error {
+	return b.addToInt(key, -1)
+}
+
+func (b *BadgerStorage) Delete(_ context.Context, key string) error {
+	return eris.Wrap(b.update(func(txn *badger.Txn) error {
+		return b.set(txn, key, nil)
+	}), "")
+}
+
+func (b *BadgerStorage) Close(_ context.Context) error {
+	if b.txn != nil {
+		b.txn.Discard()
+		b.txn = nil
+		b.writes = nil
+		return nil
+	}
+	return eris.Wrap(b.db.Close(), "")
+}
+
+func (b *BadgerStorage) Keys(_ context.Context) ([]string, error) {
+	keys := make([]string, 0)
+	err := b.view(func(txn *badger.Txn) error {
+		return b.iterateKeys(txn, "", func(key string) error {
+			keys = append(keys, key)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	return keys, nil
+}
+
+func (b *BadgerStorage) Clear(_ context.Context) error {
+	if b.txn != nil {
+		return eris.New("cannot clear badger storage from inside a transaction")
+	}
+	return eris.Wrap(b.db.DropAll(), "")
+}
+
+func (b *BadgerStorage) StartTransaction(_ context.Context) (Transaction[string], error) {
+	if b.txn != nil {
+		return nil, eris.New("nested badger transactions are not supported")
+	}
+	b.commitLock.RLock()
+	defer b.commitLock.RUnlock()
+	return &BadgerStorage{
+		db:         b.db,
+		commitLock: b.commitLock,
+		txn:        b.db.NewTransaction(false),
+		writes:     make(map[string][]byte),
+		tracer:     b.tracer,
+	}, nil
+}
+
+func (b *BadgerStorage) EndTransaction(ctx context.Context) error {
+	_, span := b.tracer.Start(ddotel.ContextWithStartOptions(ctx, ddtracer.Measured()), "badger.transaction.end")
+	defer span.End()
+
+	if b.txn == nil {
+		err := eris.New("current badger dbStorage is not a transaction")
+		span.SetStatus(codes.Error, eris.ToString(err, true))
+		span.RecordError(err)
+		return err
+	}
+
+	// The transaction is discarded whether it is committed or not, so it can not be reused.
+	writes := b.writes
+	b.txn.Discard()
+	b.txn = nil
+	b.writes = nil
+	err := b.commit(writes)
+	if err != nil {
+		err = eris.Wrap(err, "failed to commit badger transaction")
+		span.SetStatus(codes.Error, eris.ToString(err, true))
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// view runs fn against the snapshot of the pending transaction if there is one, or a new read-only transaction
+// otherwise.
+func (b *BadgerStorage) view(fn func(txn *badger.Txn) error) error {
+	if b.txn != nil {
+		return fn(b.txn)
+	}
+	b.commitLock.RLock()
+	defer b.commitLock.RUnlock()
+	return b.db.View(fn)
+}
+
+// update runs fn against the snapshot of the pending transaction if there is one, or a new read-write transaction
+// otherwise. fn must write with set, so the writes of a pending transaction are buffered until it is committed.
+func (b *BadgerStorage) update(fn func(txn *badger.Txn) error) error {
+	if b.txn != nil {
+		return fn(b.txn)
+	}
+	b.commitLock.RLock()
+	defer b.commitLock.RUnlock()
+	return b.db.Update(fn)
+}
+
+// get returns a copy of the value of the key, looking at the writes of the pending transaction first if there is one.
+func (b *BadgerStorage) get(txn *badger.Txn, key string) ([]byte, error) {
+	if value, ok := b.writes[key]; ok {
+		if value == nil {
+			return nil, badger.ErrKeyNotFound
+		}
+		return append([]byte{}, value...), nil
+	}
+	item, err := txn.Get([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+	return item.ValueCopy(nil)
+}
+
+// set writes the value of the key, or deletes the key if value is nil. The write is buffered if there is a pending
+// transaction.
+func (b *BadgerStorage) set(txn *badger.Txn, key string, value []byte) error {
+	if b.writes != nil {
+		b.writes[key] = value
+		return nil
+	}
+	if value == nil {
+		return txn.Delete([]byte(key))
+	}
+	return txn.Set([]byte(key), value)
+}
+
+// iterateKeys calls fn with the keys that start with the given prefix, in order, including the keys written by the
+// pending transaction if there is one. The keys of the journal are skipped.
+func (b *BadgerStorage) iterateKeys(txn *badger.Txn, prefix string, fn func(key string) error) error {
+	var pending []string
+	for key, value := range b.writes {
+		if value != nil && strings.HasPrefix(key, prefix) {
+			pending = append(pending, key)
+		}
+	}
+	sort.Strings(pending)
+
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = false
+	opts.Prefix = []byte(prefix)
+	it := txn.NewIterator(opts)
+	defer it.Close()
+	for it.Rewind(); it.Valid(); it.Next() {
+		key := string(it.Item().KeyCopy(nil))
+		for len(pending) > 0 && pending[0] <= key {
+			if pending[0] != key {
+				if err := fn(pending[0]); err != nil {
+					return err
+				}
+			}
+			pending = pending[1:]
+		}
+		if value, ok := b.writes[key]; ok && value == nil {
+			continue
+		}
+		if strings.HasPrefix(key, journalKeyPrefix) {
+			continue
+		}
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+	for _, key := range pending {
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addToInt atomically adds delta to the integer stored at key. A missing key is treated as 0, which matches
+// the behavior of INCR and DECR in Redis.
+func (b *BadgerStorage) addToInt(key string, delta int64) error {
+	return eris.Wrap(b.update(func(txn *badger.Txn) error {
+		curr := int64(0)
+		bz, err := b.get(txn, key)
+		if err == nil {
+			if curr, err = strconv.ParseInt(string(bz), 10, 64); err != nil {
+				return eris.Wrapf(err, "value at key %q is not an integer", key)
+			}
+		} else if !eris.Is(err, badger.ErrKeyNotFound) {
+			return err
+		}
+		return b.set(txn, key, []byte(strconv.FormatInt(curr+delta, 10)))
+	}), "")
+}
+
+// encodeBadgerValue converts the given value to bytes using the same rules the go-redis client uses when writing
+// command arguments. This keeps values written to BadgerStorage readable by the typed getters.
+func encodeBadgerValue(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return []byte{}, nil
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	case int:
+		return []byte(strconv.FormatInt(int64(v), 10)), nil
+	case int8:
+		return []byte(strconv.FormatInt(int64(v), 10)), nil
+	case int16:
+		return []byte(strconv.FormatInt(int64(v), 10)), nil
+	case int32:
+		return []byte(strconv.FormatInt(int64(v), 10)), nil
+	case int64:
+		return []byte(strconv.FormatInt(v, 10)), nil
+	case uint:
+		return []byte(strconv.FormatUint(uint64(v), 10)), nil
+	case uint8:
+		return []byte(strconv.FormatUint(uint64(v), 10)), nil
+	case uint16:
+		return []byte(strconv.FormatUint(uint64(v), 10)), nil
+	case uint32:
+		return []byte(strconv.FormatUint(uint64(v), 10)), nil
+	case uint64:
+		return []byte(strconv.FormatUint(v, 10)), nil
+	case float32:
+		return []byte(strconv.FormatFloat(float64(v), 'f', -1, 32)), nil
+	case float64:
+		return []byte(strconv.FormatFloat(v, 'f', -1, 64)), nil
+	case bool:
+		if v {
+			return []byte("1"), nil
+		}
+		return []byte("0"), nil
+	case encoding.BinaryMarshaler:
+		bz, err := v.MarshalBinary()
+		return bz, eris.Wrap(err, "")
+	default:
+		return nil, eris.Errorf("can't marshal %T (implement encoding.BinaryMarshaler)", value)
+	}
+}
@@ -0,0 +1,206 @@
+package gamestate
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/rotisserie/eris"
+)
+
+const (
+	// journalKeyPrefix is the prefix of the keys that BadgerStorage uses to commit transactions that do not fit in a
+	// single Badger transaction. These keys are never listed by Keys or ScanKeys.
+	journalKeyPrefix = "BADGER:JOURNAL:"
+
+	journalEntryKeyFormat = "BADGER:JOURNAL:ENTRY:%020d"
+
+	journalEntryKeyPrefix = "BADGER:JOURNAL:ENTRY:"
+
+	// journalCommittedKey is written once all the entries of the journal are written. The transaction in the journal
+	// is committed from that point, even if the process stops before it is applied.
+	journalCommittedKey = "BADGER:JOURNAL:COMMITTED"
+)
+
+const (
+	journalSet    = 0
+	journalDelete = 1
+)
+
+// commit writes the given writes of a transaction in a single Badger transaction, or through the journal if they do not
+// fit in one.
+func (b *BadgerStorage) commit(writes map[string][]byte) error {
+	err := b.update(func(txn *badger.Txn) error {
+		for key, value := range writes {
+			if err := b.set(txn, key, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if !errors.Is(err, badger.ErrTxnTooBig) {
+		return err
+	}
+	return b.commitJournaled(writes)
+}
+
+// commitJournaled commits writes that do not fit in a single Badger transaction. The writes are first written to the
+// journal, in as many Badger transactions as needed, then journalCommittedKey is written, and then the writes are
+// applied and the journal is removed. If the process stops before journalCommittedKey is written, the journal is
+// discarded when the database is opened again; if it stops after, the rest of the journal is applied, so either all
+// the writes are applied or none. The commit lock is held for writing the whole time, so nobody sees the writes half
+// applied.
+func (b *BadgerStorage) commitJournaled(writes map[string][]byte) error {
+	b.commitLock.Lock()
+	defer b.commitLock.Unlock()
+
+	w := newChunkedWriter(b.db)
+	i := 0
+	for key, value := range writes {
+		if err := w.set(journalEntryKey(i), encodeJournalEntry(key, value)); err != nil {
+			w.discard()
+			return eris.Wrap(err, "failed to write the journal")
+		}
+		i++
+	}
+	if err := w.set([]byte(journalCommittedKey), []byte(strconv.Itoa(len(writes)))); err != nil {
+		w.discard()
+		return eris.Wrap(err, "failed to write the journal")
+	}
+	if err := w.commit(); err != nil {
+		return eris.Wrap(err, "failed to write the journal")
+	}
+	return b.applyJournal()
+}
+
+// applyJournal applies the writes in the journal if journalCommittedKey is set, and removes the journal. It must only
+// be called while nobody else uses the database.
+func (b *BadgerStorage) applyJournal() error {
+	committed := true
+	err := b.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get([]byte(journalCommittedKey))
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			committed = false
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return eris.Wrap(err, "failed to read the journal")
+	}
+
+	w := newChunkedWriter(b.db)
+	err = b.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(journalEntryKeyPrefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			if committed {
+				bz, err := it.Item().ValueCopy(nil)
+				if err != nil {
+					return err
+				}
+				key, value, err := decodeJournalEntry(bz)
+				if err != nil {
+					return err
+				}
+				if err := w.set(key, value); err != nil {
+					return err
+				}
+			}
+			// An entry is removed in the same Badger transaction as its write, or in a later one, so the entries that
+			// are left after a crash are the ones that may not be applied yet.
+			if err := w.set(it.Item().KeyCopy(nil), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		w.discard()
+		return eris.Wrap(err, "failed to apply the journal")
+	}
+	if committed {
+		if err := w.set([]byte(journalCommittedKey), nil); err != nil {
+			w.discard()
+			return eris.Wrap(err, "failed to apply the journal")
+		}
+	}
+	return eris.Wrap(w.commit(), "failed to apply the journal")
+}
+
+// chunkedWriter writes to Badger in as many transactions as needed, committing the current transaction whenever it
+// gets too big. The transactions are committed in order.
+type chunkedWriter struct {
+	db  *badger.DB
+	txn *badger.Txn
+}
+
+func newChunkedWriter(db *badger.DB) *chunkedWriter {
+	return &chunkedWriter{db: db, txn: db.NewTransaction(true)}
+}
+
+// set writes the value of the key, or deletes the key if value is nil.
+func (w *chunkedWriter) set(key, value []byte) error {
+	err := w.write(key, value)
+	if !errors.Is(err, badger.ErrTxnTooBig) {
+		return err
+	}
+	if err := w.commit(); err != nil {
+		return err
+	}
+	w.txn = w.db.NewTransaction(true)
+	return w.write(key, value)
+}
+
+func (w *chunkedWriter) write(key, value []byte) error {
+	if value == nil {
+		return w.txn.Delete(key)
+	}
+	return w.txn.Set(key, value)
+}
+
+func (w *chunkedWriter) commit() error {
+	return w.txn.Commit()
+}
+
+func (w *chunkedWriter) discard() {
+	w.txn.Discard()
+}
+
+func journalEntryKey(i int) []byte {
+	return []byte(fmt.Sprintf(journalEntryKeyFormat, i))
+}
+
+// encodeJournalEntry encodes the write of a value to a key, or the deletion of the key if value is nil, as the kind
+// of write, the length of the key as a uvarint, the key and the value.
+func encodeJournalEntry(key string, value []byte) []byte {
+	bz := make([]byte, 0, 1+binary.MaxVarintLen64+len(key)+len(value))
+	if value == nil {
+		bz = append(bz, journalDelete)
+	} else {
+		bz = append(bz, journalSet)
+	}
+	bz = binary.AppendUvarint(bz, uint64(len(key)))
+	bz = append(bz, key...)
+	return append(bz, value...)
+}
+
+func decodeJournalEntry(bz []byte) (key, value []byte, err error) {
+	if len(bz) == 0 || bz[0] > journalDelete {
+		return nil, nil, eris.New("invalid journal entry")
+	}
+	kind := bz[0]
+	keyLen, n := binary.Uvarint(bz[1:])
+	if n <= 0 || uint64(len(bz)-1-n) < keyLen {
+		return nil, nil, eris.New("invalid journal entry")
+	}
+	key = bz[1+n : 1+n+int(keyLen)]
+	if kind == journalDelete {
+		return key, nil, nil
+	}
+	return key, append([]byte{}, bz[1+n+int(keyLen):]...), nil
+}
@@ -0,0 +1,83 @@
+package gamestate
+
+// Tests in this file write the journal of BadgerStorage directly, to check what happens when the process stops in the
+// middle of a journaled commit.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/assert"
+)
+
+// writeJournal writes a journal that sets "new" and deletes "old", as if the process stopped while it was committed.
+func writeJournal(t *testing.T, dir string, committed bool) {
+	storage, err := NewBadgerPrimitiveStorage(dir)
+	assert.NilError(t, err)
+	ctx := context.Background()
+	assert.NilError(t, storage.Set(ctx, "old", "value"))
+	assert.NilError(t, storage.db.Update(func(txn *badger.Txn) error {
+		if err := txn.Set(journalEntryKey(0), encodeJournalEntry("new", []byte("value"))); err != nil {
+			return err
+		}
+		if err := txn.Set(journalEntryKey(1), encodeJournalEntry("old", nil)); err != nil {
+			return err
+		}
+		if !committed {
+			return nil
+		}
+		return txn.Set([]byte(journalCommittedKey), []byte("2"))
+	}))
+	assert.NilError(t, storage.Close(ctx))
+}
+
+func TestBadgerStorageAppliesCommittedJournalOnOpen(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	writeJournal(t, dir, true)
+
+	storage, err := NewBadgerPrimitiveStorage(dir)
+	assert.NilError(t, err)
+	defer func() {
+		assert.NilError(t, storage.Close(ctx))
+	}()
+	got, err := storage.Get(ctx, "new")
+	assert.NilError(t, err)
+	assert.Equal(t, "value", got)
+	_, err = storage.Get(ctx, "old")
+	assert.Check(t, eris.Is(eris.Cause(err), redis.Nil))
+	assert.NilError(t, storage.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			assert.Check(t, string(it.Item().Key()) == "new", "unexpected key %q", it.Item().Key())
+		}
+		return nil
+	}))
+}
+
+func TestBadgerStorageDiscardsUncommittedJournalOnOpen(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	writeJournal(t, dir, false)
+
+	storage, err := NewBadgerPrimitiveStorage(dir)
+	assert.NilError(t, err)
+	defer func() {
+		assert.NilError(t, storage.Close(ctx))
+	}()
+	_, err = storage.Get(ctx, "new")
+	assert.Check(t, eris.Is(eris.Cause(err), redis.Nil))
+	got, err := storage.Get(ctx, "old")
+	assert.NilError(t, err)
+	assert.Equal(t, "value", got)
+	assert.NilError(t, storage.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(journalEntryKey(0))
+		assert.Check(t, eris.Is(err, badger.ErrKeyNotFound))
+		return nil
+	}))
+}
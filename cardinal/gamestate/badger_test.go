@@ -0,0 +1,239 @@
+package gamestate_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	cardinalstorage "pkg.world.dev/world-engine/cardinal/storage"
+	cardinalredis "pkg.world.dev/world-engine/cardinal/storage/redis"
+)
+
+func newBadgerStorageForTest(t *testing.T, dir string) *gamestate.BadgerStorage {
+	storage, err := gamestate.NewBadgerPrimitiveStorage(dir)
+	assert.NilError(t, err)
+	return storage
+}
+
+func TestBadgerStorageTypedValues(t *testing.T) {
+	storage := newBadgerStorageForTest(t, "")
+	defer func() {
+		assert.NilError(t, storage.Close(context.Background()))
+	}()
+	ctx := context.Background()
+
+	assert.NilError(t, storage.Set(ctx, "int", 99))
+	assert.NilError(t, storage.Set(ctx, "uint64", uint64(1<<40)))
+	assert.NilError(t, storage.Set(ctx, "float", 1.5))
+	assert.NilError(t, storage.Set(ctx, "bool", true))
+	assert.NilError(t, storage.Set(ctx, "bytes", []byte("hello")))
+
+	gotInt, err := storage.GetInt(ctx, "int")
+	assert.NilError(t, err)
+	assert.Equal(t, 99, gotInt)
+
+	gotUInt64, err := storage.GetUInt64(ctx, "uint64")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1<<40), gotUInt64)
+
+	gotFloat, err := storage.GetFloat64(ctx, "float")
+	assert.NilError(t, err)
+	assert.Equal(t, 1.5, gotFloat)
+
+	gotBool, err := storage.GetBool(ctx, "bool")
+	assert.NilError(t, err)
+	assert.Equal(t, true, gotBool)
+
+	gotBytes, err := storage.GetBytes(ctx, "bytes")
+	assert.NilError(t, err)
+	assert.Equal(t, "hello", string(gotBytes))
+
+	// Incr and Decr treat a missing key as 0
+	assert.NilError(t, storage.Incr(ctx, "counter"))
+	assert.NilError(t, storage.Incr(ctx, "counter"))
+	assert.NilError(t, storage.Decr(ctx, "counter"))
+	gotCounter, err := storage.GetUInt64(ctx, "counter")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), gotCounter)
+
+	assert.NilError(t, storage.Delete(ctx, "int"))
+	_, err = storage.GetInt(ctx, "int")
+	assert.Check(t, eris.Is(eris.Cause(err), redis.Nil))
+
+	keys, err := storage.Keys(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, 5, len(keys))
+}
+
+func TestBadgerStorageTransactionIsAtomic(t *testing.T) {
+	storage := newBadgerStorageForTest(t, "")
+	defer func() {
+		assert.NilError(t, storage.Close(context.Background()))
+	}()
+	ctx := context.Background()
+
+	txn, err := storage.StartTransaction(ctx)
+	assert.NilError(t, err)
+	assert.NilError(t, txn.Set(ctx, "foo", "bar"))
+	assert.NilError(t, txn.Incr(ctx, "tick"))
+
+	// Writes inside the transaction are not visible until the transaction is committed
+	_, err = storage.GetBytes(ctx, "foo")
+	assert.Check(t, eris.Is(eris.Cause(err), redis.Nil))
+
+	assert.NilError(t, txn.EndTransaction(ctx))
+	got, err := storage.Get(ctx, "foo")
+	assert.NilError(t, err)
+	assert.Equal(t, "bar", got)
+	tick, err := storage.GetUInt64(ctx, "tick")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), tick)
+
+	// A finished transaction cannot be committed again
+	assert.IsError(t, txn.EndTransaction(ctx))
+}
+
+func TestBadgerStorageCommitsTransactionsLargerThanABadgerTransaction(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	storage := newBadgerStorageForTest(t, dir)
+	assert.NilError(t, storage.Set(ctx, "old", "value"))
+
+	// Badger limits a transaction to 15% of its 64MB memtable by default, so these writes need several of them.
+	const count = 40
+	value := bytes.Repeat([]byte{'x'}, 512*1024)
+	txn, err := storage.StartTransaction(ctx)
+	assert.NilError(t, err)
+	for i := 0; i < count; i++ {
+		assert.NilError(t, txn.Set(ctx, fmt.Sprintf("key-%d", i), value))
+	}
+	assert.NilError(t, txn.Delete(ctx, "old"))
+	assert.NilError(t, txn.Incr(ctx, "tick"))
+	got, err := txn.GetBytes(ctx, "key-0")
+	assert.NilError(t, err)
+	assert.Equal(t, len(value), len(got))
+	_, err = storage.GetBytes(ctx, "key-0")
+	assert.Check(t, eris.Is(eris.Cause(err), redis.Nil))
+	assert.NilError(t, txn.EndTransaction(ctx))
+
+	check := func(storage *gamestate.BadgerStorage) {
+		for i := 0; i < count; i++ {
+			got, err := storage.GetBytes(ctx, fmt.Sprintf("key-%d", i))
+			assert.NilError(t, err)
+			assert.Check(t, bytes.Equal(value, got))
+		}
+		_, err = storage.GetBytes(ctx, "old")
+		assert.Check(t, eris.Is(eris.Cause(err), redis.Nil))
+		tick, err := storage.GetUInt64(ctx, "tick")
+		assert.NilError(t, err)
+		assert.Equal(t, uint64(1), tick)
+		// The journal used to commit the transaction is not visible.
+		keys, err := storage.Keys(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, count+1, len(keys))
+	}
+	check(storage)
+	assert.NilError(t, storage.Close(ctx))
+
+	storage = newBadgerStorageForTest(t, dir)
+	defer func() {
+		assert.NilError(t, storage.Close(ctx))
+	}()
+	check(storage)
+}
+
+func TestBadgerStorageStatePersistsAcrossRestarts(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	storage := newBadgerStorageForTest(t, dir)
+	manager, err := gamestate.NewEntityCommandBuffer(storage)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.RegisterComponents(allComponents))
+
+	ids, err := manager.CreateManyEntities(10, fooComp)
+	assert.NilError(t, err)
+	for i, id := range ids {
+		assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{i}))
+	}
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	assert.NilError(t, storage.Close(ctx))
+
+	// Reopen the same data directory and make sure the previously finalized state is still there
+	storage = newBadgerStorageForTest(t, dir)
+	defer func() {
+		assert.NilError(t, storage.Close(ctx))
+	}()
+	manager, err = gamestate.NewEntityCommandBuffer(storage)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.RegisterComponents(allComponents))
+
+	tick, err := manager.GetLastFinalizedTick()
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), tick)
+
+	for i, id := range ids {
+		got, err := manager.GetComponentForEntity(fooComp, id)
+		assert.NilError(t, err)
+		assert.Equal(t, Foo{i}, got)
+	}
+
+	// New entities must not reuse the IDs that were assigned before the restart
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.Equal(t, ids[len(ids)-1]+1, id)
+}
+
+func TestBadgerSchemaStorage(t *testing.T) {
+	storage := newBadgerStorageForTest(t, "")
+	defer func() {
+		assert.NilError(t, storage.Close(context.Background()))
+	}()
+	schemaStorage := gamestate.NewSchemaStorage(storage)
+
+	_, err := schemaStorage.GetSchema("foo")
+	assert.Check(t, eris.Is(err, cardinalredis.ErrNoSchemaFound))
+
+	assert.NilError(t, schemaStorage.SetSchema("foo", []byte(`{"type":"object"}`)))
+	got, err := schemaStorage.GetSchema("foo")
+	assert.NilError(t, err)
+	assert.Equal(t, `{"type":"object"}`, string(got))
+}
+
+func TestBadgerNonceStorage(t *testing.T) {
+	storage := newBadgerStorageForTest(t, "")
+	defer func() {
+		assert.NilError(t, storage.Close(context.Background()))
+	}()
+	nonceStorage := gamestate.NewNonceStorage(storage)
+
+	assert.NilError(t, nonceStorage.UseNonce("signer", 10))
+	assert.ErrorIs(t, nonceStorage.UseNonce("signer", 10), cardinalredis.ErrNonceHasAlreadyBeenUsed)
+	assert.NilError(t, nonceStorage.UseNonce("signer", 5))
+	// Nonces that fell out of the window are rejected.
+	assert.NilError(t, nonceStorage.UseNonce("signer", 10+cardinalredis.NonceSlidingWindowSize))
+	assert.ErrorContains(t, nonceStorage.UseNonce("signer", 5), "nonce is too old")
+	assert.NilError(t, nonceStorage.UseNonce("other-signer", 5))
+
+	next, err := nonceStorage.GetNextNonce("signer")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), next)
+	assert.NilError(t, nonceStorage.UseIncreasingNonce("signer", 3))
+	assert.ErrorIs(t, nonceStorage.UseIncreasingNonce("signer", 3), cardinalstorage.ErrNonceTooLow)
+	assert.NilError(t, nonceStorage.UseIncreasingNonce("signer", 7))
+	next, err = nonceStorage.GetNextNonce("signer")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(8), next)
+	assert.NilError(t, nonceStorage.ReleaseIncreasingNonces("signer", 3, 3))
+	assert.NilError(t, nonceStorage.ReleaseIncreasingNonces("signer", 7, 7))
+	next, err = nonceStorage.GetNextNonce("signer")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(7), next)
+}
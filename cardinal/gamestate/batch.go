@@ -0,0 +1,200 @@
+package gamestate
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+)
+
+// keyBatchSize is the number of keys that are listed or read from storage in one round trip.
+const keyBatchSize = 1000
+
+// BatchReader is implemented by PrimitiveStorages that can list and read many keys in a few round trips. The
+// EntityCommandBuffer falls back to listing all the keys and reading keys one by one for storages that do not implement
+// it.
+type BatchReader interface {
+	// GetManyBytes returns the values of the given keys, in the same order. The value of a key that does not exist is
+	// nil.
+	GetManyBytes(ctx context.Context, keys []string) ([][]byte, error)
+	// ScanKeys calls fn with batches of the keys that start with the given prefix, until all the keys are listed or fn
+	// returns an error. A key may be listed more than once, and keys that are added or removed while the keys are
+	// listed may or may not be listed.
+	ScanKeys(ctx context.Context, prefix string, fn func(keys []string) error) error
+}
+
+var (
+	_ BatchReader = &RedisStorage{}
+	_ BatchReader = &BadgerStorage{}
+)
+
+// GetManyBytesFrom returns the values of the given keys, using a BatchReader if the storage implements it. The value
+// of a key that does not exist is nil.
+func GetManyBytesFrom(ctx context.Context, storage PrimitiveStorage[string], keys []string) ([][]byte, error) {
+	if reader, ok := storage.(BatchReader); ok {
+		return reader.GetManyBytes(ctx, keys)
+	}
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		bz, err := storage.GetBytes(ctx, key)
+		if eris.Is(eris.Cause(err), redis.Nil) {
+			continue
+		} else if err != nil {
+			return nil, eris.Wrapf(err, "failed to read key %q", key)
+		}
+		if bz == nil {
+			bz = []byte{}
+		}
+		values[i] = bz
+	}
+	return values, nil
+}
+
+// scanKeys calls fn with batches of the keys that start with the given prefix, using a BatchReader if the storage
+// implements it.
+func scanKeys(
+	ctx context.Context, storage PrimitiveStorage[string], prefix string, fn func(keys []string) error,
+) error {
+	if reader, ok := storage.(BatchReader); ok {
+		return reader.ScanKeys(ctx, prefix, fn)
+	}
+	keys, err := storage.Keys(ctx)
+	if err != nil {
+		return eris.Wrap(err, "failed to list storage keys")
+	}
+	batch := make([]string, 0, keyBatchSize)
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		batch = append(batch, key)
+		if len(batch) == keyBatchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([]string, 0, keyBatchSize)
+		}
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+func (r *RedisStorage) GetManyBytes(ctx context.Context, keys []string) ([][]byte, error) {
+	values := make([][]byte, 0, len(keys))
+	for start := 0; start < len(keys); start += keyBatchSize {
+		res, err := r.currentClient.MGet(ctx, keys[start:min(start+keyBatchSize, len(keys))]...).Result()
+		if err != nil {
+			return nil, eris.Wrap(err, "")
+		}
+		for _, value := range res {
+			switch v := value.(type) {
+			case nil:
+				values = append(values, nil)
+			case string:
+				values = append(values, []byte(v))
+			default:
+				return nil, eris.Errorf("unexpected value of type %T", value)
+			}
+		}
+	}
+	return values, nil
+}
+
+func (r *RedisStorage) ScanKeys(ctx context.Context, prefix string, fn func(keys []string) error) error {
+	match := escapeGlob(prefix) + "*"
+	var cursor uint64
+	for {
+		keys, next, err := r.currentClient.Scan(ctx, cursor, match, keyBatchSize).Result()
+		if err != nil {
+			return eris.Wrap(err, "")
+		}
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// escapeGlob escapes the characters that have a special meaning in the patterns of the Redis SCAN command.
+func escapeGlob(s string) string {
+	var sb strings.Builder
+	for _, c := range s {
+		if strings.ContainsRune(`*?[]\^`, c) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+func (b *BadgerStorage) GetManyBytes(_ context.Context, keys []string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	err := b.view(func(txn *badger.Txn) error {
+		for i, key := range keys {
+			bz, err := b.get(txn, key)
+			if eris.Is(err, badger.ErrKeyNotFound) {
+				continue
+			} else if err != nil {
+				return err
+			}
+			if bz == nil {
+				bz = []byte{}
+			}
+			values[i] = bz
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	return values, nil
+}
+
+func (b *BadgerStorage) ScanKeys(_ context.Context, prefix string, fn func(keys []string) error) error {
+	return b.view(func(txn *badger.Txn) error {
+		batch := make([]string, 0, keyBatchSize)
+		err := b.iterateKeys(txn, prefix, func(key string) error {
+			batch = append(batch, key)
+			if len(batch) < keyBatchSize {
+				return nil
+			}
+			err := fn(batch)
+			batch = make([]string, 0, keyBatchSize)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if len(batch) > 0 {
+			return fn(batch)
+		}
+		return nil
+	})
+}
+
+// previousValues returns the values that the keys written in the given transaction have in storage before the
+// transaction is committed.
+func (m *EntityCommandBuffer) previousValues(ctx context.Context, writes *writeRecorder) (tickDiff, error) {
+	keys := make([]string, 0, len(writes.writes))
+	for key := range writes.writes {
+		keys = append(keys, key)
+	}
+	values, err := GetManyBytesFrom(ctx, m.dbStorage, keys)
+	if err != nil {
+		return nil, err
+	}
+	previous := make(tickDiff, len(keys))
+	for i, key := range keys {
+		previous[key] = diffEntry{Exists: values[i] != nil, Value: values[i]}
+	}
+	return previous, nil
+}
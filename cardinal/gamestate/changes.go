@@ -0,0 +1,222 @@
+package gamestate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"sort"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+var _ ChangeTracker = &EntityCommandBuffer{}
+
+// ChangeTracker is implemented by game state managers that are able to report the state changes that will be
+// committed by the next call to FinalizeTick.
+type ChangeTracker interface {
+	PendingChanges(ctx context.Context) (*StateChanges, error)
+}
+
+// StateChanges describes the entities that were created or removed, and the component values that were changed in a
+// single tick.
+type StateChanges struct {
+	Tick             uint64            `json:"tick"`
+	CreatedEntities  []EntityChange    `json:"createdEntities"`
+	RemovedEntities  []EntityChange    `json:"removedEntities"`
+	ComponentChanges []ComponentChange `json:"componentChanges"`
+}
+
+// EntityChange is an entity that was created or removed, along with the names of the components the entity had.
+type EntityChange struct {
+	ID         types.EntityID `json:"id"`
+	Components []string       `json:"components"`
+}
+
+// ComponentChange is a component value that was set or removed.
+type ComponentChange struct {
+	EntityID  types.EntityID  `json:"entityId"`
+	Component string          `json:"component"`
+	Value     json.RawMessage `json:"value,omitempty"`
+	Removed   bool            `json:"removed,omitempty"`
+}
+
+// IsEmpty returns true if nothing changed.
+func (s *StateChanges) IsEmpty() bool {
+	return len(s.CreatedEntities) == 0 && len(s.RemovedEntities) == 0 && len(s.ComponentChanges) == 0
+}
+
+// FilterByComponents returns the subset of changes that involve at least one of the given component names. Created and
+// removed entities are kept if they have any of the given components. If no component names are given, all changes
+// are returned.
+func (s *StateChanges) FilterByComponents(names []string) *StateChanges {
+	if len(names) == 0 {
+		return s
+	}
+	hasAny := func(comps []string) bool {
+		for _, comp := range comps {
+			if slices.Contains(names, comp) {
+				return true
+			}
+		}
+		return false
+	}
+	filtered := &StateChanges{
+		Tick:             s.Tick,
+		CreatedEntities:  []EntityChange{},
+		RemovedEntities:  []EntityChange{},
+		ComponentChanges: []ComponentChange{},
+	}
+	for _, e := range s.CreatedEntities {
+		if hasAny(e.Components) {
+			filtered.CreatedEntities = append(filtered.CreatedEntities, e)
+		}
+	}
+	for _, e := range s.RemovedEntities {
+		if hasAny(e.Components) {
+			filtered.RemovedEntities = append(filtered.RemovedEntities, e)
+		}
+	}
+	for _, c := range s.ComponentChanges {
+		if slices.Contains(names, c.Component) {
+			filtered.ComponentChanges = append(filtered.ComponentChanges, c)
+		}
+	}
+	return filtered
+}
+
+// PendingChanges returns the changes that will be committed by the next call to FinalizeTick. Component values that
+// were only read during the tick, or that were set to the value they already had, are not reported as changed.
+func (m *EntityCommandBuffer) PendingChanges(ctx context.Context) (*StateChanges, error) {
+	changes := &StateChanges{
+		Tick:             0,
+		CreatedEntities:  []EntityChange{},
+		RemovedEntities:  []EntityChange{},
+		ComponentChanges: []ComponentChange{},
+	}
+
+	// removedEntities tracks the removed entities so their individual component removals are not reported again.
+	removedEntities := map[types.EntityID]bool{}
+	ids, err := m.entityIDToOriginArchID.Keys()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		originArchID, err := m.entityIDToOriginArchID.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		archID, err := m.entityIDToArchID.Get(id)
+		isRemoved := err != nil
+		switch {
+		case originArchID == doesNotExistArchetypeID && !isRemoved:
+			entity, err := m.entityChange(id, archID)
+			if err != nil {
+				return nil, err
+			}
+			changes.CreatedEntities = append(changes.CreatedEntities, entity)
+		case originArchID != doesNotExistArchetypeID && isRemoved:
+			entity, err := m.entityChange(id, originArchID)
+			if err != nil {
+				return nil, err
+			}
+			changes.RemovedEntities = append(changes.RemovedEntities, entity)
+			removedEntities[id] = true
+		}
+	}
+
+	keysToDelete, err := m.compValuesToDelete.Keys()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keysToDelete {
+		if removedEntities[key.entityID] {
+			continue
+		}
+		cType, err := m.typeToComponent.Get(key.typeID)
+		if err != nil {
+			return nil, err
+		}
+		changes.ComponentChanges = append(changes.ComponentChanges, ComponentChange{
+			EntityID:  key.entityID,
+			Component: cType.Name(),
+			Value:     nil,
+			Removed:   true,
+		})
+	}
+
+	keys, err := m.compValues.Keys()
+	if err != nil {
+		return nil, err
+	}
+	// The saved values are read in a batch, so only the values that really changed are reported.
+	storageKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		storageKeys = append(storageKeys, storageComponentKey(key.typeID, key.entityID))
+	}
+	saved, err := GetManyBytesFrom(ctx, m.dbStorage, storageKeys)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to read saved component values")
+	}
+	for i, key := range keys {
+		cType, err := m.typeToComponent.Get(key.typeID)
+		if err != nil {
+			return nil, err
+		}
+		value, err := m.compValues.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		bz, err := cType.Encode(value)
+		if err != nil {
+			return nil, err
+		}
+		if saved[i] != nil && bytes.Equal(saved[i], bz) {
+			continue
+		}
+		if !codec.IsJSON(cType.Codec()) {
+			if bz, err = codec.Encode(value); err != nil {
+				return nil, err
+			}
+		}
+		changes.ComponentChanges = append(changes.ComponentChanges, ComponentChange{
+			EntityID:  key.entityID,
+			Component: cType.Name(),
+			Value:     bz,
+			Removed:   false,
+		})
+	}
+
+	// Keep the output stable so subscribers see the changes in the same order they would be found in the state.
+	sortEntityChanges(changes.CreatedEntities)
+	sortEntityChanges(changes.RemovedEntities)
+	sort.Slice(changes.ComponentChanges, func(i, j int) bool {
+		a, b := changes.ComponentChanges[i], changes.ComponentChanges[j]
+		if a.EntityID != b.EntityID {
+			return a.EntityID < b.EntityID
+		}
+		return a.Component < b.Component
+	})
+	return changes, nil
+}
+
+func (m *EntityCommandBuffer) entityChange(id types.EntityID, archID types.ArchetypeID) (EntityChange, error) {
+	comps, err := m.GetComponentTypesForArchID(archID)
+	if err != nil {
+		return EntityChange{}, err
+	}
+	names := make([]string, 0, len(comps))
+	for _, comp := range comps {
+		names = append(names, comp.Name())
+	}
+	return EntityChange{ID: id, Components: names}, nil
+}
+
+func sortEntityChanges(entities []EntityChange) {
+	sort.Slice(entities, func(i, j int) bool {
+		return entities[i].ID < entities[j].ID
+	})
+}
@@ -0,0 +1,50 @@
+package gamestate_test
+
+import (
+	"context"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+)
+
+func TestPendingChangesOnlyIncludeModifiedState(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+
+	ids, err := manager.CreateManyEntities(3, fooComp, barComp)
+	assert.NilError(t, err)
+	for i, id := range ids {
+		assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{i}))
+	}
+
+	changes, err := manager.PendingChanges(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, 3, len(changes.CreatedEntities))
+	assert.DeepEqual(t, []string{"foo", "bar"}, changes.CreatedEntities[0].Components)
+	assert.Equal(t, 3, len(changes.ComponentChanges))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// Reading a value, or setting it to the value it already has is not a change
+	_, err = manager.GetComponentForEntity(fooComp, ids[0])
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, ids[1], Foo{1}))
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, ids[2], Foo{100}))
+	assert.NilError(t, manager.RemoveComponentFromEntity(barComp, ids[1]))
+	assert.NilError(t, manager.RemoveEntity(ids[0]))
+
+	changes, err = manager.PendingChanges(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(changes.CreatedEntities))
+	assert.Equal(t, 1, len(changes.RemovedEntities))
+	assert.Equal(t, ids[0], changes.RemovedEntities[0].ID)
+	assert.Equal(t, 2, len(changes.ComponentChanges))
+	assert.Equal(t, ids[1], changes.ComponentChanges[0].EntityID)
+	assert.Equal(t, "bar", changes.ComponentChanges[0].Component)
+	assert.Check(t, changes.ComponentChanges[0].Removed)
+	assert.Equal(t, ids[2], changes.ComponentChanges[1].EntityID)
+	assert.Equal(t, `{"Value":100}`, string(changes.ComponentChanges[1].Value))
+
+	filtered := changes.FilterByComponents([]string{"bar"})
+	assert.Equal(t, 1, len(filtered.RemovedEntities))
+	assert.Equal(t, 1, len(filtered.ComponentChanges))
+}
@@ -1,10 +1,12 @@
 package gamestate
 
 import (
+	"encoding/json"
 	"sort"
 
 	"github.com/rotisserie/eris"
 
+	"pkg.world.dev/world-engine/cardinal/codec"
 	"pkg.world.dev/world-engine/cardinal/types"
 )
 
@@ -43,3 +45,11 @@ func isComponentSetMatch(a, b []types.ComponentMetadata) bool {
 	}
 	return true
 }
+
+// encodeJSON encodes a component value as JSON, whatever the codec the component is stored with.
+func encodeJSON(cType types.ComponentMetadata, value any) (json.RawMessage, error) {
+	if codec.IsJSON(cType.Codec()) {
+		return cType.Encode(value)
+	}
+	return codec.Encode(value)
+}
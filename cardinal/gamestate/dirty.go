@@ -0,0 +1,108 @@
+package gamestate
+
+import (
+	"slices"
+	"sync"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+var _ ComponentChangeTracker = &EntityCommandBuffer{}
+
+// ComponentChangeTracker is implemented by managers that track which components were added, set or removed in the
+// current tick. Unlike PendingChanges, this does not compare values with the saved state, so setting a component to
+// the value it already had counts as a change.
+type ComponentChangeTracker interface {
+	// AddedComponents returns the sorted IDs of the entities that had the component added in the current tick.
+	AddedComponents(componentName string) []types.EntityID
+	// ChangedComponents returns the sorted IDs of the entities whose component was set or added in the current tick.
+	ChangedComponents(componentName string) []types.EntityID
+	// RemovedComponents returns the sorted IDs of the entities that had the component removed in the current tick,
+	// including entities that were removed.
+	RemovedComponents(componentName string) []types.EntityID
+}
+
+type entitySet map[types.EntityID]struct{}
+
+// dirtyComponents holds the entities whose components were added, set or removed in the current tick, keyed by
+// component name.
+type dirtyComponents struct {
+	mu      sync.Mutex
+	added   map[string]entitySet
+	changed map[string]entitySet
+	removed map[string]entitySet
+}
+
+func newDirtyComponents() *dirtyComponents {
+	return &dirtyComponents{
+		mu:      sync.Mutex{},
+		added:   map[string]entitySet{},
+		changed: map[string]entitySet{},
+		removed: map[string]entitySet{},
+	}
+}
+
+func (d *dirtyComponents) add(componentName string, ids ...types.EntityID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, id := range ids {
+		insert(d.added, componentName, id)
+		insert(d.changed, componentName, id)
+	}
+}
+
+func (d *dirtyComponents) set(componentName string, id types.EntityID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	insert(d.changed, componentName, id)
+}
+
+func (d *dirtyComponents) remove(componentName string, id types.EntityID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.added[componentName], id)
+	delete(d.changed[componentName], id)
+	insert(d.removed, componentName, id)
+}
+
+func (d *dirtyComponents) clear() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.added = map[string]entitySet{}
+	d.changed = map[string]entitySet{}
+	d.removed = map[string]entitySet{}
+}
+
+// list returns the sorted IDs of the entities in the set selected from d.
+func (d *dirtyComponents) list(
+	selectSets func(d *dirtyComponents) map[string]entitySet, componentName string,
+) []types.EntityID {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	sets := selectSets(d)
+	ids := make([]types.EntityID, 0, len(sets[componentName]))
+	for id := range sets[componentName] {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func insert(sets map[string]entitySet, componentName string, id types.EntityID) {
+	if sets[componentName] == nil {
+		sets[componentName] = entitySet{}
+	}
+	sets[componentName][id] = struct{}{}
+}
+
+func (m *EntityCommandBuffer) AddedComponents(componentName string) []types.EntityID {
+	return m.dirty.list(func(d *dirtyComponents) map[string]entitySet { return d.added }, componentName)
+}
+
+func (m *EntityCommandBuffer) ChangedComponents(componentName string) []types.EntityID {
+	return m.dirty.list(func(d *dirtyComponents) map[string]entitySet { return d.changed }, componentName)
+}
+
+func (m *EntityCommandBuffer) RemovedComponents(componentName string) []types.EntityID {
+	return m.dirty.list(func(d *dirtyComponents) map[string]entitySet { return d.removed }, componentName)
+}
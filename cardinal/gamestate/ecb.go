@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"sync"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/rotisserie/eris"
@@ -15,6 +16,7 @@ import (
 	"pkg.world.dev/world-engine/cardinal/codec"
 	"pkg.world.dev/world-engine/cardinal/filter"
 	ecslog "pkg.world.dev/world-engine/cardinal/log"
+	"pkg.world.dev/world-engine/cardinal/merkle"
 	"pkg.world.dev/world-engine/cardinal/types"
 )
 
@@ -46,6 +48,59 @@ type EntityCommandBuffer struct {
 	archIDToComps  VolatileStorage[types.ArchetypeID, []types.ComponentMetadata]
 	pendingArchIDs []types.ArchetypeID
 
+	// Secondary indexes on component fields.
+	indexes *indexSet
+
+	// Parent/child relationships between entities, loaded from storage or changed in the current tick.
+	entityParents  VolatileStorage[types.EntityID, parentEntry]
+	entityChildren VolatileStorage[types.EntityID, childrenEntry]
+
+	// The components that were added, set or removed in the current tick.
+	dirty *dirtyComponents
+
+	// World resources, loaded from storage or changed in the current tick.
+	resources VolatileStorage[string, resourceEntry]
+
+	// The number of ticks worth of state diffs to keep around for historical reads. 0 means no diffs are recorded.
+	historySize uint64
+	// The number of ticks worth of state hashes to keep around. 0 means no hashes are recorded.
+	stateHashSize uint64
+	// The state hash at the end of stateHashTick. It is nil until it is loaded from storage.
+	stateHash     *stateHash
+	stateHashTick uint64
+	// The state hash of the tick that is being finalized, which becomes stateHash once the tick is committed.
+	pendingStateHash     *stateHash
+	pendingStateHashTick uint64
+	// The number of ticks worth of state roots to keep around. 0 means no state roots are computed.
+	stateRootSize uint64
+	// The Merkle tree over the saved game state. It is nil until it is loaded from storage.
+	stateTree *merkle.Tree
+	// The number of finalized ticks the state tree covers, i.e. the tree is the state at the end of tick
+	// stateTreeTicks-1.
+	stateTreeTicks uint64
+	// The state tree with the changes of the tick that is being finalized, which becomes stateTree once the tick is
+	// committed.
+	pendingStateTree *merkle.Tree
+	// stateTreeMutex guards the state tree. It is held while a tick is finalized, so the state tree always matches the
+	// game state in storage.
+	stateTreeMutex *sync.Mutex
+	// stateTrees holds the state trees of the most recent finalized ticks, which are used to serve proofs without
+	// waiting for the tick that is being finalized. It is guarded by stateTreesMutex, which is only held briefly.
+	stateTrees      map[uint64]*merkle.Tree
+	stateTreesMutex *sync.RWMutex
+
+	// oldestTickKeys holds, for each prefix of the keys stored for every tick (tick diffs, state hashes and state
+	// roots), the oldest tick that may still have a key in storage. A prefix is loaded when it is first pruned.
+	oldestTickKeys map[string]uint64
+	// pendingOldestTickKeys holds the oldest ticks that are left once the tick that is being finalized is committed.
+	pendingOldestTickKeys map[string]uint64
+
+	// snapshotMutex is held while a tick is finalized, and guards activeSnapshot.
+	snapshotMutex *sync.Mutex
+	// activeSnapshot is only set while a snapshot is being taken (see StartSnapshot). It holds the values that the keys
+	// changed by the ticks finalized since the snapshot started had when it started.
+	activeSnapshot tickDiff
+
 	// OpenTelemetry tracer
 	tracer trace.Tracer
 }
@@ -67,6 +122,23 @@ func NewEntityCommandBuffer(storage PrimitiveStorage[string]) (*EntityCommandBuf
 		// This field cannot be set until RegisterComponents is called
 		typeToComponent: nil,
 
+		indexes: newIndexSet(),
+
+		entityParents:  NewMapStorage[types.EntityID, parentEntry](),
+		entityChildren: NewMapStorage[types.EntityID, childrenEntry](),
+
+		dirty: newDirtyComponents(),
+
+		resources: NewMapStorage[string, resourceEntry](),
+
+		stateTreeMutex:        &sync.Mutex{},
+		stateTrees:            map[uint64]*merkle.Tree{},
+		stateTreesMutex:       &sync.RWMutex{},
+		oldestTickKeys:        map[string]uint64{},
+		pendingOldestTickKeys: map[string]uint64{},
+		snapshotMutex:         &sync.Mutex{},
+		activeSnapshot:        nil,
+
 		tracer: otel.Tracer("ecb"),
 	}
 
@@ -82,7 +154,10 @@ func (m *EntityCommandBuffer) RegisterComponents(comps []types.ComponentMetadata
 		}
 	}
 
-	return m.loadArchIDs()
+	if err := m.loadArchIDs(); err != nil {
+		return err
+	}
+	return m.buildIndexes()
 }
 
 // DiscardPending discards any pending state changes.
@@ -122,10 +197,19 @@ func (m *EntityCommandBuffer) DiscardPending() error {
 		}
 	}
 	m.pendingArchIDs = m.pendingArchIDs[:0]
-	return nil
+	m.indexes.discardPending()
+	m.dirty.clear()
+
+	if err = m.entityParents.Clear(); err != nil {
+		return err
+	}
+	if err = m.entityChildren.Clear(); err != nil {
+		return err
+	}
+	return m.resources.Clear()
 }
 
-// RemoveEntity removes the given entity from the ECS data model.
+// RemoveEntity removes the given entity from the ECS data model. The children of the entity are removed as well.
 func (m *EntityCommandBuffer) RemoveEntity(idToRemove types.EntityID) error {
 	archID, err := m.getArchetypeForEntity(idToRemove)
 	if err != nil {
@@ -169,9 +253,11 @@ func (m *EntityCommandBuffer) RemoveEntity(idToRemove types.EntityID) error {
 		if err != nil {
 			return err
 		}
+		m.indexes.remove(comp.ID(), idToRemove)
+		m.dirty.remove(comp.Name(), idToRemove)
 	}
 
-	return nil
+	return m.removeEntityRelationships(idToRemove)
 }
 
 // CreateEntity creates a single entity with the given set of components.
@@ -217,6 +303,12 @@ func (m *EntityCommandBuffer) CreateManyEntities(num int, comps ...types.Compone
 	if err != nil {
 		return nil, err
 	}
+	if err = m.indexDefaultValues(comps, ids...); err != nil {
+		return nil, err
+	}
+	for _, comp := range comps {
+		m.dirty.add(comp.Name(), ids...)
+	}
 	return ids, nil
 }
 
@@ -234,7 +326,11 @@ func (m *EntityCommandBuffer) SetComponentForEntity(
 	}
 
 	key := compKey{cType.ID(), id}
-	return m.compValues.Set(key, value)
+	if err = m.compValues.Set(key, value); err != nil {
+		return err
+	}
+	m.dirty.set(cType.Name(), id)
+	return m.indexes.set(cType.ID(), id, value)
 }
 
 // GetComponentForEntity returns the saved component data for the given entity.
@@ -285,7 +381,7 @@ func (m *EntityCommandBuffer) GetComponentForEntityInRawJSON(cType types.Compone
 	if err != nil {
 		return nil, err
 	}
-	return cType.Encode(value)
+	return encodeJSON(cType, value)
 }
 
 // AddComponentToEntity adds the given component to the given entity. An error is returned if the entity
@@ -311,7 +407,11 @@ func (m *EntityCommandBuffer) AddComponentToEntity(cType types.ComponentMetadata
 	if err != nil {
 		return err
 	}
-	return m.moveEntityByArchetype(fromArchID, toArchID, id)
+	if err = m.moveEntityByArchetype(fromArchID, toArchID, id); err != nil {
+		return err
+	}
+	m.dirty.add(cType.Name(), id)
+	return m.indexDefaultValues([]types.ComponentMetadata{cType}, id)
 }
 
 // RemoveComponentFromEntity removes the given component from the given entity. An error is returned if the entity
@@ -345,6 +445,8 @@ func (m *EntityCommandBuffer) RemoveComponentFromEntity(cType types.ComponentMet
 	if err != nil {
 		return err
 	}
+	m.indexes.remove(cType.ID(), id)
+	m.dirty.remove(cType.Name(), id)
 	fromArchID, err := m.getOrMakeArchIDForComponents(comps)
 	if err != nil {
 		return err
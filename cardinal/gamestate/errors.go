@@ -10,6 +10,7 @@ var (
 	ErrComponentNotOnEntity              = errors.New("component not on entity")
 	ErrEntityMustHaveAtLeastOneComponent = errors.New("entities must have at least 1 component")
 	ErrMustRegisterComponent             = errors.New("must register component")
+	ErrRelationshipCycle                 = errors.New("an entity cannot be its own ancestor")
 
 	// ErrComponentMismatchWithSavedState is an error that is returned when a ComponentID from
 	// the saved state is not found in the passed in list of components.
@@ -0,0 +1,405 @@
+package gamestate
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+var (
+	// ErrTickNotInHistory is returned when the state at a given tick can not be reconstructed, either because the tick
+	// has not been finalized yet, or because it is older than the retained history.
+	ErrTickNotInHistory = errors.New("tick is not available in state history")
+
+	ErrReadOnlyStorage = errors.New("storage is read only")
+)
+
+var _ HistoricalManager = &EntityCommandBuffer{}
+
+// HistoricalManager is implemented by game state managers that are able to read the state as it was at a past tick.
+type HistoricalManager interface {
+	// SetHistorySize sets how many ticks worth of state diffs are recorded. A size of 0 disables recording.
+	SetHistorySize(ticksToRetain uint64)
+	// ToReadOnlyAtTick returns a Reader of the state right after the given tick was finalized.
+	ToReadOnlyAtTick(tick uint64) (Reader, error)
+}
+
+// tickDiff holds the values that storage keys had before they were changed in a tick. Replaying the tick diffs from
+// the most recent tick back to tick N+1 reverts the state to what it was at tick N.
+type tickDiff map[string]diffEntry
+
+type diffEntry struct {
+	// Exists is false if the key did not exist before the tick.
+	Exists bool   `json:"exists"`
+	Value  []byte `json:"value,omitempty"`
+}
+
+func (m *EntityCommandBuffer) SetHistorySize(ticksToRetain uint64) {
+	m.historySize = ticksToRetain
+}
+
+// ToReadOnlyAtTick returns a Reader that sees the state right after the given tick was finalized. The tick must be
+// one of the most recent finalized ticks that are still covered by the recorded history (see SetHistorySize).
+func (m *EntityCommandBuffer) ToReadOnlyAtTick(tick uint64) (Reader, error) {
+	storage := &historicalStorage{
+		storage: m.dbStorage,
+		tick:    tick,
+		diffs:   map[uint64]tickDiff{},
+		mutex:   &sync.Mutex{},
+	}
+	// Make sure the tick can actually be reconstructed before handing out the reader.
+	if _, err := storage.diffsAfterTick(context.Background()); err != nil {
+		return nil, err
+	}
+	return &readOnlyManager{
+		storage:         storage,
+		typeToComponent: m.typeToComponent,
+		// The archetypes that exist now may not have existed at the given tick, so they must be loaded from the
+		// historical state instead of being shared with the EntityCommandBuffer.
+		archIDToComps: NewMapStorage[types.ArchetypeID, []types.ComponentMetadata](),
+	}, nil
+}
+
+// diffRecorder is a Transaction that remembers the keys changed in the transaction, so the values they had in storage
+// before the transaction can be read in a batch (see load) right before the transaction is committed.
+type diffRecorder struct {
+	Transaction[string]
+	storage PrimitiveStorage[string]
+	keys    map[string]struct{}
+}
+
+func newDiffRecorder(txn Transaction[string], storage PrimitiveStorage[string]) *diffRecorder {
+	return &diffRecorder{
+		Transaction: txn,
+		storage:     storage,
+		keys:        map[string]struct{}{},
+	}
+}
+
+func (d *diffRecorder) Set(ctx context.Context, key string, value any) error {
+	d.record(key)
+	return d.Transaction.Set(ctx, key, value)
+}
+
+func (d *diffRecorder) Incr(ctx context.Context, key string) error {
+	d.record(key)
+	return d.Transaction.Incr(ctx, key)
+}
+
+func (d *diffRecorder) Decr(ctx context.Context, key string) error {
+	d.record(key)
+	return d.Transaction.Decr(ctx, key)
+}
+
+func (d *diffRecorder) Delete(ctx context.Context, key string) error {
+	d.record(key)
+	return d.Transaction.Delete(ctx, key)
+}
+
+func (d *diffRecorder) record(key string) {
+	if isHistoryKey(key) {
+		d.keys[key] = struct{}{}
+	}
+}
+
+// load reads the values that the recorded keys have in storage, i.e. before the transaction is committed.
+func (d *diffRecorder) load(ctx context.Context) (tickDiff, error) {
+	keys := make([]string, 0, len(d.keys))
+	for key := range d.keys {
+		keys = append(keys, key)
+	}
+	values, err := GetManyBytesFrom(ctx, d.storage, keys)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to read previous values of the changed keys")
+	}
+	diff := make(tickDiff, len(keys))
+	for i, key := range keys {
+		diff[key] = diffEntry{Exists: values[i] != nil, Value: values[i]}
+	}
+	return diff, nil
+}
+
+// addRecordedDiffToPipe saves the diff of the tick that is being finalized, and removes the diffs that fell out of the
+// retained history. The diff is returned so it can be reused by the other consumers of the previous values.
+func (m *EntityCommandBuffer) addRecordedDiffToPipe(ctx context.Context, recorder *diffRecorder) (tickDiff, error) {
+	// The tick that is being finalized is the one right after the last finalized tick.
+	tick, err := m.GetLastFinalizedTick()
+	if err != nil {
+		return nil, err
+	}
+	diff, err := recorder.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bz, err := codec.Encode(diff)
+	if err != nil {
+		return nil, err
+	}
+	pipe := recorder.Transaction
+	if err := pipe.Set(ctx, storageTickDiffKey(tick), bz); err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	if err := m.addPruneTickKeysToPipe(ctx, pipe, storageTickDiffKeyPrefix, tick, m.historySize); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// addPruneTickKeysToPipe deletes the per-tick keys with the given prefix of every tick before the ticksToRetain ticks
+// that end with the given tick. Every tick from the oldest tick that still has a key is pruned, so no key is left
+// behind when the retained size is lowered or when a previous prune failed.
+func (m *EntityCommandBuffer) addPruneTickKeysToPipe(
+	ctx context.Context, pipe Transaction[string], prefix string, tick, ticksToRetain uint64,
+) error {
+	if tick < ticksToRetain {
+		return nil
+	}
+	keep := tick + 1 - ticksToRetain
+	oldest, ok := m.oldestTickKeys[prefix]
+	if !ok {
+		var err error
+		if oldest, err = m.loadOldestTickKey(ctx, prefix, keep); err != nil {
+			return err
+		}
+	}
+	for old := oldest; old < keep; old++ {
+		if err := pipe.Delete(ctx, tickKey(prefix, old)); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	m.pendingOldestTickKeys[prefix] = max(oldest, keep)
+	return nil
+}
+
+// loadOldestTickKey returns the oldest tick that has a key with the given prefix in storage, or the given default if
+// there is no such key.
+func (m *EntityCommandBuffer) loadOldestTickKey(ctx context.Context, prefix string, oldest uint64) (uint64, error) {
+	err := scanKeys(ctx, m.dbStorage, prefix, func(keys []string) error {
+		for _, key := range keys {
+			tick, err := strconv.ParseUint(strings.TrimPrefix(key, prefix), 10, 64)
+			if err != nil {
+				return eris.Wrapf(err, "invalid per-tick key %q", key)
+			}
+			oldest = min(oldest, tick)
+		}
+		return nil
+	})
+	return oldest, err
+}
+
+// commitOldestTickKeys is called once a tick is committed, so the per-tick keys pruned by the tick are not pruned
+// again.
+func (m *EntityCommandBuffer) commitOldestTickKeys() {
+	for prefix, oldest := range m.pendingOldestTickKeys {
+		m.oldestTickKeys[prefix] = oldest
+	}
+	clear(m.pendingOldestTickKeys)
+}
+
+// isHistoryKey returns true if changes to the given key should be recorded in the tick diffs.
+func isHistoryKey(key string) bool {
+	return isSnapshotKey(key) && key != storageLastFinalizedTickKey()
+}
+
+var _ PrimitiveStorage[string] = &historicalStorage{}
+
+// historicalStorage is a read only PrimitiveStorage that returns values as they were right after the given tick was
+// finalized. This is done by starting from the current value of a key, and applying the tick diffs in reverse.
+type historicalStorage struct {
+	storage PrimitiveStorage[string]
+	tick    uint64
+
+	// diffs caches the tick diffs that have been loaded so far. Diffs never change once they are written.
+	diffs map[uint64]tickDiff
+	mutex *sync.Mutex
+}
+
+func (h *historicalStorage) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	// A new tick may be finalized while the value is being read. In that case the current value of the key may
+	// already include the changes of that tick, so the lookup is retried with the new diff included.
+	for {
+		diffs, err := h.diffsAfterTick(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, diff := range diffs {
+			if entry, ok := diff[key]; ok {
+				if !entry.Exists {
+					return nil, eris.Wrap(redis.Nil, "")
+				}
+				return entry.Value, nil
+			}
+		}
+		bz, err := h.storage.GetBytes(ctx, key)
+		if err != nil && !eris.Is(eris.Cause(err), redis.Nil) {
+			return nil, err
+		}
+		lastTick, tickErr := h.lastFinalizedTick(ctx)
+		if tickErr != nil {
+			return nil, tickErr
+		}
+		if lastTick == h.tick+uint64(len(diffs)) {
+			return bz, err
+		}
+	}
+}
+
+// diffsAfterTick returns the diffs for every tick after h.tick, in ascending tick order.
+func (h *historicalStorage) diffsAfterTick(ctx context.Context) ([]tickDiff, error) {
+	lastTick, err := h.lastFinalizedTick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if h.tick > lastTick {
+		return nil, eris.Wrapf(ErrTickNotInHistory, "tick %d has not been finalized yet", h.tick)
+	}
+
+	diffs := make([]tickDiff, 0, lastTick-h.tick)
+	for tick := h.tick + 1; tick <= lastTick; tick++ {
+		diff, err := h.diff(ctx, tick)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// diff returns the diff of the given tick.
+func (h *historicalStorage) diff(ctx context.Context, tick uint64) (tickDiff, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if diff, ok := h.diffs[tick]; ok {
+		return diff, nil
+	}
+	bz, err := h.storage.GetBytes(ctx, storageTickDiffKey(tick))
+	if eris.Is(eris.Cause(err), redis.Nil) {
+		return nil, eris.Wrapf(ErrTickNotInHistory, "no state diff recorded for tick %d", tick)
+	} else if err != nil {
+		return nil, err
+	}
+	diff, err := codec.Decode[tickDiff](bz)
+	if err != nil {
+		return nil, err
+	}
+	h.diffs[tick] = diff
+	return diff, nil
+}
+
+// lastFinalizedTick returns the most recent tick that has been finalized.
+func (h *historicalStorage) lastFinalizedTick(ctx context.Context) (uint64, error) {
+	// The stored value is the number of finalized ticks.
+	count, err := h.storage.GetUInt64(ctx, storageLastFinalizedTickKey())
+	if eris.Is(eris.Cause(err), redis.Nil) || (err == nil && count == 0) {
+		return 0, eris.Wrap(ErrTickNotInHistory, "no ticks have been finalized yet")
+	} else if err != nil {
+		return 0, eris.Wrap(err, "failed to get latest finalized tick")
+	}
+	return count - 1, nil
+}
+
+func (h *historicalStorage) GetFloat64(ctx context.Context, key string) (float64, error) {
+	bz, err := h.GetBytes(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	res, err := strconv.ParseFloat(string(bz), 64)
+	return res, eris.Wrap(err, "")
+}
+
+func (h *historicalStorage) GetFloat32(ctx context.Context, key string) (float32, error) {
+	bz, err := h.GetBytes(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	res, err := strconv.ParseFloat(string(bz), 32)
+	return float32(res), eris.Wrap(err, "")
+}
+
+func (h *historicalStorage) GetUInt64(ctx context.Context, key string) (uint64, error) {
+	bz, err := h.GetBytes(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	res, err := strconv.ParseUint(string(bz), 10, 64)
+	return res, eris.Wrap(err, "")
+}
+
+func (h *historicalStorage) GetInt64(ctx context.Context, key string) (int64, error) {
+	bz, err := h.GetBytes(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	res, err := strconv.ParseInt(string(bz), 10, 64)
+	return res, eris.Wrap(err, "")
+}
+
+func (h *historicalStorage) GetInt(ctx context.Context, key string) (int, error) {
+	bz, err := h.GetBytes(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	res, err := strconv.Atoi(string(bz))
+	return res, eris.Wrap(err, "")
+}
+
+func (h *historicalStorage) GetBool(ctx context.Context, key string) (bool, error) {
+	bz, err := h.GetBytes(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	res, err := strconv.ParseBool(string(bz))
+	return res, eris.Wrap(err, "")
+}
+
+func (h *historicalStorage) Get(ctx context.Context, key string) (any, error) {
+	bz, err := h.GetBytes(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return string(bz), nil
+}
+
+func (h *historicalStorage) Set(context.Context, string, any) error {
+	return eris.Wrap(ErrReadOnlyStorage, "")
+}
+
+func (h *historicalStorage) Incr(context.Context, string) error {
+	return eris.Wrap(ErrReadOnlyStorage, "")
+}
+
+func (h *historicalStorage) Decr(context.Context, string) error {
+	return eris.Wrap(ErrReadOnlyStorage, "")
+}
+
+func (h *historicalStorage) Delete(context.Context, string) error {
+	return eris.Wrap(ErrReadOnlyStorage, "")
+}
+
+func (h *historicalStorage) StartTransaction(context.Context) (Transaction[string], error) {
+	return nil, eris.Wrap(ErrReadOnlyStorage, "")
+}
+
+func (h *historicalStorage) EndTransaction(context.Context) error {
+	return eris.Wrap(ErrReadOnlyStorage, "")
+}
+
+func (h *historicalStorage) Close(context.Context) error {
+	return nil
+}
+
+func (h *historicalStorage) Clear(context.Context) error {
+	return eris.Wrap(ErrReadOnlyStorage, "")
+}
+
+func (h *historicalStorage) Keys(context.Context) ([]string, error) {
+	return nil, eris.New("listing keys is not supported on historical state")
+}
@@ -0,0 +1,143 @@
+package gamestate_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/filter"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+)
+
+func TestCanReadStateAtPastTick(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+	manager.SetHistorySize(3)
+
+	// Tick 0
+	alpha, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, alpha, Foo{1}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// Tick 1
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, alpha, Foo{2}))
+	beta, err := manager.CreateEntity(fooComp, barComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(barComp, beta, Bar{10}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// Tick 2
+	assert.NilError(t, manager.RemoveEntity(alpha))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	atTick0, err := manager.ToReadOnlyAtTick(0)
+	assert.NilError(t, err)
+	got, err := atTick0.GetComponentForEntity(fooComp, alpha)
+	assert.NilError(t, err)
+	assert.Equal(t, Foo{1}, got)
+	_, err = atTick0.GetComponentForEntity(fooComp, beta)
+	assert.IsError(t, err)
+	// The archetype of beta was created in tick 1
+	assert.Equal(t, 1, atTick0.ArchetypeCount())
+	assert.Equal(t, 1, len(atTick0.SearchFrom(filter.Contains(filter.Component[Foo]()), 0).Values))
+
+	atTick1, err := manager.ToReadOnlyAtTick(1)
+	assert.NilError(t, err)
+	got, err = atTick1.GetComponentForEntity(fooComp, alpha)
+	assert.NilError(t, err)
+	assert.Equal(t, Foo{2}, got)
+	got, err = atTick1.GetComponentForEntity(barComp, beta)
+	assert.NilError(t, err)
+	assert.Equal(t, Bar{10}, got)
+	assert.Equal(t, 2, atTick1.ArchetypeCount())
+
+	atTick2, err := manager.ToReadOnlyAtTick(2)
+	assert.NilError(t, err)
+	_, err = atTick2.GetComponentForEntity(fooComp, alpha)
+	assert.IsError(t, err)
+
+	// Tick 3 has not been finalized yet
+	_, err = manager.ToReadOnlyAtTick(3)
+	assert.Check(t, errors.Is(err, gamestate.ErrTickNotInHistory))
+}
+
+func TestStateBeyondHistorySizeIsNotAvailable(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+	manager.SetHistorySize(2)
+
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	for i := 0; i < 5; i++ {
+		assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{i}))
+		assert.NilError(t, manager.FinalizeTick(ctx))
+	}
+
+	// Ticks 0 through 4 have been finalized, and only the diffs for ticks 3 and 4 are retained.
+	for tick := 0; tick < 2; tick++ {
+		_, err = manager.ToReadOnlyAtTick(uint64(tick))
+		assert.Check(t, errors.Is(err, gamestate.ErrTickNotInHistory))
+	}
+	for tick := 2; tick < 5; tick++ {
+		reader, err := manager.ToReadOnlyAtTick(uint64(tick))
+		assert.NilError(t, err)
+		got, err := reader.GetComponentForEntity(fooComp, id)
+		assert.NilError(t, err)
+		assert.Equal(t, Foo{tick}, got)
+	}
+}
+
+func TestHistoryIsNotRecordedByDefault(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+
+	_, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	_, err = manager.ToReadOnlyAtTick(0)
+	assert.Check(t, errors.Is(err, gamestate.ErrTickNotInHistory))
+	// The most recent tick is always available
+	_, err = manager.ToReadOnlyAtTick(1)
+	assert.NilError(t, err)
+}
+
+func TestLoweringHistorySizeRemovesAllOlderDiffs(t *testing.T) {
+	ctx := context.Background()
+	manager, client := newCmdBufferAndRedisClientForTest(t, nil)
+	manager.SetHistorySize(10)
+
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	for i := 0; i < 5; i++ {
+		assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{i}))
+		assert.NilError(t, manager.FinalizeTick(ctx))
+	}
+	keys, err := client.Keys(ctx, "ECB:TICK-DIFF:*").Result()
+	assert.NilError(t, err)
+	assert.Equal(t, 5, len(keys))
+
+	// A new manager does not know which diffs are in storage, and still removes all the diffs that are too old.
+	restarted, _ := newCmdBufferAndRedisClientForTest(t, client)
+	restarted.SetHistorySize(2)
+	assert.NilError(t, restarted.SetComponentForEntity(fooComp, id, Foo{5}))
+	assert.NilError(t, restarted.FinalizeTick(ctx))
+	assert.NilError(t, restarted.FinalizeTick(ctx))
+
+	keys, err = client.Keys(ctx, "ECB:TICK-DIFF:*").Result()
+	assert.NilError(t, err)
+	slices.Sort(keys)
+	assert.DeepEqual(t, []string{"ECB:TICK-DIFF:5", "ECB:TICK-DIFF:6"}, keys)
+
+	_, err = restarted.ToReadOnlyAtTick(3)
+	assert.Check(t, errors.Is(err, gamestate.ErrTickNotInHistory))
+	reader, err := restarted.ToReadOnlyAtTick(4)
+	assert.NilError(t, err)
+	got, err := reader.GetComponentForEntity(fooComp, id)
+	assert.NilError(t, err)
+	assert.Equal(t, Foo{4}, got)
+}
@@ -0,0 +1,357 @@
+package gamestate
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+var (
+	ErrIndexNotFound          = errors.New("index not found")
+	ErrIndexAlreadyRegistered = errors.New("index is already registered")
+	ErrIndexNotAvailable      = errors.New("indexes are not available on this reader")
+)
+
+// Indexer is implemented by managers that can maintain secondary indexes on component fields.
+type Indexer interface {
+	// RegisterIndex adds a secondary index. Indexes are built from the saved state when RegisterComponents is called.
+	RegisterIndex(def IndexDefinition) error
+	// RegisterSpatialIndex adds a spatial index. Like other indexes, it is built when RegisterComponents is called.
+	RegisterSpatialIndex(def SpatialIndexDefinition) error
+}
+
+// IndexReader is implemented by readers that can look up entities in a secondary index.
+type IndexReader interface {
+	// LookupIndex returns the sorted IDs of the entities whose indexed value is in the given range.
+	LookupIndex(componentName, field string, r IndexRange) ([]types.EntityID, error)
+	// LookupRect returns the sorted IDs of the entities whose position is in the given rectangle.
+	LookupRect(componentName string, rect Rect) ([]types.EntityID, error)
+	// LookupRadius returns the sorted IDs of the entities whose position is within radius of the given point.
+	LookupRadius(componentName string, center Point, radius float64) ([]types.EntityID, error)
+	// LookupNearest returns the IDs of the k entities nearest to the given point for which accept returns true, from
+	// nearest to farthest. Entities at the same distance are ordered by ID.
+	LookupNearest(componentName string, center Point, k int, accept func(types.EntityID) bool) (
+		[]types.EntityID, error)
+}
+
+// IndexDefinition describes a secondary index on a field of a component.
+type IndexDefinition struct {
+	Component types.ComponentMetadata
+	// Field is the name of the index. It must be unique among the indexes of the component.
+	Field string
+	// KeyType is the type of the values returned by Key.
+	KeyType reflect.Type
+	// Key extracts the indexed value from a component value.
+	Key func(component any) (any, error)
+	// Compare orders two values returned by Key.
+	Compare func(a, b any) int
+}
+
+// IndexRange is an inclusive range of indexed values. A nil Min or Max means the range is unbounded on that side.
+type IndexRange struct {
+	Min any
+	Max any
+}
+
+type indexName struct {
+	component string
+	field     string
+}
+
+type indexEntry struct {
+	key any
+	id  types.EntityID
+}
+
+// pendingIndexEntry is the indexed value of an entity in the current tick.
+type pendingIndexEntry struct {
+	key     any
+	removed bool
+}
+
+// index maps the values of a component field to entities. committed reflects the saved state, and pending holds the
+// changes made in the current tick, which are applied to committed once the tick is finalized.
+type index struct {
+	def       IndexDefinition
+	committed *indexEntries
+	keys      map[types.EntityID]any
+	pending   map[types.EntityID]pendingIndexEntry
+}
+
+// indexSet holds all the secondary indexes of an EntityCommandBuffer.
+type indexSet struct {
+	mu      sync.RWMutex
+	indexes map[indexName]*index
+	// byComponent holds the indexes of each component.
+	byComponent map[types.ComponentID][]*index
+	// spatial holds the spatial index of each component, keyed by component name.
+	spatial map[string]*spatialIndex
+	// spatialByComponent holds the same spatial indexes, keyed by component ID.
+	spatialByComponent map[types.ComponentID]*spatialIndex
+}
+
+func newIndexSet() *indexSet {
+	return &indexSet{
+		mu:                 sync.RWMutex{},
+		indexes:            map[indexName]*index{},
+		byComponent:        map[types.ComponentID][]*index{},
+		spatial:            map[string]*spatialIndex{},
+		spatialByComponent: map[types.ComponentID]*spatialIndex{},
+	}
+}
+
+func (s *indexSet) add(def IndexDefinition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name := indexName{def.Component.Name(), def.Field}
+	if _, ok := s.indexes[name]; ok {
+		return eris.Wrapf(ErrIndexAlreadyRegistered, "index %q on component %q", def.Field, def.Component.Name())
+	}
+	idx := &index{
+		def:       def,
+		committed: newIndexEntries(def.Compare),
+		keys:      map[types.EntityID]any{},
+		pending:   map[types.EntityID]pendingIndexEntry{},
+	}
+	s.indexes[name] = idx
+	s.byComponent[def.Component.ID()] = append(s.byComponent[def.Component.ID()], idx)
+	return nil
+}
+
+func (s *indexSet) isEmpty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.indexes) == 0 && len(s.spatial) == 0
+}
+
+func (s *indexSet) hasIndex(componentID types.ComponentID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, hasSpatial := s.spatialByComponent[componentID]
+	return len(s.byComponent[componentID]) > 0 || hasSpatial
+}
+
+// set records the new value of a component of an entity in the current tick.
+func (s *indexSet) set(componentID types.ComponentID, id types.EntityID, value any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, idx := range s.byComponent[componentID] {
+		key, err := idx.def.Key(value)
+		if err != nil {
+			return err
+		}
+		idx.pending[id] = pendingIndexEntry{key: key, removed: false}
+	}
+	if idx, ok := s.spatialByComponent[componentID]; ok {
+		return idx.set(id, value)
+	}
+	return nil
+}
+
+// remove records that a component was removed from an entity in the current tick.
+func (s *indexSet) remove(componentID types.ComponentID, id types.EntityID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, idx := range s.byComponent[componentID] {
+		idx.pending[id] = pendingIndexEntry{key: nil, removed: true}
+	}
+	if idx, ok := s.spatialByComponent[componentID]; ok {
+		idx.remove(id)
+	}
+}
+
+// commit applies the changes of the current tick to the committed indexes.
+func (s *indexSet) commit() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, idx := range s.indexes {
+		for id, entry := range idx.pending {
+			if oldKey, ok := idx.keys[id]; ok {
+				idx.committed.delete(indexEntry{key: oldKey, id: id})
+				delete(idx.keys, id)
+			}
+			if !entry.removed {
+				idx.committed.insert(indexEntry{key: entry.key, id: id})
+				idx.keys[id] = entry.key
+			}
+		}
+		idx.pending = map[types.EntityID]pendingIndexEntry{}
+	}
+	for _, idx := range s.spatial {
+		idx.commit()
+	}
+}
+
+// reset drops all the indexed values.
+func (s *indexSet) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, idx := range s.indexes {
+		idx.committed = newIndexEntries(idx.def.Compare)
+		idx.keys = map[types.EntityID]any{}
+		idx.pending = map[types.EntityID]pendingIndexEntry{}
+	}
+	for _, idx := range s.spatial {
+		idx.reset()
+	}
+}
+
+// discardPending drops the changes of the current tick.
+func (s *indexSet) discardPending() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, idx := range s.indexes {
+		idx.pending = map[types.EntityID]pendingIndexEntry{}
+	}
+	for _, idx := range s.spatial {
+		idx.discardPending()
+	}
+}
+
+// lookup returns the sorted IDs of the entities whose indexed value is in the given range. If includePending is false,
+// only the committed state is used.
+func (s *indexSet) lookup(componentName, field string, r IndexRange, includePending bool) ([]types.EntityID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	idx, ok := s.indexes[indexName{componentName, field}]
+	if !ok {
+		return nil, eris.Wrapf(ErrIndexNotFound, "index %q on component %q", field, componentName)
+	}
+	for _, bound := range []any{r.Min, r.Max} {
+		if bound != nil && reflect.TypeOf(bound) != idx.def.KeyType {
+			return nil, eris.Errorf("index %q on component %q has values of type %s, got %T",
+				field, componentName, idx.def.KeyType, bound)
+		}
+	}
+
+	ids := make([]types.EntityID, 0)
+	idx.committed.ascend(r.Min, func(entry indexEntry) bool {
+		if r.Max != nil && idx.def.Compare(entry.key, r.Max) > 0 {
+			return false
+		}
+		if _, ok := idx.pending[entry.id]; !ok || !includePending {
+			ids = append(ids, entry.id)
+		}
+		return true
+	})
+	if includePending {
+		for id, entry := range idx.pending {
+			if !entry.removed && idx.inRange(entry.key, r) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+func (idx *index) inRange(key any, r IndexRange) bool {
+	if r.Min != nil && idx.def.Compare(key, r.Min) < 0 {
+		return false
+	}
+	if r.Max != nil && idx.def.Compare(key, r.Max) > 0 {
+		return false
+	}
+	return true
+}
+
+// RegisterIndex adds a secondary index on a component field. The index is built the next time RegisterComponents is
+// called.
+func (m *EntityCommandBuffer) RegisterIndex(def IndexDefinition) error {
+	return m.indexes.add(def)
+}
+
+// LookupIndex returns the sorted IDs of the entities whose indexed value is in the given range, including the changes
+// made in the current tick.
+func (m *EntityCommandBuffer) LookupIndex(componentName, field string, r IndexRange) ([]types.EntityID, error) {
+	return m.indexes.lookup(componentName, field, r, true)
+}
+
+// LookupIndex returns the sorted IDs of the entities whose indexed value is in the given range as of the last
+// finalized tick.
+func (r *readOnlyManager) LookupIndex(componentName, field string, rng IndexRange) ([]types.EntityID, error) {
+	if r.indexes == nil {
+		return nil, eris.Wrap(ErrIndexNotAvailable, "")
+	}
+	return r.indexes.lookup(componentName, field, rng, false)
+}
+
+// buildIndexes fills the indexes with the saved state of every entity that has an indexed component.
+func (m *EntityCommandBuffer) buildIndexes() error {
+	if m.indexes.isEmpty() {
+		return nil
+	}
+	m.indexes.reset()
+	ctx := context.Background()
+	reader := m.ToReadOnly()
+	for i := 0; i < m.archIDToComps.Len(); i++ {
+		archID := types.ArchetypeID(i)
+		comps, err := m.archIDToComps.Get(archID)
+		if err != nil {
+			return err
+		}
+		var ids []types.EntityID
+		for _, comp := range comps {
+			if !m.indexes.hasIndex(comp.ID()) {
+				continue
+			}
+			if ids == nil {
+				if ids, err = reader.GetEntitiesForArchID(archID); err != nil {
+					return err
+				}
+			}
+			for _, id := range ids {
+				bz, err := m.dbStorage.GetBytes(ctx, storageComponentKey(comp.ID(), id))
+				if err != nil {
+					// todo: this is redis specific, should be changed to a general error on storage
+					if !errors.Is(err, redis.Nil) {
+						return err
+					}
+					// This value has never been set, so the entity has the default value.
+					if bz, err = comp.New(); err != nil {
+						return err
+					}
+				}
+				value, err := comp.Decode(bz)
+				if err != nil {
+					return err
+				}
+				if err = m.indexes.set(comp.ID(), id, value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	m.indexes.commit()
+	return nil
+}
+
+// indexDefaultValues records the default value of the given components for newly added entities or components.
+func (m *EntityCommandBuffer) indexDefaultValues(comps []types.ComponentMetadata, ids ...types.EntityID) error {
+	for _, comp := range comps {
+		if !m.indexes.hasIndex(comp.ID()) {
+			continue
+		}
+		bz, err := comp.New()
+		if err != nil {
+			return err
+		}
+		value, err := comp.Decode(bz)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err = m.indexes.set(comp.ID(), id, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
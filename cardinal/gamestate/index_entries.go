@@ -0,0 +1,110 @@
+package gamestate
+
+import (
+	"slices"
+	"sort"
+)
+
+// indexChunkSize is the largest number of entries kept in a single chunk of indexEntries.
+const indexChunkSize = 256
+
+// indexEntries is an ordered set of index entries. The entries are kept in sorted chunks of at most indexChunkSize
+// entries, so inserting or deleting an entry only moves the entries of one chunk, and the chunk list itself only
+// changes when a chunk is split or emptied.
+type indexEntries struct {
+	compare func(a, b any) int
+	chunks  [][]indexEntry
+}
+
+func newIndexEntries(compare func(a, b any) int) *indexEntries {
+	return &indexEntries{
+		compare: compare,
+		chunks:  nil,
+	}
+}
+
+// compareEntries orders entries by key, then by entity ID.
+func (e *indexEntries) compareEntries(a, b indexEntry) int {
+	if c := e.compare(a.key, b.key); c != 0 {
+		return c
+	}
+	switch {
+	case a.id < b.id:
+		return -1
+	case a.id > b.id:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// find returns the chunk, and the position in that chunk, of the first entry for which atOrAfter returns true. The
+// chunk is len(e.chunks) if there is no such entry. atOrAfter must be false for a prefix of the entries, and true for
+// the rest.
+func (e *indexEntries) find(atOrAfter func(indexEntry) bool) (chunk, pos int) {
+	chunk = sort.Search(len(e.chunks), func(i int) bool {
+		return atOrAfter(e.chunks[i][len(e.chunks[i])-1])
+	})
+	if chunk == len(e.chunks) {
+		return chunk, 0
+	}
+	entries := e.chunks[chunk]
+	return chunk, sort.Search(len(entries), func(i int) bool {
+		return atOrAfter(entries[i])
+	})
+}
+
+func (e *indexEntries) insert(entry indexEntry) {
+	if len(e.chunks) == 0 {
+		e.chunks = [][]indexEntry{{entry}}
+		return
+	}
+	chunk, pos := e.find(func(other indexEntry) bool {
+		return e.compareEntries(other, entry) >= 0
+	})
+	if chunk == len(e.chunks) {
+		// The entry goes after every other entry.
+		chunk--
+		pos = len(e.chunks[chunk])
+	}
+	entries := slices.Insert(e.chunks[chunk], pos, entry)
+	if len(entries) > indexChunkSize {
+		half := len(entries) / 2
+		e.chunks = slices.Insert(e.chunks, chunk+1, slices.Clone(entries[half:]))
+		entries = entries[:half]
+	}
+	e.chunks[chunk] = entries
+}
+
+func (e *indexEntries) delete(entry indexEntry) {
+	chunk, pos := e.find(func(other indexEntry) bool {
+		return e.compareEntries(other, entry) >= 0
+	})
+	if chunk == len(e.chunks) || e.compareEntries(e.chunks[chunk][pos], entry) != 0 {
+		return
+	}
+	entries := slices.Delete(e.chunks[chunk], pos, pos+1)
+	if len(entries) == 0 {
+		e.chunks = slices.Delete(e.chunks, chunk, chunk+1)
+		return
+	}
+	e.chunks[chunk] = entries
+}
+
+// ascend calls fn with the entries whose key is greater than or equal to minKey, in order, until fn returns false. A
+// nil minKey starts from the first entry.
+func (e *indexEntries) ascend(minKey any, fn func(indexEntry) bool) {
+	chunk, pos := 0, 0
+	if minKey != nil {
+		chunk, pos = e.find(func(other indexEntry) bool {
+			return e.compare(other.key, minKey) >= 0
+		})
+	}
+	for ; chunk < len(e.chunks); chunk, pos = chunk+1, 0 {
+		for _, entry := range e.chunks[chunk][pos:] {
+			if !fn(entry) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package gamestate_test
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+func fooValueIndex() gamestate.IndexDefinition {
+	return gamestate.IndexDefinition{
+		Component: fooComp,
+		Field:     "value",
+		KeyType:   reflect.TypeOf(0),
+		Key: func(value any) (any, error) {
+			switch foo := value.(type) {
+			case Foo:
+				return foo.Value, nil
+			case *Foo:
+				return foo.Value, nil
+			}
+			return nil, fmt.Errorf("unexpected component %T", value)
+		},
+		Compare: func(a, b any) int {
+			return cmp.Compare(a.(int), b.(int)) //nolint:errcheck // keys are always ints
+		},
+	}
+}
+
+func newIndexedCmdBuffer(t *testing.T, client *redis.Client) *gamestate.EntityCommandBuffer {
+	storage := gamestate.NewRedisPrimitiveStorage(client)
+	manager, err := gamestate.NewEntityCommandBuffer(&storage)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.RegisterIndex(fooValueIndex()))
+	assert.NilError(t, manager.RegisterComponents(allComponents))
+	return manager
+}
+
+func TestIndexTracksPendingAndFinalizedChanges(t *testing.T) {
+	ctx := context.Background()
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	manager := newIndexedCmdBuffer(t, client)
+	equals := func(v int) gamestate.IndexRange { return gamestate.IndexRange{Min: v, Max: v} }
+
+	ids, err := manager.CreateManyEntities(4, fooComp)
+	assert.NilError(t, err)
+	for i, id := range ids {
+		assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{i}))
+	}
+
+	// Pending changes are visible to the manager, but not to read only readers.
+	got, err := manager.LookupIndex("foo", "value", gamestate.IndexRange{Min: 1, Max: 2})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ids[1:3], got)
+	got, err = manager.ToReadOnly().(gamestate.IndexReader).LookupIndex("foo", "value", equals(1))
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(got))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// Discarded changes are dropped from the index.
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, ids[0], Foo{100}))
+	assert.NilError(t, manager.RemoveEntity(ids[1]))
+	got, err = manager.LookupIndex("foo", "value", gamestate.IndexRange{Min: 50})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{ids[0]}, got)
+	assert.NilError(t, manager.DiscardPending())
+	got, err = manager.LookupIndex("foo", "value", gamestate.IndexRange{Max: 1})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ids[0:2], got)
+
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, ids[3], Foo{0}))
+	assert.NilError(t, manager.RemoveEntity(ids[1]))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// The index is rebuilt from the saved state when a new manager is started.
+	restarted := newIndexedCmdBuffer(t, client)
+	got, err = restarted.LookupIndex("foo", "value", equals(0))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{ids[0], ids[3]}, got)
+	got, err = restarted.LookupIndex("foo", "value", equals(1))
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(got))
+
+	_, err = restarted.LookupIndex("foo", "value", gamestate.IndexRange{Min: int64(0)})
+	assert.ErrorContains(t, err, "has values of type int")
+}
+
+func TestIndexStaysSortedAcrossManyChanges(t *testing.T) {
+	ctx := context.Background()
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	manager := newIndexedCmdBuffer(t, client)
+
+	// Enough entities for the index to be split into several chunks, with many entities sharing the same value.
+	const numEntities = 2000
+	ids, err := manager.CreateManyEntities(numEntities, fooComp)
+	assert.NilError(t, err)
+	values := map[types.EntityID]int{}
+	for i, id := range ids {
+		values[id] = (i * 7919) % 100
+		assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{values[id]}))
+	}
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// Move some entities to other values, and remove others.
+	for i, id := range ids {
+		switch i % 3 {
+		case 0:
+			values[id] = (values[id] + 50) % 100
+			assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{values[id]}))
+		case 1:
+			if i%5 == 0 {
+				delete(values, id)
+				assert.NilError(t, manager.RemoveEntity(id))
+			}
+		}
+	}
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	reader := manager.ToReadOnly().(gamestate.IndexReader) //nolint:errcheck // the read only manager has indexes
+	for _, rng := range [][2]int{{0, 0}, {10, 20}, {0, 99}, {98, 200}} {
+		want := make([]types.EntityID, 0)
+		for _, id := range ids {
+			if value, ok := values[id]; ok && value >= rng[0] && value <= rng[1] {
+				want = append(want, id)
+			}
+		}
+		got, err := reader.LookupIndex("foo", "value", gamestate.IndexRange{Min: rng[0], Max: rng[1]})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, want, got)
+	}
+}
@@ -2,13 +2,36 @@ package gamestate
 
 import (
 	"fmt"
+	"strconv"
 
 	"pkg.world.dev/world-engine/cardinal/types"
 )
 
+const (
+	// storageECBKeyPrefix is the prefix shared by all keys written by the EntityCommandBuffer.
+	storageECBKeyPrefix = "ECB:"
+
+	storageComponentSchemaKeyPrefix = "ECB:COMPONENT-SCHEMA:"
+
+	storageTickDiffKeyPrefix = "ECB:TICK-DIFF:"
+
+	storageStateHashKeyPrefix = "ECB:STATE-HASH:"
+
+	storageStateRootKeyPrefix = "ECB:STATE-ROOT:"
+
+	storageComponentKeyFormat = "ECB:COMPONENT-VALUE:TYPE-ID-%d:ENTITY-ID-%d"
+)
+
 // storageComponentKey is the key that maps an entity ID and a specific component ID to the value of that component.
 func storageComponentKey(typeID types.ComponentID, id types.EntityID) string {
-	return fmt.Sprintf("ECB:COMPONENT-VALUE:TYPE-ID-%d:ENTITY-ID-%d", typeID, id)
+	return fmt.Sprintf(storageComponentKeyFormat, typeID, id)
+}
+
+// parseStorageComponentKey returns the component ID and the entity ID of a key made by storageComponentKey. ok is
+// false if the key is not a component key.
+func parseStorageComponentKey(key string) (typeID types.ComponentID, id types.EntityID, ok bool) {
+	n, err := fmt.Sscanf(key, storageComponentKeyFormat, &typeID, &id)
+	return typeID, id, err == nil && n == 2 //nolint:gomnd // the 2 IDs in the key
 }
 
 // storageNextEntityIDKey is the key that stores the next available entity ID that can be assigned to a newly created
@@ -42,3 +65,68 @@ func storageArchIDsToCompTypesKey() string {
 func storageLastFinalizedTickKey() string {
 	return "ECB:LAST-FINALIZED-TICK"
 }
+
+// storageTickDiffKey is the key that stores the values that were overwritten when the given tick was finalized.
+func storageTickDiffKey(tick uint64) string {
+	return tickKey(storageTickDiffKeyPrefix, tick)
+}
+
+// tickKey is the key with the given prefix of a key that is stored for every tick.
+func tickKey(prefix string, tick uint64) string {
+	return prefix + strconv.FormatUint(tick, 10)
+}
+
+// storageComponentSchemaKey is the key that stores the JSON schema of the component with the given name. It is only
+// used when component schemas are kept in the same PrimitiveStorage as the rest of the ECB state.
+func storageComponentSchemaKey(componentName string) string {
+	return storageComponentSchemaKeyPrefix + componentName
+}
+
+// storageParentKey is the key that maps an entity ID to the ID of its parent entity.
+func storageParentKey(id types.EntityID) string {
+	return fmt.Sprintf("ECB:PARENT:ENTITY-ID-%d", id)
+}
+
+// storageChildrenKey is the key that maps an entity ID to the IDs of its child entities.
+// Note, this key and storageParentKey represent the same information.
+func storageChildrenKey(id types.EntityID) string {
+	return fmt.Sprintf("ECB:CHILDREN:ENTITY-ID-%d", id)
+}
+
+// storageResourceKey is the key that stores the value of the world resource with the given name.
+func storageResourceKey(name string) string {
+	return "ECB:RESOURCE:" + name
+}
+
+// storageStateHashKey is the key that stores the hash of the game state at the end of the given tick.
+func storageStateHashKey(tick uint64) string {
+	return tickKey(storageStateHashKeyPrefix, tick)
+}
+
+// storageStateRootKey is the key that stores the state root of the game state at the end of the given tick.
+func storageStateRootKey(tick uint64) string {
+	return tickKey(storageStateRootKeyPrefix, tick)
+}
+
+// storageMigratedSchemaKey is the key that stores the schema the values of a component were last migrated to. It is
+// not part of the ECB state, and only exists until the stored schema of the component is updated.
+func storageMigratedSchemaKey(componentName string) string {
+	return "MIGRATION:SCHEMA:" + componentName
+}
+
+// storageUsedNonceKey is the key that marks the given nonce of a signer as used. Like the other nonce keys, it is only
+// used when nonces are kept in the same PrimitiveStorage as the rest of the ECB state, and it is not part of the ECB
+// state.
+func storageUsedNonceKey(signerAddress string, nonce uint64) string {
+	return fmt.Sprintf("NONCE:USED:%s:%d", signerAddress, nonce)
+}
+
+// storageMaxNonceKey is the key that stores the highest nonce used by a signer.
+func storageMaxNonceKey(signerAddress string) string {
+	return "NONCE:MAX:" + signerAddress
+}
+
+// storageNextNonceKey is the key that stores the next nonce expected from a signer when nonces must increase.
+func storageNextNonceKey(signerAddress string) string {
+	return "NONCE:NEXT:" + signerAddress
+}
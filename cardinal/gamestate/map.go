@@ -2,6 +2,7 @@ package gamestate
 
 import (
 	"errors"
+	"sync"
 
 	"github.com/rotisserie/eris"
 )
@@ -10,17 +11,23 @@ var _ VolatileStorage[string, any] = &MapStorage[string, any]{}
 
 var ErrNotFound = errors.New("key not found in map")
 
+// MapStorage is a VolatileStorage backed by a map. It is safe for concurrent use, so systems running concurrently can
+// read and write disjoint keys of the EntityCommandBuffer.
 type MapStorage[K comparable, V any] struct {
+	mu          sync.RWMutex
 	internalMap map[K]V
 }
 
 func NewMapStorage[K comparable, V any]() *MapStorage[K, V] {
 	return &MapStorage[K, V]{
+		mu:          sync.RWMutex{},
 		internalMap: make(map[K]V),
 	}
 }
 
 func (m *MapStorage[K, V]) Keys() ([]K, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	acc := make([]K, 0, len(m.internalMap))
 	for k := range m.internalMap {
 		acc = append(acc, k)
@@ -29,11 +36,15 @@ func (m *MapStorage[K, V]) Keys() ([]K, error) {
 }
 
 func (m *MapStorage[K, V]) Delete(key K) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	delete(m.internalMap, key)
 	return nil
 }
 
 func (m *MapStorage[K, V]) Get(key K) (V, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	v, ok := m.internalMap[key]
 	if !ok {
 		return v, eris.Wrap(ErrNotFound, "")
@@ -42,15 +53,21 @@ func (m *MapStorage[K, V]) Get(key K) (V, error) {
 }
 
 func (m *MapStorage[K, V]) Set(key K, value V) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.internalMap[key] = value
 	return nil
 }
 
 func (m *MapStorage[K, V]) Clear() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.internalMap = make(map[K]V)
 	return nil
 }
 
 func (m *MapStorage[K, V]) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	return len(m.internalMap)
 }
@@ -0,0 +1,27 @@
+package gamestate
+
+import (
+	"pkg.world.dev/world-engine/cardinal/storage"
+)
+
+var _ storage.Storage = &MetaStorage{}
+
+// MetaStorage stores the nonces and the component schemas in a PrimitiveStorage. It replaces the redis backed
+// storage.Storage when Cardinal is configured to keep its state in a non-Redis storage backend, so that the shard does
+// not need Redis at all.
+type MetaStorage struct {
+	*NonceStorage
+	*SchemaStorage
+}
+
+func NewMetaStorage(storage PrimitiveStorage[string]) *MetaStorage {
+	return &MetaStorage{
+		NonceStorage:  NewNonceStorage(storage),
+		SchemaStorage: NewSchemaStorage(storage),
+	}
+}
+
+// Close does nothing: the PrimitiveStorage is closed by its owner.
+func (m *MetaStorage) Close() error {
+	return nil
+}
@@ -0,0 +1,261 @@
+package gamestate
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+var _ ComponentMigrator = &EntityCommandBuffer{}
+
+// ComponentMigration rewrites the stored values of a component.
+type ComponentMigration struct {
+	Component types.ComponentMetadata
+	// Schema is the schema of the component once its values are migrated.
+	Schema []byte
+	// Migrate converts a stored value into a value of the registered component.
+	Migrate func(bz []byte) ([]byte, error)
+}
+
+// ComponentMigrator is implemented by managers that can rewrite the stored values of components whose schema changed.
+type ComponentMigrator interface {
+	// MigrateComponents runs the migrations on every stored value of their component and returns the number of values
+	// migrated by each of them. All the values are written in a single transaction, or not at all if dryRun is true.
+	// The transaction also records that the values of each component now have the migration's Schema, and the
+	// migrations whose values were already migrated to their Schema are skipped, so the values are never migrated
+	// twice if the stored schema of the component could not be updated afterward.
+	// The old values of the components in the state history are migrated in the same transaction, and the recorded
+	// state hashes and state roots, which were computed from the values before the migration, are removed, so the next
+	// tick computes its state hash and state root from the migrated state.
+	// It must be called with all the registered components before RegisterComponents, so the saved state is only
+	// loaded once it has been migrated.
+	MigrateComponents(
+		ctx context.Context, comps []types.ComponentMetadata, migrations []ComponentMigration, dryRun bool,
+	) ([]int, error)
+	// CompleteComponentMigration removes the migration progress of the component recorded by MigrateComponents. It is
+	// called once the stored schema of the component has been updated.
+	CompleteComponentMigration(ctx context.Context, componentName string) error
+}
+
+func (m *EntityCommandBuffer) MigrateComponents(
+	ctx context.Context, comps []types.ComponentMetadata, migrations []ComponentMigration, dryRun bool,
+) ([]int, error) {
+	counts := make([]int, len(migrations))
+	if len(migrations) == 0 {
+		return counts, nil
+	}
+
+	typeToComp := NewMapStorage[types.ComponentID, types.ComponentMetadata]()
+	for _, comp := range comps {
+		if err := typeToComp.Set(comp.ID(), comp); err != nil {
+			return nil, err
+		}
+	}
+	// The values of a component may have been migrated by a previous run that failed to update the stored schema.
+	done := make([]bool, len(migrations))
+	for i, migration := range migrations {
+		bz, err := m.dbStorage.GetBytes(ctx, storageMigratedSchemaKey(migration.Component.Name()))
+		if eris.Is(eris.Cause(err), redis.Nil) {
+			continue
+		} else if err != nil {
+			return nil, eris.Wrap(err, "failed to read component migration progress")
+		}
+		done[i] = bytes.Equal(bz, migration.Schema)
+	}
+
+	archIDToComps, ok, err := getArchIDToCompTypesFromRedis(m.dbStorage, typeToComp)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		// Nothing is saved in the DB, so there is nothing to migrate.
+		return counts, nil
+	}
+
+	migrated := map[string][]byte{}
+	for i := 0; i < archIDToComps.Len(); i++ {
+		archID := types.ArchetypeID(i)
+		archComps, err := archIDToComps.Get(archID)
+		if err != nil {
+			return nil, err
+		}
+		var ids []types.EntityID
+		for j, migration := range migrations {
+			if done[j] || !containsComponent(archComps, migration.Component) {
+				continue
+			}
+			if ids == nil {
+				if ids, err = getSavedEntitiesForArchID(ctx, m.dbStorage, archID); err != nil {
+					return nil, err
+				}
+			}
+			for _, id := range ids {
+				key := storageComponentKey(migration.Component.ID(), id)
+				bz, err := m.dbStorage.GetBytes(ctx, key)
+				if err != nil {
+					// todo: this is redis specific, should be changed to a general error on storage
+					if eris.Is(eris.Cause(err), redis.Nil) {
+						// This value has never been set, so the entity has the default value of the new component.
+						continue
+					}
+					return nil, err
+				}
+				if migrated[key], err = migration.Migrate(bz); err != nil {
+					return nil, eris.Wrapf(err, "failed to migrate component %q of entity %d",
+						migration.Component.Name(), id)
+				}
+				counts[j]++
+			}
+		}
+	}
+	if dryRun {
+		return counts, nil
+	}
+
+	pending := make([]ComponentMigration, 0, len(migrations))
+	for i, migration := range migrations {
+		if !done[i] {
+			pending = append(pending, migration)
+		}
+	}
+	if len(pending) == 0 {
+		return counts, nil
+	}
+	diffs, err := m.migrateTickDiffs(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe, err := m.dbStorage.StartTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for key, bz := range migrated {
+		if err = pipe.Set(ctx, key, bz); err != nil {
+			return nil, err
+		}
+	}
+	for key, bz := range diffs {
+		if err = pipe.Set(ctx, key, bz); err != nil {
+			return nil, err
+		}
+	}
+	for _, prefix := range []string{storageStateHashKeyPrefix, storageStateRootKeyPrefix} {
+		if err = deleteKeysWithPrefix(ctx, m.dbStorage, pipe, prefix); err != nil {
+			return nil, err
+		}
+	}
+	for _, migration := range pending {
+		if err = pipe.Set(ctx, storageMigratedSchemaKey(migration.Component.Name()), migration.Schema); err != nil {
+			return nil, err
+		}
+	}
+	if err = pipe.EndTransaction(ctx); err != nil {
+		return nil, eris.Wrap(err, "failed to save migrated components")
+	}
+
+	// The state hash and the state trees kept in memory were computed from the values before the migration.
+	m.stateHash = nil
+	m.clearStateTrees()
+	delete(m.oldestTickKeys, storageStateHashKeyPrefix)
+	delete(m.oldestTickKeys, storageStateRootKeyPrefix)
+	return counts, nil
+}
+
+// migrateTickDiffs runs the migrations on the old values of their component recorded in the tick diffs, so the state
+// of the ticks before the migration can still be read with the registered components. It returns the diffs that
+// changed, by key.
+func (m *EntityCommandBuffer) migrateTickDiffs(
+	ctx context.Context, migrations []ComponentMigration,
+) (map[string][]byte, error) {
+	migrationOf := map[types.ComponentID]ComponentMigration{}
+	for _, migration := range migrations {
+		migrationOf[migration.Component.ID()] = migration
+	}
+	changed := map[string][]byte{}
+	err := scanKeys(ctx, m.dbStorage, storageTickDiffKeyPrefix, func(keys []string) error {
+		values, err := GetManyBytesFrom(ctx, m.dbStorage, keys)
+		if err != nil {
+			return err
+		}
+		for i, diffKey := range keys {
+			if values[i] == nil {
+				continue
+			}
+			diff, err := codec.Decode[tickDiff](values[i])
+			if err != nil {
+				return eris.Wrapf(err, "failed to decode state diff %q", diffKey)
+			}
+			modified := false
+			for key, entry := range diff {
+				typeID, id, ok := parseStorageComponentKey(key)
+				migration, found := migrationOf[typeID]
+				if !ok || !found || !entry.Exists {
+					continue
+				}
+				if entry.Value, err = migration.Migrate(entry.Value); err != nil {
+					return eris.Wrapf(err, "failed to migrate component %q of entity %d in state diff %q",
+						migration.Component.Name(), id, diffKey)
+				}
+				diff[key] = entry
+				modified = true
+			}
+			if !modified {
+				continue
+			}
+			if changed[diffKey], err = codec.Encode(diff); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+// deleteKeysWithPrefix adds the deletion of every key of the storage that starts with the given prefix to the pipe.
+func deleteKeysWithPrefix(
+	ctx context.Context, storage PrimitiveStorage[string], pipe Transaction[string], prefix string,
+) error {
+	return scanKeys(ctx, storage, prefix, func(keys []string) error {
+		for _, key := range keys {
+			if err := pipe.Delete(ctx, key); err != nil {
+				return eris.Wrap(err, "")
+			}
+		}
+		return nil
+	})
+}
+
+func (m *EntityCommandBuffer) CompleteComponentMigration(ctx context.Context, componentName string) error {
+	return eris.Wrap(m.dbStorage.Delete(ctx, storageMigratedSchemaKey(componentName)), "")
+}
+
+// getSavedEntitiesForArchID returns the entities that belong to the given archetype in the saved state.
+func getSavedEntitiesForArchID(
+	ctx context.Context, storage PrimitiveStorage[string], archID types.ArchetypeID,
+) ([]types.EntityID, error) {
+	bz, err := storage.GetBytes(ctx, storageActiveEntityIDKey(archID))
+	if err != nil {
+		if eris.Is(eris.Cause(err), redis.Nil) {
+			return []types.EntityID{}, nil
+		}
+		return nil, eris.Wrap(err, "")
+	}
+	return codec.Decode[[]types.EntityID](bz)
+}
+
+func containsComponent(comps []types.ComponentMetadata, comp types.ComponentMetadata) bool {
+	for _, c := range comps {
+		if c.ID() == comp.ID() {
+			return true
+		}
+	}
+	return false
+}
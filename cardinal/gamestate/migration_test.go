@@ -0,0 +1,139 @@
+package gamestate_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+func TestComponentValuesAreNotMigratedTwice(t *testing.T) {
+	ctx := context.Background()
+	manager, client := newCmdBufferAndRedisClientForTest(t, nil)
+	ids, err := manager.CreateManyEntities(3, fooComp)
+	assert.NilError(t, err)
+	for i, id := range ids {
+		assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{i + 1}))
+	}
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	double := gamestate.ComponentMigration{
+		Component: fooComp,
+		Schema:    []byte(`{"version":2}`),
+		Migrate: func(bz []byte) ([]byte, error) {
+			value, err := fooComp.Decode(bz)
+			if err != nil {
+				return nil, err
+			}
+			return fooComp.Encode(Foo{value.(Foo).Value * 2}) //nolint:errcheck // the value is always a Foo
+		},
+	}
+	migrate := func() []int {
+		migrator, _ := newCmdBufferAndRedisClientForTest(t, client)
+		counts, err := migrator.MigrateComponents(ctx, allComponents, []gamestate.ComponentMigration{double}, false)
+		assert.NilError(t, err)
+		return counts
+	}
+	values := func() []int {
+		reader, _ := newCmdBufferAndRedisClientForTest(t, client)
+		got := make([]int, 0, len(ids))
+		for _, id := range ids {
+			value, err := reader.GetComponentForEntity(fooComp, id)
+			assert.NilError(t, err)
+			got = append(got, value.(Foo).Value) //nolint:errcheck // the value is always a Foo
+		}
+		return got
+	}
+
+	assert.DeepEqual(t, []int{3}, migrate())
+	assert.DeepEqual(t, []int{2, 4, 6}, values())
+
+	// The stored schema was not updated, e.g. because the process crashed, so the migration is run again. The values
+	// were already migrated and are left as they are.
+	assert.DeepEqual(t, []int{0}, migrate())
+	assert.DeepEqual(t, []int{2, 4, 6}, values())
+
+	// Once the migration is completed, a new migration to the same schema migrates the values again.
+	assert.NilError(t, manager.CompleteComponentMigration(ctx, fooComp.Name()))
+	assert.DeepEqual(t, []int{3}, migrate())
+	assert.DeepEqual(t, []int{4, 8, 12}, values())
+}
+
+func TestMigrationUpdatesTheStateHistory(t *testing.T) {
+	ctx := context.Background()
+	double := gamestate.ComponentMigration{
+		Component: fooComp,
+		Schema:    []byte(`{"version":2}`),
+		Migrate: func(bz []byte) ([]byte, error) {
+			value, err := fooComp.Decode(bz)
+			if err != nil {
+				return nil, err
+			}
+			return fooComp.Encode(Foo{value.(Foo).Value * 2}) //nolint:errcheck // the value is always a Foo
+		},
+	}
+	newManager := func(client *redis.Client) (*gamestate.EntityCommandBuffer, *redis.Client) {
+		manager, client := newCmdBufferAndRedisClientForTest(t, client)
+		manager.SetHistorySize(10)
+		manager.SetStateHashSize(10)
+		manager.SetStateRootSize(10)
+		return manager, client
+	}
+	// runTicks runs two ticks that set the given values, then a tick without changes.
+	runTicks := func(manager *gamestate.EntityCommandBuffer, values []int) []types.EntityID {
+		ids, err := manager.CreateManyEntities(len(values), fooComp)
+		assert.NilError(t, err)
+		for i, id := range ids {
+			assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{values[i]}))
+		}
+		assert.NilError(t, manager.FinalizeTick(ctx))
+		assert.NilError(t, manager.SetComponentForEntity(fooComp, ids[0], Foo{values[0] * 10}))
+		assert.NilError(t, manager.FinalizeTick(ctx))
+		return ids
+	}
+
+	manager, client := newManager(nil)
+	ids := runTicks(manager, []int{1, 2, 3})
+	migrator, _ := newManager(client)
+	counts, err := migrator.MigrateComponents(ctx, allComponents, []gamestate.ComponentMigration{double}, false)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []int{3}, counts)
+	assert.NilError(t, migrator.CompleteComponentMigration(ctx, fooComp.Name()))
+	manager, _ = newManager(client)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// The state hash and the state root of the tick after the migration are the ones of a game that always had the
+	// migrated values.
+	want, _ := newManager(nil)
+	runTicks(want, []int{2, 4, 6})
+	assert.NilError(t, want.FinalizeTick(ctx))
+	wantHash, err := want.StateHash(2)
+	assert.NilError(t, err)
+	gotHash, err := manager.StateHash(2)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, wantHash, gotHash)
+	wantRoot, err := want.StateRoot(2)
+	assert.NilError(t, err)
+	gotRoot, err := manager.StateRoot(2)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, wantRoot, gotRoot)
+	proof, err := manager.ProveComponent(fooComp, ids[0])
+	assert.NilError(t, err)
+	assert.Check(t, proof.Verify(gotRoot))
+
+	// The hashes and roots recorded before the migration were removed, and the old values in the state history were
+	// migrated.
+	_, err = manager.StateHash(1)
+	assert.ErrorIs(t, err, gamestate.ErrStateHashNotFound)
+	_, err = manager.StateRoot(1)
+	assert.ErrorIs(t, err, gamestate.ErrStateRootNotFound)
+	reader, err := manager.ToReadOnlyAtTick(0)
+	assert.NilError(t, err)
+	value, err := reader.GetComponentForEntity(fooComp, ids[0])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, Foo{2}, value)
+}
@@ -0,0 +1,154 @@
+package gamestate
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/storage"
+	cardinalredis "pkg.world.dev/world-engine/cardinal/storage/redis"
+)
+
+var _ storage.NonceStorage = &NonceStorage{}
+
+// NonceStorage stores the nonces used by signers in a PrimitiveStorage. It is used in place of the redis backed
+// NonceStorage when Cardinal is configured to keep its state in a non-Redis storage backend.
+//
+// The non-Redis storage backends are embedded databases that are opened by a single process, so the nonces are
+// checked and updated atomically by holding mutex while the nonces are read and written in a transaction.
+type NonceStorage struct {
+	storage PrimitiveStorage[string]
+	mutex   *sync.Mutex
+}
+
+func NewNonceStorage(storage PrimitiveStorage[string]) *NonceStorage {
+	return &NonceStorage{
+		storage: storage,
+		mutex:   &sync.Mutex{},
+	}
+}
+
+// UseNonce marks the given nonce as used. Like the redis backed NonceStorage, nonces that are
+// cardinalredis.NonceSlidingWindowSize or more below the highest nonce of the signer are rejected outright, so only
+// the nonces in the window need to be kept.
+func (n *NonceStorage) UseNonce(signerAddress string, nonce uint64) error {
+	ctx := context.Background()
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	maxNonce, err := n.getUInt64(ctx, storageMaxNonceKey(signerAddress))
+	if err != nil {
+		return eris.Wrap(err, "failed to get max nonce for signer address")
+	}
+	if nonce < maxNonce && maxNonce-nonce >= cardinalredis.NonceSlidingWindowSize {
+		return eris.New("nonce is too old")
+	}
+	_, err = n.storage.GetBytes(ctx, storageUsedNonceKey(signerAddress, nonce))
+	if err == nil {
+		return eris.Wrapf(cardinalredis.ErrNonceHasAlreadyBeenUsed, "signer %q has already used nonce %d",
+			signerAddress, nonce)
+	} else if !eris.Is(eris.Cause(err), redis.Nil) {
+		return eris.Wrap(err, "failed to check nonce")
+	}
+
+	txn, err := n.storage.StartTransaction(ctx)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	if err := txn.Set(ctx, storageUsedNonceKey(signerAddress, nonce), true); err != nil {
+		return eris.Wrap(err, "failed to add nonce")
+	}
+	if nonce > maxNonce {
+		if err := txn.Set(ctx, storageMaxNonceKey(signerAddress), nonce); err != nil {
+			return eris.Wrap(err, "failed to store max nonce")
+		}
+		// Remove the nonces that fell out of the window. The nonces below the previous window were already removed.
+		for old := windowStart(maxNonce); old < windowStart(nonce) && old <= maxNonce; old++ {
+			if err := txn.Delete(ctx, storageUsedNonceKey(signerAddress, old)); err != nil {
+				return eris.Wrap(err, "failed to remove old nonce")
+			}
+		}
+	}
+	return eris.Wrap(txn.EndTransaction(ctx), "")
+}
+
+// UseIncreasingNonce marks the given nonce as used if it is not lower than the next expected nonce of the signer, see
+// GetNextNonce. storage.ErrNonceTooLow is returned if the nonce is too low.
+func (n *NonceStorage) UseIncreasingNonce(signerAddress string, nonce uint64) error {
+	if nonce == math.MaxUint64 {
+		return eris.New("nonce is too large")
+	}
+	ctx := context.Background()
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	next, err := n.getUInt64(ctx, storageNextNonceKey(signerAddress))
+	if err != nil {
+		return eris.Wrap(err, "failed to get next nonce")
+	}
+	if nonce < next {
+		return eris.Wrapf(storage.ErrNonceTooLow, "signer %q used nonce %d, expected at least %d",
+			signerAddress, nonce, next)
+	}
+	txn, err := n.storage.StartTransaction(ctx)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	if err := txn.Set(ctx, storageNextNonceKey(signerAddress), nonce+1); err != nil {
+		return eris.Wrap(err, "failed to store next nonce")
+	}
+	return eris.Wrap(txn.EndTransaction(ctx), "")
+}
+
+// ReleaseIncreasingNonces undoes the use of the nonces from first to last of the signer with UseIncreasingNonce, if
+// the signer did not use a higher nonce since: the next expected nonce becomes first again.
+func (n *NonceStorage) ReleaseIncreasingNonces(signerAddress string, first, last uint64) error {
+	ctx := context.Background()
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	next, err := n.getUInt64(ctx, storageNextNonceKey(signerAddress))
+	if err != nil {
+		return eris.Wrap(err, "failed to get next nonce")
+	}
+	if next != last+1 {
+		return nil
+	}
+	txn, err := n.storage.StartTransaction(ctx)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	if err := txn.Set(ctx, storageNextNonceKey(signerAddress), first); err != nil {
+		return eris.Wrap(err, "failed to store next nonce")
+	}
+	return eris.Wrap(txn.EndTransaction(ctx), "")
+}
+
+// GetNextNonce returns the lowest nonce the given signer can use next with UseIncreasingNonce. It is 0 for signers
+// that never used a nonce.
+func (n *NonceStorage) GetNextNonce(signerAddress string) (uint64, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	next, err := n.getUInt64(context.Background(), storageNextNonceKey(signerAddress))
+	return next, eris.Wrap(err, "failed to get next nonce")
+}
+
+// getUInt64 returns the number stored at the given key, or 0 if the key does not exist.
+func (n *NonceStorage) getUInt64(ctx context.Context, key string) (uint64, error) {
+	value, err := n.storage.GetUInt64(ctx, key)
+	if eris.Is(eris.Cause(err), redis.Nil) {
+		return 0, nil
+	}
+	return value, err
+}
+
+// windowStart returns the lowest nonce that is not rejected outright when the highest nonce of a signer is maxNonce.
+func windowStart(maxNonce uint64) uint64 {
+	if maxNonce < cardinalredis.NonceSlidingWindowSize {
+		return 0
+	}
+	return maxNonce - cardinalredis.NonceSlidingWindowSize + 1
+}
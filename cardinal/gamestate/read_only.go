@@ -22,6 +22,8 @@ type readOnlyManager struct {
 	storage         PrimitiveStorage[string]
 	typeToComponent VolatileStorage[types.ComponentID, types.ComponentMetadata]
 	archIDToComps   VolatileStorage[types.ArchetypeID, []types.ComponentMetadata]
+	// indexes is nil for readers of historical state, which don't support index lookups.
+	indexes *indexSet
 }
 
 func (m *EntityCommandBuffer) ToReadOnly() Reader {
@@ -29,6 +31,7 @@ func (m *EntityCommandBuffer) ToReadOnly() Reader {
 		storage:         m.dbStorage,
 		typeToComponent: m.typeToComponent,
 		archIDToComps:   m.archIDToComps,
+		indexes:         m.indexes,
 	}
 }
 
@@ -49,9 +52,11 @@ func (r *readOnlyManager) refreshArchIDToCompTypes() error {
 func (r *readOnlyManager) GetComponentForEntity(
 	cType types.ComponentMetadata, id types.EntityID,
 ) (any, error) {
-	bz, err := r.GetComponentForEntityInRawJSON(cType, id)
+	ctx := context.Background()
+	key := storageComponentKey(cType.ID(), id)
+	bz, err := r.storage.GetBytes(ctx, key)
 	if err != nil {
-		return nil, err
+		return nil, eris.Wrap(err, "")
 	}
 	return cType.Decode(bz)
 }
@@ -62,7 +67,14 @@ func (r *readOnlyManager) GetComponentForEntityInRawJSON(
 	ctx := context.Background()
 	key := storageComponentKey(cType.ID(), id)
 	res, err := r.storage.GetBytes(ctx, key)
-	return res, eris.Wrap(err, "")
+	if err != nil || codec.IsJSON(cType.Codec()) {
+		return res, eris.Wrap(err, "")
+	}
+	value, err := cType.Decode(res)
+	if err != nil {
+		return nil, err
+	}
+	return codec.Encode(value)
 }
 
 func (r *readOnlyManager) getComponentsForArchID(archID types.ArchetypeID) ([]types.ComponentMetadata, error) {
@@ -159,6 +159,12 @@ func (m *EntityCommandBuffer) makePipeOfRedisCommands(ctx context.Context) (Prim
 		span.RecordError(err)
 		return nil, err
 	}
+	if m.stateHashSize > 0 || m.stateRootSize > 0 || m.activeSnapshot != nil {
+		pipe = newWriteRecorder(pipe)
+	}
+	if m.historySize > 0 {
+		pipe = newDiffRecorder(pipe, m.dbStorage)
+	}
 
 	if m.typeToComponent == nil {
 		err := eris.New("must call RegisterComponents before flushing to DB")
@@ -176,6 +182,8 @@ func (m *EntityCommandBuffer) makePipeOfRedisCommands(ctx context.Context) (Prim
 		{"pending_arch_ids", m.addPendingArchIDsToPipe},
 		{"entity_id_to_arch_id", m.addEntityIDToArchIDToPipe},
 		{"active_entity_ids", m.addActiveEntityIDsToPipe},
+		{"relationships", m.addRelationshipsToPipe},
+		{"resources", m.addResourcesToPipe},
 	}
 
 	for _, operation := range operations {
@@ -0,0 +1,311 @@
+package gamestate
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+var (
+	_ RelationshipWriter = &EntityCommandBuffer{}
+	_ RelationshipReader = &EntityCommandBuffer{}
+	_ RelationshipReader = &readOnlyManager{}
+)
+
+// RelationshipReader is implemented by readers that can look up the parent and children of entities.
+type RelationshipReader interface {
+	// GetParent returns the parent of the given entity. The returned bool is false if the entity has no parent.
+	GetParent(id types.EntityID) (types.EntityID, bool, error)
+	// GetChildren returns the sorted IDs of the children of the given entity.
+	GetChildren(id types.EntityID) ([]types.EntityID, error)
+}
+
+// RelationshipWriter is implemented by managers that can maintain parent/child relationships between entities. When an
+// entity is removed, its children are removed as well.
+type RelationshipWriter interface {
+	// SetParent makes parent the parent of child, replacing its current parent.
+	SetParent(child, parent types.EntityID) error
+	// RemoveParent detaches child from its parent, if it has one.
+	RemoveParent(child types.EntityID) error
+}
+
+// parentEntry is the parent of an entity, loaded from storage or changed in the current tick.
+type parentEntry struct {
+	parent    types.EntityID
+	hasParent bool
+	modified  bool
+}
+
+// childrenEntry is the sorted list of children of an entity, loaded from storage or changed in the current tick.
+type childrenEntry struct {
+	ids      []types.EntityID
+	modified bool
+}
+
+// SetParent makes parent the parent of child. An error is returned if either entity does not exist, or if child is
+// parent or one of its ancestors.
+func (m *EntityCommandBuffer) SetParent(child, parent types.EntityID) error {
+	for _, id := range []types.EntityID{child, parent} {
+		if _, err := m.getArchetypeForEntity(id); err != nil {
+			return err
+		}
+	}
+	for curr, ok := parent, true; ok; {
+		if curr == child {
+			return eris.Wrapf(ErrRelationshipCycle, "entity %d cannot be the parent of entity %d", parent, child)
+		}
+		entry, err := m.getParentEntry(curr)
+		if err != nil {
+			return err
+		}
+		curr, ok = entry.parent, entry.hasParent
+	}
+
+	old, err := m.getParentEntry(child)
+	if err != nil {
+		return err
+	}
+	if old.hasParent {
+		if old.parent == parent {
+			return nil
+		}
+		if err = m.removeChild(old.parent, child); err != nil {
+			return err
+		}
+	}
+	children, err := m.getChildrenEntry(parent)
+	if err != nil {
+		return err
+	}
+	if i, found := slices.BinarySearch(children.ids, child); !found {
+		children.ids = slices.Insert(slices.Clone(children.ids), i, child)
+	}
+	if err = m.setChildrenEntry(parent, children); err != nil {
+		return err
+	}
+	return m.setParentEntry(child, parentEntry{parent: parent, hasParent: true, modified: true})
+}
+
+// RemoveParent detaches child from its parent. Nothing happens if child has no parent.
+func (m *EntityCommandBuffer) RemoveParent(child types.EntityID) error {
+	if _, err := m.getArchetypeForEntity(child); err != nil {
+		return err
+	}
+	old, err := m.getParentEntry(child)
+	if err != nil {
+		return err
+	}
+	if !old.hasParent {
+		return nil
+	}
+	if err = m.removeChild(old.parent, child); err != nil {
+		return err
+	}
+	return m.setParentEntry(child, parentEntry{parent: 0, hasParent: false, modified: true})
+}
+
+// GetParent returns the parent of the given entity, including the changes made in the current tick.
+func (m *EntityCommandBuffer) GetParent(id types.EntityID) (types.EntityID, bool, error) {
+	if _, err := m.getArchetypeForEntity(id); err != nil {
+		return 0, false, err
+	}
+	entry, err := m.getParentEntry(id)
+	if err != nil {
+		return 0, false, err
+	}
+	return entry.parent, entry.hasParent, nil
+}
+
+// GetChildren returns the sorted IDs of the children of the given entity, including the changes made in the current
+// tick.
+func (m *EntityCommandBuffer) GetChildren(id types.EntityID) ([]types.EntityID, error) {
+	if _, err := m.getArchetypeForEntity(id); err != nil {
+		return nil, err
+	}
+	entry, err := m.getChildrenEntry(id)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(entry.ids), nil
+}
+
+// removeEntityRelationships detaches a removed entity from its parent, and removes all of its children.
+func (m *EntityCommandBuffer) removeEntityRelationships(id types.EntityID) error {
+	parent, err := m.getParentEntry(id)
+	if err != nil {
+		return err
+	}
+	if parent.hasParent {
+		if err = m.removeChild(parent.parent, id); err != nil {
+			return err
+		}
+		if err = m.setParentEntry(id, parentEntry{parent: 0, hasParent: false, modified: true}); err != nil {
+			return err
+		}
+	}
+
+	children, err := m.getChildrenEntry(id)
+	if err != nil {
+		return err
+	}
+	if len(children.ids) == 0 {
+		return nil
+	}
+	if err = m.setChildrenEntry(id, childrenEntry{ids: nil, modified: true}); err != nil {
+		return err
+	}
+	for _, child := range children.ids {
+		if err = m.RemoveEntity(child); err != nil {
+			return eris.Wrapf(err, "failed to remove child %d of entity %d", child, id)
+		}
+	}
+	return nil
+}
+
+func (m *EntityCommandBuffer) removeChild(parent, child types.EntityID) error {
+	children, err := m.getChildrenEntry(parent)
+	if err != nil {
+		return err
+	}
+	i, found := slices.BinarySearch(children.ids, child)
+	if !found {
+		return nil
+	}
+	children.ids = slices.Delete(slices.Clone(children.ids), i, i+1)
+	return m.setChildrenEntry(parent, children)
+}
+
+func (m *EntityCommandBuffer) getParentEntry(id types.EntityID) (parentEntry, error) {
+	if entry, err := m.entityParents.Get(id); err == nil {
+		return entry, nil
+	}
+	parent, hasParent, err := loadParent(m.dbStorage, id)
+	if err != nil {
+		return parentEntry{}, err
+	}
+	entry := parentEntry{parent: parent, hasParent: hasParent, modified: false}
+	return entry, m.entityParents.Set(id, entry)
+}
+
+func (m *EntityCommandBuffer) setParentEntry(id types.EntityID, entry parentEntry) error {
+	entry.modified = true
+	return m.entityParents.Set(id, entry)
+}
+
+func (m *EntityCommandBuffer) getChildrenEntry(id types.EntityID) (childrenEntry, error) {
+	if entry, err := m.entityChildren.Get(id); err == nil {
+		return entry, nil
+	}
+	ids, err := loadChildren(m.dbStorage, id)
+	if err != nil {
+		return childrenEntry{}, err
+	}
+	entry := childrenEntry{ids: ids, modified: false}
+	return entry, m.entityChildren.Set(id, entry)
+}
+
+func (m *EntityCommandBuffer) setChildrenEntry(id types.EntityID, entry childrenEntry) error {
+	entry.modified = true
+	return m.entityChildren.Set(id, entry)
+}
+
+// addRelationshipsToPipe adds the changed parents and children of entities to the redis pipe.
+func (m *EntityCommandBuffer) addRelationshipsToPipe(ctx context.Context, pipe PrimitiveStorage[string]) error {
+	ids, err := m.entityParents.Keys()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		entry, err := m.entityParents.Get(id)
+		if err != nil {
+			return err
+		}
+		if !entry.modified {
+			continue
+		}
+		key := storageParentKey(id)
+		if !entry.hasParent {
+			err = pipe.Delete(ctx, key)
+		} else {
+			err = pipe.Set(ctx, key, uint64(entry.parent))
+		}
+		if err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+
+	ids, err = m.entityChildren.Keys()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		entry, err := m.entityChildren.Get(id)
+		if err != nil {
+			return err
+		}
+		if !entry.modified {
+			continue
+		}
+		key := storageChildrenKey(id)
+		if len(entry.ids) == 0 {
+			if err = pipe.Delete(ctx, key); err != nil {
+				return eris.Wrap(err, "")
+			}
+			continue
+		}
+		bz, err := codec.Encode(entry.ids)
+		if err != nil {
+			return err
+		}
+		if err = pipe.Set(ctx, key, bz); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	return nil
+}
+
+// GetParent returns the parent of the given entity as of the last finalized tick.
+func (r *readOnlyManager) GetParent(id types.EntityID) (types.EntityID, bool, error) {
+	if _, err := r.GetComponentTypesForEntity(id); err != nil {
+		return 0, false, err
+	}
+	return loadParent(r.storage, id)
+}
+
+// GetChildren returns the sorted IDs of the children of the given entity as of the last finalized tick.
+func (r *readOnlyManager) GetChildren(id types.EntityID) ([]types.EntityID, error) {
+	if _, err := r.GetComponentTypesForEntity(id); err != nil {
+		return nil, err
+	}
+	return loadChildren(r.storage, id)
+}
+
+func loadParent(storage PrimitiveStorage[string], id types.EntityID) (types.EntityID, bool, error) {
+	parent, err := storage.GetUInt64(context.Background(), storageParentKey(id))
+	if err != nil {
+		// todo: this is redis specific, should be changed to a general error on storage
+		if errors.Is(err, redis.Nil) {
+			return 0, false, nil
+		}
+		return 0, false, eris.Wrap(err, "")
+	}
+	return types.EntityID(parent), true, nil
+}
+
+func loadChildren(storage PrimitiveStorage[string], id types.EntityID) ([]types.EntityID, error) {
+	bz, err := storage.GetBytes(context.Background(), storageChildrenKey(id))
+	if err != nil {
+		// todo: this is redis specific, should be changed to a general error on storage
+		if errors.Is(err, redis.Nil) {
+			return []types.EntityID{}, nil
+		}
+		return nil, eris.Wrap(err, "")
+	}
+	return codec.Decode[[]types.EntityID](bz)
+}
@@ -0,0 +1,51 @@
+package gamestate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+func TestRelationshipsAreDiscardedAndSaved(t *testing.T) {
+	ctx := context.Background()
+	manager, client := newCmdBufferAndRedisClientForTest(t, nil)
+	ids, err := manager.CreateManyEntities(3, fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	assert.NilError(t, manager.SetParent(ids[1], ids[0]))
+	assert.NilError(t, manager.DiscardPending())
+	children, err := manager.GetChildren(ids[0])
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(children))
+
+	assert.NilError(t, manager.SetParent(ids[1], ids[0]))
+	assert.NilError(t, manager.SetParent(ids[2], ids[1]))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// The relationships are loaded from storage by a new manager.
+	restarted, _ := newCmdBufferAndRedisClientForTest(t, client)
+	children, err = restarted.GetChildren(ids[0])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{ids[1]}, children)
+	parent, ok, err := restarted.ToReadOnly().(gamestate.RelationshipReader).GetParent(ids[2])
+	assert.NilError(t, err)
+	assert.Check(t, ok)
+	assert.Equal(t, ids[1], parent)
+
+	// Removing the root removes the whole tree, and cleans up the saved relationships.
+	assert.NilError(t, restarted.RemoveEntity(ids[0]))
+	assert.NilError(t, restarted.FinalizeTick(ctx))
+	keys, err := client.Keys(ctx, "ECB:*PARENT*").Result()
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(keys))
+	keys, err = client.Keys(ctx, "ECB:CHILDREN:*").Result()
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(keys))
+	_, err = restarted.GetComponentTypesForEntity(ids[2])
+	assert.Check(t, errors.Is(err, gamestate.ErrEntityDoesNotExist))
+}
@@ -0,0 +1,118 @@
+package gamestate
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+var (
+	_ ResourceWriter = &EntityCommandBuffer{}
+	_ ResourceReader = &EntityCommandBuffer{}
+	_ ResourceReader = &readOnlyManager{}
+)
+
+// ResourceReader is implemented by readers that can read world resources. Resources are world-global values that are
+// not attached to an entity. They are described by a ComponentMetadata, which is not registered as a component.
+type ResourceReader interface {
+	// GetResource returns the value of the resource, or its default value if it was never set.
+	GetResource(resource types.ComponentMetadata) (any, error)
+	// GetResourceInRawJSON returns the value of the resource as JSON.
+	GetResourceInRawJSON(resource types.ComponentMetadata) (json.RawMessage, error)
+}
+
+// ResourceWriter is implemented by managers that can change world resources. Changes are saved when the tick is
+// finalized, like component values.
+type ResourceWriter interface {
+	SetResource(resource types.ComponentMetadata, value any) error
+}
+
+// resourceEntry is the value of a resource, loaded from storage or changed in the current tick.
+type resourceEntry struct {
+	resource types.ComponentMetadata
+	value    any
+	modified bool
+}
+
+func (m *EntityCommandBuffer) GetResource(resource types.ComponentMetadata) (any, error) {
+	if entry, err := m.resources.Get(resource.Name()); err == nil {
+		return entry.value, nil
+	}
+	value, err := loadResource(m.dbStorage, resource)
+	if err != nil {
+		return nil, err
+	}
+	entry := resourceEntry{resource: resource, value: value, modified: false}
+	return value, m.resources.Set(resource.Name(), entry)
+}
+
+func (m *EntityCommandBuffer) GetResourceInRawJSON(resource types.ComponentMetadata) (json.RawMessage, error) {
+	value, err := m.GetResource(resource)
+	if err != nil {
+		return nil, err
+	}
+	return encodeJSON(resource, value)
+}
+
+func (m *EntityCommandBuffer) SetResource(resource types.ComponentMetadata, value any) error {
+	entry := resourceEntry{resource: resource, value: value, modified: true}
+	return m.resources.Set(resource.Name(), entry)
+}
+
+// addResourcesToPipe adds the changed resources to the redis pipe.
+func (m *EntityCommandBuffer) addResourcesToPipe(ctx context.Context, pipe PrimitiveStorage[string]) error {
+	names, err := m.resources.Keys()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		entry, err := m.resources.Get(name)
+		if err != nil {
+			return err
+		}
+		if !entry.modified {
+			continue
+		}
+		bz, err := entry.resource.Encode(entry.value)
+		if err != nil {
+			return err
+		}
+		if err = pipe.Set(ctx, storageResourceKey(name), bz); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	return nil
+}
+
+// GetResource returns the value of the resource as of the last finalized tick.
+func (r *readOnlyManager) GetResource(resource types.ComponentMetadata) (any, error) {
+	return loadResource(r.storage, resource)
+}
+
+// GetResourceInRawJSON returns the value of the resource as JSON as of the last finalized tick.
+func (r *readOnlyManager) GetResourceInRawJSON(resource types.ComponentMetadata) (json.RawMessage, error) {
+	value, err := loadResource(r.storage, resource)
+	if err != nil {
+		return nil, err
+	}
+	return encodeJSON(resource, value)
+}
+
+// loadResource reads the value of the resource from storage, or returns its default value if it was never set.
+func loadResource(storage PrimitiveStorage[string], resource types.ComponentMetadata) (any, error) {
+	bz, err := storage.GetBytes(context.Background(), storageResourceKey(resource.Name()))
+	if err != nil {
+		// todo: this is redis specific, should be changed to a general error on storage
+		if !eris.Is(eris.Cause(err), redis.Nil) {
+			return nil, eris.Wrap(err, "")
+		}
+		if bz, err = resource.New(); err != nil {
+			return nil, err
+		}
+	}
+	return resource.Decode(bz)
+}
@@ -0,0 +1,36 @@
+package gamestate
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	cardinalredis "pkg.world.dev/world-engine/cardinal/storage/redis"
+)
+
+// SchemaStorage stores component schemas in a PrimitiveStorage. It is used in place of the redis backed
+// SchemaStorage when Cardinal is configured to keep its state in a non-Redis storage backend.
+type SchemaStorage struct {
+	storage PrimitiveStorage[string]
+}
+
+func NewSchemaStorage(storage PrimitiveStorage[string]) *SchemaStorage {
+	return &SchemaStorage{
+		storage: storage,
+	}
+}
+
+func (s *SchemaStorage) GetSchema(componentName string) ([]byte, error) {
+	bz, err := s.storage.GetBytes(context.Background(), storageComponentSchemaKey(componentName))
+	if eris.Is(eris.Cause(err), redis.Nil) {
+		return nil, eris.Wrap(err, cardinalredis.ErrNoSchemaFound.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	return bz, nil
+}
+
+func (s *SchemaStorage) SetSchema(componentName string, schemaData []byte) error {
+	return s.storage.Set(context.Background(), storageComponentSchemaKey(componentName), schemaData)
+}
@@ -0,0 +1,243 @@
+package gamestate
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"go.opentelemetry.io/otel/codes"
+	ddotel "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentelemetry"
+	ddtracer "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+)
+
+// SnapshotVersion is the version of the snapshot format produced by this version of Cardinal. It must be incremented
+// whenever the layout of the ECB keys changes in a way that makes older snapshots unusable.
+const SnapshotVersion = 1
+
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+var _ Snapshotter = &EntityCommandBuffer{}
+
+// Snapshot is a full copy of the finalized ECB state (entities, archetypes, component values and the last finalized
+// tick) at a point in time.
+type Snapshot struct {
+	Version int `json:"version"`
+	// Tick is the last finalized tick of the state in this snapshot, i.e. the next tick to be executed after restoring.
+	Tick      uint64 `json:"tick"`
+	CreatedAt int64  `json:"createdAt"`
+	// Entries maps the raw ECB storage keys to their stored values.
+	Entries map[string][]byte `json:"entries"`
+}
+
+// Snapshotter is implemented by game state managers that are able to take and restore snapshots of their state.
+type Snapshotter interface {
+	// Snapshot returns a snapshot of the finalized state.
+	Snapshot(ctx context.Context) (*Snapshot, error)
+	// StartSnapshot starts a snapshot of the finalized state as it is now. The returned function reads the snapshot; it
+	// can be called from another goroutine, while more ticks are finalized.
+	StartSnapshot(ctx context.Context) (func(ctx context.Context) (*Snapshot, error), error)
+	RestoreSnapshot(ctx context.Context, snapshot *Snapshot) error
+}
+
+// SnapshotStore persists snapshots so the game state can be restored without replaying every tick from the base shard.
+type SnapshotStore interface {
+	// Save stores the given snapshot.
+	Save(ctx context.Context, snapshot *Snapshot) error
+	// Latest returns the snapshot with the highest tick. ErrSnapshotNotFound is returned if there are no snapshots.
+	Latest(ctx context.Context) (*Snapshot, error)
+}
+
+// Snapshot copies all finalized ECB state from the underlying storage. Pending (non-finalized) changes are not
+// included.
+func (m *EntityCommandBuffer) Snapshot(ctx context.Context) (*Snapshot, error) {
+	read, err := m.StartSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return read(ctx)
+}
+
+// StartSnapshot starts a snapshot of the finalized ECB state. The state is read from storage by the returned function,
+// which does not block ticks from being finalized: until it returns, FinalizeTick keeps the values that the keys it
+// changes had when the snapshot started (see activeSnapshot), and these values take precedence over the ones read from
+// storage. Only one snapshot can be taken at a time.
+func (m *EntityCommandBuffer) StartSnapshot(context.Context) (func(ctx context.Context) (*Snapshot, error), error) {
+	m.snapshotMutex.Lock()
+	defer m.snapshotMutex.Unlock()
+	if m.activeSnapshot != nil {
+		return nil, eris.New("a snapshot is already being taken")
+	}
+	tick, err := m.GetLastFinalizedTick()
+	if err != nil {
+		return nil, err
+	}
+	m.activeSnapshot = tickDiff{}
+
+	return func(ctx context.Context) (*Snapshot, error) {
+		ctx, span := m.tracer.Start(ddotel.ContextWithStartOptions(ctx, ddtracer.Measured()), "ecb.snapshot")
+		defer span.End()
+
+		snapshot, err := m.readSnapshot(ctx, tick)
+		if err != nil {
+			span.SetStatus(codes.Error, eris.ToString(err, true))
+			span.RecordError(err)
+			return nil, err
+		}
+		return snapshot, nil
+	}, nil
+}
+
+// readSnapshot reads the snapshot started by StartSnapshot at the given tick.
+func (m *EntityCommandBuffer) readSnapshot(ctx context.Context, tick uint64) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		Version:   SnapshotVersion,
+		Tick:      tick,
+		CreatedAt: time.Now().UnixMilli(),
+		Entries:   map[string][]byte{},
+	}
+	err := scanKeys(ctx, m.dbStorage, storageECBKeyPrefix, func(keys []string) error {
+		keys = slices.DeleteFunc(keys, func(key string) bool {
+			return !isSnapshotKey(key)
+		})
+		values, err := GetManyBytesFrom(ctx, m.dbStorage, keys)
+		if err != nil {
+			return err
+		}
+		for i, key := range keys {
+			if values[i] != nil {
+				snapshot.Entries[key] = values[i]
+			}
+		}
+		return nil
+	})
+
+	m.snapshotMutex.Lock()
+	defer m.snapshotMutex.Unlock()
+	changed := m.activeSnapshot
+	m.activeSnapshot = nil
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to read snapshot")
+	}
+
+	// Undo the changes of the ticks that were finalized while the keys were read.
+	for key, entry := range changed {
+		if entry.Exists {
+			snapshot.Entries[key] = entry.Value
+		} else {
+			delete(snapshot.Entries, key)
+		}
+	}
+	snapshot.Entries[storageLastFinalizedTickKey()] = []byte(strconv.FormatUint(tick, 10))
+	return snapshot, nil
+}
+
+// recordSnapshotChanges keeps the values that keys had when the snapshot in progress started, given the values the
+// keys have before the tick that is being finalized changes them.
+func (m *EntityCommandBuffer) recordSnapshotChanges(previous tickDiff) {
+	for key, entry := range previous {
+		if _, ok := m.activeSnapshot[key]; !ok {
+			m.activeSnapshot[key] = entry
+		}
+	}
+}
+
+// RestoreSnapshot replaces all ECB state in the underlying storage with the contents of the given snapshot. Any
+// pending state changes are discarded.
+func (m *EntityCommandBuffer) RestoreSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	ctx, span := m.tracer.Start(ddotel.ContextWithStartOptions(ctx, ddtracer.Measured()), "ecb.snapshot.restore")
+	defer span.End()
+
+	if err := m.restoreSnapshot(ctx, snapshot); err != nil {
+		span.SetStatus(codes.Error, eris.ToString(err, true))
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+func (m *EntityCommandBuffer) restoreSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	if snapshot.Version != SnapshotVersion {
+		return eris.Errorf("unsupported snapshot version %d, expected %d", snapshot.Version, SnapshotVersion)
+	}
+
+	if err := m.DiscardPending(); err != nil {
+		return eris.Wrap(err, "failed to discard pending state changes")
+	}
+
+	// The state that is replaced includes the state diffs, hashes and roots, which do not apply to the restored state.
+	var oldKeys []string
+	err := scanKeys(ctx, m.dbStorage, storageECBKeyPrefix, func(keys []string) error {
+		for _, key := range keys {
+			if _, ok := snapshot.Entries[key]; !ok && !isSchemaKey(key) {
+				oldKeys = append(oldKeys, key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return eris.Wrap(err, "failed to list storage keys")
+	}
+
+	pipe, err := m.dbStorage.StartTransaction(ctx)
+	if err != nil {
+		return eris.Wrap(err, "failed to start transaction")
+	}
+	for _, key := range oldKeys {
+		if err := pipe.Delete(ctx, key); err != nil {
+			return eris.Wrapf(err, "failed to delete key %q", key)
+		}
+	}
+	for key, value := range snapshot.Entries {
+		if !isSnapshotKey(key) {
+			return eris.Errorf("snapshot contains unexpected key %q", key)
+		}
+		if err := pipe.Set(ctx, key, value); err != nil {
+			return eris.Wrapf(err, "failed to set key %q", key)
+		}
+	}
+	if err := pipe.EndTransaction(ctx); err != nil {
+		return eris.Wrap(err, "failed to end transaction")
+	}
+
+	// The archetypes, entity IDs, state tree, state hash and per-tick keys that were loaded before the restore are no
+	// longer valid.
+	m.clearStateTrees()
+	m.stateHash = nil
+	clear(m.oldestTickKeys)
+	m.isEntityIDLoaded = false
+	if err := m.entityIDToArchID.Clear(); err != nil {
+		return err
+	}
+	if m.typeToComponent != nil {
+		if err := m.archIDToComps.Clear(); err != nil {
+			return err
+		}
+		if err := m.loadArchIDs(); err != nil {
+			return err
+		}
+		return m.buildIndexes()
+	}
+	return nil
+}
+
+// isSnapshotKey returns true if the given key is part of the ECB state that is captured in a snapshot. Component
+// schemas are registered by the game at startup, and the state diffs, hashes and roots are derived from the state, so
+// they are not part of the snapshot.
+func isSnapshotKey(key string) bool {
+	return strings.HasPrefix(key, storageECBKeyPrefix) && !isSchemaKey(key) && !isDerivedKey(key)
+}
+
+func isSchemaKey(key string) bool {
+	return strings.HasPrefix(key, storageComponentSchemaKeyPrefix)
+}
+
+// isDerivedKey returns true if the given key holds data that is derived from the ECB state of each tick.
+func isDerivedKey(key string) bool {
+	return strings.HasPrefix(key, storageTickDiffKeyPrefix) ||
+		strings.HasPrefix(key, storageStateHashKeyPrefix) ||
+		strings.HasPrefix(key, storageStateRootKeyPrefix)
+}
@@ -0,0 +1,135 @@
+package gamestate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rotisserie/eris"
+)
+
+const (
+	snapshotFilePrefix = "snapshot-"
+	snapshotFileSuffix = ".json"
+
+	// DefaultSnapshotsToRetain is the number of snapshot files a FileSnapshotStore keeps on disk by default.
+	DefaultSnapshotsToRetain = 3
+)
+
+var _ SnapshotStore = &FileSnapshotStore{}
+
+// FileSnapshotStore stores each snapshot as a JSON file in a directory. Only the most recent snapshots are kept.
+type FileSnapshotStore struct {
+	dir    string
+	retain int
+}
+
+// NewFileSnapshotStore creates a SnapshotStore that writes snapshots to the given directory, creating it if needed.
+// At most `retain` snapshots are kept on disk; older snapshots are deleted after a new snapshot is saved.
+func NewFileSnapshotStore(dir string, retain int) (*FileSnapshotStore, error) {
+	if retain < 1 {
+		return nil, eris.Errorf("must retain at least 1 snapshot, got %d", retain)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gomnd // standard directory permissions
+		return nil, eris.Wrapf(err, "failed to create snapshot directory %q", dir)
+	}
+	return &FileSnapshotStore{
+		dir:    dir,
+		retain: retain,
+	}, nil
+}
+
+func (f *FileSnapshotStore) Save(_ context.Context, snapshot *Snapshot) error {
+	bz, err := json.Marshal(snapshot)
+	if err != nil {
+		return eris.Wrap(err, "failed to marshal snapshot")
+	}
+
+	// Write to a temporary file first so a crash in the middle of a write never leaves a partial snapshot behind.
+	tmp, err := os.CreateTemp(f.dir, snapshotFilePrefix+"*.tmp")
+	if err != nil {
+		return eris.Wrap(err, "failed to create snapshot file")
+	}
+	if _, err := tmp.Write(bz); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return eris.Wrap(err, "failed to write snapshot file")
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return eris.Wrap(err, "failed to close snapshot file")
+	}
+	if err := os.Rename(tmp.Name(), f.path(snapshot.Tick)); err != nil {
+		_ = os.Remove(tmp.Name())
+		return eris.Wrap(err, "failed to rename snapshot file")
+	}
+
+	return f.prune()
+}
+
+func (f *FileSnapshotStore) Latest(_ context.Context) (*Snapshot, error) {
+	ticks, err := f.ticks()
+	if err != nil {
+		return nil, err
+	}
+	if len(ticks) == 0 {
+		return nil, eris.Wrapf(ErrSnapshotNotFound, "no snapshots in %q", f.dir)
+	}
+
+	bz, err := os.ReadFile(f.path(ticks[len(ticks)-1]))
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to read snapshot file")
+	}
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(bz, snapshot); err != nil {
+		return nil, eris.Wrap(err, "failed to unmarshal snapshot")
+	}
+	return snapshot, nil
+}
+
+func (f *FileSnapshotStore) path(tick uint64) string {
+	return filepath.Join(f.dir, fmt.Sprintf("%s%020d%s", snapshotFilePrefix, tick, snapshotFileSuffix))
+}
+
+// ticks returns the ticks of all the snapshots in the directory in ascending order.
+func (f *FileSnapshotStore) ticks() ([]uint64, error) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to read snapshot directory %q", f.dir)
+	}
+	ticks := make([]uint64, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, snapshotFilePrefix) || !strings.HasSuffix(name, snapshotFileSuffix) {
+			continue
+		}
+		tick, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, snapshotFilePrefix),
+			snapshotFileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		ticks = append(ticks, tick)
+	}
+	slices.Sort(ticks)
+	return ticks, nil
+}
+
+// prune deletes the oldest snapshots until only f.retain snapshots are left.
+func (f *FileSnapshotStore) prune() error {
+	ticks, err := f.ticks()
+	if err != nil {
+		return err
+	}
+	for len(ticks) > f.retain {
+		if err := os.Remove(f.path(ticks[0])); err != nil {
+			return eris.Wrap(err, "failed to remove old snapshot file")
+		}
+		ticks = ticks[1:]
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package gamestate_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+)
+
+func TestSnapshotCanBeRestoredToNewStorage(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+
+	ids, err := manager.CreateManyEntities(5, fooComp)
+	assert.NilError(t, err)
+	for i, id := range ids {
+		assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{i}))
+	}
+	barID, err := manager.CreateEntity(fooComp, barComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(barComp, barID, Bar{100}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// Pending changes are not part of the snapshot
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, ids[0], Foo{999}))
+
+	snapshot, err := manager.Snapshot(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, gamestate.SnapshotVersion, snapshot.Version)
+	assert.Equal(t, uint64(1), snapshot.Tick)
+
+	// Restore the snapshot into a manager that already has some unrelated state
+	other := newCmdBufferForTest(t)
+	_, err = other.CreateManyEntities(20, barComp)
+	assert.NilError(t, err)
+	assert.NilError(t, other.FinalizeTick(ctx))
+	assert.NilError(t, other.FinalizeTick(ctx))
+	assert.NilError(t, other.RestoreSnapshot(ctx, snapshot))
+
+	tick, err := other.GetLastFinalizedTick()
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), tick)
+	for i, id := range ids {
+		got, err := other.GetComponentForEntity(fooComp, id)
+		assert.NilError(t, err)
+		assert.Equal(t, Foo{i}, got)
+	}
+	gotBar, err := other.GetComponentForEntity(barComp, barID)
+	assert.NilError(t, err)
+	assert.Equal(t, Bar{100}, gotBar)
+
+	// The entities that only existed before the restore are gone
+	_, err = other.GetComponentForEntity(barComp, barID+1)
+	assert.IsError(t, err)
+
+	nextID, err := other.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.Equal(t, barID+1, nextID)
+}
+
+func TestSnapshotIsNotChangedByTicksFinalizedWhileItIsRead(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+	manager.SetHistorySize(10)
+	manager.SetStateHashSize(10)
+
+	ids, err := manager.CreateManyEntities(3, fooComp)
+	assert.NilError(t, err)
+	for i, id := range ids {
+		assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{i}))
+	}
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	read, err := manager.StartSnapshot(ctx)
+	assert.NilError(t, err)
+	_, err = manager.StartSnapshot(ctx)
+	assert.ErrorContains(t, err, "already being taken")
+
+	// Change, remove and create entities before the snapshot is read.
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, ids[0], Foo{100}))
+	assert.NilError(t, manager.RemoveEntity(ids[1]))
+	_, err = manager.CreateEntity(fooComp, barComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	snapshot, err := read(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), snapshot.Tick)
+	for key := range snapshot.Entries {
+		assert.Check(t, !strings.HasPrefix(key, "ECB:TICK-DIFF:") && !strings.HasPrefix(key, "ECB:STATE-HASH:"),
+			"derived key %q is part of the snapshot", key)
+	}
+
+	other := newCmdBufferForTest(t)
+	assert.NilError(t, other.RestoreSnapshot(ctx, snapshot))
+	for i, id := range ids {
+		got, err := other.GetComponentForEntity(fooComp, id)
+		assert.NilError(t, err)
+		assert.Equal(t, Foo{i}, got)
+	}
+	nextID, err := other.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.Equal(t, ids[2]+1, nextID)
+
+	// Another snapshot can be taken once the previous one is read.
+	snapshot, err = manager.Snapshot(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(2), snapshot.Tick)
+}
+
+func TestCannotRestoreSnapshotWithUnknownVersion(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	err := manager.RestoreSnapshot(context.Background(), &gamestate.Snapshot{Version: gamestate.SnapshotVersion + 1})
+	assert.ErrorContains(t, err, "unsupported snapshot version")
+}
+
+func TestFileSnapshotStoreKeepsLatestSnapshots(t *testing.T) {
+	ctx := context.Background()
+	store, err := gamestate.NewFileSnapshotStore(t.TempDir(), 2)
+	assert.NilError(t, err)
+
+	_, err = store.Latest(ctx)
+	assert.Check(t, errors.Is(err, gamestate.ErrSnapshotNotFound))
+
+	for _, tick := range []uint64{10, 30, 20} {
+		err = store.Save(ctx, &gamestate.Snapshot{
+			Version: gamestate.SnapshotVersion,
+			Tick:    tick,
+			Entries: map[string][]byte{"ECB:LAST-FINALIZED-TICK": []byte("x")},
+		})
+		assert.NilError(t, err)
+	}
+
+	latest, err := store.Latest(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(30), latest.Tick)
+	assert.Equal(t, "x", string(latest.Entries["ECB:LAST-FINALIZED-TICK"]))
+}
@@ -0,0 +1,372 @@
+package gamestate
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// SpatialIndexDefinition describes a spatial index on the position stored in a component.
+type SpatialIndexDefinition struct {
+	Component types.ComponentMetadata
+	// CellSize is the width and height of the cells of the grid the entities are bucketed in. Lookups are fastest when
+	// it is close to the usual search radius.
+	CellSize float64
+	// Position extracts the position from a component value.
+	Position func(component any) (Point, error)
+}
+
+// Point is a position in a spatial index.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Rect is an axis aligned rectangle. It includes its edges.
+type Rect struct {
+	Min Point
+	Max Point
+}
+
+func (r Rect) contains(p Point) bool {
+	return p.X >= r.Min.X && p.X <= r.Max.X && p.Y >= r.Min.Y && p.Y <= r.Max.Y
+}
+
+func (p Point) distanceSquared(other Point) float64 {
+	dx, dy := p.X-other.X, p.Y-other.Y
+	return dx*dx + dy*dy
+}
+
+type gridCell struct {
+	x int64
+	y int64
+}
+
+type spatialEntry struct {
+	id       types.EntityID
+	position Point
+}
+
+// pendingSpatialEntry is the position of an entity in the current tick.
+type pendingSpatialEntry struct {
+	position Point
+	removed  bool
+}
+
+// spatialIndex buckets entities in a uniform grid by their position. committed and cells reflect the saved state, and
+// pending holds the changes made in the current tick, which are applied once the tick is finalized.
+type spatialIndex struct {
+	def       SpatialIndexDefinition
+	committed map[types.EntityID]Point
+	cells     map[gridCell]map[types.EntityID]struct{}
+	pending   map[types.EntityID]pendingSpatialEntry
+}
+
+func newSpatialIndex(def SpatialIndexDefinition) *spatialIndex {
+	return &spatialIndex{
+		def:       def,
+		committed: map[types.EntityID]Point{},
+		cells:     map[gridCell]map[types.EntityID]struct{}{},
+		pending:   map[types.EntityID]pendingSpatialEntry{},
+	}
+}
+
+func (idx *spatialIndex) cellOf(p Point) gridCell {
+	return gridCell{
+		x: int64(math.Floor(p.X / idx.def.CellSize)),
+		y: int64(math.Floor(p.Y / idx.def.CellSize)),
+	}
+}
+
+func (idx *spatialIndex) set(id types.EntityID, value any) error {
+	p, err := idx.def.Position(value)
+	if err != nil {
+		return err
+	}
+	if !isFinite(p.X) || !isFinite(p.Y) {
+		return eris.Errorf("position %v of entity %d in component %q is not finite", p, id, idx.def.Component.Name())
+	}
+	idx.pending[id] = pendingSpatialEntry{position: p, removed: false}
+	return nil
+}
+
+func (idx *spatialIndex) remove(id types.EntityID) {
+	idx.pending[id] = pendingSpatialEntry{position: Point{}, removed: true}
+}
+
+func (idx *spatialIndex) commit() {
+	for id, entry := range idx.pending {
+		if old, ok := idx.committed[id]; ok {
+			cell := idx.cellOf(old)
+			delete(idx.cells[cell], id)
+			if len(idx.cells[cell]) == 0 {
+				delete(idx.cells, cell)
+			}
+			delete(idx.committed, id)
+		}
+		if !entry.removed {
+			cell := idx.cellOf(entry.position)
+			if idx.cells[cell] == nil {
+				idx.cells[cell] = map[types.EntityID]struct{}{}
+			}
+			idx.cells[cell][id] = struct{}{}
+			idx.committed[id] = entry.position
+		}
+	}
+	idx.pending = map[types.EntityID]pendingSpatialEntry{}
+}
+
+func (idx *spatialIndex) discardPending() {
+	idx.pending = map[types.EntityID]pendingSpatialEntry{}
+}
+
+func (idx *spatialIndex) reset() {
+	idx.committed = map[types.EntityID]Point{}
+	idx.cells = map[gridCell]map[types.EntityID]struct{}{}
+	idx.pending = map[types.EntityID]pendingSpatialEntry{}
+}
+
+// count returns the number of entities in the index.
+func (idx *spatialIndex) count(includePending bool) int {
+	total := len(idx.committed)
+	if !includePending {
+		return total
+	}
+	for id, entry := range idx.pending {
+		_, wasCommitted := idx.committed[id]
+		switch {
+		case wasCommitted && entry.removed:
+			total--
+		case !wasCommitted && !entry.removed:
+			total++
+		}
+	}
+	return total
+}
+
+// collect returns the entities whose position is in the given rectangle, in no particular order.
+func (idx *spatialIndex) collect(rect Rect, includePending bool) []spatialEntry {
+	entries := make([]spatialEntry, 0)
+	visit := func(ids map[types.EntityID]struct{}) {
+		for id := range ids {
+			if _, ok := idx.pending[id]; ok && includePending {
+				continue
+			}
+			if p := idx.committed[id]; rect.contains(p) {
+				entries = append(entries, spatialEntry{id: id, position: p})
+			}
+		}
+	}
+
+	// Walk the cells covered by the rectangle, unless there are fewer non-empty cells than that.
+	width := math.Floor(rect.Max.X/idx.def.CellSize) - math.Floor(rect.Min.X/idx.def.CellSize) + 1
+	height := math.Floor(rect.Max.Y/idx.def.CellSize) - math.Floor(rect.Min.Y/idx.def.CellSize) + 1
+	if isFinite(width*height) && width*height <= float64(len(idx.cells)) {
+		minCell, maxCell := idx.cellOf(rect.Min), idx.cellOf(rect.Max)
+		for x := minCell.x; x <= maxCell.x; x++ {
+			for y := minCell.y; y <= maxCell.y; y++ {
+				visit(idx.cells[gridCell{x: x, y: y}])
+			}
+		}
+	} else {
+		for _, ids := range idx.cells {
+			visit(ids)
+		}
+	}
+
+	if includePending {
+		for id, entry := range idx.pending {
+			if !entry.removed && rect.contains(entry.position) {
+				entries = append(entries, spatialEntry{id: id, position: entry.position})
+			}
+		}
+	}
+	return entries
+}
+
+func isFinite(f float64) bool {
+	return !math.IsInf(f, 0) && !math.IsNaN(f)
+}
+
+func sortedEntryIDs(entries []spatialEntry) []types.EntityID {
+	ids := make([]types.EntityID, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func (s *indexSet) addSpatial(def SpatialIndexDefinition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if def.CellSize <= 0 || !isFinite(def.CellSize) {
+		return eris.Errorf("spatial index cell size must be a positive number, got %v", def.CellSize)
+	}
+	if _, ok := s.spatial[def.Component.Name()]; ok {
+		return eris.Wrapf(ErrIndexAlreadyRegistered, "spatial index on component %q", def.Component.Name())
+	}
+	idx := newSpatialIndex(def)
+	s.spatial[def.Component.Name()] = idx
+	s.spatialByComponent[def.Component.ID()] = idx
+	return nil
+}
+
+// collectSpatial returns the entities in the given rectangle, and the total number of entities in the spatial index.
+func (s *indexSet) collectSpatial(componentName string, rect Rect, includePending bool) ([]spatialEntry, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	idx, ok := s.spatial[componentName]
+	if !ok {
+		return nil, 0, eris.Wrapf(ErrIndexNotFound, "spatial index on component %q", componentName)
+	}
+	return idx.collect(rect, includePending), idx.count(includePending), nil
+}
+
+func (s *indexSet) lookupRect(componentName string, rect Rect, includePending bool) ([]types.EntityID, error) {
+	entries, _, err := s.collectSpatial(componentName, rect, includePending)
+	if err != nil {
+		return nil, err
+	}
+	return sortedEntryIDs(entries), nil
+}
+
+func (s *indexSet) lookupRadius(componentName string, center Point, radius float64, includePending bool) (
+	[]types.EntityID, error,
+) {
+	rect := Rect{
+		Min: Point{X: center.X - radius, Y: center.Y - radius},
+		Max: Point{X: center.X + radius, Y: center.Y + radius},
+	}
+	entries, _, err := s.collectSpatial(componentName, rect, includePending)
+	if err != nil {
+		return nil, err
+	}
+	entries = slices.DeleteFunc(entries, func(entry spatialEntry) bool {
+		return entry.position.distanceSquared(center) > radius*radius
+	})
+	return sortedEntryIDs(entries), nil
+}
+
+// lookupNearest searches squares of growing size around center until it has found k accepted entities within the
+// square's inner circle, or the square contains every entity in the index.
+func (s *indexSet) lookupNearest(
+	componentName string, center Point, k int, accept func(types.EntityID) bool, includePending bool,
+) ([]types.EntityID, error) {
+	if !isFinite(center.X) || !isFinite(center.Y) {
+		return nil, eris.Errorf("nearest search position %v is not finite", center)
+	}
+	if k <= 0 {
+		return []types.EntityID{}, nil
+	}
+
+	s.mu.RLock()
+	idx, ok := s.spatial[componentName]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, eris.Wrapf(ErrIndexNotFound, "spatial index on component %q", componentName)
+	}
+
+	accepted := map[types.EntityID]bool{}
+	isAccepted := func(id types.EntityID) bool {
+		if ok, seen := accepted[id]; seen {
+			return ok
+		}
+		accepted[id] = accept(id)
+		return accepted[id]
+	}
+
+	for radius := idx.def.CellSize; ; radius *= 2 {
+		rect := Rect{
+			Min: Point{X: center.X - radius, Y: center.Y - radius},
+			Max: Point{X: center.X + radius, Y: center.Y + radius},
+		}
+		entries, total, err := s.collectSpatial(componentName, rect, includePending)
+		if err != nil {
+			return nil, err
+		}
+		// Entities in the corners of the square may be farther away than entities outside of it, unless there are no
+		// entities outside of it.
+		foundAll := len(entries) >= total || math.IsInf(radius, 1)
+		matches := slices.DeleteFunc(entries, func(entry spatialEntry) bool {
+			return (!foundAll && entry.position.distanceSquared(center) > radius*radius) || !isAccepted(entry.id)
+		})
+		if len(matches) < k && !foundAll {
+			continue
+		}
+
+		slices.SortFunc(matches, func(a, b spatialEntry) int {
+			if c := cmp.Compare(a.position.distanceSquared(center), b.position.distanceSquared(center)); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.id, b.id)
+		})
+		ids := make([]types.EntityID, 0, k)
+		for _, entry := range matches[:min(k, len(matches))] {
+			ids = append(ids, entry.id)
+		}
+		return ids, nil
+	}
+}
+
+// RegisterSpatialIndex adds a spatial index on the position stored in a component. The index is built the next time
+// RegisterComponents is called.
+func (m *EntityCommandBuffer) RegisterSpatialIndex(def SpatialIndexDefinition) error {
+	return m.indexes.addSpatial(def)
+}
+
+// LookupRect returns the sorted IDs of the entities whose position is in the given rectangle, including the changes
+// made in the current tick.
+func (m *EntityCommandBuffer) LookupRect(componentName string, rect Rect) ([]types.EntityID, error) {
+	return m.indexes.lookupRect(componentName, rect, true)
+}
+
+// LookupRadius returns the sorted IDs of the entities whose position is within radius of center, including the
+// changes made in the current tick.
+func (m *EntityCommandBuffer) LookupRadius(componentName string, center Point, radius float64) (
+	[]types.EntityID, error,
+) {
+	return m.indexes.lookupRadius(componentName, center, radius, true)
+}
+
+// LookupNearest returns the IDs of the k accepted entities nearest to center, including the changes made in the
+// current tick.
+func (m *EntityCommandBuffer) LookupNearest(
+	componentName string, center Point, k int, accept func(types.EntityID) bool,
+) ([]types.EntityID, error) {
+	return m.indexes.lookupNearest(componentName, center, k, accept, true)
+}
+
+// LookupRect returns the sorted IDs of the entities whose position is in the given rectangle as of the last finalized
+// tick.
+func (r *readOnlyManager) LookupRect(componentName string, rect Rect) ([]types.EntityID, error) {
+	if r.indexes == nil {
+		return nil, eris.Wrap(ErrIndexNotAvailable, "")
+	}
+	return r.indexes.lookupRect(componentName, rect, false)
+}
+
+// LookupRadius returns the sorted IDs of the entities whose position is within radius of center as of the last
+// finalized tick.
+func (r *readOnlyManager) LookupRadius(componentName string, center Point, radius float64) (
+	[]types.EntityID, error,
+) {
+	if r.indexes == nil {
+		return nil, eris.Wrap(ErrIndexNotAvailable, "")
+	}
+	return r.indexes.lookupRadius(componentName, center, radius, false)
+}
+
+// LookupNearest returns the IDs of the k accepted entities nearest to center as of the last finalized tick.
+func (r *readOnlyManager) LookupNearest(
+	componentName string, center Point, k int, accept func(types.EntityID) bool,
+) ([]types.EntityID, error) {
+	if r.indexes == nil {
+		return nil, eris.Wrap(ErrIndexNotAvailable, "")
+	}
+	return r.indexes.lookupNearest(componentName, center, k, accept, false)
+}
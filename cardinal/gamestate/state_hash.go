@@ -0,0 +1,233 @@
+package gamestate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"slices"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+)
+
+var ErrStateHashNotFound = errors.New("no state hash recorded for tick")
+
+var _ StateHasher = &EntityCommandBuffer{}
+
+// StateHasher is implemented by game state managers that record a hash of the game state at the end of each tick.
+// The state hash covers every key of the ECB state (component values, archetypes, relationships, resources, ...), so
+// two runs of a game that produce the same state hash for a tick have the same state at the end of that tick, and the
+// first tick whose hashes differ is the tick where the runs diverged.
+//
+// The state hash is the sum modulo 2^256 of the SHA-256 hashes of every key and its value, with the key and the value
+// prefixed with their lengths. The sum does not depend on the order of the keys, so it is updated from the keys
+// changed by each tick instead of being computed over the whole state.
+type StateHasher interface {
+	// SetStateHashSize sets how many ticks worth of state hashes are kept. A size of 0 disables recording.
+	SetStateHashSize(ticksToRetain uint64)
+	// StateHash returns the state hash recorded for the given tick.
+	StateHash(tick uint64) ([]byte, error)
+}
+
+func (m *EntityCommandBuffer) SetStateHashSize(ticksToRetain uint64) {
+	m.stateHashSize = ticksToRetain
+}
+
+func (m *EntityCommandBuffer) StateHash(tick uint64) ([]byte, error) {
+	return LoadStateHash(m.dbStorage, tick)
+}
+
+// LoadStateHash reads the state hash recorded for the given tick from the storage of a game shard. It can be used to
+// compare the hashes of a live shard with the hashes of a replay.
+func LoadStateHash(storage PrimitiveStorage[string], tick uint64) ([]byte, error) {
+	bz, err := storage.GetBytes(context.Background(), storageStateHashKey(tick))
+	if eris.Is(eris.Cause(err), redis.Nil) {
+		return nil, eris.Wrapf(ErrStateHashNotFound, "tick %d", tick)
+	} else if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	return bz, nil
+}
+
+// writeRecorder is a Transaction that remembers the last value written to each key in the transaction, so the
+// state hash and the state root of the tick can be computed before the transaction is committed.
+type writeRecorder struct {
+	Transaction[string]
+	writes map[string]writeEntry
+}
+
+type writeEntry struct {
+	deleted bool
+	value   []byte
+}
+
+func newWriteRecorder(txn Transaction[string]) *writeRecorder {
+	return &writeRecorder{
+		Transaction: txn,
+		writes:      map[string]writeEntry{},
+	}
+}
+
+func (s *writeRecorder) Set(ctx context.Context, key string, value any) error {
+	if isStateHashKey(key) {
+		var bz []byte
+		switch v := value.(type) {
+		case []byte:
+			bz = v
+		case string:
+			bz = []byte(v)
+		default:
+			// Storage keeps other values in their string representation.
+			bz = []byte(fmt.Sprint(v))
+		}
+		s.writes[key] = writeEntry{deleted: false, value: bz}
+	}
+	return s.Transaction.Set(ctx, key, value)
+}
+
+func (s *writeRecorder) Delete(ctx context.Context, key string) error {
+	if isStateHashKey(key) {
+		s.writes[key] = writeEntry{deleted: true, value: nil}
+	}
+	return s.Transaction.Delete(ctx, key)
+}
+
+// addStateHashToPipe saves the state hash of the tick that is being finalized, and removes the hashes that fell out of
+// the retained ticks. previous holds the values the keys written by the tick had before the tick.
+func (m *EntityCommandBuffer) addStateHashToPipe(
+	ctx context.Context, recorder *writeRecorder, previous tickDiff,
+) error {
+	// The tick that is being finalized is the one right after the last finalized tick.
+	tick, err := m.GetLastFinalizedTick()
+	if err != nil {
+		return err
+	}
+	sum := m.stateHash
+	if sum == nil || m.stateHashTick+1 != tick {
+		if sum, err = m.loadStateHashBefore(ctx, tick); err != nil {
+			return err
+		}
+	}
+
+	next := new(stateHash)
+	*next = *sum
+	for key, entry := range recorder.writes {
+		if old, ok := previous[key]; ok && old.Exists {
+			next.remove(key, old.Value)
+		}
+		if !entry.deleted {
+			next.add(key, entry.value)
+		}
+	}
+
+	pipe := recorder.Transaction
+	if err := pipe.Set(ctx, storageStateHashKey(tick), next[:]); err != nil {
+		return eris.Wrap(err, "")
+	}
+	if err := m.addPruneTickKeysToPipe(ctx, pipe, storageStateHashKeyPrefix, tick, m.stateHashSize); err != nil {
+		return err
+	}
+	m.pendingStateHash, m.pendingStateHashTick = next, tick
+	return nil
+}
+
+// commitStateHash is called once a tick is committed, so the next tick starts from the state hash of the tick.
+func (m *EntityCommandBuffer) commitStateHash() {
+	if m.pendingStateHash != nil {
+		m.stateHash, m.stateHashTick = m.pendingStateHash, m.pendingStateHashTick
+		m.pendingStateHash = nil
+	}
+}
+
+// loadStateHashBefore returns the state hash of the state the given tick starts from. It is the hash recorded for the
+// previous tick if there is one, otherwise it is computed from every key of the state.
+func (m *EntityCommandBuffer) loadStateHashBefore(ctx context.Context, tick uint64) (*stateHash, error) {
+	if tick > 0 {
+		bz, err := LoadStateHash(m.dbStorage, tick-1)
+		if err == nil && len(bz) == len(stateHash{}) {
+			sum := new(stateHash)
+			copy(sum[:], bz)
+			return sum, nil
+		} else if err != nil && !eris.Is(err, ErrStateHashNotFound) {
+			return nil, err
+		}
+	}
+	sum := new(stateHash)
+	// A key may be listed more than once, but it must only be added once.
+	seen := map[string]struct{}{}
+	err := scanKeys(ctx, m.dbStorage, storageECBKeyPrefix, func(keys []string) error {
+		keys = slices.DeleteFunc(keys, func(key string) bool {
+			_, ok := seen[key]
+			seen[key] = struct{}{}
+			return ok || !isStateHashKey(key)
+		})
+		values, err := GetManyBytesFrom(ctx, m.dbStorage, keys)
+		if err != nil {
+			return err
+		}
+		for i, key := range keys {
+			if values[i] != nil {
+				sum.add(key, values[i])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to compute the state hash")
+	}
+	return sum, nil
+}
+
+// stateHash is the sum modulo 2^256 of the hashes of the keys in the state, as a big-endian number.
+type stateHash [sha256.Size]byte
+
+func (s *stateHash) add(key string, value []byte) {
+	h := hashStateEntry(key, value)
+	var carry uint16
+	for i := len(s) - 1; i >= 0; i-- {
+		sum := uint16(s[i]) + uint16(h[i]) + carry
+		s[i] = byte(sum)
+		carry = sum >> 8 //nolint:gomnd // carry of a byte addition
+	}
+}
+
+func (s *stateHash) remove(key string, value []byte) {
+	h := hashStateEntry(key, value)
+	var borrow int16
+	for i := len(s) - 1; i >= 0; i-- {
+		diff := int16(s[i]) - int16(h[i]) - borrow
+		borrow = 0
+		if diff < 0 {
+			diff += 256
+			borrow = 1
+		}
+		s[i] = byte(diff)
+	}
+}
+
+// hashStateEntry returns the hash of a key of the state and its value.
+func hashStateEntry(key string, value []byte) [sha256.Size]byte {
+	h := sha256.New()
+	writeUint64(h, uint64(len(key)))
+	h.Write([]byte(key))
+	writeUint64(h, uint64(len(value)))
+	h.Write(value)
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// isStateHashKey returns true if changes to the given key are part of the state hash.
+func isStateHashKey(key string) bool {
+	return isHistoryKey(key) && !strings.HasPrefix(key, storageStateHashKeyPrefix)
+}
+
+func writeUint64(h hash.Hash, v uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	h.Write(buf[:])
+}
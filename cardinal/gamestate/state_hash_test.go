@@ -0,0 +1,56 @@
+package gamestate_test
+
+import (
+	"context"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+)
+
+func TestStateHashCoversTheWholeState(t *testing.T) {
+	ctx := context.Background()
+	manager, client := newCmdBufferAndRedisClientForTest(t, nil)
+	manager.SetStateHashSize(10)
+
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{1}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	_, err = manager.CreateEntity(barComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	// A tick without changes leaves the state, and so the state hash, unchanged.
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	hashes := make([][]byte, 3)
+	for tick := range hashes {
+		hashes[tick], err = manager.StateHash(uint64(tick))
+		assert.NilError(t, err)
+	}
+	assert.NotEqual(t, string(hashes[0]), string(hashes[1]))
+	assert.DeepEqual(t, hashes[1], hashes[2])
+
+	// Changing a value and changing it back gives back the same state hash.
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{2}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{1}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	hash, err := manager.StateHash(3)
+	assert.NilError(t, err)
+	assert.NotEqual(t, string(hashes[2]), string(hash))
+	hash, err = manager.StateHash(4)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, hashes[2], hash)
+
+	// A manager that has no recorded hash to start from computes the hash of the whole state.
+	assert.NilError(t, client.Del(ctx, "ECB:STATE-HASH:4").Err())
+	other, _ := newCmdBufferAndRedisClientForTest(t, client)
+	other.SetStateHashSize(10)
+	assert.NilError(t, other.FinalizeTick(ctx))
+	hash, err = other.StateHash(5)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, hashes[2], hash)
+	_, err = other.StateHash(4)
+	assert.ErrorIs(t, err, gamestate.ErrStateHashNotFound)
+}
@@ -0,0 +1,327 @@
+package gamestate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/merkle"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// stateTreeCacheSize is the maximum number of ticks whose state trees are kept in memory to serve proofs.
+const stateTreeCacheSize = 16
+
+var (
+	ErrStateRootNotFound  = errors.New("no state root recorded for tick")
+	ErrStateRootsDisabled = errors.New("state roots are not enabled")
+)
+
+var _ StateCommitter = &EntityCommandBuffer{}
+
+// StateCommitter is implemented by game state managers that compute a state root at the end of each tick. The state
+// root is the root of a Merkle tree (see package merkle) with a leaf for every key of the game state: component
+// values, archetypes, relationships, resources and the other ECB keys. The key of a component value leaf is
+// StateRootLeafKey of the component and the entity, and the key of any other leaf is its storage key. The value of a
+// leaf is the stored value, e.g. the encoded component value.
+type StateCommitter interface {
+	// SetStateRootSize sets how many ticks worth of state roots are kept. A size of 0 disables state roots.
+	SetStateRootSize(ticksToRetain uint64)
+	// StateRoot returns the state root of the game state at the end of the given tick.
+	StateRoot(tick uint64) ([]byte, error)
+	// ProveComponent returns a proof of the value of a component of an entity at the end of the last finalized tick.
+	ProveComponent(cType types.ComponentMetadata, id types.EntityID) (*ComponentProof, error)
+	// ProveComponentAtTick returns a proof of the value of a component of an entity at the end of the given tick.
+	// Ticks other than the last finalized one must still be covered by the recorded state history.
+	ProveComponentAtTick(cType types.ComponentMetadata, id types.EntityID, tick uint64) (*ComponentProof, error)
+}
+
+// ComponentProof proves the value of a component of an entity at the end of a tick.
+type ComponentProof struct {
+	Tick      uint64
+	StateRoot []byte
+	// Key is the key of the state tree leaf that holds the component value, see StateRootLeafKey.
+	Key string
+	// Value is the encoded component value.
+	Value []byte
+	Proof *merkle.Proof
+}
+
+// Verify returns true if the proof holds for the given state root, e.g. the state root that was submitted to the base
+// shard for the tick of the proof.
+func (p *ComponentProof) Verify(stateRoot []byte) bool {
+	return merkle.Verify(stateRoot, p.Key, p.Value, p.Proof)
+}
+
+// StateRootLeafKey returns the key of the state tree leaf that holds the value of the given component of an entity.
+func StateRootLeafKey(componentName string, id types.EntityID) string {
+	return fmt.Sprintf("%s/%d", componentName, id)
+}
+
+func (m *EntityCommandBuffer) SetStateRootSize(ticksToRetain uint64) {
+	m.stateRootSize = ticksToRetain
+}
+
+func (m *EntityCommandBuffer) StateRoot(tick uint64) ([]byte, error) {
+	bz, err := m.dbStorage.GetBytes(context.Background(), storageStateRootKey(tick))
+	if eris.Is(eris.Cause(err), redis.Nil) {
+		return nil, eris.Wrapf(ErrStateRootNotFound, "tick %d", tick)
+	} else if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	return bz, nil
+}
+
+func (m *EntityCommandBuffer) ProveComponent(cType types.ComponentMetadata, id types.EntityID) (
+	*ComponentProof, error,
+) {
+	if m.stateRootSize == 0 {
+		return nil, eris.Wrap(ErrStateRootsDisabled, "")
+	}
+	for {
+		tree, tick, err := m.latestStateTree()
+		if err != nil {
+			return nil, err
+		}
+		proof, err := proveComponent(tree, m.dbStorage, cType, id, tick)
+		if err == nil && proof.Verify(proof.StateRoot) {
+			return proof, nil
+		}
+		// The value is read from storage after the tree was taken, so a tick that was committed in between may have
+		// changed it. In that case the proof is made again with the tree of the new tick, which is cached right after
+		// the tick is committed.
+		finalized, tickErr := m.GetLastFinalizedTick()
+		if tickErr != nil {
+			return nil, tickErr
+		}
+		if finalized > tick+1 {
+			runtime.Gosched()
+			continue
+		}
+		if err == nil {
+			err = eris.Errorf("the value of component %q for entity %d does not match the state tree", cType.Name(), id)
+		}
+		return nil, err
+	}
+}
+
+func (m *EntityCommandBuffer) ProveComponentAtTick(cType types.ComponentMetadata, id types.EntityID, tick uint64) (
+	*ComponentProof, error,
+) {
+	if m.stateRootSize == 0 {
+		return nil, eris.Wrap(ErrStateRootsDisabled, "")
+	}
+	ctx := context.Background()
+	storage := &historicalStorage{
+		storage: m.dbStorage,
+		tick:    tick,
+		diffs:   map[uint64]tickDiff{},
+		mutex:   &sync.Mutex{},
+	}
+
+	m.stateTreesMutex.RLock()
+	tree, ok := m.stateTrees[tick]
+	m.stateTreesMutex.RUnlock()
+	if !ok {
+		// Only the trees of the most recent ticks are kept, so the tree of an older tick is a newer tree with the
+		// changes of the ticks after it reverted from the state history.
+		latest, latestTick, err := m.latestStateTree()
+		if err != nil {
+			return nil, err
+		}
+		if tick > latestTick {
+			return nil, eris.Wrapf(ErrTickNotInHistory, "tick %d has not been finalized yet", tick)
+		}
+		tree = latest.Clone()
+		for t := latestTick; t > tick; t-- {
+			diff, err := storage.diff(ctx, t)
+			if err != nil {
+				return nil, err
+			}
+			for key, entry := range diff {
+				if err := m.setStateLeaf(tree, key, entry.Exists, entry.Value); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	// The value is read from the state history, which is not changed by the ticks finalized after the tick.
+	return proveComponent(tree, storage, cType, id, tick)
+}
+
+// latestStateTree returns the state tree of the last finalized tick. It does not wait for the tick that is being
+// finalized, unless no tick was finalized since the state tree was last loaded from storage.
+func (m *EntityCommandBuffer) latestStateTree() (*merkle.Tree, uint64, error) {
+	m.stateTreesMutex.RLock()
+	tick, ok := m.latestStateTreeTick()
+	tree := m.stateTrees[tick]
+	m.stateTreesMutex.RUnlock()
+	if ok {
+		return tree, tick, nil
+	}
+
+	m.stateTreeMutex.Lock()
+	defer m.stateTreeMutex.Unlock()
+	if m.stateTree == nil {
+		if err := m.loadStateTree(context.Background()); err != nil {
+			return nil, 0, err
+		}
+	}
+	if m.stateTreeTicks == 0 {
+		return nil, 0, eris.Wrap(ErrStateRootNotFound, "no tick has been finalized yet")
+	}
+	m.cacheStateTree(m.stateTree, m.stateTreeTicks-1)
+	return m.stateTree, m.stateTreeTicks - 1, nil
+}
+
+// latestStateTreeTick returns the tick of the most recent cached state tree. stateTreesMutex must be held.
+func (m *EntityCommandBuffer) latestStateTreeTick() (uint64, bool) {
+	var latest uint64
+	for tick := range m.stateTrees {
+		latest = max(latest, tick)
+	}
+	return latest, len(m.stateTrees) > 0
+}
+
+// cacheStateTree keeps the state tree of the given tick for proofs, and drops the trees of the ticks that are no longer
+// among the most recent ones. The trees share their unchanged nodes, so each tree only costs the nodes that changed.
+func (m *EntityCommandBuffer) cacheStateTree(tree *merkle.Tree, tick uint64) {
+	m.stateTreesMutex.Lock()
+	defer m.stateTreesMutex.Unlock()
+	m.stateTrees[tick] = tree
+	for cached := range m.stateTrees {
+		if cached+min(m.stateRootSize, stateTreeCacheSize) <= tick {
+			delete(m.stateTrees, cached)
+		}
+	}
+}
+
+// clearStateTrees drops the state tree and the cached trees, so they are loaded again from storage.
+func (m *EntityCommandBuffer) clearStateTrees() {
+	m.stateTree = nil
+	m.stateTreesMutex.Lock()
+	defer m.stateTreesMutex.Unlock()
+	clear(m.stateTrees)
+}
+
+// proveComponent returns a proof of the value of a component of an entity in the given state tree. The storage must
+// hold the component values the tree was built from.
+func proveComponent(
+	tree *merkle.Tree, storage PrimitiveStorage[string], cType types.ComponentMetadata, id types.EntityID, tick uint64,
+) (*ComponentProof, error) {
+	key := StateRootLeafKey(cType.Name(), id)
+	proof, err := tree.Proof(key)
+	if err != nil {
+		return nil, eris.Wrapf(err, "no value of component %q for entity %d", cType.Name(), id)
+	}
+	value, err := storage.GetBytes(context.Background(), storageComponentKey(cType.ID(), id))
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	return &ComponentProof{
+		Tick:      tick,
+		StateRoot: tree.Root(),
+		Key:       key,
+		Value:     value,
+		Proof:     proof,
+	}, nil
+}
+
+// addStateRootToPipe applies the keys written by the tick that is being finalized to a copy of the state tree, and
+// saves the new state root. The state roots that fell out of the retained ticks are removed.
+func (m *EntityCommandBuffer) addStateRootToPipe(ctx context.Context, recorder *writeRecorder) error {
+	// The tick that is being finalized is the one right after the last finalized tick.
+	tick, err := m.GetLastFinalizedTick()
+	if err != nil {
+		return err
+	}
+	if m.stateTree == nil || m.stateTreeTicks != tick {
+		if err := m.loadStateTree(ctx); err != nil {
+			return err
+		}
+	}
+	// The state tree is only replaced once the tick is committed.
+	tree := m.stateTree.Clone()
+	for key, entry := range recorder.writes {
+		if err := m.setStateLeaf(tree, key, !entry.deleted, entry.value); err != nil {
+			return err
+		}
+	}
+
+	pipe := recorder.Transaction
+	if err := pipe.Set(ctx, storageStateRootKey(tick), tree.Root()); err != nil {
+		return eris.Wrap(err, "")
+	}
+	if err := m.addPruneTickKeysToPipe(ctx, pipe, storageStateRootKeyPrefix, tick, m.stateRootSize); err != nil {
+		return err
+	}
+	m.pendingStateTree = tree
+	return nil
+}
+
+// commitStateTree is called once a tick is committed, so the state tree includes the changes of the tick.
+func (m *EntityCommandBuffer) commitStateTree() {
+	if m.pendingStateTree != nil {
+		m.stateTree = m.pendingStateTree
+		m.stateTreeTicks++
+		m.pendingStateTree = nil
+		m.cacheStateTree(m.stateTree, m.stateTreeTicks-1)
+	}
+}
+
+// setStateLeaf sets or deletes the leaf of the given storage key in the state tree. Keys that are not part of the game
+// state are ignored.
+func (m *EntityCommandBuffer) setStateLeaf(tree *merkle.Tree, key string, exists bool, value []byte) error {
+	if !isStateHashKey(key) {
+		return nil
+	}
+	leafKey := key
+	if typeID, id, ok := parseStorageComponentKey(key); ok {
+		comp, err := m.typeToComponent.Get(typeID)
+		if err != nil {
+			return err
+		}
+		leafKey = StateRootLeafKey(comp.Name(), id)
+	}
+	if exists {
+		tree.Set(leafKey, value)
+	} else {
+		tree.Delete(leafKey)
+	}
+	return nil
+}
+
+// loadStateTree builds the state tree from the game state saved in storage.
+func (m *EntityCommandBuffer) loadStateTree(ctx context.Context) error {
+	ticks, err := m.GetLastFinalizedTick()
+	if err != nil {
+		return err
+	}
+	tree := merkle.NewTree()
+	err = scanKeys(ctx, m.dbStorage, storageECBKeyPrefix, func(keys []string) error {
+		keys = slices.DeleteFunc(keys, func(key string) bool { return !isStateHashKey(key) })
+		values, err := GetManyBytesFrom(ctx, m.dbStorage, keys)
+		if err != nil {
+			return err
+		}
+		for i, key := range keys {
+			// A key that is listed more than once is set to the same value again.
+			if err := m.setStateLeaf(tree, key, values[i] != nil, values[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return eris.Wrap(err, "failed to build the state tree")
+	}
+	m.stateTree = tree
+	m.stateTreeTicks = ticks
+	return nil
+}
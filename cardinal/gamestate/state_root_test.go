@@ -0,0 +1,66 @@
+package gamestate_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+)
+
+func TestComponentProofsAreServedWhileTicksAreFinalized(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newCmdBufferAndRedisClientForTest(t, nil)
+	manager.SetStateRootSize(100)
+	manager.SetHistorySize(100)
+
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{0}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	const ticks = 40
+	done := make(chan struct{})
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			// Every proof must hold for the state root of its tick, even if a tick is finalized while it is made.
+			proof, err := manager.ProveComponent(fooComp, id)
+			if !assert.Check(t, err) {
+				return
+			}
+			root, err := manager.StateRoot(proof.Tick)
+			if !assert.Check(t, err) {
+				return
+			}
+			assert.Check(t, proof.Verify(root), "tick %d", proof.Tick)
+		}
+	}()
+	for i := 1; i <= ticks; i++ {
+		assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{i}))
+		assert.NilError(t, manager.FinalizeTick(ctx))
+	}
+	close(done)
+	wg.Wait()
+
+	// The trees of the recent ticks are kept in memory, and the trees of the older ticks are rebuilt from the history.
+	for tick := uint64(0); tick <= ticks; tick++ {
+		proof, err := manager.ProveComponentAtTick(fooComp, id, tick)
+		assert.NilError(t, err)
+		root, err := manager.StateRoot(tick)
+		assert.NilError(t, err)
+		assert.Check(t, proof.Verify(root), "tick %d", tick)
+		reader, err := manager.ToReadOnlyAtTick(tick)
+		assert.NilError(t, err)
+		value, err := reader.GetComponentForEntity(fooComp, id)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, value, Foo{int(tick)})
+	}
+}
@@ -40,6 +40,11 @@ func (m *EntityCommandBuffer) FinalizeTick(ctx context.Context) error {
 	ctx, span := m.tracer.Start(ddotel.ContextWithStartOptions(ctx, ddtracer.Measured()), "ecb.tick.finalize")
 	defer span.End()
 
+	m.snapshotMutex.Lock()
+	defer m.snapshotMutex.Unlock()
+	m.stateTreeMutex.Lock()
+	defer m.stateTreeMutex.Unlock()
+
 	pipe, err := m.makePipeOfRedisCommands(ctx)
 	if err != nil {
 		span.SetStatus(codes.Error, eris.ToString(err, true))
@@ -47,6 +52,51 @@ func (m *EntityCommandBuffer) FinalizeTick(ctx context.Context) error {
 		return eris.Wrap(err, "failed to make redis commands pipe")
 	}
 
+	// The per-tick keys pruned by a tick that fails to be committed are pruned again by the next tick.
+	clear(m.pendingOldestTickKeys)
+	m.pendingStateHash = nil
+	m.pendingStateTree = nil
+
+	// previous holds the values the keys changed by this tick had before the tick, if they are needed.
+	var previous tickDiff
+	writes, hasWrites := pipe.(*writeRecorder)
+	if recorder, ok := pipe.(*diffRecorder); ok {
+		if previous, err = m.addRecordedDiffToPipe(ctx, recorder); err != nil {
+			span.SetStatus(codes.Error, eris.ToString(err, true))
+			span.RecordError(err)
+			return eris.Wrap(err, "failed to save state diff")
+		}
+		writes, hasWrites = recorder.Transaction.(*writeRecorder)
+	}
+
+	if hasWrites && previous == nil && (m.activeSnapshot != nil || m.stateHashSize > 0) {
+		if previous, err = m.previousValues(ctx, writes); err != nil {
+			span.SetStatus(codes.Error, eris.ToString(err, true))
+			span.RecordError(err)
+			return eris.Wrap(err, "failed to read the previous values of the changed keys")
+		}
+	}
+
+	if hasWrites && m.activeSnapshot != nil {
+		m.recordSnapshotChanges(previous)
+	}
+
+	if hasWrites && m.stateHashSize > 0 {
+		if err := m.addStateHashToPipe(ctx, writes, previous); err != nil {
+			span.SetStatus(codes.Error, eris.ToString(err, true))
+			span.RecordError(err)
+			return eris.Wrap(err, "failed to save state hash")
+		}
+	}
+
+	if hasWrites && m.stateRootSize > 0 {
+		if err := m.addStateRootToPipe(ctx, writes); err != nil {
+			span.SetStatus(codes.Error, eris.ToString(err, true))
+			span.RecordError(err)
+			return eris.Wrap(err, "failed to save state root")
+		}
+	}
+
 	if err := pipe.Incr(ctx, storageLastFinalizedTickKey()); err != nil {
 		span.SetStatus(codes.Error, eris.ToString(err, true))
 		span.RecordError(err)
@@ -60,6 +110,10 @@ func (m *EntityCommandBuffer) FinalizeTick(ctx context.Context) error {
 	}
 
 	m.pendingArchIDs = nil
+	m.indexes.commit()
+	m.commitOldestTickKeys()
+	m.commitStateHash()
+	m.commitStateTree()
 
 	if err := m.DiscardPending(); err != nil {
 		span.SetStatus(codes.Error, eris.ToString(err, true))
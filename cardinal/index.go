@@ -0,0 +1,105 @@
+package cardinal
+
+import (
+	"cmp"
+	"reflect"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// RegisterIndex adds a secondary index on a field of the component T. The index maps the value returned by extractor to
+// the entities that have it, so searches can find those entities without reading every component. The index is kept
+// up to date when the component is set or removed, and rebuilt from the saved state when the game starts. The field
+// name identifies the index in EntitySearch.WhereIndexEquals and in the index filters.
+//
+// Usage:
+//
+// cardinal.RegisterIndex[Position](world, "x", func(p Position) int { return p.X })
+func RegisterIndex[T types.Component, K cmp.Ordered](w *World, field string, extractor func(T) K) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"world state is %s, expected %s to register index",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+
+	var t T
+	c, err := w.GetComponentByName(t.Name())
+	if err != nil {
+		return eris.Wrap(err, "component must be registered before its indexes")
+	}
+
+	indexer, ok := w.entityStore.(gamestate.Indexer)
+	if !ok {
+		return eris.New("indexes are not supported by the configured game state manager")
+	}
+
+	var k K
+	return indexer.RegisterIndex(gamestate.IndexDefinition{
+		Component: c,
+		Field:     field,
+		KeyType:   reflect.TypeOf(k),
+		Key: func(value any) (any, error) {
+			switch comp := value.(type) {
+			case T:
+				return extractor(comp), nil
+			case *T:
+				return extractor(*comp), nil
+			default:
+				return nil, eris.Errorf("unable to index %T as component %s", value, t.Name())
+			}
+		},
+		Compare: func(a, b any) int {
+			return cmp.Compare(a.(K), b.(K)) //nolint:errcheck // the index checks the type of keys
+		},
+	})
+}
+
+// IndexFilter restricts a search to the entities whose indexed component field is in a range of values. It is created
+// with IndexBetween, IndexAtLeast or IndexAtMost, and used with EntitySearch.WhereIndexRange.
+type IndexFilter struct {
+	component string
+	field     string
+	rng       gamestate.IndexRange
+}
+
+// IndexBetween matches the entities whose indexed field of component T is between minValue and maxValue (inclusive).
+func IndexBetween[T types.Component, K cmp.Ordered](field string, minValue, maxValue K) IndexFilter {
+	return newIndexFilter[T](field, minValue, maxValue)
+}
+
+// IndexAtLeast matches the entities whose indexed field of component T is greater than or equal to minValue.
+func IndexAtLeast[T types.Component, K cmp.Ordered](field string, minValue K) IndexFilter {
+	return newIndexFilter[T](field, minValue, nil)
+}
+
+// IndexAtMost matches the entities whose indexed field of component T is less than or equal to maxValue.
+func IndexAtMost[T types.Component, K cmp.Ordered](field string, maxValue K) IndexFilter {
+	return newIndexFilter[T](field, nil, maxValue)
+}
+
+func newIndexFilter[T types.Component](field string, minValue, maxValue any) IndexFilter {
+	var t T
+	return IndexFilter{
+		component: t.Name(),
+		field:     field,
+		rng:       gamestate.IndexRange{Min: minValue, Max: maxValue},
+	}
+}
+
+// lookup returns the sorted IDs of the entities that match the filter.
+func (f IndexFilter) lookup(wCtx WorldContext) ([]types.EntityID, error) {
+	if err := wCtx.componentAccess().checkRead(f.component); err != nil {
+		return nil, err
+	}
+	reader, ok := wCtx.storeReader().(gamestate.IndexReader)
+	if !ok {
+		return nil, eris.Wrap(gamestate.ErrIndexNotAvailable, "")
+	}
+	return reader.LookupIndex(f.component, f.field, f.rng)
+}
@@ -0,0 +1,269 @@
+// Package merkle implements the binary Merkle tree that commits to the state of a game shard, and the verification of
+// inclusion proofs. It only depends on the standard library.
+//
+// The tree is a compressed binary trie over key/value pairs. The path of a key is SHA-256(key), read from its most
+// significant bit. Every inner node is the point where the paths of the keys below it diverge: its depth is the index
+// of the first bit that differs, and the keys whose bit is 0 are on its left. The hash of a leaf is
+// SHA-256(0x00 || len(key) || key || value), where len(key) is a big endian uint64, and the hash of an inner node is
+// SHA-256(0x01 || depth || left || right), where depth is a single byte. The root of an empty tree is SHA-256 of no
+// data.
+//
+// The shape of the tree only depends on the keys, so the root does not depend on the order in which the keys are set.
+// Nodes are never changed once they are created: setting or deleting a key only hashes the nodes on the path of the
+// key, and a copy of a tree (see Clone) shares its nodes with the original.
+//
+// An inclusion proof holds the depth and the hash of the sibling of every inner node on the path from the leaf to the
+// root. It is verified with the following algorithm, which only needs sha256 and integer arithmetic and can be written
+// as is in Solidity:
+//
+//	hash, path := LeafHash(key, value), sha256(key)
+//	for i := range proof.Siblings { // depths must be strictly decreasing
+//		if bit(path, proof.Depths[i]) == 0 {
+//			hash = InnerHash(proof.Depths[i], hash, proof.Siblings[i])
+//		} else {
+//			hash = InnerHash(proof.Depths[i], proof.Siblings[i], hash)
+//		}
+//	}
+//	valid := hash == root
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+var ErrKeyNotFound = errors.New("key is not in the tree")
+
+const (
+	leafPrefix  = 0x00
+	innerPrefix = 0x01
+)
+
+type path = [sha256.Size]byte
+
+// node is a leaf if left and right are nil. Nodes are immutable.
+type node struct {
+	hash []byte
+	// path is the path of the key of a leaf. For an inner node, it is the path of one of the keys below it, which
+	// shares its first depth bits with all the other keys below it.
+	path path
+	key  string
+	// depth is the index of the bit of the paths that decides the child of an inner node.
+	depth       uint8
+	left, right *node
+}
+
+func (n *node) isLeaf() bool {
+	return n.left == nil
+}
+
+// Tree is a Merkle tree over a set of key/value pairs. It keeps the hashes of all the nodes in memory, so the root is
+// updated in O(log n) hashes when the value of a key changes.
+type Tree struct {
+	root *node
+	size int
+}
+
+func NewTree() *Tree {
+	return &Tree{}
+}
+
+// Clone returns a copy of the tree in O(1). Changes to the copy do not affect the original and vice versa.
+func (t *Tree) Clone() *Tree {
+	return &Tree{root: t.root, size: t.size}
+}
+
+// Set sets the value of the leaf with the given key.
+func (t *Tree) Set(key string, value []byte) {
+	leaf := &node{hash: LeafHash(key, value), path: sha256.Sum256([]byte(key)), key: key}
+	var added bool
+	t.root, added = insert(t.root, leaf)
+	if added {
+		t.size++
+	}
+}
+
+// Delete removes the leaf with the given key, if any.
+func (t *Tree) Delete(key string) {
+	var removed bool
+	t.root, removed = remove(t.root, key, sha256.Sum256([]byte(key)))
+	if removed {
+		t.size--
+	}
+}
+
+// Len returns the number of leaves in the tree.
+func (t *Tree) Len() int {
+	return t.size
+}
+
+// Proof proves that a key/value pair is a leaf of a tree with a given root.
+type Proof struct {
+	// Depths are the depths of the inner nodes on the path from the leaf to the root, starting at the leaf.
+	Depths []uint8
+	// Siblings are the hashes of the other child of the inner nodes on the path from the leaf to the root, starting at
+	// the leaf.
+	Siblings [][]byte
+}
+
+// Root returns the root hash of the tree.
+func (t *Tree) Root() []byte {
+	if t.root == nil {
+		root := sha256.Sum256(nil)
+		return root[:]
+	}
+	return t.root.hash
+}
+
+// Proof returns an inclusion proof of the leaf with the given key.
+func (t *Tree) Proof(key string) (*Proof, error) {
+	keyPath := sha256.Sum256([]byte(key))
+	var depths []uint8
+	var siblings [][]byte
+	n := t.root
+	for n != nil && !n.isLeaf() {
+		depths = append(depths, n.depth)
+		if bit(keyPath, n.depth) == 0 {
+			siblings = append(siblings, n.right.hash)
+			n = n.left
+		} else {
+			siblings = append(siblings, n.left.hash)
+			n = n.right
+		}
+	}
+	if n == nil || n.key != key {
+		return nil, ErrKeyNotFound
+	}
+	// The path was walked from the root, and the proof starts at the leaf.
+	for i, j := 0, len(depths)-1; i < j; i, j = i+1, j-1 {
+		depths[i], depths[j] = depths[j], depths[i]
+		siblings[i], siblings[j] = siblings[j], siblings[i]
+	}
+	return &Proof{Depths: depths, Siblings: siblings}, nil
+}
+
+// Verify returns true if the proof proves that the key/value pair is a leaf of the tree with the given root.
+func Verify(root []byte, key string, value []byte, proof *Proof) bool {
+	if proof == nil || len(proof.Depths) != len(proof.Siblings) {
+		return false
+	}
+	hash, keyPath := LeafHash(key, value), sha256.Sum256([]byte(key))
+	for i, depth := range proof.Depths {
+		if i > 0 && depth >= proof.Depths[i-1] {
+			return false
+		}
+		if bit(keyPath, depth) == 0 {
+			hash = InnerHash(depth, hash, proof.Siblings[i])
+		} else {
+			hash = InnerHash(depth, proof.Siblings[i], hash)
+		}
+	}
+	return bytes.Equal(hash, root)
+}
+
+// insert returns the subtree n with the given leaf set, and whether the key of the leaf was not in the subtree.
+func insert(n *node, leaf *node) (*node, bool) {
+	if n == nil {
+		return leaf, true
+	}
+	if n.isLeaf() && n.key == leaf.key {
+		return leaf, false
+	}
+	depth, ok := firstDiff(n.path, leaf.path)
+	if !ok && n.isLeaf() {
+		// Two keys with the same SHA-256 hash.
+		panic("merkle: hash collision between keys " + n.key + " and " + leaf.key)
+	}
+	// If the paths are equal, the key of the leaf is the key below n whose path n holds.
+	if ok && (n.isLeaf() || depth < n.depth) {
+		// The leaf diverges from all the keys of n above n, so it becomes the sibling of n.
+		if bit(leaf.path, depth) == 0 {
+			return newInner(depth, leaf, n), true
+		}
+		return newInner(depth, n, leaf), true
+	}
+	if bit(leaf.path, n.depth) == 0 {
+		left, added := insert(n.left, leaf)
+		return newInner(n.depth, left, n.right), added
+	}
+	right, added := insert(n.right, leaf)
+	return newInner(n.depth, n.left, right), added
+}
+
+// remove returns the subtree n without the given key, and whether the key was in the subtree.
+func remove(n *node, key string, keyPath path) (*node, bool) {
+	if n == nil {
+		return nil, false
+	}
+	if n.isLeaf() {
+		if n.key != key {
+			return n, false
+		}
+		return nil, true
+	}
+	left, right := n.left, n.right
+	var removed bool
+	if bit(keyPath, n.depth) == 0 {
+		left, removed = remove(left, key, keyPath)
+	} else {
+		right, removed = remove(right, key, keyPath)
+	}
+	switch {
+	case !removed:
+		return n, false
+	case left == nil:
+		return right, true
+	case right == nil:
+		return left, true
+	}
+	return newInner(n.depth, left, right), true
+}
+
+func newInner(depth uint8, left, right *node) *node {
+	return &node{
+		hash:  InnerHash(depth, left.hash, right.hash),
+		path:  left.path,
+		depth: depth,
+		left:  left,
+		right: right,
+	}
+}
+
+// firstDiff returns the index of the first bit that differs between the two paths. ok is false if they are equal.
+func firstDiff(a, b path) (uint8, bool) {
+	for i := range a {
+		if x := a[i] ^ b[i]; x != 0 {
+			return uint8(i*8 + bits.LeadingZeros8(x)), true //nolint:gomnd // bits in a byte
+		}
+	}
+	return 0, false
+}
+
+// bit returns the bit of the path at the given index, starting at the most significant bit of the first byte.
+func bit(p path, index uint8) byte {
+	return (p[index/8] >> (7 - index%8)) & 1 //nolint:gomnd // bits in a byte
+}
+
+// LeafHash returns the hash of the leaf with the given key and value.
+func LeafHash(key string, value []byte) []byte {
+	h := sha256.New()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(len(key)))
+	h.Write([]byte{leafPrefix})
+	h.Write(buf[:])
+	h.Write([]byte(key))
+	h.Write(value)
+	return h.Sum(nil)
+}
+
+// InnerHash returns the hash of the inner node at the given depth with the given children.
+func InnerHash(depth uint8, left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{innerPrefix, depth})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
@@ -0,0 +1,116 @@
+package merkle_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/merkle"
+)
+
+func TestRoot(t *testing.T) {
+	tree := merkle.NewTree()
+	empty := sha256.Sum256(nil)
+	assert.DeepEqual(t, tree.Root(), empty[:])
+
+	tree.Set("a", []byte("1"))
+	assert.DeepEqual(t, tree.Root(), merkle.LeafHash("a", []byte("1")))
+
+	// The root does not depend on the order in which the leaves are set.
+	tree.Set("c", []byte("3"))
+	tree.Set("b", []byte("2"))
+	other := merkle.NewTree()
+	other.Set("b", []byte("2"))
+	other.Set("c", []byte("3"))
+	other.Set("a", []byte("1"))
+	assert.DeepEqual(t, tree.Root(), other.Root())
+
+	// Changing or removing a value changes the root.
+	root := tree.Root()
+	tree.Set("b", []byte("20"))
+	assert.Check(t, string(root) != string(tree.Root()))
+	tree.Set("b", []byte("2"))
+	assert.DeepEqual(t, tree.Root(), root)
+	tree.Delete("c")
+	tree.Delete("missing")
+	assert.Equal(t, 2, tree.Len())
+	two := merkle.NewTree()
+	two.Set("a", []byte("1"))
+	two.Set("b", []byte("2"))
+	assert.DeepEqual(t, tree.Root(), two.Root())
+	tree.Delete("a")
+	tree.Delete("b")
+	assert.Equal(t, 0, tree.Len())
+	assert.DeepEqual(t, tree.Root(), empty[:])
+}
+
+func TestRootMatchesTheRootOfATreeBuiltFromScratch(t *testing.T) {
+	tree := merkle.NewTree()
+	values := map[string]string{}
+	for i := 0; i < 500; i++ {
+		key := fmt.Sprintf("key-%d", i%200)
+		if i%7 == 0 {
+			tree.Delete(key)
+			delete(values, key)
+			continue
+		}
+		tree.Set(key, []byte(fmt.Sprint(i)))
+		values[key] = fmt.Sprint(i)
+	}
+	fresh := merkle.NewTree()
+	for key, value := range values {
+		fresh.Set(key, []byte(value))
+	}
+	assert.Equal(t, len(values), tree.Len())
+	assert.DeepEqual(t, tree.Root(), fresh.Root())
+}
+
+func TestClone(t *testing.T) {
+	tree := merkle.NewTree()
+	tree.Set("a", []byte("1"))
+	tree.Set("b", []byte("2"))
+	root := tree.Root()
+	clone := tree.Clone()
+
+	tree.Set("a", []byte("10"))
+	tree.Delete("b")
+	assert.DeepEqual(t, clone.Root(), root)
+	assert.Equal(t, 2, clone.Len())
+	proof, err := clone.Proof("b")
+	assert.NilError(t, err)
+	assert.Check(t, merkle.Verify(root, "b", []byte("2"), proof))
+}
+
+func TestProof(t *testing.T) {
+	for _, count := range []int{1, 2, 3, 5, 8, 13, 100} {
+		tree := merkle.NewTree()
+		for i := 0; i < count; i++ {
+			tree.Set(fmt.Sprintf("key-%02d", i), []byte(fmt.Sprint(i)))
+		}
+		root := tree.Root()
+		for i := 0; i < count; i++ {
+			key := fmt.Sprintf("key-%02d", i)
+			proof, err := tree.Proof(key)
+			assert.NilError(t, err)
+			assert.Equal(t, len(proof.Depths), len(proof.Siblings))
+			assert.Check(t, merkle.Verify(root, key, []byte(fmt.Sprint(i)), proof), "count %d, leaf %d", count, i)
+			// The proof does not hold for another value or another key.
+			assert.Check(t, !merkle.Verify(root, key, []byte("other"), proof))
+			assert.Check(t, !merkle.Verify(root, "other", []byte(fmt.Sprint(i)), proof))
+			if len(proof.Depths) > 1 {
+				// The depths of the proof must match the tree.
+				proof.Depths[0], proof.Depths[1] = proof.Depths[1], proof.Depths[0]
+				assert.Check(t, !merkle.Verify(root, key, []byte(fmt.Sprint(i)), proof))
+			}
+		}
+	}
+
+	tree := merkle.NewTree()
+	_, err := tree.Proof("missing")
+	assert.ErrorIs(t, err, merkle.ErrKeyNotFound)
+	assert.Check(t, !merkle.Verify(tree.Root(), "missing", nil, &merkle.Proof{}))
+	tree.Set("present", nil)
+	_, err = tree.Proof("missing")
+	assert.ErrorIs(t, err, merkle.ErrKeyNotFound)
+}
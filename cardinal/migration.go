@@ -0,0 +1,110 @@
+package cardinal
+
+import (
+	"context"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/component"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// ComponentMigrationReport describes the migration of the stored values of a component.
+type ComponentMigrationReport struct {
+	Component string
+	// Steps is the number of registered migrations that are applied one after the other.
+	Steps int
+	// Values is the number of stored values that are (or would be) rewritten.
+	Values int
+}
+
+// RegisterComponentMigration registers a migration from the component type Old to the component type New. Both types
+// must have the same name, Old is usually a copy of the component struct as it was before it was changed.
+// When the schema stored for the component matches Old instead of the registered component, the stored values are
+// migrated when the world starts and the stored schema is updated. Migrations can be chained, e.g. V1 to V2 and V2 to
+// V3. The migration must be registered before the component.
+//
+// Usage:
+//
+// cardinal.RegisterComponentMigration(world, func(old HealthV1) (Health, error) {
+// return Health{Current: old.HP, Max: 100}, nil
+// })
+func RegisterComponentMigration[Old, New types.Component](w *World, migrate func(Old) (New, error)) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"world state is %s, expected %s to register component migration",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+
+	migration, err := component.NewMigration[Old, New](migrate)
+	if err != nil {
+		return err
+	}
+	return w.RegisterMigration(migration)
+}
+
+// DryRunComponentMigrations runs the pending component migrations on the stored values without saving anything, and
+// returns what would be migrated when the world starts. An error is returned if a stored value cannot be migrated.
+// It must be called after the components are registered and before the world is started.
+func DryRunComponentMigrations(w *World) ([]ComponentMigrationReport, error) {
+	if w.worldStage.Current() != worldstage.Init {
+		return nil, eris.Errorf(
+			"world state is %s, expected %s to dry run component migrations",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	return w.migrateComponents(context.Background(), true)
+}
+
+// migrateComponents rewrites the stored values of the components with pending migrations and updates their schemas.
+func (w *World) migrateComponents(ctx context.Context, dryRun bool) ([]ComponentMigrationReport, error) {
+	pending := w.PendingMigrations()
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	migrator, ok := w.entityStore.(gamestate.ComponentMigrator)
+	if !ok {
+		return nil, eris.New("component migrations are not supported by the configured game state manager")
+	}
+
+	migrations := make([]gamestate.ComponentMigration, 0, len(pending))
+	for _, p := range pending {
+		migrations = append(migrations, gamestate.ComponentMigration{
+			Component: p.Component,
+			Schema:    p.Component.GetSchema(),
+			Migrate:   p.Migrate,
+		})
+	}
+	counts, err := migrator.MigrateComponents(ctx, w.GetComponents(), migrations, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]ComponentMigrationReport, 0, len(pending))
+	for i, p := range pending {
+		reports = append(reports, ComponentMigrationReport{
+			Component: p.Component.Name(),
+			Steps:     len(p.Steps),
+			Values:    counts[i],
+		})
+		if dryRun {
+			continue
+		}
+		// The schema is only updated once the values are saved. If it can not be updated, the values are not migrated
+		// again on the next start, since MigrateComponents recorded that they were migrated.
+		if err := w.CompleteMigration(p.Component.Name()); err != nil {
+			return nil, err
+		}
+		if err := migrator.CompleteComponentMigration(ctx, p.Component.Name()); err != nil {
+			return nil, err
+		}
+		log.Info().Str("component", p.Component.Name()).Int("values", counts[i]).Msg("migrated component")
+	}
+	return reports, nil
+}
@@ -12,6 +12,9 @@ import (
 	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/router"
 	"pkg.world.dev/world-engine/cardinal/server"
+	"pkg.world.dev/world-engine/cardinal/txpool"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/sign"
 )
 
 // WorldOption represents an option that can be used to augment how the cardinal.World will be run.
@@ -30,6 +33,14 @@ func WithPort(port string) WorldOption {
 	}
 }
 
+// WithGRPCPort enables the gRPC API on the given port. It serves the Cardinal service defined in rift/proto/cardinal/v1
+// alongside the HTTP server.
+func WithGRPCPort(port string) WorldOption {
+	return WorldOption{
+		serverOption: server.WithGRPCPort(port),
+	}
+}
+
 // WithReceiptHistorySize specifies how many ticks worth of transaction receipts should be kept in memory. The default
 // is 10. A smaller number uses less memory, but limits the amount of historical receipts available.
 func WithReceiptHistorySize(size int) WorldOption {
@@ -40,6 +51,27 @@ func WithReceiptHistorySize(size int) WorldOption {
 	}
 }
 
+// WithReceiptStore persists the transaction receipts of the last `ticksToRetain` ticks in the game state storage
+// (Redis, or the storage set by WithStorageBackend), so receipts can be looked up by transaction hash long after they
+// are discarded from memory and after a restart (see /query/receipts/{txHash}).
+func WithReceiptStore(ticksToRetain uint64) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.receiptStoreSize = ticksToRetain
+		},
+	}
+}
+
+// WithReceiptStoreTTL persists the transaction receipts like WithReceiptStore, and removes them once they are older
+// than the given duration. When both options are used, receipts are removed as soon as either limit is reached.
+func WithReceiptStoreTTL(ttl time.Duration) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.receiptStoreTTL = ttl
+		},
+	}
+}
+
 // WithDisableSignatureVerification disables signature verification for the HTTP server. This should only be
 // used for local development.
 func WithDisableSignatureVerification() WorldOption {
@@ -48,6 +80,15 @@ func WithDisableSignatureVerification() WorldOption {
 	}
 }
 
+// WithStateDiffStream enables the /events/diffs websocket endpoint. Clients connected to it receive the entities that
+// were created and removed, and the component values that changed in every tick. Clients can limit the changes they
+// receive to a set of components with the components query parameter, e.g. /events/diffs?components=health,position.
+func WithStateDiffStream() WorldOption {
+	return WorldOption{
+		serverOption: server.EnableStateDiffStream(),
+	}
+}
+
 // WithMessageExpiration How long messages will live past their creation
 // time on the sender before they are considered to be expired and will
 // not be processed. Default is 10 seconds.
@@ -71,6 +112,18 @@ func WithHashCacheSize(sizeKB uint) WorldOption {
 	}
 }
 
+// WithNonceReplayProtection replaces the hash cache with per signer nonces for replay protection: the nonce of each
+// transaction must be higher than the nonce of the previous transaction of the same signer, and transactions must be
+// signed with sign.HashVersion1 (see sign.NewTransactionWithNonce). The nonces are stored in Redis (or the storage set
+// by WithStorageBackend), so replay protection is kept across restarts. Transactions still expire after the message
+// expiration time. The next nonce of a signer is returned by the /query/nonces/{signerAddress} endpoint.
+// This setting is ignored if the DisableSignatureVerification option is used
+func WithNonceReplayProtection() WorldOption {
+	return WorldOption{
+		serverOption: server.WithNonceReplayProtection(),
+	}
+}
+
 // WithTickChannel sets the channel that will be used to decide when world.doTick is executed. If unset, a loop interval
 // of 1 second will be set. To set some other time, use: WithTickChannel(time.Tick(<some-duration>)). Tests can pass
 // in a channel controlled by the test for fine-grained control over when ticks are executed.
@@ -100,6 +153,148 @@ func WithStoreManager(s gamestate.Manager) WorldOption {
 	}
 }
 
+// WithStorageBackend stores the game state, the nonces, the component schemas and the receipts in the given
+// PrimitiveStorage instead of Redis. This can be used together with gamestate.NewBadgerPrimitiveStorage to run a shard
+// without an external Redis instance: no connection to Redis is made. The storage will be closed when the World shuts
+// down.
+func WithStorageBackend(storage gamestate.PrimitiveStorage[string]) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.storageBackend = storage
+		},
+	}
+}
+
+// WithSnapshots periodically saves a full snapshot of the game state to the given store, once every `interval` ticks.
+// On StartGame, the state is restored from the newest snapshot (if it is newer than the state in storage), and only
+// the ticks after the snapshot are replayed from the base shard. See gamestate.NewFileSnapshotStore.
+func WithSnapshots(store gamestate.SnapshotStore, interval uint64) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.snapshotStore = store
+			world.snapshotInterval = interval
+		},
+	}
+}
+
+// WithStateHistory records the changes made to the game state in each tick, so that the state as it was in any of the
+// last `ticksToRetain` ticks can be queried (see the atTick parameter of the /query and /cql endpoints).
+func WithStateHistory(ticksToRetain uint64) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.stateHistorySize = ticksToRetain
+		},
+	}
+}
+
+// WithStateHashes records a hash of the game state at the end of each tick, and keeps the hashes of the last
+// `ticksToRetain` ticks. The hashes can be compared with the hashes of a replay of the same transactions to find the
+// tick where a game diverged (see ReplayAndCheck).
+func WithStateHashes(ticksToRetain uint64) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.stateHashSize = ticksToRetain
+		},
+	}
+}
+
+// WithStateRoots computes a Merkle root over the game state at the end of each tick, and keeps the state roots of the
+// last `ticksToRetain` ticks. In rollup mode, the state root of each tick is also submitted to the base shard along
+// with the transactions of the tick.
+func WithStateRoots(ticksToRetain uint64) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.stateRootSize = ticksToRetain
+		},
+	}
+}
+
+// WithMaxTxsPerTick limits the number of transactions handled in a tick. When more transactions are pending, the ones
+// with the highest priority (see WithTxPriority) are handled and the others are deferred to the next tick. Deferred
+// transactions get a receipt with Deferred set in each tick they are deferred.
+func WithMaxTxsPerTick(limit int) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			txpool.WithMaxTxsPerTick(limit)(world.txPool)
+		},
+	}
+}
+
+// WithMaxTxBytesPerTick limits the total size of the message bodies of the transactions handled in a tick. Like
+// WithMaxTxsPerTick, the transactions that do not fit are deferred to the next tick.
+func WithMaxTxBytesPerTick(limit int) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			txpool.WithMaxBytesPerTick(limit)(world.txPool)
+		},
+	}
+}
+
+// WithTxPoolSize limits the number of transactions waiting to be handled, including deferred transactions. The server
+// rejects transactions with 503 Service Unavailable while the pool is full.
+func WithTxPoolSize(limit int) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			txpool.WithMaxPoolSize(limit)(world.txPool)
+		},
+	}
+}
+
+// WithPersonaTxRateLimit limits the number of transactions each persona can submit in a tick. The server rejects the
+// transactions above the limit with 429 Too Many Requests.
+func WithPersonaTxRateLimit(txsPerTick int) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			txpool.WithPersonaRateLimit(txsPerTick)(world.txPool)
+		},
+	}
+}
+
+// WithTxPoolWAL logs the transactions accepted by the server to a write-ahead log in the given file, so that the
+// transactions that were accepted but not handled by a finalized tick are put back in the transaction pool by
+// StartGame after a crash or a restart. The restored transactions are validated again, like new transactions. NewWorld
+// fails if the file can not be opened.
+func WithTxPoolWAL(path string) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.txPoolWALPath = path
+		},
+	}
+}
+
+// TxPriorityFunc returns the priority of a transaction, given its message type, the signed transaction and the
+// decoded message. For example, transactions can be prioritized by message type, or by a fee field of the message.
+type TxPriorityFunc func(msgType types.Message, tx *sign.Transaction, msg any) int64
+
+// WithTxPriority sets the priority of transactions when they do not all fit in a tick (see WithMaxTxsPerTick).
+// Transactions with a higher priority are handled first, and transactions with the same priority are handled in the
+// order they were submitted. Transactions that were deferred too many times are handled first regardless of their
+// priority, see WithMaxTxDeferrals.
+func WithTxPriority(priority TxPriorityFunc) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			txpool.WithPriority(func(tx txpool.TxData) int64 {
+				msgType, ok := world.GetMessageByID(tx.MsgID)
+				if !ok {
+					return 0
+				}
+				return priority(msgType, tx.Tx, tx.Msg)
+			})(world.txPool)
+		},
+	}
+}
+
+// WithMaxTxDeferrals sets the number of ticks a transaction can be deferred to because transactions with a higher
+// priority filled the ticks. Once a transaction was deferred that many times, it is handled ahead of the transactions
+// that were deferred fewer times. The default is 10 ticks; 0 lets high priorities starve the low ones.
+func WithMaxTxDeferrals(ticks int) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			txpool.WithMaxDeferrals(ticks)(world.txPool)
+		},
+	}
+}
+
 // WithMockRedis runs the World with an embedded miniredis instance on port 6379.
 func WithMockRedis() WorldOption {
 	// Start a miniredis instance on port 6379.
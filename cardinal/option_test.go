@@ -1,6 +1,7 @@
 package cardinal_test
 
 import (
+	"context"
 	"io"
 	"os"
 	"testing"
@@ -11,6 +12,8 @@ import (
 
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/filter"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
 )
 
 func TestOptionFunctionSignatures(_ *testing.T) {
@@ -20,6 +23,45 @@ func TestOptionFunctionSignatures(_ *testing.T) {
 	cardinal.WithCustomLogger(zerolog.New(os.Stdout))
 	cardinal.WithPort("")
 	cardinal.WithPrettyLog() //nolint:staticcheck // not applicable.
+	cardinal.WithStorageBackend(nil)
+}
+
+func TestWithStorageBackend_StateIsNotStoredInRedis(t *testing.T) {
+	storage, err := gamestate.NewBadgerPrimitiveStorage("")
+	assert.NilError(t, err)
+	tf := cardinal.NewTestFixture(t, nil, cardinal.WithStorageBackend(storage))
+	world := tf.World
+
+	assert.NilError(t, cardinal.RegisterComponent[CounterComponent](world))
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx cardinal.WorldContext) error {
+		id := cardinal.NewSearch().Entity(filter.Exact(filter.Component[CounterComponent]())).MustFirst(wCtx)
+		return cardinal.UpdateComponent[CounterComponent](wCtx, id, func(c *CounterComponent) *CounterComponent {
+			c.Count++
+			return c
+		})
+	}))
+	tf.StartWorld()
+
+	id, err := cardinal.Create(cardinal.NewWorldContext(world), CounterComponent{})
+	assert.NilError(t, err)
+	for i := 0; i < 5; i++ {
+		tf.DoTick()
+	}
+
+	counter, err := cardinal.GetComponent[CounterComponent](cardinal.NewReadOnlyWorldContext(world), id)
+	assert.NilError(t, err)
+	assert.Equal(t, 5, counter.Count)
+
+	assert.NilError(t, world.UseIncreasingNonce("some-signer", 3))
+	nonce, err := world.GetNextNonce("some-signer")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(4), nonce)
+
+	// The finalized ticks, component schemas and nonces live in the storage backend, so nothing is written to redis.
+	tick, err := storage.GetUInt64(context.Background(), "ECB:LAST-FINALIZED-TICK")
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(5), tick)
+	assert.Equal(t, 0, len(tf.Redis.Keys()))
 }
 
 func TestWithPrettyLog_LogIsNotJSONFormatted(t *testing.T) {
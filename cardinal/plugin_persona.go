@@ -14,29 +14,11 @@ import (
 	"pkg.world.dev/world-engine/cardinal/types"
 )
 
-var (
-	_ Plugin = (*personaPlugin)(nil)
-
-	// TODO: Replace these global variables when indexing/fast-searching is supported.
-	// See https://linear.app/arguslabs/issue/WORLD-1057/spec-out-component-indexing
-	// These global variables are used to quickly identify already-created persona tags. The map should exactly match
-	// the persona tag information stored in the ECS layer. When Cardinal restarts, this map needs to be rebuilt.
-	//
-	// globalPersonaTagToAddressIndex keeps track of the mapping of persona-tags->signer-address so it doesn't need to
-	// be recomputed each tick.
-	globalPersonaTagToAddressIndex personaIndex
-	// tickOfPersonaTagToAddressIndex is the tick that the globalPersonaTagToAddressIndex was built on. In normal usage,
-	// wCtx.CurrentTick should always be greater than this number, but during tests the currentTick will be reset.
-	// Tracking this number at the global is easier than updating each test to reset these global value.
-	tickOfPersonaTagToAddressIndex uint64
-)
-
-type personaIndex = map[string]personaIndexEntry
+var _ Plugin = (*personaPlugin)(nil)
 
-type personaIndexEntry struct {
-	SignerAddress string
-	EntityID      types.EntityID
-}
+// personaTagIndexField is the name of the index on the lowercase persona tag of signer components. It is used to
+// quickly identify already-created persona tags.
+const personaTagIndexField = "persona_tag"
 
 type personaPlugin struct {
 }
@@ -76,7 +58,10 @@ func (p *personaPlugin) RegisterQueries(world *World) error {
 }
 
 func (p *personaPlugin) RegisterSystems(world *World) error {
-	err := RegisterSystems(world, createPersonaSystem, authorizePersonaAddressSystem)
+	// Personas are created and authorized before any other system runs, so systems in the same tick can use them.
+	err := world.SystemManager.registerSystems(
+		false, []SystemOption{WithPhase(PreUpdate)}, createPersonaSystem, authorizePersonaAddressSystem,
+	)
 	if err != nil {
 		return err
 	}
@@ -88,7 +73,8 @@ func (p *personaPlugin) RegisterComponents(world *World) error {
 	if err != nil {
 		return err
 	}
-	return nil
+	return RegisterIndex[component.SignerComponent](world, personaTagIndexField,
+		func(s component.SignerComponent) string { return strings.ToLower(s.PersonaTag) })
 }
 
 func (p *personaPlugin) RegisterMessages(world *World) error {
@@ -112,9 +98,6 @@ func (p *personaPlugin) RegisterMessages(world *World) error {
 // users who want to interact with the game via smart contract can link their EVM address to their persona tag, enabling
 // them to mutate their owned state from the context of the EVM.
 func authorizePersonaAddressSystem(wCtx WorldContext) error {
-	if err := buildGlobalPersonaIndex(wCtx); err != nil {
-		return err
-	}
 	return EachMessage[msg.AuthorizePersonaAddress, msg.AuthorizePersonaAddressResult](
 		wCtx,
 		func(txData TxData[msg.AuthorizePersonaAddress]) (
@@ -124,8 +107,10 @@ func authorizePersonaAddressSystem(wCtx WorldContext) error {
 			result.Success = false
 
 			// Check if the Persona Tag exists
-			lowerPersona := strings.ToLower(tx.PersonaTag)
-			data, ok := globalPersonaTagToAddressIndex[lowerPersona]
+			id, ok, err := findPersona(wCtx, tx.PersonaTag)
+			if err != nil {
+				return result, err
+			}
 			if !ok {
 				return result, eris.Errorf("persona %s does not exist", tx.PersonaTag)
 			}
@@ -139,7 +124,7 @@ func authorizePersonaAddressSystem(wCtx WorldContext) error {
 			}
 
 			err = UpdateComponent[component.SignerComponent](
-				wCtx, data.EntityID, func(s *component.SignerComponent) *component.SignerComponent {
+				wCtx, id, func(s *component.SignerComponent) *component.SignerComponent {
 					for _, addr := range s.AuthorizedAddresses {
 						if addr == txMsg.Address {
 							return s
@@ -165,9 +150,6 @@ func authorizePersonaAddressSystem(wCtx WorldContext) error {
 // createPersonaSystem is a system that will associate persona tags with signature addresses. Each persona tag
 // may have at most 1 signer, so additional attempts to register a signer with a persona tag will be ignored.
 func createPersonaSystem(wCtx WorldContext) error {
-	if err := buildGlobalPersonaIndex(wCtx); err != nil {
-		return err
-	}
 	return EachMessage[msg.CreatePersona, msg.CreatePersonaResult](
 		wCtx,
 		func(txData TxData[msg.CreatePersona]) (result msg.CreatePersonaResult, err error) {
@@ -183,9 +165,12 @@ func createPersonaSystem(wCtx WorldContext) error {
 				return result, err
 			}
 
-			// Temporarily convert tag to lowercase to check against mapping of lowercase tags
-			lowerPersona := strings.ToLower(txMsg.PersonaTag)
-			if _, ok := globalPersonaTagToAddressIndex[lowerPersona]; ok {
+			// Persona tags are case-insensitive
+			_, exists, err := findPersona(wCtx, txMsg.PersonaTag)
+			if err != nil {
+				return result, err
+			}
+			if exists {
 				// This PersonaTag has already been registered. Don't do anything
 				err = eris.Errorf("persona tag %s has already been registered", txMsg.PersonaTag)
 				return result, err
@@ -203,10 +188,6 @@ func createPersonaSystem(wCtx WorldContext) error {
 			); err != nil {
 				return result, eris.Wrap(err, "")
 			}
-			globalPersonaTagToAddressIndex[lowerPersona] = personaIndexEntry{
-				SignerAddress: txMsg.SignerAddress,
-				EntityID:      id,
-			}
 			result.Success = true
 			return result, nil
 		},
@@ -217,35 +198,18 @@ func createPersonaSystem(wCtx WorldContext) error {
 // Persona Index
 // -----------------------------------------------------------------------------
 
-func buildGlobalPersonaIndex(wCtx WorldContext) error {
-	// Rebuild the index if we haven't built it yet OR if we're in test and the CurrentTick has been reset.
-	if globalPersonaTagToAddressIndex != nil && tickOfPersonaTagToAddressIndex < wCtx.CurrentTick() {
-		return nil
-	}
-	tickOfPersonaTagToAddressIndex = wCtx.CurrentTick()
-	globalPersonaTagToAddressIndex = map[string]personaIndexEntry{}
-	var errs []error
-	s := NewSearch().Entity(filter.Exact(filter.Component[component.SignerComponent]()))
-	err := s.Each(wCtx,
-		func(id types.EntityID) bool {
-			sc, err := GetComponent[component.SignerComponent](wCtx, id)
-			if err != nil {
-				errs = append(errs, err)
-				return true
-			}
-			lowerPersona := strings.ToLower(sc.PersonaTag)
-			globalPersonaTagToAddressIndex[lowerPersona] = personaIndexEntry{
-				SignerAddress: sc.SignerAddress,
-				EntityID:      id,
-			}
-			return true
-		},
-	)
+// findPersona returns the entity that holds the signer component of the given persona tag. Persona tags are compared
+// case-insensitively.
+func findPersona(wCtx WorldContext, personaTag string) (types.EntityID, bool, error) {
+	ids, err := NewSearch().
+		Entity(filter.Exact(filter.Component[component.SignerComponent]())).
+		WhereIndexEquals(component.SignerComponent{}, personaTagIndexField, strings.ToLower(personaTag)).
+		Collect(wCtx)
 	if err != nil {
-		return err
+		return 0, false, err
 	}
-	if len(errs) != 0 {
-		return errors.Join(errs...)
+	if len(ids) == 0 {
+		return 0, false, nil
 	}
-	return nil
+	return ids[0], true, nil
 }
@@ -13,6 +13,7 @@ type QueryManager interface {
 	RegisterQuery(queryInput query) error
 	GetRegisteredQueries() []query
 	HandleQuery(group string, name string, bz []byte) ([]byte, error)
+	HandleQueryAtTick(group string, name string, bz []byte, tick uint64) ([]byte, error)
 	HandleQueryEVM(group string, name string, abiRequest []byte) ([]byte, error)
 	getQuery(group string, name string) (query, error)
 	BuildQueryFields() []types.FieldDetail
@@ -33,6 +34,11 @@ func newQueryManager(world *World) QueryManager {
 // RegisterQuery registers a query with the query manager.
 // There can only be one query with a given name.
 func (m *queryManager) RegisterQuery(queryInput query) error {
+	// The route of a query with the name of a resource in the resources group serves the resource instead.
+	if _, ok := m.world.resources[queryInput.Name()]; ok && queryInput.Group() == resourceQueryGroup {
+		return eris.Errorf("query %q of the %q group has the same name as a registered resource",
+			queryInput.Name(), resourceQueryGroup)
+	}
 	_, ok := m.registeredQueriesByGroup[queryInput.Group()]
 	if !ok {
 		m.registeredQueriesByGroup[queryInput.Group()] = make(map[string]query)
@@ -61,6 +67,19 @@ func (m *queryManager) HandleQuery(group string, name string, bz []byte) ([]byte
 	return q.handleQueryJSON(NewReadOnlyWorldContext(m.world), bz)
 }
 
+// HandleQueryAtTick runs the query against the game state as it was right after the given tick was finalized.
+func (m *queryManager) HandleQueryAtTick(group string, name string, bz []byte, tick uint64) ([]byte, error) {
+	q, err := m.getQuery(group, name)
+	if err != nil {
+		return nil, eris.Wrapf(err, "unable to find query %s/%s", group, name)
+	}
+	wCtx, err := NewReadOnlyWorldContextAtTick(m.world, tick)
+	if err != nil {
+		return nil, err
+	}
+	return q.handleQueryJSON(wCtx, bz)
+}
+
 func (m *queryManager) HandleQueryEVM(group string, name string, abiRequest []byte) ([]byte, error) {
 	q, err := m.getQuery(group, name)
 	if err != nil {
@@ -3,6 +3,7 @@
 package receipt
 
 import (
+	"sync"
 	"sync/atomic"
 
 	"github.com/rotisserie/eris"
@@ -19,17 +20,21 @@ var (
 // History keeps track of transaction "receipts" (the result of a transaction and any associated errors) for some number
 // of ticks.
 type History struct {
+	// mu guards history, which is written to by systems that may run concurrently.
+	mu           sync.RWMutex
 	currTick     *atomic.Uint64
 	ticksToStore uint64
 	// Receipts for a given tick are assigned to an index into this history slice which acts as a ring buffer.
 	history []map[types.TxHash]Receipt
 }
 
-// Receipt contains a transaction hash, an arbitrary result, and a list of errors.
+// Receipt contains a transaction hash, an arbitrary result, and a list of errors. Deferred is set when the transaction
+// did not fit in the tick and was left in the pool for the next tick.
 type Receipt struct {
-	TxHash types.TxHash
-	Result any
-	Errs   []error
+	TxHash   types.TxHash
+	Result   any
+	Errs     []error
+	Deferred bool
 }
 
 func (r Receipt) MarshalJSON() ([]byte, error) {
@@ -39,13 +44,15 @@ func (r Receipt) MarshalJSON() ([]byte, error) {
 	}
 
 	return codec.Encode(struct {
-		TxHash types.TxHash `json:"txHash"`
-		Result any          `json:"result"`
-		Errs   []string     `json:"errors"`
+		TxHash   types.TxHash `json:"txHash"`
+		Result   any          `json:"result"`
+		Errs     []string     `json:"errors"`
+		Deferred bool         `json:"deferred,omitempty"`
 	}{
-		TxHash: r.TxHash,
-		Result: r.Result,
-		Errs:   errStrings,
+		TxHash:   r.TxHash,
+		Result:   r.Result,
+		Errs:     errStrings,
+		Deferred: r.Deferred,
 	})
 }
 
@@ -73,6 +80,9 @@ func (h *History) Size() uint64 {
 // NextTick advances the internal History tick by 1. Errors and results can only be set on the current tick. Receipts
 // from ticks in the past are read only.
 func (h *History) NextTick() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	newCurr := h.currTick.Add(1)
 	mod := newCurr % h.ticksToStore
 	h.history[mod] = map[types.TxHash]Receipt{}
@@ -85,6 +95,9 @@ func (h *History) SetTick(tick uint64) {
 // AddError associates the given error with the given transaction hash. Calling this multiple times will append
 // the error any previously added errors.
 func (h *History) AddError(hash types.TxHash, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	tick := int(h.currTick.Load() % h.ticksToStore)
 	rec := h.history[tick][hash]
 	rec.TxHash = hash
@@ -95,6 +108,9 @@ func (h *History) AddError(hash types.TxHash, err error) {
 // SetResult sets the given transaction hash to the given result. Calling this multiple times will replace any previous
 // results.
 func (h *History) SetResult(hash types.TxHash, result any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	tick := int(h.currTick.Load() % h.ticksToStore)
 	rec := h.history[tick][hash]
 	rec.TxHash = hash
@@ -102,9 +118,36 @@ func (h *History) SetResult(hash types.TxHash, result any) {
 	h.history[tick][hash] = rec
 }
 
+// SetDeferred records that the given transaction hash was deferred to the next tick.
+func (h *History) SetDeferred(hash types.TxHash) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	tick := int(h.currTick.Load() % h.ticksToStore)
+	rec := h.history[tick][hash]
+	rec.TxHash = hash
+	rec.Deferred = true
+	h.history[tick][hash] = rec
+}
+
+// SetProcessed records that the given transaction hash was handled in the current tick, so it has a receipt even if no
+// result or error was set for it.
+func (h *History) SetProcessed(hash types.TxHash) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	tick := int(h.currTick.Load() % h.ticksToStore)
+	if _, ok := h.history[tick][hash]; !ok {
+		h.history[tick][hash] = Receipt{TxHash: hash}
+	}
+}
+
 // GetReceipt gets the receipt (the transaction result and the list of errors) for the given transaction hash in the
 // current tick. To get receipts from previous ticks use GetReceiptsForTick.
 func (h *History) GetReceipt(hash types.TxHash) (Receipt, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
 	tick := int(h.currTick.Load() % h.ticksToStore)
 	rec, ok := h.history[tick][hash]
 	return rec, ok
@@ -113,6 +156,9 @@ func (h *History) GetReceipt(hash types.TxHash) (Receipt, bool) {
 // GetReceiptsForTick gets all receipts for the given tick. If the tick is still active, or if the tick is too
 // far in the past, an error is returned.
 func (h *History) GetReceiptsForTick(tick uint64) ([]Receipt, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
 	currTick := h.currTick.Load()
 	// The requested tick is either in the future, or it is currently being processed. We don't yet know
 	// what the results of this tick will be.
@@ -0,0 +1,359 @@
+package receipt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+var ErrReceiptNotFound = eris.New("no receipt stored for transaction")
+
+const (
+	storeTxKeyPrefix   = "RECEIPT:TX:"
+	storeTickKeyPrefix = "RECEIPT:TICK:"
+	storeOldestTickKey = "RECEIPT:OLDEST"
+
+	// storeBatchSize is the largest number of ticks whose receipts are read from storage in one round trip.
+	storeBatchSize    = 1000
+	minStoreBatchSize = 16
+)
+
+// Store keeps the receipts of a number of ticks, or of a period of time, in a PrimitiveStorage (e.g. Redis or Badger),
+// so receipts can be looked up long after History discarded them, and after a restart. Receipts read from a Store
+// have a json.RawMessage Result.
+type Store struct {
+	storage      gamestate.PrimitiveStorage[string]
+	ticksToStore uint64
+	ttl          time.Duration
+	now          func() time.Time
+}
+
+// TickReceipts are the receipts of a tick.
+type TickReceipts struct {
+	Tick     uint64
+	Receipts []Receipt
+}
+
+// storedReceipt is the encoding of a receipt in the storage.
+type storedReceipt struct {
+	TxHash   types.TxHash    `json:"txHash"`
+	Tick     uint64          `json:"tick"`
+	Result   json.RawMessage `json:"result"`
+	Errs     []string        `json:"errors"`
+	Deferred bool            `json:"deferred,omitempty"`
+}
+
+// storedTick is the encoding of the list of the receipts of a tick in the storage. It is only stored for the ticks
+// that have receipts.
+type storedTick struct {
+	// Time is the time at which the receipts were saved, in Unix milliseconds.
+	Time     int64          `json:"time"`
+	TxHashes []types.TxHash `json:"txHashes"`
+}
+
+// NewStore creates a Store that keeps the receipts of the last ticksToStore ticks in the given storage, and removes
+// the receipts that were saved more than ttl ago. A limit of 0 is no limit.
+func NewStore(storage gamestate.PrimitiveStorage[string], ticksToStore uint64, ttl time.Duration) *Store {
+	return &Store{
+		storage:      storage,
+		ticksToStore: ticksToStore,
+		ttl:          ttl,
+		now:          time.Now,
+	}
+}
+
+// SaveReceipts saves the receipts of the given tick, and removes the receipts of the ticks that fell out of the
+// stored ticks or whose receipts are older than the TTL. The receipt of a transaction that was deferred replaces its
+// receipt of the tick it was deferred in.
+func (s *Store) SaveReceipts(ctx context.Context, tick uint64, receipts []Receipt) error {
+	now := s.now()
+	oldest, err := s.OldestTick(ctx)
+	if eris.Is(err, ErrReceiptNotFound) {
+		oldest = tick
+	} else if err != nil {
+		return err
+	}
+	// A tick that is saved again (e.g. in recovery) is older than the ticks that were saved after it.
+	oldest = min(oldest, tick)
+	newOldest, expiredKeys, err := s.expiredKeys(ctx, oldest, tick, now)
+	if err != nil {
+		return err
+	}
+
+	pipe, err := s.storage.StartTransaction(ctx)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	txHashes := make([]types.TxHash, 0, len(receipts))
+	for _, rec := range receipts {
+		bz, err := encodeReceipt(rec, tick)
+		if err != nil {
+			return err
+		}
+		if err := pipe.Set(ctx, storeTxKey(rec.TxHash), bz); err != nil {
+			return eris.Wrap(err, "")
+		}
+		txHashes = append(txHashes, rec.TxHash)
+	}
+	if len(txHashes) > 0 {
+		bz, err := json.Marshal(storedTick{Time: now.UnixMilli(), TxHashes: txHashes})
+		if err != nil {
+			return eris.Wrap(err, "")
+		}
+		if err := pipe.Set(ctx, storeTickKey(tick), bz); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	for _, key := range expiredKeys {
+		if err := pipe.Delete(ctx, key); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	if err := pipe.Set(ctx, storeOldestTickKey, newOldest); err != nil {
+		return eris.Wrap(err, "")
+	}
+	return eris.Wrap(pipe.EndTransaction(ctx), "")
+}
+
+// OldestTick returns the oldest tick whose receipts may still be stored. ErrReceiptNotFound is returned if no
+// receipts were saved yet.
+func (s *Store) OldestTick(ctx context.Context) (uint64, error) {
+	tick, err := s.storage.GetUInt64(ctx, storeOldestTickKey)
+	if eris.Is(eris.Cause(err), redis.Nil) {
+		return 0, eris.Wrap(ErrReceiptNotFound, "no receipts were saved")
+	} else if err != nil {
+		return 0, eris.Wrap(err, "")
+	}
+	return tick, nil
+}
+
+// GetReceipt returns the receipt of the given transaction, and the tick in which the transaction was handled.
+// ErrReceiptNotFound is returned if no receipt is stored for the transaction.
+func (s *Store) GetReceipt(ctx context.Context, txHash types.TxHash) (Receipt, uint64, error) {
+	bz, err := s.storage.GetBytes(ctx, storeTxKey(txHash))
+	if eris.Is(eris.Cause(err), redis.Nil) {
+		return Receipt{}, 0, eris.Wrapf(ErrReceiptNotFound, "tx %s", txHash)
+	} else if err != nil {
+		return Receipt{}, 0, eris.Wrap(err, "")
+	}
+	stored, err := decodeStoredReceipt(bz, txHash)
+	if err != nil {
+		return Receipt{}, 0, err
+	}
+	return decodeReceipt(stored), stored.Tick, nil
+}
+
+// GetReceiptsForTick returns the stored receipts of the given tick.
+func (s *Store) GetReceiptsForTick(ctx context.Context, tick uint64) ([]Receipt, error) {
+	ticks, err := s.GetReceiptsForTicks(ctx, tick, tick+1)
+	if err != nil || len(ticks) == 0 {
+		return nil, err
+	}
+	return ticks[0].Receipts, nil
+}
+
+// GetReceiptsForTicks returns the stored receipts of the ticks from startTick up to but not including endTick, in tick
+// order. Ticks without receipts are left out. The receipts of many ticks are read in a few round trips.
+func (s *Store) GetReceiptsForTicks(ctx context.Context, startTick, endTick uint64) ([]TickReceipts, error) {
+	oldest, err := s.OldestTick(ctx)
+	if eris.Is(err, ErrReceiptNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var ticks []TickReceipts
+	for start := max(startTick, oldest); start < endTick; start += storeBatchSize {
+		end := min(start+storeBatchSize, endTick)
+		stored, err := s.getTicks(ctx, start, end)
+		if err != nil {
+			return nil, err
+		}
+		txTicks, records, err := s.getReceipts(ctx, stored)
+		if err != nil {
+			return nil, err
+		}
+		for i, record := range records {
+			// The transaction was deferred in this tick, and its receipt was replaced by the one of a later tick.
+			if record == nil || record.Tick != txTicks[i] {
+				continue
+			}
+			if len(ticks) == 0 || ticks[len(ticks)-1].Tick != record.Tick {
+				ticks = append(ticks, TickReceipts{Tick: record.Tick})
+			}
+			last := &ticks[len(ticks)-1]
+			last.Receipts = append(last.Receipts, decodeReceipt(record))
+		}
+	}
+	return ticks, nil
+}
+
+// expiredKeys returns the keys of the receipts that expire when the given tick is saved, and the oldest tick whose
+// receipts are kept. The ticks are walked from the oldest stored tick until a tick whose receipts are kept. Ticks
+// without receipts are skipped, so every tick is only walked once.
+func (s *Store) expiredKeys(ctx context.Context, oldest, tick uint64, now time.Time) (uint64, []string, error) {
+	// Without a TTL, only the ticks that fell out of the stored ticks can expire.
+	limit := tick
+	if s.ttl == 0 {
+		limit = min(limit, max(tick+1, s.ticksToStore)-s.ticksToStore)
+	}
+	var expiredKeys []string
+	expiredTicks := map[uint64]*storedTick{}
+	// The oldest stored tick is usually kept, so the ticks are read in batches that start small.
+	batchSize := uint64(minStoreBatchSize)
+walk:
+	for oldest < limit {
+		end := min(oldest+batchSize, limit)
+		batchSize = min(batchSize*2, storeBatchSize)
+		stored, err := s.getTicks(ctx, oldest, end)
+		if err != nil {
+			return 0, nil, err
+		}
+		for ; oldest < end; oldest++ {
+			record, ok := stored[oldest]
+			expired := s.ticksToStore > 0 && oldest+s.ticksToStore <= tick
+			if ok && !expired && s.ttl > 0 {
+				expired = now.Sub(time.UnixMilli(record.Time)) >= s.ttl
+			}
+			if !ok {
+				// There is nothing to remove.
+				continue
+			} else if !expired {
+				break walk
+			}
+			expiredTicks[oldest] = record
+			expiredKeys = append(expiredKeys, storeTickKey(oldest))
+		}
+	}
+
+	// The receipts of the transactions that were deferred in an expired tick may be from a later tick.
+	txTicks, records, err := s.getReceipts(ctx, expiredTicks)
+	if err != nil {
+		return 0, nil, err
+	}
+	for i, record := range records {
+		if record != nil && record.Tick == txTicks[i] {
+			expiredKeys = append(expiredKeys, storeTxKey(record.TxHash))
+		}
+	}
+	return oldest, expiredKeys, nil
+}
+
+// getTicks returns the lists of receipts of the ticks from start up to but not including end, by tick. Ticks without
+// receipts are left out.
+func (s *Store) getTicks(ctx context.Context, start, end uint64) (map[uint64]*storedTick, error) {
+	keys := make([]string, 0, end-start)
+	for t := start; t < end; t++ {
+		keys = append(keys, storeTickKey(t))
+	}
+	values, err := gamestate.GetManyBytesFrom(ctx, s.storage, keys)
+	if err != nil {
+		return nil, err
+	}
+	ticks := map[uint64]*storedTick{}
+	for i, bz := range values {
+		if bz == nil {
+			continue
+		}
+		tick := start + uint64(i)
+		stored := &storedTick{}
+		if err := json.Unmarshal(bz, stored); err != nil {
+			return nil, eris.Wrapf(err, "failed to decode the receipts of tick %d", tick)
+		}
+		ticks[tick] = stored
+	}
+	return ticks, nil
+}
+
+// getReceipts reads the receipts listed by the given ticks in one round trip. For each receipt, the tick that lists
+// it is returned with it, in tick order. The receipts that are not found are nil.
+func (s *Store) getReceipts(ctx context.Context, ticks map[uint64]*storedTick) ([]uint64, []*storedReceipt, error) {
+	order := make([]uint64, 0, len(ticks))
+	for tick := range ticks {
+		order = append(order, tick)
+	}
+	slices.Sort(order)
+	var txTicks []uint64
+	var keys []string
+	for _, tick := range order {
+		for _, txHash := range ticks[tick].TxHashes {
+			txTicks = append(txTicks, tick)
+			keys = append(keys, storeTxKey(txHash))
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil, nil
+	}
+	values, err := gamestate.GetManyBytesFrom(ctx, s.storage, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	records := make([]*storedReceipt, len(values))
+	for i, bz := range values {
+		if bz == nil {
+			continue
+		}
+		records[i], err = decodeStoredReceipt(bz, types.TxHash(keys[i][len(storeTxKeyPrefix):]))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return txTicks, records, nil
+}
+
+func decodeStoredReceipt(bz []byte, txHash types.TxHash) (*storedReceipt, error) {
+	stored := &storedReceipt{}
+	if err := json.Unmarshal(bz, stored); err != nil {
+		return nil, eris.Wrapf(err, "failed to decode the receipt of tx %s", txHash)
+	}
+	return stored, nil
+}
+
+func encodeReceipt(rec Receipt, tick uint64) ([]byte, error) {
+	result, err := json.Marshal(rec.Result)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to encode the result of tx %s", rec.TxHash)
+	}
+	errStrings := make([]string, len(rec.Errs))
+	for i, err := range rec.Errs {
+		errStrings[i] = err.Error()
+	}
+	bz, err := json.Marshal(storedReceipt{
+		TxHash:   rec.TxHash,
+		Tick:     tick,
+		Result:   result,
+		Errs:     errStrings,
+		Deferred: rec.Deferred,
+	})
+	return bz, eris.Wrap(err, "")
+}
+
+func decodeReceipt(stored *storedReceipt) Receipt {
+	rec := Receipt{
+		TxHash:   stored.TxHash,
+		Deferred: stored.Deferred,
+	}
+	if string(stored.Result) != "null" {
+		rec.Result = stored.Result
+	}
+	for _, errString := range stored.Errs {
+		rec.Errs = append(rec.Errs, errors.New(errString))
+	}
+	return rec
+}
+
+func storeTxKey(txHash types.TxHash) string {
+	return storeTxKeyPrefix + string(txHash)
+}
+
+func storeTickKey(tick uint64) string {
+	return fmt.Sprintf("%s%d", storeTickKeyPrefix, tick)
+}
@@ -0,0 +1,160 @@
+package receipt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+func newTestStore(t *testing.T, ticksToStore uint64, ttl time.Duration) *Store {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	storage := gamestate.NewRedisPrimitiveStorage(client)
+	return NewStore(&storage, ticksToStore, ttl)
+}
+
+func TestStoreCanSaveAndGetReceipts(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, 10, 0)
+	hashA, hashB := txHash(t), txHash(t)
+
+	assert.NilError(t, store.SaveReceipts(ctx, 3, []Receipt{
+		{TxHash: hashA, Result: map[string]int{"value": 1}},
+		{TxHash: hashB, Errs: []error{errors.New("some error")}},
+	}))
+
+	rec, tick, err := store.GetReceipt(ctx, hashA)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(3), tick)
+	assert.Equal(t, 0, len(rec.Errs))
+	assert.Equal(t, `{"value":1}`, string(rec.Result.(json.RawMessage)))
+
+	rec, tick, err = store.GetReceipt(ctx, hashB)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(3), tick)
+	assert.Equal(t, nil, rec.Result)
+	assert.Equal(t, 1, len(rec.Errs))
+	assert.Equal(t, "some error", rec.Errs[0].Error())
+
+	recs, err := store.GetReceiptsForTick(ctx, 3)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(recs))
+
+	recs, err = store.GetReceiptsForTick(ctx, 4)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(recs))
+
+	_, _, err = store.GetReceipt(ctx, txHash(t))
+	assert.ErrorIs(t, err, ErrReceiptNotFound)
+}
+
+func TestStoreRemovesOldReceipts(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, 2, 0)
+	hashes := make([]types.TxHash, 0, 3)
+	for tick := uint64(0); tick < 3; tick++ {
+		hash := txHash(t)
+		hashes = append(hashes, hash)
+		assert.NilError(t, store.SaveReceipts(ctx, tick, []Receipt{{TxHash: hash}}))
+	}
+
+	_, _, err := store.GetReceipt(ctx, hashes[0])
+	assert.ErrorIs(t, err, ErrReceiptNotFound)
+	recs, err := store.GetReceiptsForTick(ctx, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(recs))
+
+	for _, hash := range hashes[1:] {
+		_, _, err = store.GetReceipt(ctx, hash)
+		assert.NilError(t, err)
+	}
+}
+
+func TestStoreReplacesTheReceiptOfADeferredTransaction(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, 2, 0)
+	hash := txHash(t)
+
+	assert.NilError(t, store.SaveReceipts(ctx, 0, []Receipt{{TxHash: hash, Deferred: true}}))
+	rec, tick, err := store.GetReceipt(ctx, hash)
+	assert.NilError(t, err)
+	assert.Check(t, rec.Deferred)
+	assert.Equal(t, uint64(0), tick)
+
+	assert.NilError(t, store.SaveReceipts(ctx, 1, []Receipt{{TxHash: hash, Result: "done"}}))
+	rec, tick, err = store.GetReceipt(ctx, hash)
+	assert.NilError(t, err)
+	assert.Check(t, !rec.Deferred)
+	assert.Equal(t, uint64(1), tick)
+
+	// The receipt is no longer listed in the tick the transaction was deferred in.
+	recs, err := store.GetReceiptsForTick(ctx, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(recs))
+
+	// Removing the tick the transaction was deferred in keeps its final receipt.
+	assert.NilError(t, store.SaveReceipts(ctx, 2, nil))
+	_, tick, err = store.GetReceipt(ctx, hash)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), tick)
+}
+
+func TestStoreRemovesReceiptsOlderThanTheTTL(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, 0, time.Minute)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	oldHash, newHash := txHash(t), txHash(t)
+
+	assert.NilError(t, store.SaveReceipts(ctx, 0, []Receipt{{TxHash: oldHash}}))
+	for tick := uint64(1); tick < 5; tick++ {
+		assert.NilError(t, store.SaveReceipts(ctx, tick, nil))
+	}
+	now = now.Add(30 * time.Second)
+	assert.NilError(t, store.SaveReceipts(ctx, 5, []Receipt{{TxHash: newHash}}))
+	_, _, err := store.GetReceipt(ctx, oldHash)
+	assert.NilError(t, err)
+
+	now = now.Add(30 * time.Second)
+	assert.NilError(t, store.SaveReceipts(ctx, 6, nil))
+	_, _, err = store.GetReceipt(ctx, oldHash)
+	assert.ErrorIs(t, err, ErrReceiptNotFound)
+	_, _, err = store.GetReceipt(ctx, newHash)
+	assert.NilError(t, err)
+	// The ticks without receipts before the oldest kept receipts are skipped.
+	oldest, err := store.OldestTick(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(5), oldest)
+}
+
+func TestStoreCanGetTheReceiptsOfManyTicks(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, 3000, 0)
+	hashes := map[uint64]types.TxHash{}
+	for tick := uint64(0); tick < 2500; tick++ {
+		var recs []Receipt
+		if tick%100 == 0 {
+			hashes[tick] = txHash(t)
+			recs = append(recs, Receipt{TxHash: hashes[tick]})
+		}
+		assert.NilError(t, store.SaveReceipts(ctx, tick, recs))
+	}
+
+	ticks, err := store.GetReceiptsForTicks(ctx, 50, 2500)
+	assert.NilError(t, err)
+	assert.Equal(t, 24, len(ticks))
+	for i, tick := range ticks {
+		assert.Equal(t, uint64(i+1)*100, tick.Tick)
+		assert.Equal(t, 1, len(tick.Receipts))
+		assert.Equal(t, hashes[tick.Tick], tick.Receipts[0].TxHash)
+	}
+}
@@ -0,0 +1,124 @@
+package cardinal
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/filter"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// SetParent makes parent the parent of child, replacing the current parent of child. Relationships are kept up to date
+// by the game state: when an entity is removed, its children (and their children) are removed with it, so child
+// entities like inventory items never refer to a removed owner.
+// An error is returned if either entity does not exist, or if child is parent or one of its ancestors.
+func SetParent(wCtx WorldContext, child, parent types.EntityID) (err error) {
+	defer func() { panicOnFatalError(wCtx, err) }()
+
+	writer, err := relationshipWriter(wCtx)
+	if err != nil {
+		return err
+	}
+	return writer.SetParent(child, parent)
+}
+
+// RemoveParent detaches child from its parent, so it is no longer removed along with it. Nothing happens if child has
+// no parent.
+func RemoveParent(wCtx WorldContext, child types.EntityID) (err error) {
+	defer func() { panicOnFatalError(wCtx, err) }()
+
+	writer, err := relationshipWriter(wCtx)
+	if err != nil {
+		return err
+	}
+	return writer.RemoveParent(child)
+}
+
+// Parent returns the parent of the given entity. The returned bool is false if the entity has no parent.
+func Parent(wCtx WorldContext, id types.EntityID) (parent types.EntityID, ok bool, err error) {
+	defer func() { panicOnFatalError(wCtx, err) }()
+
+	reader, err := relationshipReader(wCtx)
+	if err != nil {
+		return 0, false, err
+	}
+	return reader.GetParent(id)
+}
+
+// Children returns the IDs of the children of the given entity, sorted by ID.
+func Children(wCtx WorldContext, id types.EntityID) (children []types.EntityID, err error) {
+	defer func() { panicOnFatalError(wCtx, err) }()
+
+	reader, err := relationshipReader(wCtx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.GetChildren(id)
+}
+
+// ChildOf is a search filter that matches the children of the given entity.
+//
+// Usage:
+//
+// cardinal.NewSearch().Entity(filter.Contains(filter.Component[Item]())).Where(cardinal.ChildOf(playerID))
+func ChildOf(parent types.EntityID) FilterFn {
+	return func(wCtx WorldContext, id types.EntityID) (bool, error) {
+		reader, err := relationshipReader(wCtx)
+		if err != nil {
+			return false, err
+		}
+		p, ok, err := reader.GetParent(id)
+		if err != nil {
+			return false, err
+		}
+		return ok && p == parent, nil
+	}
+}
+
+// ParentMatches is a search filter that matches the entities whose parent has components matching the given filter.
+//
+// Usage:
+//
+// cardinal.NewSearch().Entity(filter.Contains(filter.Component[Unit]())).
+// Where(cardinal.ParentMatches(filter.Contains(filter.Component[Squad]())))
+func ParentMatches(parentFilter filter.ComponentFilter) FilterFn {
+	return func(wCtx WorldContext, id types.EntityID) (bool, error) {
+		reader, err := relationshipReader(wCtx)
+		if err != nil {
+			return false, err
+		}
+		parent, ok, err := reader.GetParent(id)
+		if err != nil || !ok {
+			return false, err
+		}
+		comps, err := wCtx.storeReader().GetComponentTypesForEntity(parent)
+		if err != nil {
+			return false, err
+		}
+		return parentFilter.MatchesComponents(types.ConvertComponentMetadatasToComponents(comps)), nil
+	}
+}
+
+func relationshipReader(wCtx WorldContext) (gamestate.RelationshipReader, error) {
+	reader, ok := wCtx.storeReader().(gamestate.RelationshipReader)
+	if !ok {
+		return nil, eris.New("relationships are not supported by the configured game state reader")
+	}
+	return reader, nil
+}
+
+func relationshipWriter(wCtx WorldContext) (gamestate.RelationshipWriter, error) {
+	// Error if the context is read only
+	if wCtx.isReadOnly() {
+		return nil, ErrEntityMutationOnReadOnly
+	}
+	// Relationships decide which entities are removed together, so changing them is a structural change.
+	if err := wCtx.componentAccess().checkStructuralChange(); err != nil {
+		return nil, err
+	}
+	writer, ok := wCtx.storeManager().(gamestate.RelationshipWriter)
+	if !ok {
+		return nil, eris.New("relationships are not supported by the configured game state manager")
+	}
+	return writer, nil
+}
@@ -0,0 +1,88 @@
+package cardinal_test
+
+import (
+	"errors"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+func TestParentChildRelationships(t *testing.T) {
+	tf := cardinal.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Player](world))
+	assert.NilError(t, cardinal.RegisterComponent[AlphaTest](world))
+	assert.NilError(t, cardinal.RegisterComponent[BetaTest](world))
+	tf.StartWorld()
+
+	wCtx := cardinal.NewWorldContext(world)
+	player, err := cardinal.Create(wCtx, Player{})
+	assert.NilError(t, err)
+	items, err := cardinal.CreateMany(wCtx, 3, AlphaTest{})
+	assert.NilError(t, err)
+	for _, item := range items {
+		assert.NilError(t, cardinal.SetParent(wCtx, item, player))
+	}
+	gem, err := cardinal.Create(wCtx, BetaTest{})
+	assert.NilError(t, err)
+	assert.NilError(t, cardinal.SetParent(wCtx, gem, items[0]))
+	unowned, err := cardinal.Create(wCtx, AlphaTest{})
+	assert.NilError(t, err)
+
+	children, err := cardinal.Children(wCtx, player)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, items, children)
+	parent, ok, err := cardinal.Parent(wCtx, gem)
+	assert.NilError(t, err)
+	assert.Check(t, ok)
+	assert.Equal(t, items[0], parent)
+	_, ok, err = cardinal.Parent(wCtx, unowned)
+	assert.NilError(t, err)
+	assert.Check(t, !ok)
+
+	// An entity cannot become its own ancestor.
+	assert.ErrorIs(t, cardinal.SetParent(wCtx, player, gem), cardinal.ErrRelationshipCycle)
+
+	search := cardinal.NewSearch().Entity(filter.Contains(filter.Component[AlphaTest]()))
+	got, err := search.Where(cardinal.ChildOf(player)).Collect(wCtx)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, items, got)
+	got, err = cardinal.NewSearch().Entity(filter.All()).
+		Where(cardinal.ParentMatches(filter.Contains(filter.Component[AlphaTest]()))).
+		Collect(wCtx)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{gem}, got)
+
+	tf.DoTick()
+
+	// Moving an item to another parent detaches it from the old one.
+	assert.NilError(t, cardinal.SetParent(wCtx, items[2], unowned))
+	readOnlyCtx := cardinal.NewReadOnlyWorldContext(world)
+	children, err = cardinal.Children(readOnlyCtx, player)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, items, children)
+
+	tf.DoTick()
+
+	children, err = cardinal.Children(readOnlyCtx, player)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, items[:2], children)
+
+	// Removing an entity removes its descendants.
+	assert.NilError(t, cardinal.Remove(wCtx, player))
+	tf.DoTick()
+	for _, id := range []types.EntityID{items[0], items[1], gem} {
+		_, _, err = cardinal.Parent(wCtx, id)
+		assert.Check(t, errors.Is(err, cardinal.ErrEntityDoesNotExist))
+	}
+
+	count, err := search.Count(wCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, count)
+	children, err = cardinal.Children(readOnlyCtx, unowned)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{items[2]}, children)
+}
@@ -0,0 +1,139 @@
+package cardinal
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/component"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// resourceQueryGroup is the query group whose route serves the values of resources. A query of this group can't have
+// the name of a resource.
+const resourceQueryGroup = "resources"
+
+// RegisterResource registers the world resource T. A resource is a single world-global value, like the game config or
+// the current season, that is not attached to an entity. Its value is saved with the tick like component values and
+// can be read by clients with the /query/resources/{name} endpoint. The same options as components are supported, e.g.
+// component.WithDefault sets the value of the resource until it is first set.
+// Systems registered with an access declaration must declare the resources they use in Reads and Writes.
+func RegisterResource[T types.Component](w *World, opts ...component.Option[T]) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"world state is %s, expected %s to register resource",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+
+	resource, err := component.NewComponentMetadata[T](opts...)
+	if err != nil {
+		return err
+	}
+	if _, ok := w.resources[resource.Name()]; ok {
+		return eris.Errorf("resource %q is already registered", resource.Name())
+	}
+	if _, err := w.GetComponentByName(resource.Name()); err == nil {
+		return eris.Errorf("resource %q has the same name as a registered component", resource.Name())
+	}
+	if _, err := w.getQuery(resourceQueryGroup, resource.Name()); err == nil {
+		return eris.Errorf("resource %q has the same name as a query of the %q group, which would be unreachable",
+			resource.Name(), resourceQueryGroup)
+	}
+	w.resources[resource.Name()] = resource
+	return nil
+}
+
+// GetResource returns the value of the resource T, or its default value if it was never set.
+func GetResource[T types.Component](wCtx WorldContext) (res *T, err error) {
+	defer func() { panicOnFatalError(wCtx, err) }()
+
+	var t T
+	if err = wCtx.componentAccess().checkRead(t.Name()); err != nil {
+		return nil, err
+	}
+	resource, err := wCtx.getResourceByName(t.Name())
+	if err != nil {
+		return nil, err
+	}
+	reader, ok := wCtx.storeReader().(gamestate.ResourceReader)
+	if !ok {
+		return nil, eris.New("resources are not supported by the configured game state reader")
+	}
+	value, err := reader.GetResource(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok = value.(T)
+	if !ok {
+		res, ok = value.(*T)
+		if !ok {
+			return nil, eris.Errorf("unexpected value of type %T for resource %q", value, t.Name())
+		}
+		return res, nil
+	}
+	return &t, nil
+}
+
+// SetResource sets the value of the resource T. The value is saved when the tick is finalized.
+func SetResource[T types.Component](wCtx WorldContext, value T) (err error) {
+	defer func() { panicOnFatalError(wCtx, err) }()
+
+	// Error if the context is read only
+	if wCtx.isReadOnly() {
+		return ErrEntityMutationOnReadOnly
+	}
+	if err = wCtx.componentAccess().checkWrite(value.Name()); err != nil {
+		return err
+	}
+	resource, err := wCtx.getResourceByName(value.Name())
+	if err != nil {
+		return err
+	}
+	writer, ok := wCtx.storeManager().(gamestate.ResourceWriter)
+	if !ok {
+		return eris.New("resources are not supported by the configured game state manager")
+	}
+	return writer.SetResource(resource, value)
+}
+
+// GetResourceByName returns the metadata of the resource with the given name.
+func (w *World) GetResourceByName(name string) (types.ComponentMetadata, error) {
+	resource, ok := w.resources[name]
+	if !ok {
+		return nil, eris.Wrap(types.ErrResourceNotRegistered, fmt.Sprintf("resource %q is not registered", name))
+	}
+	return resource, nil
+}
+
+// GetResourceInRawJSON returns the value of the resource with the given name as JSON, as of the last finalized tick.
+func (w *World) GetResourceInRawJSON(name string) (json.RawMessage, error) {
+	return w.getResourceInRawJSON(w.StoreReader(), name)
+}
+
+// GetResourceInRawJSONAtTick returns the value of the resource with the given name as JSON, as it was right after the
+// given tick was finalized.
+func (w *World) GetResourceInRawJSONAtTick(name string, tick uint64) (json.RawMessage, error) {
+	reader, err := w.StoreReaderAtTick(tick)
+	if err != nil {
+		return nil, err
+	}
+	return w.getResourceInRawJSON(reader, name)
+}
+
+func (w *World) getResourceInRawJSON(reader gamestate.Reader, name string) (json.RawMessage, error) {
+	resource, err := w.GetResourceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	resourceReader, ok := reader.(gamestate.ResourceReader)
+	if !ok {
+		return nil, eris.New("resources are not supported by the configured game state reader")
+	}
+	return resourceReader.GetResourceInRawJSON(resource)
+}
@@ -0,0 +1,95 @@
+package cardinal_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/component"
+)
+
+type Season struct {
+	Number int
+}
+
+func (Season) Name() string {
+	return "season"
+}
+
+func TestResources(t *testing.T) {
+	tf := cardinal.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterResource[Season](world, component.WithDefault(Season{Number: 1})))
+	assert.ErrorContains(t, cardinal.RegisterResource[Season](world), "already registered")
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.ErrorContains(t, cardinal.RegisterResource[Health](world), "same name as a registered component")
+	assert.ErrorContains(t, cardinal.RegisterComponent[Season](world), "same name as a registered resource")
+	type SeasonInfo struct {
+		Name string
+	}
+	assert.ErrorContains(t, cardinal.RegisterQuery[SeasonInfo, SeasonInfo](world, "season",
+		func(_ cardinal.WorldContext, req *SeasonInfo) (*SeasonInfo, error) { return req, nil },
+		cardinal.WithCustomQueryGroup[SeasonInfo, SeasonInfo]("resources")), "same name as a registered resource")
+	// Other queries of the resources group are still served by the query handler
+	assert.NilError(t, cardinal.RegisterQuery[SeasonInfo, SeasonInfo](world, "info",
+		func(_ cardinal.WorldContext, req *SeasonInfo) (*SeasonInfo, error) { return req, nil },
+		cardinal.WithCustomQueryGroup[SeasonInfo, SeasonInfo]("resources")))
+	var seenInTick int
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx cardinal.WorldContext) error {
+		season, err := cardinal.GetResource[Season](wCtx)
+		if err != nil {
+			return err
+		}
+		if err = cardinal.SetResource(wCtx, Season{Number: season.Number + 1}); err != nil {
+			return err
+		}
+		// The new value is visible for the rest of the tick
+		season, err = cardinal.GetResource[Season](wCtx)
+		if err != nil {
+			return err
+		}
+		seenInTick = season.Number
+		return nil
+	}))
+	tf.StartWorld()
+
+	readOnlyCtx := cardinal.NewReadOnlyWorldContext(world)
+	season, err := cardinal.GetResource[Season](readOnlyCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, season.Number)
+	assert.ErrorIs(t, cardinal.SetResource(readOnlyCtx, Season{}), cardinal.ErrEntityMutationOnReadOnly)
+
+	tf.DoTick()
+	tf.DoTick()
+	assert.Equal(t, 3, seenInTick)
+	season, err = cardinal.GetResource[Season](readOnlyCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, 3, season.Number)
+
+	res := tf.Post("query/resources/season", nil)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	bz, err := io.ReadAll(res.Body)
+	assert.NilError(t, err)
+	var got Season
+	assert.NilError(t, json.Unmarshal(bz, &got))
+	assert.Equal(t, 3, got.Number)
+	res = tf.Post("query/resources/unknown", nil)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	res = tf.Post("query/resources/info", SeasonInfo{Name: "summer"})
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	var info SeasonInfo
+	assert.NilError(t, json.NewDecoder(res.Body).Decode(&info))
+	assert.Equal(t, "summer", info.Name)
+
+	// The resource is saved with the game state
+	tf2 := cardinal.NewTestFixture(t, tf.Redis)
+	assert.NilError(t, cardinal.RegisterResource[Season](tf2.World))
+	assert.NilError(t, cardinal.RegisterComponent[Health](tf2.World))
+	tf2.StartWorld()
+	season, err = cardinal.GetResource[Season](cardinal.NewReadOnlyWorldContext(tf2.World))
+	assert.NilError(t, err)
+	assert.Equal(t, 3, season.Number)
+}
@@ -131,6 +131,8 @@ func protoTxToSignTx(t *shard.Transaction) *sign.Transaction {
 		Signature:  t.GetSignature(),
 		Hash:       common.Hash{},
 		Body:       t.GetBody(),
+		Nonce:      t.GetNonce(),
+		Version:    t.GetVersion(),
 	}
 	// HashHex will populate the hash.
 	tx.HashHex()
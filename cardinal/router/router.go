@@ -41,12 +41,14 @@ type Router interface {
 	// route requests from the EVM to this game shard by using its namespace.
 	RegisterGameShard(context.Context) error
 
-	// SubmitTxBlob submits transactions processed in a tick to the base shard.
+	// SubmitTxBlob submits transactions processed in a tick to the base shard, along with the state root of the game
+	// state at the end of the tick.
 	SubmitTxBlob(
 		ctx context.Context,
 		processedTxs txpool.TxMap,
 		epoch,
 		unixTimestamp uint64,
+		stateRoot []byte,
 	) error
 
 	TransactionIterator() iterator.Iterator
@@ -135,6 +137,7 @@ func (r *router) SubmitTxBlob(
 	processedTxs txpool.TxMap,
 	epoch,
 	unixTimestamp uint64,
+	stateRoot []byte,
 ) error {
 	_, span := r.tracer.Start(ddotel.ContextWithStartOptions(ctx, ddtracer.Measured()), "router.submit-tx-blob")
 	defer span.End()
@@ -150,6 +153,8 @@ func (r *router) SubmitTxBlob(
 				Timestamp:  tx.Timestamp,
 				Signature:  tx.Signature,
 				Body:       tx.Body,
+				Nonce:      tx.Nonce,
+				Version:    tx.Version,
 			})
 		}
 		messageIDtoTxs[uint64(msgID)] = &shard.Transactions{Txs: protoTxs}
@@ -160,6 +165,7 @@ func (r *router) SubmitTxBlob(
 		UnixTimestamp: unixTimestamp,
 		Namespace:     r.namespace,
 		Transactions:  messageIDtoTxs,
+		StateRoot:     stateRoot,
 	}
 
 	_, err := r.sequencerJobQueue.Enqueue(&req)
@@ -7,6 +7,7 @@ import (
 	"github.com/rotisserie/eris"
 
 	"pkg.world.dev/world-engine/cardinal/filter"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
 	"pkg.world.dev/world-engine/cardinal/types"
 )
 
@@ -21,6 +22,9 @@ type cache struct {
 type EntitySearch interface {
 	Searchable
 	Where(componentFilter FilterFn) EntitySearch
+	WhereIndexEquals(component types.Component, field string, value any) EntitySearch
+	WhereIndexRange(indexFilter IndexFilter) EntitySearch
+	WhereSpatial(spatialFilter SpatialFilter) EntitySearch
 }
 
 type Searchable interface {
@@ -44,6 +48,8 @@ type Search struct {
 	archMatches             *cache
 	filter                  filter.ComponentFilter
 	componentPropertyFilter FilterFn
+	indexFilters            []IndexFilter
+	spatialFilters          []SpatialFilter
 }
 
 // interfaces restrict order of operations.
@@ -144,6 +150,44 @@ func (s *Search) Where(componentFilter FilterFn) EntitySearch {
 		archMatches:             &cache{},
 		filter:                  s.filter,
 		componentPropertyFilter: componentPropertyFilter,
+		indexFilters:            s.indexFilters,
+		spatialFilters:          s.spatialFilters,
+	}
+}
+
+// WhereIndexEquals restricts the search to the entities whose indexed field of the given component is equal to value
+// (see RegisterIndex). The value must have the type returned by the extractor of the index. Instead of going through
+// every entity of the matching archetypes, only the entities found in the index are evaluated, so it should be used
+// over Where(ComponentFilter(...)) for searches on indexed fields.
+func (s *Search) WhereIndexEquals(component types.Component, field string, value any) EntitySearch {
+	return s.WhereIndexRange(IndexFilter{
+		component: component.Name(),
+		field:     field,
+		rng:       gamestate.IndexRange{Min: value, Max: value},
+	})
+}
+
+// WhereIndexRange restricts the search to the entities matched by the given index filter, see IndexBetween,
+// IndexAtLeast and IndexAtMost. Like WhereIndexEquals, only the entities found in the index are evaluated.
+func (s *Search) WhereIndexRange(indexFilter IndexFilter) EntitySearch {
+	return &Search{
+		archMatches:             &cache{},
+		filter:                  s.filter,
+		componentPropertyFilter: s.componentPropertyFilter,
+		indexFilters:            append(slices.Clone(s.indexFilters), indexFilter),
+		spatialFilters:          s.spatialFilters,
+	}
+}
+
+// WhereSpatial restricts the search to the entities matched by the given spatial filter (see RegisterSpatialIndex).
+// Like WhereIndexEquals, only the entities found in the spatial index are evaluated.
+func (s *Search) WhereSpatial(spatialFilter SpatialFilter) EntitySearch {
+	return &Search{
+		archMatches:             &cache{},
+		filter:                  s.filter,
+		componentPropertyFilter: s.componentPropertyFilter,
+		indexFilters:            s.indexFilters,
+		spatialFilters:          append(slices.Clone(s.spatialFilters), spatialFilter),
 	}
 }
 
@@ -152,8 +196,10 @@ func (s *Search) Where(componentFilter FilterFn) EntitySearch {
 func (s *Search) Each(wCtx WorldContext, callback CallbackFn) (err error) {
 	defer func() { defer panicOnFatalError(wCtx, err) }()
 
-	result := s.evaluateSearch(wCtx)
-	iter := newSearchIterator(wCtx.storeReader(), result)
+	iter, err := s.newIterator(wCtx)
+	if err != nil {
+		return err
+	}
 	for iter.HasNext() {
 		entities, err := iter.Next()
 		if err != nil {
@@ -202,8 +248,10 @@ func (s *Search) Collect(wCtx WorldContext) ([]types.EntityID, error) {
 func (s *Search) Count(wCtx WorldContext) (ret int, err error) {
 	defer func() { defer panicOnFatalError(wCtx, err) }()
 
-	result := s.evaluateSearch(wCtx)
-	iter := newSearchIterator(wCtx.storeReader(), result)
+	iter, err := s.newIterator(wCtx)
+	if err != nil {
+		return 0, err
+	}
 	for iter.HasNext() {
 		entities, err := iter.Next()
 		if err != nil {
@@ -231,8 +279,10 @@ func (s *Search) Count(wCtx WorldContext) (ret int, err error) {
 func (s *Search) First(wCtx WorldContext) (id types.EntityID, err error) {
 	defer func() { defer panicOnFatalError(wCtx, err) }()
 
-	result := s.evaluateSearch(wCtx)
-	iter := newSearchIterator(wCtx.storeReader(), result)
+	iter, err := s.newIterator(wCtx)
+	if err != nil {
+		return badEntityID, err
+	}
 	if !iter.HasNext() {
 		return badEntityID, eris.Wrap(err, "")
 	}
@@ -267,6 +317,97 @@ func (s *Search) MustFirst(wCtx WorldContext) types.EntityID {
 	return id
 }
 
+// newIterator returns an iterator over the entities that match the component filter of the search. If the search has
+// index, spatial or change filters, only the entities found in the indexes or changed in the current tick are returned.
+func (s *Search) newIterator(wCtx WorldContext) (searchIterator, error) {
+	changeFilters, err := filter.ChangeFilters(s.filter)
+	if err != nil {
+		return searchIterator{}, err
+	}
+	if len(s.indexFilters) == 0 && len(s.spatialFilters) == 0 && len(changeFilters) == 0 {
+		return newSearchIterator(wCtx.storeReader(), s.evaluateSearch(wCtx)), nil
+	}
+	ids, err := s.evaluateIndexSearch(wCtx, changeFilters)
+	if err != nil {
+		return searchIterator{}, err
+	}
+	return newEntitySearchIterator(ids), nil
+}
+
+// evaluateIndexSearch returns the entities that are matched by all the index, spatial and change filters and the
+// component filter. If the search has a Nearest filter, the entities are returned from nearest to farthest.
+func (s *Search) evaluateIndexSearch(wCtx WorldContext, changeFilters []filter.ChangeFilter) ([]types.EntityID, error) {
+	var ids []types.EntityID
+	restricted := false
+	intersect := func(matches []types.EntityID) {
+		if !restricted {
+			ids, restricted = matches, true
+			return
+		}
+		// Both lists are sorted, so they can be intersected in place.
+		ids = slices.DeleteFunc(ids, func(id types.EntityID) bool {
+			_, found := slices.BinarySearch(matches, id)
+			return !found
+		})
+	}
+
+	for _, changeFilter := range changeFilters {
+		matches, err := changedEntities(wCtx, changeFilter)
+		if err != nil {
+			return nil, err
+		}
+		intersect(matches)
+	}
+	for _, indexFilter := range s.indexFilters {
+		matches, err := indexFilter.lookup(wCtx)
+		if err != nil {
+			return nil, err
+		}
+		intersect(matches)
+	}
+	var nearest *SpatialFilter
+	for i, spatialFilter := range s.spatialFilters {
+		if spatialFilter.nearest != nil {
+			if nearest != nil {
+				return nil, eris.New("a search can have at most one Nearest filter")
+			}
+			nearest = &s.spatialFilters[i]
+			continue
+		}
+		matches, err := spatialFilter.lookupIDs(wCtx)
+		if err != nil {
+			return nil, err
+		}
+		intersect(matches)
+	}
+
+	reader := wCtx.storeReader()
+	matchesComponents := func(id types.EntityID) bool {
+		comps, err := reader.GetComponentTypesForEntity(id)
+		return err == nil && s.filter.MatchesComponents(types.ConvertComponentMetadatasToComponents(comps))
+	}
+	if nearest == nil {
+		return slices.DeleteFunc(ids, func(id types.EntityID) bool {
+			return !matchesComponents(id)
+		}), nil
+	}
+
+	// The nearest entities are picked among the entities that match the rest of the search, where clauses included.
+	return nearest.lookupNearest(wCtx, func(id types.EntityID) bool {
+		if _, found := slices.BinarySearch(ids, id); restricted && !found {
+			return false
+		}
+		if !matchesComponents(id) {
+			return false
+		}
+		if s.componentPropertyFilter == nil {
+			return true
+		}
+		ok, err := s.componentPropertyFilter(wCtx, id)
+		return err == nil && ok
+	})
+}
+
 func (s *Search) evaluateSearch(wCtx WorldContext) []types.ArchetypeID {
 	cache := s.archMatches
 	for it := wCtx.storeReader().SearchFrom(s.filter, cache.seen); it.HasNext(); {
@@ -12,6 +12,8 @@ type searchIterator struct {
 	archIDs []types.ArchetypeID
 	// stateReader is an interface that allows us to read the current entity state
 	stateReader gamestate.Reader
+	// entityIDs is set instead of archIDs when the entities to iterate over are already known, e.g. from an index.
+	entityIDs []types.EntityID
 }
 
 // newSearchIterator returns an iterator that returns the list of entities for the given archetype ids.
@@ -23,13 +25,29 @@ func newSearchIterator(stateReader gamestate.Reader, archIDs []types.ArchetypeID
 	}
 }
 
+// newEntitySearchIterator returns an iterator that returns the given entities in a single list.
+func newEntitySearchIterator(ids []types.EntityID) searchIterator {
+	return searchIterator{
+		current:   0,
+		archIDs:   nil,
+		entityIDs: ids,
+	}
+}
+
 // HasNext evaluates to true if there are still archetypes to iterate over.
 func (it *searchIterator) HasNext() bool {
+	if it.entityIDs != nil {
+		return it.current == 0
+	}
 	return it.current < len(it.archIDs)
 }
 
 // Next returns the next entity list based on the list of archetypes in archIds.
 func (it *searchIterator) Next() ([]types.EntityID, error) {
+	if it.entityIDs != nil {
+		it.current++
+		return it.entityIDs, nil
+	}
 	archetypeID := it.archIDs[it.current]
 	it.current++
 	return it.stateReader.GetEntitiesForArchID(archetypeID)
@@ -496,3 +496,51 @@ func TestWhereClauseOnSearch(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, amt, 40)
 }
+
+func TestWhereIndexOnSearch(t *testing.T) {
+	tf := cardinal.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterComponent[AlphaTest](world))
+	assert.NilError(t, cardinal.RegisterIndex[Health](world, "value", func(h Health) int { return h.Value }))
+	tf.StartWorld()
+
+	wCtx := cardinal.NewWorldContext(world)
+	ids, err := cardinal.CreateMany(wCtx, 5, Health{})
+	assert.NilError(t, err)
+	for i, id := range ids {
+		assert.NilError(t, cardinal.SetComponent[Health](wCtx, id, &Health{Value: i}))
+	}
+	alphaID, err := cardinal.Create(wCtx, Health{Value: 2}, AlphaTest{})
+	assert.NilError(t, err)
+
+	count := func(search cardinal.EntitySearch) int {
+		amount, err := search.Count(wCtx)
+		assert.NilError(t, err)
+		return amount
+	}
+	search := func() cardinal.EntitySearch {
+		return cardinal.NewSearch().Entity(filter.Contains(filter.Component[Health]()))
+	}
+
+	assert.Equal(t, 2, count(search().WhereIndexEquals(Health{}, "value", 2)))
+	assert.Equal(t, 4, count(search().WhereIndexRange(cardinal.IndexBetween[Health]("value", 1, 3))))
+	assert.Equal(t, 2, count(search().WhereIndexRange(cardinal.IndexAtLeast[Health]("value", 3))))
+	assert.Equal(t, 1, count(search().WhereIndexRange(cardinal.IndexAtMost[Health]("value", 0))))
+
+	// Index filters are combined with each other and with the archetype filter.
+	exact := cardinal.NewSearch().Entity(filter.Exact(filter.Component[Health]()))
+	assert.Equal(t, 1, count(exact.WhereIndexEquals(Health{}, "value", 2)))
+	assert.Equal(t, 1, count(search().
+		WhereIndexRange(cardinal.IndexAtLeast[Health]("value", 2)).
+		WhereIndexRange(cardinal.IndexAtMost[Health]("value", 2)).
+		Where(func(_ cardinal.WorldContext, id types.EntityID) (bool, error) { return id != alphaID, nil })))
+
+	tf.DoTick()
+
+	assert.NilError(t, cardinal.SetComponent[Health](wCtx, ids[0], &Health{Value: 2}))
+	assert.NilError(t, cardinal.Remove(wCtx, alphaID))
+	got, err := search().WhereIndexEquals(Health{}, "value", 2).Collect(wCtx)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.EntityID{ids[0], ids[2]}, got)
+}
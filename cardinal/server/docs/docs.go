@@ -34,6 +34,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/cardinal_server_handler.CQLQueryRequest"
                         }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Run against the state at this tick of the retained history",
+                        "name": "atTick",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -74,11 +80,44 @@ const docTemplate = `{
         },
         "/events": {
             "get": {
-                "description": "Establishes a new websocket connection to retrieve system events",
+                "description": "Establishes a new websocket connection to retrieve system events. Clients can limit the receipts and\nevents they receive to a set of topics (e.g. persona:\u003ctag\u003e, tx:\u003chash\u003e, entity:\u003cid\u003e, event:\u003ctype\u003e)\nwith the topics query parameter, or by sending an EventSubscriptionRequest over the websocket.",
                 "produces": [
                     "application/json"
                 ],
                 "summary": "Establishes a new websocket connection to retrieve system events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated list of topics to subscribe to",
+                        "name": "topics",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switch protocol to ws",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/diffs": {
+            "get": {
+                "description": "Establishes a new websocket connection that receives the entities created and removed, and the\ncomponent values changed in each tick. Each message is a JSON encoded StateChanges object\nfor one tick, and ticks without a matching change are sent as an empty diff.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Establishes a new websocket connection to retrieve per-tick state diffs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated list of component names to receive changes for",
+                        "name": "components",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "101": {
                         "description": "Switch protocol to ws",
@@ -106,6 +145,83 @@ const docTemplate = `{
                 }
             }
         },
+        "/query/nonces/{signerAddress}": {
+            "post": {
+                "description": "Retrieves the lowest nonce the signer can use in its next transaction. Nonces are only enforced when the world uses nonces for replay protection.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves the next nonce of a signer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Address of the signer",
+                        "name": "signerAddress",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Next nonce of the signer",
+                        "schema": {
+                            "$ref": "#/definitions/cardinal_server_handler.GetNextNonceResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/query/proofs/{component}/{id}": {
+            "post": {
+                "description": "Retrieves a Merkle proof of the value of a component of an entity against the state root of a tick",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves a Merkle proof of the value of a component of an entity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered component",
+                        "name": "component",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the entity",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Prove the value as it was at this tick",
+                        "name": "atTick",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Proof of the component value",
+                        "schema": {
+                            "$ref": "#/definitions/cardinal_server_handler.ComponentProofResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Component value not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/query/receipts/list": {
             "post": {
                 "description": "Retrieves all transaction receipts",
@@ -143,6 +259,82 @@ const docTemplate = `{
                 }
             }
         },
+        "/query/receipts/{txHash}": {
+            "post": {
+                "description": "Retrieves the receipt of a transaction by its hash, from memory or from the receipt store",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves the receipt of a transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hash of the transaction",
+                        "name": "txHash",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Receipt of the transaction",
+                        "schema": {
+                            "$ref": "#/definitions/cardinal_server_handler.ReceiptEntry"
+                        }
+                    },
+                    "404": {
+                        "description": "Receipt not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/query/resources/{name}": {
+            "post": {
+                "description": "Retrieves the value of a world resource. Queries registered in the \"resources\" group are served by this route as long as their name is not the name of a resource.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves the value of a world resource",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a registered resource",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Get the value as it was at this tick of the retained history",
+                        "name": "atTick",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Value of the resource",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Resource or query not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/query/{queryGroup}/{queryName}": {
             "post": {
                 "description": "Executes a query",
@@ -176,6 +368,12 @@ const docTemplate = `{
                         "schema": {
                             "type": "object"
                         }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Run against the state at this tick of the retained history",
+                        "name": "atTick",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -194,6 +392,43 @@ const docTemplate = `{
                 }
             }
         },
+        "/tx/batch": {
+            "post": {
+                "description": "Submits many transactions of any message type at once. Each transaction is validated on its own, and\nall the valid transactions are enqueued atomically into the same tick, or none if they do not all fit\nin the pool.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Submits a batch of transactions",
+                "parameters": [
+                    {
+                        "description": "Transactions to be submitted",
+                        "name": "txBody",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cardinal_server_handler.PostBatchTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tick, and hash or error of each transaction",
+                        "schema": {
+                            "$ref": "#/definitions/cardinal_server_handler.PostBatchTransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameter",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/tx/game/{txName}": {
             "post": {
                 "description": "Submits a transaction",
@@ -275,6 +510,44 @@ const docTemplate = `{
                 }
             }
         },
+        "/tx/{txHash}/status": {
+            "get": {
+                "description": "Retrieves the status of a transaction: pending, processed, failed or unknown. If the wait parameter is set and the transaction is pending, the request waits until the tick that handles the transaction is finalized, or until the wait duration (at most 30s) elapses.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Retrieves the status of a transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hash of the transaction",
+                        "name": "txHash",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "How long to wait for a pending transaction, e.g. 5s",
+                        "name": "wait",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status of the transaction",
+                        "schema": {
+                            "$ref": "#/definitions/cardinal_server_handler.GetTxStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid wait duration",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/tx/{txGroup}/{txName}": {
             "post": {
                 "description": "Submits a transaction",
@@ -354,6 +627,34 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "cardinal_server_handler.BatchTransaction": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tx": {
+                    "type": "object"
+                }
+            }
+        },
+        "cardinal_server_handler.BatchTransactionResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "txHash": {
+                    "type": "string"
+                }
+            }
+        },
         "cardinal_server_handler.CQLQueryRequest": {
             "type": "object",
             "properties": {
@@ -373,6 +674,35 @@ const docTemplate = `{
                 }
             }
         },
+        "cardinal_server_handler.ComponentProofResponse": {
+            "type": "object",
+            "properties": {
+                "depths": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "key": {
+                    "type": "string"
+                },
+                "siblings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "stateRoot": {
+                    "type": "string"
+                },
+                "tick": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
         "cardinal_server_handler.GetHealthResponse": {
             "type": "object",
             "properties": {
@@ -384,6 +714,31 @@ const docTemplate = `{
                 }
             }
         },
+        "cardinal_server_handler.GetNextNonceResponse": {
+            "type": "object",
+            "properties": {
+                "nextNonce": {
+                    "type": "integer"
+                },
+                "signerAddress": {
+                    "type": "string"
+                }
+            }
+        },
+        "cardinal_server_handler.GetTxStatusResponse": {
+            "type": "object",
+            "properties": {
+                "receipt": {
+                    "$ref": "#/definitions/cardinal_server_handler.ReceiptEntry"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "txHash": {
+                    "type": "string"
+                }
+            }
+        },
         "cardinal_server_handler.GetWorldResponse": {
             "type": "object",
             "properties": {
@@ -436,6 +791,31 @@ const docTemplate = `{
                 }
             }
         },
+        "cardinal_server_handler.PostBatchTransactionRequest": {
+            "type": "object",
+            "properties": {
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cardinal_server_handler.BatchTransaction"
+                    }
+                }
+            }
+        },
+        "cardinal_server_handler.PostBatchTransactionResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cardinal_server_handler.BatchTransactionResult"
+                    }
+                },
+                "tick": {
+                    "type": "integer"
+                }
+            }
+        },
         "cardinal_server_handler.PostTransactionResponse": {
             "type": "object",
             "properties": {
@@ -450,6 +830,9 @@ const docTemplate = `{
         "cardinal_server_handler.ReceiptEntry": {
             "type": "object",
             "properties": {
+                "deferred": {
+                    "type": "boolean"
+                },
                 "errors": {
                     "type": "array",
                     "items": {
@@ -481,6 +864,14 @@ const docTemplate = `{
                 "timestamp": {
                     "type": "integer"
                 },
+                "nonce": {
+                    "description": "per signer nonce, required when nonces are used for replay protection",
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "format of the hash; 1 is required with nonces and signs the nonce, 0 (legacy) if omitted",
+                    "type": "integer"
+                },
                 "personaTag": {
                     "type": "string"
                 },
@@ -6,11 +6,15 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+	"pkg.world.dev/world-engine/cardinal/types"
 )
 
 type SendEnergyTx struct {
@@ -117,3 +121,107 @@ func TestEventsThroughSystems(t *testing.T) {
 func wsURL(addr, path string) string {
 	return fmt.Sprintf("ws://%s/%s", addr, path)
 }
+
+func TestStateDiffStream(t *testing.T) {
+	tf := cardinal.NewTestFixture(t, nil, cardinal.WithStateDiffStream())
+	world, addr := tf.World, tf.BaseURL
+	assert.NilError(t, cardinal.RegisterComponent[Alpha](world))
+	assert.NilError(t, cardinal.RegisterComponent[Beta](world))
+	tf.StartWorld()
+
+	allChanges, _, err := websocket.DefaultDialer.Dial(wsURL(addr, "events/diffs"), nil)
+	assert.NilError(t, err)
+	betaChanges, _, err := websocket.DefaultDialer.Dial(wsURL(addr, "events/diffs?components=beta"), nil)
+	assert.NilError(t, err)
+	// Give the server a moment to register the subscriptions
+	time.Sleep(100 * time.Millisecond)
+
+	wCtx := cardinal.NewWorldContext(world)
+	alphaID, err := cardinal.Create(wCtx, Alpha{Something: 1})
+	assert.NilError(t, err)
+	betaID, err := cardinal.Create(wCtx, Beta{Something: 2})
+	assert.NilError(t, err)
+	tf.DoTick()
+
+	assert.NilError(t, cardinal.SetComponent[Alpha](wCtx, alphaID, &Alpha{Something: 3}))
+	assert.NilError(t, cardinal.Remove(wCtx, betaID))
+	tf.DoTick()
+
+	readChanges := func(conn *websocket.Conn) gamestate.StateChanges {
+		_, message, err := conn.ReadMessage()
+		assert.NilError(t, err)
+		var changes gamestate.StateChanges
+		assert.NilError(t, json.Unmarshal(message, &changes))
+		return changes
+	}
+
+	// The first tick creates both entities
+	changes := readChanges(allChanges)
+	assert.Equal(t, 2, len(changes.CreatedEntities))
+	assert.Equal(t, 2, len(changes.ComponentChanges))
+	assert.Equal(t, `{"something":1}`, string(changes.ComponentChanges[0].Value))
+
+	changes = readChanges(betaChanges)
+	assert.Equal(t, 1, len(changes.CreatedEntities))
+	assert.Equal(t, betaID, changes.CreatedEntities[0].ID)
+	assert.Equal(t, 1, len(changes.ComponentChanges))
+	assert.Equal(t, "beta", changes.ComponentChanges[0].Component)
+
+	// The second tick changes alpha and removes beta
+	changes = readChanges(allChanges)
+	assert.Equal(t, changes.Tick, world.CurrentTick()-1)
+	assert.Equal(t, 0, len(changes.CreatedEntities))
+	assert.Equal(t, 1, len(changes.RemovedEntities))
+	assert.Equal(t, 1, len(changes.ComponentChanges))
+	assert.Equal(t, alphaID, changes.ComponentChanges[0].EntityID)
+	assert.Equal(t, `{"something":3}`, string(changes.ComponentChanges[0].Value))
+
+	changes = readChanges(betaChanges)
+	assert.Equal(t, 1, len(changes.RemovedEntities))
+	assert.Equal(t, betaID, changes.RemovedEntities[0].ID)
+	assert.Equal(t, 0, len(changes.ComponentChanges))
+}
+
+func TestEventsAreOnlySentToSubscribedTopics(t *testing.T) {
+	tf := cardinal.NewTestFixture(t, nil)
+	world, addr := tf.World, tf.BaseURL
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx cardinal.WorldContext) error {
+		assert.NilError(t, wCtx.EmitEvent(map[string]any{"to": "alice"}, types.PersonaTopic("alice")))
+		assert.NilError(t, wCtx.EmitEvent(map[string]any{"to": "bob"}, types.PersonaTopic("bob")))
+		assert.NilError(t, wCtx.EmitEvent(map[string]any{"to": "everyone"}))
+		return nil
+	}))
+	tf.StartWorld()
+
+	everything, _, err := websocket.DefaultDialer.Dial(wsURL(addr, "events"), nil)
+	assert.NilError(t, err)
+	alice, _, err := websocket.DefaultDialer.Dial(wsURL(addr, "events?topics=persona:alice"), nil)
+	assert.NilError(t, err)
+	bob, _, err := websocket.DefaultDialer.Dial(wsURL(addr, "events"), nil)
+	assert.NilError(t, err)
+	assert.NilError(t, bob.WriteJSON(handler.EventSubscriptionRequest{
+		Subscribe:   []types.Topic{types.PersonaTopic("bob")},
+		Unsubscribe: nil,
+	}))
+	// Give the server a moment to register the subscriptions
+	time.Sleep(100 * time.Millisecond)
+
+	tf.DoTick()
+
+	readEvents := func(conn *websocket.Conn) []string {
+		_, message, err := conn.ReadMessage()
+		assert.NilError(t, err)
+		var results cardinal.TickResults
+		assert.NilError(t, json.Unmarshal(message, &results))
+		recipients := make([]string, 0, len(results.Events))
+		for _, bz := range results.Events {
+			event := map[string]string{}
+			assert.NilError(t, json.Unmarshal(bz, &event))
+			recipients = append(recipients, event["to"])
+		}
+		return recipients
+	}
+	assert.DeepEqual(t, []string{"alice", "bob", "everyone"}, readEvents(everything))
+	assert.DeepEqual(t, []string{"alice"}, readEvents(alice))
+	assert.DeepEqual(t, []string{"bob"}, readEvents(bob))
+}
@@ -0,0 +1,288 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/receipt"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/server/validator"
+	"pkg.world.dev/world-engine/cardinal/txpool"
+	"pkg.world.dev/world-engine/cardinal/types"
+	cardinalv1 "pkg.world.dev/world-engine/rift/cardinal/v1"
+	"pkg.world.dev/world-engine/sign"
+)
+
+// tickResultsBufferSize is the number of ticks worth of results that are buffered for a TickResults stream. Ticks are
+// dropped for streams that fall further behind.
+const tickResultsBufferSize = 16
+
+var _ cardinalv1.CardinalServer = (*grpcService)(nil)
+
+// grpcService implements the Cardinal gRPC service on top of the same world, messages and signature validator as the
+// HTTP handlers.
+type grpcService struct {
+	cardinalv1.UnimplementedCardinalServer
+
+	world       servertypes.ProviderWorld
+	msgs        map[string]map[string]types.Message
+	validator   *validator.SignatureValidator
+	tickResults *tickResultsSubscriptions
+}
+
+func (g *grpcService) SubmitTransaction(
+	_ context.Context, req *cardinalv1.SubmitTransactionRequest,
+) (*cardinalv1.SubmitTransactionResponse, error) {
+	msgType, ok := g.msgs[req.GetMessageGroup()][req.GetMessageName()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "message %s.%s not found", req.GetMessageGroup(), req.GetMessageName())
+	}
+	if req.GetTransaction() == nil {
+		return nil, status.Error(codes.InvalidArgument, "transaction is required")
+	}
+	if req.GetTransaction().GetSalt() > math.MaxUint16 {
+		return nil, status.Error(codes.InvalidArgument, "salt must fit in 16 bits")
+	}
+	tx := &sign.Transaction{
+		PersonaTag: req.GetTransaction().GetPersonaTag(),
+		Namespace:  req.GetTransaction().GetNamespace(),
+		Timestamp:  req.GetTransaction().GetTimestamp(),
+		Salt:       uint16(req.GetTransaction().GetSalt()),
+		Signature:  req.GetTransaction().GetSignature(),
+		Body:       req.GetTransaction().GetBody(),
+		Nonce:      req.GetTransaction().GetNonce(),
+		Version:    req.GetTransaction().GetVersion(),
+	}
+	// Computes the hash of the transaction.
+	tx.HashHex()
+
+	msg, err := handler.ValidateTransaction(msgType, tx, g.validator)
+	if err != nil {
+		return nil, grpcErrorFromHTTPError(err)
+	}
+	tick, hash, err := g.world.SubmitTransaction(msgType.ID(), msg, tx)
+	if err != nil {
+		handler.ReleaseNonces([]txpool.TxData{{MsgID: msgType.ID(), Msg: msg, Tx: tx}}, g.validator)
+		return nil, grpcErrorFromHTTPError(handler.HTTPResultFromTxPoolError(err))
+	}
+	return &cardinalv1.SubmitTransactionResponse{
+		TxHash: string(hash),
+		Tick:   tick,
+	}, nil
+}
+
+func (g *grpcService) Query(_ context.Context, req *cardinalv1.QueryRequest) (*cardinalv1.QueryResponse, error) {
+	var res []byte
+	var err error
+	if req.AtTick != nil {
+		res, err = g.world.HandleQueryAtTick(req.GetGroup(), req.GetName(), req.GetRequest(), req.GetAtTick())
+	} else {
+		res, err = g.world.HandleQuery(req.GetGroup(), req.GetName(), req.GetRequest())
+	}
+	switch {
+	case eris.Is(err, types.ErrQueryNotFound):
+		return nil, status.Errorf(codes.NotFound, "query %s.%s not found", req.GetGroup(), req.GetName())
+	case eris.Is(err, gamestate.ErrTickNotInHistory):
+		return nil, status.Error(codes.OutOfRange, err.Error())
+	case err != nil:
+		return nil, status.Errorf(codes.InvalidArgument, "encountered an error in query: %v", err)
+	}
+	return &cardinalv1.QueryResponse{Response: res}, nil
+}
+
+func (g *grpcService) CQL(_ context.Context, req *cardinalv1.CQLRequest) (*cardinalv1.CQLResponse, error) {
+	var result []types.EntityStateElement
+	var err error
+	if req.AtTick != nil {
+		result, err = g.world.EvaluateCQLAtTick(req.GetCql(), req.GetAtTick())
+	} else {
+		result, err = g.world.EvaluateCQL(req.GetCql())
+	}
+	if eris.Is(err, gamestate.ErrTickNotInHistory) {
+		return nil, status.Error(codes.OutOfRange, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	entities := make([]*cardinalv1.Entity, 0, len(result))
+	for _, element := range result {
+		components := make([][]byte, 0, len(element.Data))
+		for _, data := range element.Data {
+			components = append(components, data)
+		}
+		entities = append(entities, &cardinalv1.Entity{Id: uint64(element.ID), Components: components})
+	}
+	return &cardinalv1.CQLResponse{Entities: entities}, nil
+}
+
+func (g *grpcService) Receipts(
+	_ context.Context, req *cardinalv1.ReceiptsRequest,
+) (*cardinalv1.ReceiptsResponse, error) {
+	list, err := handler.ListReceipts(g.world, req.GetStartTick())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	receipts := make([]*cardinalv1.Receipt, 0, len(list.Receipts))
+	for _, entry := range list.Receipts {
+		result, err := json.Marshal(entry.Result)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal result of tx %s: %v", entry.TxHash, err)
+		}
+		receipts = append(receipts, &cardinalv1.Receipt{
+			TxHash:   entry.TxHash,
+			Tick:     entry.Tick,
+			Result:   result,
+			Errors:   entry.Errors,
+			Deferred: entry.Deferred,
+		})
+	}
+	return &cardinalv1.ReceiptsResponse{
+		StartTick: list.StartTick,
+		EndTick:   list.EndTick,
+		Receipts:  receipts,
+	}, nil
+}
+
+func (g *grpcService) TickResults(
+	_ *cardinalv1.TickResultsRequest, stream cardinalv1.Cardinal_TickResultsServer,
+) error {
+	results, unsubscribe := g.tickResults.subscribe()
+	defer unsubscribe()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case res, ok := <-results:
+			if !ok {
+				// The server is shutting down.
+				return nil
+			}
+			if err := stream.Send(res); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// grpcErrorFromHTTPError converts the *fiber.Error returned by the HTTP validation helpers to a gRPC status error.
+func grpcErrorFromHTTPError(err error) error {
+	var fiberErr *fiber.Error
+	if !errors.As(err, &fiberErr) {
+		return status.Error(codes.Internal, err.Error())
+	}
+	code := codes.Internal
+	switch fiberErr.Code {
+	case fiber.StatusBadRequest:
+		code = codes.InvalidArgument
+	case fiber.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case fiber.StatusForbidden:
+		code = codes.PermissionDenied
+	case fiber.StatusNotFound:
+		code = codes.NotFound
+	case fiber.StatusRequestTimeout:
+		code = codes.DeadlineExceeded
+	case fiber.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case fiber.StatusServiceUnavailable:
+		code = codes.Unavailable
+	}
+	return status.Error(code, fiberErr.Message)
+}
+
+// tickResultsSubscriptions keeps track of the TickResults streams of the gRPC service.
+type tickResultsSubscriptions struct {
+	mu          sync.Mutex
+	subscribers map[chan *cardinalv1.TickResultsResponse]struct{}
+	closed      bool
+}
+
+func newTickResultsSubscriptions() *tickResultsSubscriptions {
+	return &tickResultsSubscriptions{
+		subscribers: map[chan *cardinalv1.TickResultsResponse]struct{}{},
+	}
+}
+
+// subscribe returns a channel that receives the results of every tick, and a function that must be called once the
+// results are no longer needed. The channel is closed when the subscriptions are closed.
+func (s *tickResultsSubscriptions) subscribe() (<-chan *cardinalv1.TickResultsResponse, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch := make(chan *cardinalv1.TickResultsResponse, tickResultsBufferSize)
+	if s.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	s.subscribers[ch] = struct{}{}
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.subscribers[ch]; ok {
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (s *tickResultsSubscriptions) hasSubscribers() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscribers) > 0
+}
+
+func (s *tickResultsSubscriptions) broadcast(tick uint64, receipts []receipt.Receipt, events [][]byte) error {
+	res := &cardinalv1.TickResultsResponse{
+		Tick:     tick,
+		Receipts: make([]*cardinalv1.Receipt, 0, len(receipts)),
+		Events:   events,
+	}
+	for _, r := range receipts {
+		result, err := json.Marshal(r.Result)
+		if err != nil {
+			return eris.Wrapf(err, "failed to marshal result of tx %s", r.TxHash)
+		}
+		errs := make([]string, 0, len(r.Errs))
+		for _, err := range r.Errs {
+			errs = append(errs, err.Error())
+		}
+		res.Receipts = append(res.Receipts, &cardinalv1.Receipt{
+			TxHash:   string(r.TxHash),
+			Tick:     tick,
+			Result:   result,
+			Errors:   errs,
+			Deferred: r.Deferred,
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscribers {
+		select {
+		case ch <- res:
+		default:
+			log.Warn().Msgf("dropping the results of tick %d for a slow TickResults stream", tick)
+		}
+	}
+	return nil
+}
+
+// close closes the channels of all the subscribers, which ends their streams.
+func (s *tickResultsSubscriptions) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscribers {
+		close(ch)
+	}
+	s.subscribers = map[chan *cardinalv1.TickResultsResponse]struct{}{}
+	s.closed = true
+}
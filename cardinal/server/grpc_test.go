@@ -0,0 +1,139 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/server"
+	cardinalv1 "pkg.world.dev/world-engine/rift/cardinal/v1"
+	"pkg.world.dev/world-engine/sign"
+)
+
+func (s *ServerTestSuite) TestGRPC() {
+	listener, err := net.Listen("tcp", ":0")
+	s.Require().NoError(err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	s.Require().NoError(listener.Close())
+
+	s.setupWorld(cardinal.WithGRPCPort(strconv.Itoa(port)))
+	s.fixture.DoTick()
+	personaTag := s.CreateRandomPersona()
+
+	conn, err := grpc.NewClient("localhost:"+strconv.Itoa(port),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	s.Require().NoError(err)
+	defer conn.Close()
+	client := cardinalv1.NewCardinalClient(conn)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.TickResults(ctx, &cardinalv1.TickResultsRequest{})
+	s.Require().NoError(err)
+	results := make(chan *cardinalv1.TickResultsResponse, 100) //nolint:gomnd // enough for the test
+	go func() {
+		for {
+			res, err := stream.Recv()
+			if err != nil {
+				close(results)
+				return
+			}
+			results <- res
+		}
+	}()
+	// The stream is subscribed asynchronously, so tick until it receives the results of a tick.
+	subscribed := false
+	for i := 0; i < 50 && !subscribed; i++ {
+		s.fixture.DoTick()
+		select {
+		case <-results:
+			subscribed = true
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	s.Require().True(subscribed)
+
+	tx, err := sign.NewTransaction(s.privateKey, personaTag, s.world.Namespace(), MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	req := &cardinalv1.SubmitTransactionRequest{
+		MessageGroup: "game",
+		MessageName:  moveMsgName,
+		Transaction: &cardinalv1.Transaction{
+			PersonaTag: tx.PersonaTag,
+			Namespace:  tx.Namespace,
+			Timestamp:  tx.Timestamp,
+			Salt:       uint32(tx.Salt),
+			Signature:  tx.Signature,
+			Body:       tx.Body,
+		},
+	}
+	submitted, err := client.SubmitTransaction(ctx, req)
+	s.Require().NoError(err)
+	s.Require().Equal(tx.HashHex(), submitted.GetTxHash())
+
+	// The same transaction can not be submitted twice.
+	_, err = client.SubmitTransaction(ctx, req)
+	s.Require().Equal(codes.PermissionDenied, status.Code(err))
+	_, err = client.SubmitTransaction(ctx, &cardinalv1.SubmitTransactionRequest{MessageGroup: "game", MessageName: "x"})
+	s.Require().Equal(codes.NotFound, status.Code(err))
+
+	// The receipt of the transaction is streamed at the end of the tick.
+	s.fixture.DoTick()
+	res := <-results
+	s.Require().Len(res.GetReceipts(), 1)
+	s.Require().Equal(submitted.GetTxHash(), res.GetReceipts()[0].GetTxHash())
+	var output MoveMessageOutput
+	s.Require().NoError(json.Unmarshal(res.GetReceipts()[0].GetResult(), &output))
+	s.Require().Equal(LocationComponent{0, 1}, output.Location)
+
+	queryReq, err := json.Marshal(QueryLocationRequest{Persona: personaTag})
+	s.Require().NoError(err)
+	queryRes, err := client.Query(ctx, &cardinalv1.QueryRequest{Group: "game", Name: "location", Request: queryReq})
+	s.Require().NoError(err)
+	var loc LocationComponent
+	s.Require().NoError(json.Unmarshal(queryRes.GetResponse(), &loc))
+	s.Require().Equal(LocationComponent{0, 1}, loc)
+	_, err = client.Query(ctx, &cardinalv1.QueryRequest{Group: "game", Name: "unknown"})
+	s.Require().Equal(codes.NotFound, status.Code(err))
+
+	cqlRes, err := client.CQL(ctx, &cardinalv1.CQLRequest{Cql: "CONTAINS(location)"})
+	s.Require().NoError(err)
+	s.Require().Len(cqlRes.GetEntities(), 1)
+
+	receipts, err := client.Receipts(ctx, &cardinalv1.ReceiptsRequest{})
+	s.Require().NoError(err)
+	found := false
+	for _, r := range receipts.GetReceipts() {
+		found = found || r.GetTxHash() == submitted.GetTxHash()
+	}
+	s.Require().True(found)
+}
+
+func (s *ServerTestSuite) TestServeStopsGRPCServerWhenHTTPServerFails() {
+	s.setupWorld()
+	s.fixture.DoTick()
+	httpListener, err := net.Listen("tcp", ":0")
+	s.Require().NoError(err)
+	defer httpListener.Close()
+	grpcListener, err := net.Listen("tcp", ":0")
+	s.Require().NoError(err)
+	grpcPort := strconv.Itoa(grpcListener.Addr().(*net.TCPAddr).Port)
+	s.Require().NoError(grpcListener.Close())
+
+	srv, err := server.New(s.world, nil, nil,
+		server.WithPort(strconv.Itoa(httpListener.Addr().(*net.TCPAddr).Port)), server.WithGRPCPort(grpcPort))
+	s.Require().NoError(err)
+	// The HTTP port is taken, so Serve fails and the gRPC server must release its port.
+	s.Require().Error(srv.Serve(context.Background()))
+	grpcListener, err = net.Listen("tcp", ":"+grpcPort)
+	s.Require().NoError(err)
+	s.Require().NoError(grpcListener.Close())
+}
@@ -21,9 +21,10 @@ type CQLQueryResponse struct {
 //	@Description  Executes a CQL (Cardinal Query Language) query
 //	@Accept       application/json
 //	@Produce      application/json
-//	@Param        cql  body      CQLQueryRequest   true  "CQL query to be executed"
-//	@Success      200  {object}  CQLQueryResponse  "Results of the executed CQL query"
-//	@Failure      400  {string}  string            "Invalid request parameters"
+//	@Param        cql     body      CQLQueryRequest  true   "CQL query to be executed"
+//	@Param        atTick  query     integer          false  "Run against the state at this tick of the retained history"
+//	@Success      200     {object}  CQLQueryResponse        "Results of the executed CQL query"
+//	@Failure      400     {string}  string                  "Invalid request parameters"
 //	@Router       /cql [post]
 func PostCQL(
 	world servertypes.ProviderWorld,
@@ -33,8 +34,19 @@ func PostCQL(
 		if err := ctx.BodyParser(req); err != nil {
 			return err
 		}
-		result, err := world.EvaluateCQL(req.CQL)
+		tick, hasTick, err := parseAtTick(ctx)
 		if err != nil {
+			return err
+		}
+		var result []types.EntityStateElement
+		if hasTick {
+			result, err = world.EvaluateCQLAtTick(req.CQL, tick)
+		} else {
+			result, err = world.EvaluateCQL(req.CQL)
+		}
+		if isTickNotInHistory(err) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		} else if err != nil {
 			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 		}
 		return ctx.JSON(CQLQueryResponse{Results: result})
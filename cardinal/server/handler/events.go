@@ -1,21 +1,219 @@
 package handler
 
 import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+
 	"github.com/gofiber/contrib/socketio"
 	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
+	"github.com/rotisserie/eris"
 	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/receipt"
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/types"
 )
 
+// EventSubscriptionRequest can be sent by a client over the /events websocket to change the topics it is subscribed
+// to.
+type EventSubscriptionRequest struct {
+	Subscribe   []types.Topic `json:"subscribe"`
+	Unsubscribe []types.Topic `json:"unsubscribe"`
+}
+
+// tickResultsMessage is the message sent to /events clients at the end of each tick. It has the same format as
+// cardinal.TickResults.
+type tickResultsMessage struct {
+	Tick     uint64
+	Receipts []receipt.Receipt
+	Events   [][]byte
+}
+
+// eventSubscriptionsAttribute is the websocket attribute that holds the EventSubscriptions of the server a connection
+// belongs to.
+const eventSubscriptionsAttribute = "eventSubscriptions"
+
+// registerEventHandlers makes sure the socketio event handlers, which are global, are only registered once. They find
+// the subscriptions of each connection through the eventSubscriptionsAttribute attribute.
+var registerEventHandlers sync.Once
+
+// EventSubscriptions keeps track of the topics each /events websocket connection is subscribed to. Connections that
+// have not subscribed to any topic receive everything.
+type EventSubscriptions struct {
+	mu sync.RWMutex
+	// topics maps the UUID of each connection to the set of topics it is subscribed to.
+	topics map[string]map[types.Topic]struct{}
+}
+
+func NewEventSubscriptions() *EventSubscriptions {
+	return &EventSubscriptions{
+		mu:     sync.RWMutex{},
+		topics: map[string]map[types.Topic]struct{}{},
+	}
+}
+
+// BroadcastTickResults sends the receipts and events of a tick to every connection. Connections that are subscribed to
+// topics only receive the receipts and events published to one of their topics. Nothing is sent to a connection if
+// none of the receipts and events match its topics.
+func (s *EventSubscriptions) BroadcastTickResults(
+	tick uint64, receipts []servertypes.TopicReceipt, events []servertypes.TopicEvent,
+) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	everything := make([]string, 0)
+	// Connections that are subscribed to the same topics receive the same message, so only build it once.
+	messages := map[string][]byte{}
+	for id, topics := range s.topics {
+		if len(topics) == 0 {
+			everything = append(everything, id)
+			continue
+		}
+		key := topicSetKey(topics)
+		bz, ok := messages[key]
+		if !ok {
+			msg := filterTickResults(tick, receipts, events, topics)
+			if len(msg.Receipts) > 0 || len(msg.Events) > 0 {
+				var err error
+				bz, err = json.Marshal(msg)
+				if err != nil {
+					return eris.Wrap(err, "failed to marshal tick results")
+				}
+			}
+			messages[key] = bz
+		}
+		if bz != nil {
+			// The connection may have been closed in the meantime, in which case there's no one to send the message to.
+			_ = socketio.EmitTo(id, bz)
+		}
+	}
+
+	if len(everything) > 0 {
+		bz, err := json.Marshal(filterTickResults(tick, receipts, events, nil))
+		if err != nil {
+			return eris.Wrap(err, "failed to marshal tick results")
+		}
+		socketio.EmitToList(everything, bz)
+	}
+	return nil
+}
+
+// filterTickResults returns the receipts and events that should be sent to a connection subscribed to the given
+// topics. If topics is nil, everything is returned.
+func filterTickResults(
+	tick uint64, receipts []servertypes.TopicReceipt, events []servertypes.TopicEvent,
+	topics map[types.Topic]struct{},
+) tickResultsMessage {
+	msg := tickResultsMessage{
+		Tick:     tick,
+		Receipts: make([]receipt.Receipt, 0, len(receipts)),
+		Events:   make([][]byte, 0, len(events)),
+	}
+	for _, r := range receipts {
+		if topics == nil || matchesAnyTopic(r.Topics, topics) {
+			msg.Receipts = append(msg.Receipts, r.Receipt)
+		}
+	}
+	for _, e := range events {
+		if topics == nil || matchesAnyTopic(e.Topics, topics) {
+			msg.Events = append(msg.Events, e.Data)
+		}
+	}
+	return msg
+}
+
+func matchesAnyTopic(published []types.Topic, subscribed map[types.Topic]struct{}) bool {
+	for _, topic := range published {
+		if _, ok := subscribed[topic]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func topicSetKey(topics map[types.Topic]struct{}) string {
+	keys := make([]string, 0, len(topics))
+	for topic := range topics {
+		keys = append(keys, string(topic))
+	}
+	slices.Sort(keys)
+	return strings.Join(keys, ",")
+}
+
+func (s *EventSubscriptions) connect(id string, topics []types.Topic) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.topics[id] = map[types.Topic]struct{}{}
+	for _, topic := range topics {
+		s.topics[id][topic] = struct{}{}
+	}
+}
+
+func (s *EventSubscriptions) disconnect(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.topics, id)
+}
+
+func (s *EventSubscriptions) update(id string, req EventSubscriptionRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	topics, ok := s.topics[id]
+	if !ok {
+		// The connection belongs to another server
+		return
+	}
+	for _, topic := range req.Subscribe {
+		topics[topic] = struct{}{}
+	}
+	for _, topic := range req.Unsubscribe {
+		delete(topics, topic)
+	}
+}
+
 // WebSocketEvents godoc
 //
 //	@Summary      Establishes a new websocket connection to retrieve system events
-//	@Description  Establishes a new websocket connection to retrieve system events
+//	@Description  Establishes a new websocket connection to retrieve system events. Clients can limit the receipts and
+//	@Description  events they receive to a set of topics (e.g. persona:<tag>, tx:<hash>, entity:<id>, event:<type>)
+//	@Description  with the topics query parameter, or by sending an EventSubscriptionRequest over the websocket.
 //	@Produce      application/json
-//	@Success      101  {string}  string  "Switch protocol to ws"
+//	@Param        topics  query     string  false  "Comma separated list of topics to subscribe to"
+//	@Success      101     {string}  string  "Switch protocol to ws"
 //	@Router       /events [get]
-func WebSocketEvents() func(c *fiber.Ctx) error {
-	return socketio.New(func(_ *socketio.Websocket) {
+func WebSocketEvents(subscriptions *EventSubscriptions) func(c *fiber.Ctx) error {
+	registerEventHandlers.Do(func() {
+		socketio.On(socketio.EventMessage, func(payload *socketio.EventPayload) {
+			subs, ok := payload.Kws.GetAttribute(eventSubscriptionsAttribute).(*EventSubscriptions)
+			if !ok {
+				return
+			}
+			var req EventSubscriptionRequest
+			if err := json.Unmarshal(payload.Data, &req); err != nil {
+				log.Debug().Err(err).Msg("ignoring invalid event subscription request")
+				return
+			}
+			subs.update(payload.Kws.GetUUID(), req)
+		})
+		socketio.On(socketio.EventDisconnect, func(payload *socketio.EventPayload) {
+			if subs, ok := payload.Kws.GetAttribute(eventSubscriptionsAttribute).(*EventSubscriptions); ok {
+				subs.disconnect(payload.Kws.GetUUID())
+			}
+		})
+	})
+
+	return socketio.New(func(kws *socketio.Websocket) {
+		topics := make([]types.Topic, 0)
+		for _, topic := range strings.Split(kws.Query("topics"), ",") {
+			if topic = strings.TrimSpace(topic); topic != "" {
+				topics = append(topics, types.Topic(topic))
+			}
+		}
+		kws.SetAttribute(eventSubscriptionsAttribute, subscriptions)
+		subscriptions.connect(kws.GetUUID(), topics)
 		log.Debug().Msg("new websocket connection established")
 	})
 }
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+)
+
+// GetNextNonceResponse is the HTTP response for the next nonce of a signer.
+type GetNextNonceResponse struct {
+	SignerAddress string `json:"signerAddress"`
+	NextNonce     uint64 `json:"nextNonce"`
+}
+
+// GetNextNonce godoc
+//
+//	@Summary      Retrieves the next nonce of a signer
+//	@Description  Retrieves the lowest nonce the signer can use in its next transaction. Nonces are only enforced when
+//	@Description  the world uses nonces for replay protection.
+//	@Produce      application/json
+//	@Param        signerAddress  path      string                true  "Address of the signer"
+//	@Success      200            {object}  GetNextNonceResponse  "Next nonce of the signer"
+//	@Router       /query/nonces/{signerAddress} [post]
+func GetNextNonce(world servertypes.ProviderWorld) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		signerAddress := ctx.Params("signerAddress")
+		nonce, err := world.GetNextNonce(signerAddress)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to get next nonce: "+err.Error())
+		}
+		return ctx.JSON(GetNextNonceResponse{
+			SignerAddress: signerAddress,
+			NextNonce:     nonce,
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"encoding/hex"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/component"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/merkle"
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// ComponentProofResponse is a Merkle proof of the value of a component of an entity. All the hashes and the value are
+// hex encoded, so they can be passed as is to a verifier contract (see package merkle for the algorithm).
+type ComponentProofResponse struct {
+	Tick      uint64   `json:"tick"`
+	StateRoot string   `json:"stateRoot"`
+	Key       string   `json:"key"`
+	Value     string   `json:"value"`
+	Depths    []uint   `json:"depths"`
+	Siblings  []string `json:"siblings"`
+}
+
+// GetComponentProof godoc
+//
+//	@Summary      Retrieves a Merkle proof of the value of a component of an entity
+//	@Description  Retrieves a Merkle proof of the value of a component of an entity against the state root of a tick
+//	@Produce      application/json
+//	@Param        component  path      string                  true   "Name of a registered component"
+//	@Param        id         path      integer                 true   "ID of the entity"
+//	@Param        atTick     query     integer                 false  "Prove the value as it was at this tick"
+//	@Success      200        {object}  ComponentProofResponse  "Proof of the component value"
+//	@Failure      400        {string}  string                  "Invalid request parameters"
+//	@Failure      404        {string}  string                  "Component value not found"
+//	@Router       /query/proofs/{component}/{id} [post]
+func GetComponentProof(world servertypes.ProviderWorld) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		id, err := strconv.ParseUint(ctx.Params("id"), 10, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid entity id: "+ctx.Params("id"))
+		}
+		tick, hasTick, err := parseAtTick(ctx)
+		if err != nil {
+			return err
+		}
+		var proof *gamestate.ComponentProof
+		if hasTick {
+			proof, err = world.GetComponentProofAtTick(ctx.Params("component"), types.EntityID(id), tick)
+		} else {
+			proof, err = world.GetComponentProof(ctx.Params("component"), types.EntityID(id))
+		}
+		switch {
+		case eris.Is(err, component.ErrComponentNotRegistered):
+			return fiber.NewError(fiber.StatusNotFound, "component not found")
+		case eris.Is(err, merkle.ErrKeyNotFound):
+			return fiber.NewError(fiber.StatusNotFound, "entity has no value for the component")
+		case isTickNotInHistory(err) || eris.Is(err, gamestate.ErrStateRootNotFound):
+			return fiber.NewError(fiber.StatusBadRequest, "tick is not in the state history")
+		case eris.Is(err, gamestate.ErrStateRootsDisabled):
+			return fiber.NewError(fiber.StatusBadRequest, "state roots are not enabled")
+		case err != nil:
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to get component proof: "+err.Error())
+		}
+
+		depths := make([]uint, 0, len(proof.Proof.Depths))
+		for _, depth := range proof.Proof.Depths {
+			depths = append(depths, uint(depth))
+		}
+		siblings := make([]string, 0, len(proof.Proof.Siblings))
+		for _, sibling := range proof.Proof.Siblings {
+			siblings = append(siblings, encodeHex(sibling))
+		}
+		return ctx.JSON(ComponentProofResponse{
+			Tick:      proof.Tick,
+			StateRoot: encodeHex(proof.StateRoot),
+			Key:       proof.Key,
+			Value:     encodeHex(proof.Value),
+			Depths:    depths,
+			Siblings:  siblings,
+		})
+	}
+}
+
+func encodeHex(bz []byte) string {
+	return "0x" + hex.EncodeToString(bz)
+}
@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"strconv"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/rotisserie/eris"
 
+	"pkg.world.dev/world-engine/cardinal/gamestate"
 	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
 	"pkg.world.dev/world-engine/cardinal/types"
 )
@@ -14,16 +17,26 @@ import (
 //	@Description  Executes a query
 //	@Accept       application/json
 //	@Produce      application/json
-//	@Param        queryGroup  path      string  true  "Query group"
-//	@Param        queryName   path      string  true  "Name of a registered query"
-//	@Param        queryBody   body      object  true  "Query to be executed"
-//	@Success      200         {object}  object  "Results of the executed query"
-//	@Failure      400         {string}  string  "Invalid request parameters"
+//	@Param        queryGroup  path      string   true   "Query group"
+//	@Param        queryName   path      string   true   "Name of a registered query"
+//	@Param        queryBody   body      object   true   "Query to be executed"
+//	@Param        atTick      query     integer  false  "Run against the state at this tick of the retained history"
+//	@Success      200         {object}  object   "Results of the executed query"
+//	@Failure      400         {string}  string   "Invalid request parameters"
 //	@Router       /query/{queryGroup}/{queryName} [post]
 func PostQuery(world servertypes.ProviderWorld) func(*fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		ctx.Set("Content-Type", "application/json")
-		resBz, err := world.HandleQuery(ctx.Params("group"), ctx.Params("name"), ctx.Body())
+		tick, hasTick, err := parseAtTick(ctx)
+		if err != nil {
+			return err
+		}
+		var resBz []byte
+		if hasTick {
+			resBz, err = world.HandleQueryAtTick(ctx.Params("group"), ctx.Params("name"), ctx.Body(), tick)
+		} else {
+			resBz, err = world.HandleQuery(ctx.Params("group"), ctx.Params("name"), ctx.Body())
+		}
 		if eris.Is(err, types.ErrQueryNotFound) {
 			return fiber.NewError(fiber.StatusNotFound, "query not found")
 		} else if err != nil {
@@ -32,3 +45,22 @@ func PostQuery(world servertypes.ProviderWorld) func(*fiber.Ctx) error {
 		return ctx.Send(resBz)
 	}
 }
+
+// parseAtTick parses the optional atTick query parameter that selects the tick of the game state a query should be run
+// against.
+func parseAtTick(ctx *fiber.Ctx) (tick uint64, ok bool, err error) {
+	param := ctx.Query("atTick")
+	if param == "" {
+		return 0, false, nil
+	}
+	tick, err = strconv.ParseUint(param, 10, 64)
+	if err != nil {
+		return 0, false, fiber.NewError(fiber.StatusBadRequest, "invalid atTick: "+param)
+	}
+	return tick, true, nil
+}
+
+// isTickNotInHistory returns true if the error was caused by querying a tick that is not in the state history.
+func isTickNotInHistory(err error) bool {
+	return eris.Is(err, gamestate.ErrTickNotInHistory)
+}
@@ -2,8 +2,11 @@ package handler
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/rotisserie/eris"
 
+	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/server/types"
+	cardinaltypes "pkg.world.dev/world-engine/cardinal/types"
 )
 
 type ListTxReceiptsRequest struct {
@@ -20,12 +23,14 @@ type ListTxReceiptsResponse struct {
 	Receipts  []ReceiptEntry `json:"receipts"`
 }
 
-// ReceiptEntry represents a single transaction receipt. It contains an ID, a result, and a list of errors.
+// ReceiptEntry represents a single transaction receipt. It contains an ID, a result, and a list of errors. Deferred is
+// set when the transaction was deferred to a later tick.
 type ReceiptEntry struct {
-	TxHash string   `json:"txHash"`
-	Tick   uint64   `json:"tick"`
-	Result any      `json:"result"`
-	Errors []string `json:"errors"`
+	TxHash   string   `json:"txHash"`
+	Tick     uint64   `json:"tick"`
+	Result   any      `json:"result"`
+	Errors   []string `json:"errors"`
+	Deferred bool     `json:"deferred,omitempty"`
 }
 
 // GetReceipts godoc
@@ -44,38 +49,61 @@ func GetReceipts(world types.ProviderWorld) func(*fiber.Ctx) error {
 		if err := ctx.BodyParser(req); err != nil {
 			return err
 		}
-		reply := ListTxReceiptsResponse{}
-		reply.EndTick = world.CurrentTick()
-		size := world.ReceiptHistorySize()
-		if size > reply.EndTick {
-			reply.StartTick = 0
-		} else {
-			reply.StartTick = reply.EndTick - size
+		reply, err := ListReceipts(world, req.StartTick)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 		}
-		// StartTick and EndTick are now at the largest possible range of ticks.
-		// Check to see if we should narrow down the range at all.
-		if req.StartTick > reply.EndTick {
-			// User is asking for ticks in the future.
-			reply.StartTick = reply.EndTick
-		} else if req.StartTick > reply.StartTick {
-			reply.StartTick = req.StartTick
+		return ctx.JSON(reply)
+	}
+}
+
+// ListReceipts returns the receipts of the ticks from startTick that are still retained by the world.
+func ListReceipts(world types.ProviderWorld, startTick uint64) (ListTxReceiptsResponse, error) {
+	reply := ListTxReceiptsResponse{}
+	reply.EndTick = world.CurrentTick()
+	firstTick, ticks, err := world.GetTransactionReceiptsForTicks(startTick, reply.EndTick)
+	if err != nil {
+		return reply, err
+	}
+	reply.StartTick = firstTick
+	for _, tick := range ticks {
+		for _, r := range tick.Receipts {
+			reply.Receipts = append(reply.Receipts, ReceiptEntry{
+				TxHash:   string(r.TxHash),
+				Tick:     tick.Tick,
+				Result:   r.Result,
+				Errors:   convertErrorsToStrings(r.Errs),
+				Deferred: r.Deferred,
+			})
 		}
+	}
+	return reply, nil
+}
 
-		for t := reply.StartTick; t < reply.EndTick; t++ {
-			currReceipts, err := world.GetTransactionReceiptsForTick(t)
-			if err != nil || len(currReceipts) == 0 {
-				continue
-			}
-			for _, r := range currReceipts {
-				reply.Receipts = append(reply.Receipts, ReceiptEntry{
-					TxHash: string(r.TxHash),
-					Tick:   t,
-					Result: r.Result,
-					Errors: convertErrorsToStrings(r.Errs),
-				})
-			}
+// GetReceipt godoc
+//
+//	@Summary      Retrieves the receipt of a transaction
+//	@Description  Retrieves the receipt of a transaction by its hash, from memory or from the receipt store
+//	@Produce      application/json
+//	@Param        txHash  path      string        true  "Hash of the transaction"
+//	@Success      200     {object}  ReceiptEntry  "Receipt of the transaction"
+//	@Failure      404     {string}  string        "Receipt not found"
+//	@Router       /query/receipts/{txHash} [post]
+func GetReceipt(world types.ProviderWorld) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		rec, tick, err := world.GetTransactionReceipt(cardinaltypes.TxHash(ctx.Params("txHash")))
+		if eris.Is(err, receipt.ErrReceiptNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "receipt not found")
+		} else if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 		}
-		return ctx.JSON(reply)
+		return ctx.JSON(ReceiptEntry{
+			TxHash:   string(rec.TxHash),
+			Tick:     tick,
+			Result:   rec.Result,
+			Errors:   convertErrorsToStrings(rec.Errs),
+			Deferred: rec.Deferred,
+		})
 	}
 }
 
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+)
+
+// GetResource godoc
+//
+//	@Summary      Retrieves the value of a world resource
+//	@Description  Retrieves the value of a world resource. Queries registered in the "resources" group are served by
+//	@Description  this route as long as their name is not the name of a resource.
+//	@Produce      application/json
+//	@Param        name    path      string   true   "Name of a registered resource"
+//	@Param        atTick  query     integer  false  "Get the value as it was at this tick of the retained history"
+//	@Success      200     {object}  object   "Value of the resource"
+//	@Failure      400     {string}  string   "Invalid request parameters"
+//	@Failure      404     {string}  string   "Resource or query not found"
+//	@Router       /query/resources/{name} [post]
+func GetResource(world servertypes.ProviderWorld) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		// Let the query handler serve queries of the "resources" group.
+		if _, err := world.GetResourceByName(ctx.Params("name")); err != nil {
+			return ctx.Next()
+		}
+		ctx.Set("Content-Type", "application/json")
+		tick, hasTick, err := parseAtTick(ctx)
+		if err != nil {
+			return err
+		}
+		var resBz []byte
+		if hasTick {
+			resBz, err = world.GetResourceInRawJSONAtTick(ctx.Params("name"), tick)
+		} else {
+			resBz, err = world.GetResourceInRawJSON(ctx.Params("name"))
+		}
+		if isTickNotInHistory(err) {
+			return fiber.NewError(fiber.StatusBadRequest, "tick is not in the state history")
+		} else if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to get resource: "+err.Error())
+		}
+		return ctx.Send(resBz)
+	}
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+)
+
+// stateDiffBufferSize is the number of tick diffs that can be queued up for a single subscriber. Subscribers that fall
+// further behind than this are disconnected.
+const stateDiffBufferSize = 64
+
+// StateDiffSubscriptions keeps track of the websocket connections that are subscribed to per-tick state diffs.
+type StateDiffSubscriptions struct {
+	mu          sync.Mutex
+	subscribers map[*stateDiffSubscriber]struct{}
+}
+
+type stateDiffSubscriber struct {
+	components []string
+	messages   chan []byte
+}
+
+func NewStateDiffSubscriptions() *StateDiffSubscriptions {
+	return &StateDiffSubscriptions{
+		mu:          sync.Mutex{},
+		subscribers: map[*stateDiffSubscriber]struct{}{},
+	}
+}
+
+// HasSubscribers returns true if at least one websocket connection is subscribed to state diffs.
+func (s *StateDiffSubscriptions) HasSubscribers() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscribers) > 0
+}
+
+// Broadcast sends the given state changes to every subscriber, filtered by the components each subscriber asked for.
+// Subscribers that filtered out every change in the tick still receive an empty diff, so they can keep track of
+// the tick. If the diff of a filter can not be encoded, the subscribers with that filter miss the tick, and the other
+// subscribers still receive it.
+func (s *StateDiffSubscriptions) Broadcast(changes *gamestate.StateChanges) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Subscribers with the same filter receive the same message, so only encode it once.
+	encoded := map[string][]byte{}
+	for sub := range s.subscribers {
+		filterKey := strings.Join(sub.components, ",")
+		bz, ok := encoded[filterKey]
+		if !ok {
+			var err error
+			bz, err = json.Marshal(changes.FilterByComponents(sub.components))
+			if err != nil {
+				log.Err(err).Uint64("tick", changes.Tick).Strs("components", sub.components).
+					Msg("failed to marshal state diff")
+				bz = nil
+			}
+			encoded[filterKey] = bz
+		}
+		if bz == nil {
+			continue
+		}
+		select {
+		case sub.messages <- bz:
+		default:
+			log.Warn().Msg("state diff subscriber is too slow, closing the connection")
+			s.remove(sub)
+		}
+	}
+}
+
+// Close disconnects all subscribers.
+func (s *StateDiffSubscriptions) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscribers {
+		s.remove(sub)
+	}
+}
+
+func (s *StateDiffSubscriptions) add(sub *stateDiffSubscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers[sub] = struct{}{}
+}
+
+func (s *StateDiffSubscriptions) unsubscribe(sub *stateDiffSubscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(sub)
+}
+
+// remove must be called while holding the lock.
+func (s *StateDiffSubscriptions) remove(sub *stateDiffSubscriber) {
+	if _, ok := s.subscribers[sub]; !ok {
+		return
+	}
+	delete(s.subscribers, sub)
+	close(sub.messages)
+}
+
+// WebSocketStateDiffs godoc
+//
+//	@Summary      Establishes a new websocket connection to retrieve per-tick state diffs
+//	@Description  Establishes a new websocket connection that receives the entities created and removed, and the
+//	@Description  component values changed in each tick. Each message is a JSON encoded StateChanges object
+//	@Description  for one tick, and ticks without a matching change are sent as an empty diff.
+//	@Produce      application/json
+//	@Param        components  query     string  false  "Comma separated list of component names to receive changes for"
+//	@Success      101         {string}  string  "Switch protocol to ws"
+//	@Router       /events/diffs [get]
+func WebSocketStateDiffs(subscriptions *StateDiffSubscriptions) func(c *fiber.Ctx) error {
+	return websocket.New(func(conn *websocket.Conn) {
+		sub := &stateDiffSubscriber{
+			components: parseComponentNames(conn.Query("components")),
+			messages:   make(chan []byte, stateDiffBufferSize),
+		}
+		subscriptions.add(sub)
+		log.Debug().Strs("components", sub.components).Msg("new state diff subscription")
+
+		// All writes happen in a separate goroutine. The messages channel is closed once the subscriber is removed, after
+		// which the connection is closed so the read loop below returns.
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for msg := range sub.messages {
+				if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+					subscriptions.unsubscribe(sub)
+				}
+			}
+			_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			_ = conn.Close()
+		}()
+
+		// Messages sent by the client are ignored, but the connection must be read from to notice that the client has
+		// disconnected.
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				break
+			}
+		}
+		subscriptions.unsubscribe(sub)
+		<-done
+	})
+}
+
+func parseComponentNames(param string) []string {
+	if param == "" {
+		return nil
+	}
+	names := make([]string, 0)
+	for _, name := range strings.Split(param, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -8,6 +8,7 @@ import (
 	personaMsg "pkg.world.dev/world-engine/cardinal/persona/msg"
 	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
 	"pkg.world.dev/world-engine/cardinal/server/validator"
+	"pkg.world.dev/world-engine/cardinal/txpool"
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/sign"
 )
@@ -31,6 +32,8 @@ type PostTransactionResponse struct {
 //	@Failure      400      {string}  string                   "Invalid request parameter"
 //	@Failure      403      {string}  string                   "Forbidden"
 //	@Failure      408      {string}  string                   "Request Timeout - message expired"
+//	@Failure      429      {string}  string                   "Too Many Requests - persona rate limit exceeded"
+//	@Failure      503      {string}  string                   "Service Unavailable - transaction pool is full"
 //	@Router       /tx/{txGroup}/{txName} [post]
 func PostTransaction(
 	world servertypes.ProviderWorld, msgs map[string]map[string]types.Message, validator *validator.SignatureValidator,
@@ -48,36 +51,18 @@ func PostTransaction(
 			return err
 		}
 
-		// make sure the transaction hasn't expired
-		if err = validator.ValidateTransactionTTL(tx); err != nil {
-			return httpResultFromError(err, false)
-		}
-
-		// Decode the message from the transaction
-		msg, err := msgType.Decode(tx.Body)
+		msg, err := ValidateTransaction(msgType, tx, validator)
 		if err != nil {
-			log.Errorf("message %s Decode failed: %v", tx.Hash.String(), err)
-			return fiber.NewError(fiber.StatusBadRequest, "Bad Request - failed to decode tx message")
-		}
-
-		// there's a special case for the CreatePersona message
-		var signerAddress string
-		if msgType.Name() == personaMsg.CreatePersonaMessageName {
-			createPersonaMsg, ok := msg.(personaMsg.CreatePersona)
-			if !ok {
-				return fiber.NewError(fiber.StatusInternalServerError, "Internal Server Error - bad message type")
-			}
-			signerAddress = createPersonaMsg.SignerAddress
-		}
-
-		// Validate the transaction's signature
-		if err = validator.ValidateTransactionSignature(tx, signerAddress); err != nil {
-			return httpResultFromError(err, true)
+			return err
 		}
 
 		// Add the transaction to the engine
 		// TODO(scott): this should just deal with txpool instead of having to go through engine
-		tick, hash := world.AddTransaction(msgType.ID(), msg, tx)
+		tick, hash, err := world.SubmitTransaction(msgType.ID(), msg, tx)
+		if err != nil {
+			ReleaseNonces([]txpool.TxData{{MsgID: msgType.ID(), Msg: msg, Tx: tx}}, validator)
+			return HTTPResultFromTxPoolError(err)
+		}
 
 		return ctx.JSON(&PostTransactionResponse{
 			TxHash: string(hash),
@@ -99,6 +84,8 @@ func PostTransaction(
 //	@Failure      400     {string}  string                   "Invalid request parameter"
 //	@Failure      403     {string}  string                   "Forbidden"
 //	@Failure      408     {string}  string                   "Request Timeout - message expired"
+//	@Failure      429     {string}  string                   "Too Many Requests - persona rate limit exceeded"
+//	@Failure      503     {string}  string                   "Service Unavailable - transaction pool is full"
 //	@Router       /tx/game/{txName} [post]
 func PostGameTransaction(
 	world servertypes.ProviderWorld, msgs map[string]map[string]types.Message, validator *validator.SignatureValidator,
@@ -119,7 +106,9 @@ func PostGameTransaction(
 //	@Failure      401     {string}  string                   "Unauthorized - signature was invalid"
 //	@Failure      403     {string}  string                   "Forbidden"
 //	@Failure      408     {string}  string                   "Request Timeout - message expired"
+//	@Failure      429     {string}  string                   "Too Many Requests - persona rate limit exceeded"
 //	@Failure      500     {string}  string                   "Internal Server Error - unexpected cache errors"
+//	@Failure      503     {string}  string                   "Service Unavailable - transaction pool is full"
 //	@Router       /tx/persona/create-persona [post]
 func PostPersonaTransaction(
 	world servertypes.ProviderWorld, msgs map[string]map[string]types.Message, validator *validator.SignatureValidator,
@@ -127,6 +116,80 @@ func PostPersonaTransaction(
 	return PostTransaction(world, msgs, validator)
 }
 
+// ValidateTransaction checks that a transaction of the given message type has not expired and is correctly signed,
+// and returns its decoded message. The returned error is a *fiber.Error.
+func ValidateTransaction(
+	msgType types.Message, tx *sign.Transaction, validator *validator.SignatureValidator,
+) (any, error) {
+	return validateTransaction(msgType, tx, validator, false)
+}
+
+// ValidateRestoredTransaction is ValidateTransaction for a transaction that was accepted before a restart, see
+// validator.SignatureValidator.ValidateRestoredTransaction.
+func ValidateRestoredTransaction(
+	msgType types.Message, tx *sign.Transaction, validator *validator.SignatureValidator,
+) (any, error) {
+	return validateTransaction(msgType, tx, validator, true)
+}
+
+func validateTransaction(
+	msgType types.Message, tx *sign.Transaction, validator *validator.SignatureValidator, restored bool,
+) (any, error) {
+	// make sure the transaction hasn't expired
+	if !restored {
+		if err := validator.ValidateTransactionTTL(tx); err != nil {
+			return nil, httpResultFromError(err, false)
+		}
+	}
+
+	// Decode the message from the transaction
+	msg, err := msgType.Decode(tx.Body)
+	if err != nil {
+		log.Errorf("message %s Decode failed: %v", tx.Hash.String(), err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Bad Request - failed to decode tx message")
+	}
+
+	// there's a special case for the CreatePersona message
+	var signerAddress string
+	if msgType.Name() == personaMsg.CreatePersonaMessageName {
+		createPersonaMsg, ok := msg.(personaMsg.CreatePersona)
+		if !ok {
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "Internal Server Error - bad message type")
+		}
+		signerAddress = createPersonaMsg.SignerAddress
+	}
+
+	// Validate the transaction's signature
+	if restored {
+		err = validator.ValidateRestoredTransaction(tx, signerAddress)
+	} else {
+		err = validator.ValidateTransactionSignature(tx, signerAddress)
+	}
+	if err != nil {
+		return nil, httpResultFromError(err, true)
+	}
+	return msg, nil
+}
+
+// ReleaseNonces gives back the nonces that the given transactions used when they were validated, after the transaction
+// pool rejected them, so their signers can submit them again. See validator.SignatureValidator.ReleaseNonces.
+func ReleaseNonces(txs []txpool.TxData, validator *validator.SignatureValidator) {
+	if len(txs) == 0 || validator == nil {
+		return
+	}
+	signTxs := make([]*sign.Transaction, len(txs))
+	signerAddresses := make([]string, len(txs))
+	for i, tx := range txs {
+		signTxs[i] = tx.Tx
+		if createPersonaMsg, ok := tx.Msg.(personaMsg.CreatePersona); ok {
+			signerAddresses[i] = createPersonaMsg.SignerAddress
+		}
+	}
+	if err := validator.ReleaseNonces(signTxs, signerAddresses); err != nil {
+		log.Errorf("failed to release the nonces of rejected transactions: %v", err)
+	}
+}
+
 func extractTx(ctx *fiber.Ctx, validator *validator.SignatureValidator) (*sign.Transaction, error) {
 	var tx *sign.Transaction
 	var err error
@@ -153,6 +216,9 @@ func httpResultFromError(err error, isSignatureValidation bool) error {
 	if eris.Is(err, validator.ErrDuplicateMessage) {
 		return fiber.NewError(fiber.StatusForbidden, "Forbidden - duplicate message")
 	}
+	if eris.Is(err, validator.ErrNonceTooLow) {
+		return fiber.NewError(fiber.StatusForbidden, "Forbidden - nonce too low")
+	}
 	if eris.Is(err, validator.ErrMessageExpired) {
 		return fiber.NewError(fiber.StatusRequestTimeout, "Request Timeout - message expired")
 	}
@@ -170,3 +236,16 @@ func httpResultFromError(err error, isSignatureValidation bool) error {
 	}
 	return fiber.NewError(fiber.StatusInternalServerError, "Internal Server Error - ttl validation failed")
 }
+
+// HTTPResultFromTxPoolError converts an error returned when a transaction is submitted to the transaction pool to a
+// *fiber.Error.
+func HTTPResultFromTxPoolError(err error) error {
+	log.Error(err)
+	if eris.Is(err, txpool.ErrRateLimited) {
+		return fiber.NewError(fiber.StatusTooManyRequests, "Too Many Requests - persona rate limit exceeded")
+	}
+	if eris.Is(err, txpool.ErrPoolFull) {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Service Unavailable - transaction pool is full")
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, "Internal Server Error - failed to add transaction")
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/rotisserie/eris"
+
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/server/validator"
+	"pkg.world.dev/world-engine/cardinal/txpool"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/sign"
+)
+
+// maxBatchSize is the maximum number of transactions in a single batch request.
+const maxBatchSize = 1024
+
+// PostBatchTransactionRequest is the HTTP request to submit many transactions at once.
+type PostBatchTransactionRequest struct {
+	Transactions []BatchTransaction `json:"transactions"`
+}
+
+// BatchTransaction is a transaction in a batch, along with the group and name of its message.
+type BatchTransaction struct {
+	Group string          `json:"group"`
+	Name  string          `json:"name"`
+	Tx    json.RawMessage `json:"tx" swaggertype:"object"`
+}
+
+// PostBatchTransactionResponse is the HTTP response for a batch of transactions. The results are in the same order as
+// the transactions of the request.
+type PostBatchTransactionResponse struct {
+	Tick    uint64                   `json:"tick"`
+	Results []BatchTransactionResult `json:"results"`
+}
+
+// BatchTransactionResult is the result of a single transaction in a batch. Status is the HTTP status the transaction
+// would have gotten if it had been submitted on its own; Error is only set when the transaction was rejected.
+type BatchTransactionResult struct {
+	TxHash string `json:"txHash,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// PostBatchTransaction godoc
+//
+//	@Summary      Submits a batch of transactions
+//	@Description  Submits many transactions of any message type at once. Each transaction is validated on its own, and
+//	@Description  all the valid transactions are enqueued atomically into the same tick, or none if they do not all fit
+//	@Description  in the pool.
+//	@Accept       application/json
+//	@Produce      application/json
+//	@Param        txBody  body      PostBatchTransactionRequest   true  "Transactions to be submitted"
+//	@Success      200     {object}  PostBatchTransactionResponse  "Tick, and hash or error of each transaction"
+//	@Failure      400     {string}  string                        "Invalid request parameter"
+//	@Router       /tx/batch [post]
+func PostBatchTransaction(
+	world servertypes.ProviderWorld, msgs map[string]map[string]types.Message, validator *validator.SignatureValidator,
+) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		var req PostBatchTransactionRequest
+		if err := ctx.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Bad Request - unparseable body")
+		}
+		if len(req.Transactions) > maxBatchSize {
+			return fiber.NewError(fiber.StatusBadRequest, "Bad Request - too many transactions in batch")
+		}
+
+		results := make([]BatchTransactionResult, len(req.Transactions))
+		txs := make([]txpool.TxData, 0, len(req.Transactions))
+		// accepted holds the index in results of each transaction in txs
+		accepted := make([]int, 0, len(req.Transactions))
+		for i, batchTx := range req.Transactions {
+			data, err := validateBatchTx(batchTx, msgs, validator)
+			if err != nil {
+				results[i] = batchResultFromError(err)
+				continue
+			}
+			txs = append(txs, data)
+			accepted = append(accepted, i)
+		}
+
+		tick, hashes, err := world.SubmitTransactions(txs)
+		if err != nil {
+			// None of the transactions were added to the pool.
+			ReleaseNonces(txs, validator)
+			result := batchResultFromError(HTTPResultFromTxPoolError(err))
+			for _, i := range accepted {
+				results[i] = result
+			}
+		}
+		for j, hash := range hashes {
+			results[accepted[j]] = BatchTransactionResult{
+				TxHash: string(hash),
+				Status: fiber.StatusOK,
+			}
+		}
+		return ctx.JSON(&PostBatchTransactionResponse{
+			Tick:    tick,
+			Results: results,
+		})
+	}
+}
+
+// validateBatchTx parses and validates a transaction of a batch the same way PostTransaction does.
+func validateBatchTx(
+	batchTx BatchTransaction, msgs map[string]map[string]types.Message, validator *validator.SignatureValidator,
+) (txpool.TxData, error) {
+	msgType, ok := msgs[batchTx.Group][batchTx.Name]
+	if !ok {
+		return txpool.TxData{}, fiber.NewError(fiber.StatusNotFound, "Not Found - bad msg type")
+	}
+	tx, err := unmarshalTx(batchTx.Tx, validator)
+	if err != nil {
+		return txpool.TxData{}, fiber.NewError(fiber.StatusBadRequest, "Bad Request - unparseable tx")
+	}
+	msg, err := ValidateTransaction(msgType, tx, validator)
+	if err != nil {
+		return txpool.TxData{}, err
+	}
+	return txpool.TxData{
+		MsgID: msgType.ID(),
+		Msg:   msg,
+		Tx:    tx,
+	}, nil
+}
+
+// unmarshalTx is the equivalent of extractTx for a transaction that is not the whole body of the request.
+func unmarshalTx(bz []byte, validator *validator.SignatureValidator) (*sign.Transaction, error) {
+	if validator != nil && !validator.IsDisabled {
+		return sign.UnmarshalTransaction(bz)
+	}
+	tx := new(sign.Transaction)
+	if err := json.Unmarshal(bz, tx); err != nil {
+		log.Errorf("tx parse failed: %v", err)
+		return nil, eris.Wrap(err, "")
+	}
+	return tx, nil
+}
+
+func batchResultFromError(err error) BatchTransactionResult {
+	var fiberErr *fiber.Error
+	if !errors.As(err, &fiberErr) {
+		fiberErr = fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return BatchTransactionResult{
+		Status: fiberErr.Code,
+		Error:  fiberErr.Message,
+	}
+}
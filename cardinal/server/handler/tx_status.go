@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"pkg.world.dev/world-engine/cardinal/receipt"
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// maxTxStatusWait is the longest a transaction status request can wait for the transaction to be handled.
+const maxTxStatusWait = 30 * time.Second
+
+// GetTxStatusResponse is the HTTP response for the status of a transaction. Status is one of pending, processed,
+// failed or unknown. Receipt is only set when the transaction was handled.
+type GetTxStatusResponse struct {
+	TxHash  string        `json:"txHash"`
+	Status  string        `json:"status"`
+	Receipt *ReceiptEntry `json:"receipt,omitempty"`
+}
+
+// GetTxStatus godoc
+//
+//	@Summary      Retrieves the status of a transaction
+//	@Description  Retrieves the status of a transaction: pending, processed, failed or unknown. If the wait parameter
+//	@Description  is set and the transaction is pending, the request waits until the tick that handles the
+//	@Description  transaction is finalized, or until the wait duration (at most 30s) elapses.
+//	@Produce      application/json
+//	@Param        txHash  path      string               true   "Hash of the transaction"
+//	@Param        wait    query     string               false  "How long to wait for a pending transaction, e.g. 5s"
+//	@Success      200     {object}  GetTxStatusResponse  "Status of the transaction"
+//	@Failure      400     {string}  string               "Invalid wait duration"
+//	@Router       /tx/{txHash}/status [get]
+func GetTxStatus(world servertypes.ProviderWorld) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		txHash := types.TxHash(ctx.Params("hash"))
+
+		var wait time.Duration
+		if waitParam := ctx.Query("wait"); waitParam != "" {
+			var err error
+			wait, err = time.ParseDuration(waitParam)
+			if err != nil || wait < 0 {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid wait duration: "+waitParam)
+			}
+			wait = min(wait, maxTxStatusWait)
+		}
+
+		var (
+			status types.TxStatus
+			rec    *receipt.Receipt
+			tick   uint64
+			err    error
+		)
+		if wait > 0 {
+			waitCtx, cancel := context.WithTimeout(ctx.Context(), wait)
+			defer cancel()
+			status, rec, tick, err = world.WaitForTransaction(waitCtx, txHash)
+		} else {
+			status, rec, tick, err = world.GetTransactionStatus(txHash)
+		}
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+
+		res := GetTxStatusResponse{TxHash: string(txHash), Status: string(status)}
+		if rec != nil {
+			res.Receipt = &ReceiptEntry{
+				TxHash:   string(rec.TxHash),
+				Tick:     tick,
+				Result:   rec.Result,
+				Errors:   convertErrorsToStrings(rec.Errs),
+				Deferred: rec.Deferred,
+			}
+		}
+		return ctx.JSON(res)
+	}
+}
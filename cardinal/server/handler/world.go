@@ -37,7 +37,8 @@ func GetWorld(
 	// Collecting name of all registered components
 	comps := make([]types.FieldDetail, 0, len(components))
 	for _, component := range components {
-		c, _ := component.Decode(component.GetSchema())
+		bz, _ := component.New()
+		c, _ := component.Decode(bz)
 		comps = append(comps, types.FieldDetail{
 			Name:   component.Name(),
 			Fields: types.GetFieldInformation(reflect.TypeOf(c)),
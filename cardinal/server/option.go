@@ -45,3 +45,29 @@ func WithHashCacheSize(sizeKB uint) Option {
 		s.config.messageHashCacheSizeKB = sizeKB
 	}
 }
+
+// WithNonceReplayProtection replaces the hash cache with per signer nonces for replay protection: the nonce of each
+// transaction must be higher than the nonce of the previous transaction of the same signer, and transactions must be
+// signed with sign.HashVersion1. The nonces are stored in Redis, so replay protection is kept across restarts.
+// Transactions still expire after the message expiration time. The next nonce of a signer is returned by the
+// /query/nonces/{signerAddress} endpoint.
+// This setting is ignored if the DisableSignatureVerification option is used
+func WithNonceReplayProtection() Option {
+	return func(s *Server) {
+		s.config.isNonceReplayProtection = true
+	}
+}
+
+// EnableStateDiffStream enables the /events/diffs websocket endpoint, which streams the state changes of every tick.
+func EnableStateDiffStream() Option {
+	return func(s *Server) {
+		s.config.isStateDiffStreamEnabled = true
+	}
+}
+
+// WithGRPCPort enables the gRPC server (see rift/proto/cardinal/v1) on the given port, alongside the HTTP server.
+func WithGRPCPort(port string) Option {
+	return func(s *Server) {
+		s.config.grpcPort = port
+	}
+}
@@ -77,3 +77,34 @@ func (s *ServerTestSuite) TestReceiptsQuery() {
 	s.Require().Equal(string(expectedJSON1), string(json1))
 	s.Require().Equal(string(expectedJSON2), string(json2))
 }
+
+func (s *ServerTestSuite) TestReceiptQuery() {
+	s.setupWorld()
+	world := s.world
+	type fooIn struct{}
+	type fooOut struct{ Y int }
+	err := cardinal.RegisterMessage[fooIn, fooOut](world, "foo")
+	s.Require().NoError(err)
+	err = cardinal.RegisterSystems(world, func(ctx cardinal.WorldContext) error {
+		return cardinal.EachMessage[fooIn, fooOut](ctx, func(cardinal.TxData[fooIn]) (fooOut, error) {
+			return fooOut{Y: 4}, nil
+		})
+	})
+	s.Require().NoError(err)
+
+	fooMsg, ok := world.GetMessageByFullName("game.foo")
+	s.Require().True(ok)
+	_, txHash := world.AddTransaction(fooMsg.ID(), fooIn{}, &sign.Transaction{PersonaTag: "alpha"})
+	s.fixture.DoTick()
+
+	res := s.fixture.Post("query/receipts/"+string(txHash), nil)
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	var entry handler.ReceiptEntry
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&entry))
+	s.Require().Equal(string(txHash), entry.TxHash)
+	s.Require().Equal(uint64(0), entry.Tick)
+	s.Require().Equal(map[string]any{"Y": float64(4)}, entry.Result)
+
+	res = s.fixture.Post("query/receipts/unknown", nil)
+	s.Require().Equal(http.StatusNotFound, res.StatusCode)
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"net"
 	"time"
 
 	"github.com/gofiber/contrib/socketio"
@@ -11,11 +12,16 @@ import (
 	"github.com/gofiber/swagger"
 	"github.com/rotisserie/eris"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
 
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/server/handler"
 	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
 	"pkg.world.dev/world-engine/cardinal/server/validator"
 	"pkg.world.dev/world-engine/cardinal/types"
+	cardinalv1 "pkg.world.dev/world-engine/rift/cardinal/v1"
+	"pkg.world.dev/world-engine/sign"
 
 	_ "pkg.world.dev/world-engine/cardinal/server/docs" // for swagger.
 )
@@ -33,12 +39,21 @@ type config struct {
 	isSignatureValidationDisabled bool
 	messageExpirationSeconds      uint
 	messageHashCacheSizeKB        uint
+	isNonceReplayProtection       bool
+	isStateDiffStreamEnabled      bool
+	// grpcPort is the port of the gRPC server. The gRPC server is disabled when it is empty.
+	grpcPort string
 }
 
 type Server struct {
-	app       *fiber.App
-	config    config
-	validator *validator.SignatureValidator
+	app        *fiber.App
+	config     config
+	validator  *validator.SignatureValidator
+	stateDiffs *handler.StateDiffSubscriptions
+	events     *handler.EventSubscriptions
+
+	grpcServer  *grpc.Server
+	tickResults *tickResultsSubscriptions
 }
 
 // New returns an HTTP server with handlers for all QueryTypes and MessageTypes.
@@ -61,7 +76,11 @@ func New(
 			isSignatureValidationDisabled: false,
 			messageExpirationSeconds:      defaultMessageExpiration,
 			messageHashCacheSizeKB:        defaultHashCacheSizeKB,
+			isStateDiffStreamEnabled:      false,
 		},
+		stateDiffs:  handler.NewStateDiffSubscriptions(),
+		events:      handler.NewEventSubscriptions(),
+		tickResults: newTickResultsSubscriptions(),
 	}
 	for _, opt := range opts {
 		opt(s)
@@ -75,12 +94,38 @@ func New(
 		world.Namespace(),
 		world, // world is a provider of signature addresses
 	)
+	if s.config.isNonceReplayProtection {
+		s.validator.EnableNonces(world) // world stores the nonces of the signers
+	}
 
 	// Enable CORS
 	app.Use(cors.New())
 
+	// /tx/:group/:txType
+	// maps group -> txType -> tx
+	msgIndex := make(map[string]map[string]types.Message)
+
+	// Create tx index
+	for _, msg := range messages {
+		// Initialize inner map if it doesn't exist
+		if _, ok := msgIndex[msg.Group()]; !ok {
+			msgIndex[msg.Group()] = make(map[string]types.Message)
+		}
+		msgIndex[msg.Group()][msg.Name()] = msg
+	}
+
 	// Register routes
-	s.setupRoutes(world, messages, components)
+	s.setupRoutes(world, msgIndex, messages, components)
+
+	if s.config.grpcPort != "" {
+		s.grpcServer = grpc.NewServer()
+		cardinalv1.RegisterCardinalServer(s.grpcServer, &grpcService{
+			world:       world,
+			msgs:        msgIndex,
+			validator:   s.validator,
+			tickResults: s.tickResults,
+		})
+	}
 
 	return s, nil
 }
@@ -88,7 +133,17 @@ func New(
 // Serve serves the application, blocking the calling thread.
 // Call this in a new go routine to prevent blocking.
 func (s *Server) Serve(ctx context.Context) error {
-	serverErr := make(chan error, 1)
+	serverErr := make(chan error, 2) //nolint:gomnd // one for each server
+
+	// The gRPC listener is opened first, so nothing has to be stopped if the port is not available.
+	var grpcListener net.Listener
+	if s.grpcServer != nil {
+		var err error
+		grpcListener, err = net.Listen("tcp", ":"+s.config.grpcPort)
+		if err != nil {
+			return eris.Wrap(err, "error starting grpc server")
+		}
+	}
 
 	// Starts the server in a new goroutine
 	go func() {
@@ -98,9 +153,22 @@ func (s *Server) Serve(ctx context.Context) error {
 		}
 	}()
 
+	if grpcListener != nil {
+		go func() {
+			log.Info().Msgf("Starting gRPC server at port %s", s.config.grpcPort)
+			if err := s.grpcServer.Serve(grpcListener); err != nil {
+				serverErr <- eris.Wrap(err, "error serving grpc server")
+			}
+		}()
+	}
+
 	// This function will block until the server is shutdown or the context is canceled.
 	select {
 	case err := <-serverErr:
+		// Stop the other server, so it does not keep running after Serve returns.
+		if shutdownErr := s.shutdown(); shutdownErr != nil {
+			log.Error().Err(shutdownErr).Msg("error shutting down server")
+		}
 		return eris.Wrap(err, "server encountered an error")
 	case <-ctx.Done():
 		if err := s.shutdown(); err != nil {
@@ -111,6 +179,12 @@ func (s *Server) Serve(ctx context.Context) error {
 	return nil
 }
 
+// ValidateRestoredTransaction checks a transaction of the given message type that was accepted before a restart, the
+// same way it was checked when it was submitted, and returns its decoded message.
+func (s *Server) ValidateRestoredTransaction(msgType types.Message, tx *sign.Transaction) (any, error) {
+	return handler.ValidateRestoredTransaction(msgType, tx, s.validator)
+}
+
 func (s *Server) BroadcastEvent(event any) error {
 	eventBz, err := json.Marshal(event)
 	if err != nil {
@@ -120,6 +194,38 @@ func (s *Server) BroadcastEvent(event any) error {
 	return nil
 }
 
+// BroadcastTickResults sends the receipts and events of a tick to the /events websocket clients that are subscribed
+// to their topics.
+func (s *Server) BroadcastTickResults(
+	tick uint64, receipts []servertypes.TopicReceipt, events []servertypes.TopicEvent,
+) error {
+	if s.grpcServer != nil && s.tickResults.hasSubscribers() {
+		plainReceipts := make([]receipt.Receipt, 0, len(receipts))
+		for _, r := range receipts {
+			plainReceipts = append(plainReceipts, r.Receipt)
+		}
+		plainEvents := make([][]byte, 0, len(events))
+		for _, e := range events {
+			plainEvents = append(plainEvents, e.Data)
+		}
+		if err := s.tickResults.broadcast(tick, plainReceipts, plainEvents); err != nil {
+			return err
+		}
+	}
+	return s.events.BroadcastTickResults(tick, receipts, events)
+}
+
+// HasStateDiffSubscribers returns true if there is at least one client subscribed to the state diff stream. The state
+// changes of a tick only need to be computed when this is true.
+func (s *Server) HasStateDiffSubscribers() bool {
+	return s.config.isStateDiffStreamEnabled && s.stateDiffs.HasSubscribers()
+}
+
+// BroadcastStateDiff sends the state changes of a tick to all clients subscribed to the state diff stream.
+func (s *Server) BroadcastStateDiff(changes *gamestate.StateChanges) {
+	s.stateDiffs.Broadcast(changes)
+}
+
 // Shutdown gracefully shuts down the server and closes all active websocket connections.
 func (s *Server) shutdown() error {
 	log.Info().Msg("Shutting down server")
@@ -127,6 +233,13 @@ func (s *Server) shutdown() error {
 	// Close websocket connections
 	socketio.Broadcast([]byte(""), socketio.CloseMessage)
 	socketio.Fire(socketio.EventClose, nil)
+	s.stateDiffs.Close()
+
+	// Stop the gRPC server once the TickResults streams have ended
+	if s.grpcServer != nil {
+		s.tickResults.close()
+		s.grpcServer.GracefulStop()
+	}
 
 	// Gracefully shutdown Fiber server
 	if err := s.app.ShutdownWithTimeout(shutdownTimeout); err != nil {
@@ -146,22 +259,10 @@ func (s *Server) shutdown() error {
 // @produces		application/json
 func (s *Server) setupRoutes(
 	world servertypes.ProviderWorld,
+	msgIndex map[string]map[string]types.Message,
 	messages []types.Message,
 	components []types.ComponentMetadata,
 ) {
-	// /tx/:group/:txType
-	// maps group -> txType -> tx
-	msgIndex := make(map[string]map[string]types.Message)
-
-	// Create tx index
-	for _, msg := range messages {
-		// Initialize inner map if it doesn't exist
-		if _, ok := msgIndex[msg.Group()]; !ok {
-			msgIndex[msg.Group()] = make(map[string]types.Message)
-		}
-		msgIndex[msg.Group()][msg.Name()] = msg
-	}
-
 	// Route: /swagger/
 	if !s.config.isSwaggerDisabled {
 		s.app.Get("/swagger/*", swagger.HandlerDefault)
@@ -169,7 +270,10 @@ func (s *Server) setupRoutes(
 
 	// Route: /events/
 	s.app.Use("/events", handler.WebSocketUpgrader)
-	s.app.Get("/events", handler.WebSocketEvents())
+	s.app.Get("/events", handler.WebSocketEvents(s.events))
+	if s.config.isStateDiffStreamEnabled {
+		s.app.Get("/events/diffs", handler.WebSocketStateDiffs(s.stateDiffs))
+	}
 
 	// Route: /world
 	s.app.Get("/world", handler.GetWorld(world, components, messages, world.Namespace()))
@@ -180,10 +284,16 @@ func (s *Server) setupRoutes(
 	// Route: /query/...
 	query := s.app.Group("/query")
 	query.Post("/receipts/list", handler.GetReceipts(world))
+	query.Post("/receipts/:txHash", handler.GetReceipt(world))
+	query.Post("/nonces/:signerAddress", handler.GetNextNonce(world))
+	query.Post("/resources/:name", handler.GetResource(world))
+	query.Post("/proofs/:component/:id", handler.GetComponentProof(world))
 	query.Post("/:group/:name", handler.PostQuery(world))
 
 	// Route: /tx/...
 	tx := s.app.Group("/tx")
+	tx.Post("/batch", handler.PostBatchTransaction(world, msgIndex, s.validator))
+	tx.Get("/:hash/status", handler.GetTxStatus(world))
 	tx.Post("/:group/:name", handler.PostTransaction(world, msgIndex, s.validator))
 
 	// Route: /cql
@@ -336,6 +336,119 @@ func (s *ServerTestSuite) TestRejectDuplicateTransactionHash() {
 	s.Require().Equal(fiber.StatusForbidden, res.StatusCode, s.readBody(res.Body))
 }
 
+func (s *ServerTestSuite) getNextNonce() uint64 {
+	res := s.fixture.Post("query/nonces/"+s.signerAddr, nil)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+	var reply handler.GetNextNonceResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&reply))
+	s.Require().Equal(s.signerAddr, reply.SignerAddress)
+	return reply.NextNonce
+}
+
+func (s *ServerTestSuite) TestNonceReplayProtection() {
+	s.setupWorld(
+		cardinal.WithNonceReplayProtection(), cardinal.WithMessageExpiration(1), cardinal.WithTxPoolSize(1))
+	s.fixture.DoTick()
+	s.Require().Equal(uint64(0), s.getNextNonce())
+
+	// Creating the persona uses the first nonce of the signer.
+	personaTag := "nonce_persona"
+	createPersonaTx, err := sign.NewSystemTransactionWithNonce(s.privateKey, s.world.Namespace(), 0,
+		msg.CreatePersona{PersonaTag: personaTag, SignerAddress: s.signerAddr})
+	s.Require().NoError(err)
+	res := s.fixture.Post(utils.GetTxURL("persona", "create-persona"), createPersonaTx)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+	s.fixture.DoTick()
+	s.Require().Equal(uint64(1), s.getNextNonce())
+	moveMessage, ok := s.world.GetMessageByFullName("game." + moveMsgName)
+	s.Require().True(ok)
+	url := utils.GetTxURL(moveMessage.Group(), moveMessage.Name())
+
+	// Transactions still expire when nonces are used, and an expired transaction does not use its nonce.
+	tx, err := sign.NewTransactionWithNonce(
+		s.privateKey, personaTag, s.world.Namespace(), 5, MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	time.Sleep(1500 * time.Millisecond)
+	res = s.fixture.Post(url, tx)
+	s.Require().Equal(fiber.StatusRequestTimeout, res.StatusCode, s.readBody(res.Body))
+	s.Require().Equal(uint64(1), s.getNextNonce())
+
+	// A transaction whose hash does not include its nonce is rejected.
+	tx, err = sign.NewTransaction(s.privateKey, personaTag, s.world.Namespace(), MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res = s.fixture.Post(url, tx)
+	s.Require().Equal(fiber.StatusUnauthorized, res.StatusCode, s.readBody(res.Body))
+
+	tx, err = sign.NewTransactionWithNonce(
+		s.privateKey, personaTag, s.world.Namespace(), 5, MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res = s.fixture.Post(url, tx)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+	s.Require().Equal(uint64(6), s.getNextNonce())
+
+	// The same transaction, or another one with a nonce that is not higher, is rejected.
+	res = s.fixture.Post(url, tx)
+	s.Require().Equal(fiber.StatusForbidden, res.StatusCode, s.readBody(res.Body))
+	tx, err = sign.NewTransactionWithNonce(
+		s.privateKey, personaTag, s.world.Namespace(), 4, MoveMsgInput{Direction: "down"})
+	s.Require().NoError(err)
+	res = s.fixture.Post(url, tx)
+	s.Require().Equal(fiber.StatusForbidden, res.StatusCode, s.readBody(res.Body))
+
+	// A transaction rejected by the full transaction pool does not use its nonce, so it can be submitted again.
+	tx, err = sign.NewTransactionWithNonce(
+		s.privateKey, personaTag, s.world.Namespace(), 6, MoveMsgInput{Direction: "down"})
+	s.Require().NoError(err)
+	res = s.fixture.Post(url, tx)
+	s.Require().Equal(fiber.StatusServiceUnavailable, res.StatusCode, s.readBody(res.Body))
+	s.Require().Equal(uint64(6), s.getNextNonce())
+	s.fixture.DoTick()
+	res = s.fixture.Post(url, tx)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+	s.Require().Equal(uint64(7), s.getNextNonce())
+}
+
+func (s *ServerTestSuite) TestBatchTransactions() {
+	s.setupWorld()
+	s.fixture.DoTick()
+
+	personaTag := s.CreateRandomPersona()
+	moveMessage, ok := s.world.GetMessageByFullName("game." + moveMsgName)
+	s.Require().True(ok)
+
+	newBatchTx := func(name string, payload any) handler.BatchTransaction {
+		tx, err := sign.NewTransaction(s.privateKey, personaTag, s.world.Namespace(), payload)
+		s.Require().NoError(err)
+		bz, err := json.Marshal(tx)
+		s.Require().NoError(err)
+		return handler.BatchTransaction{Group: moveMessage.Group(), Name: name, Tx: bz}
+	}
+	up := newBatchTx(moveMessage.Name(), MoveMsgInput{Direction: "up"})
+	right := newBatchTx(moveMessage.Name(), MoveMsgInput{Direction: "right"})
+	unknown := newBatchTx("unknown", MoveMsgInput{Direction: "up"})
+	res := s.fixture.Post("tx/batch", handler.PostBatchTransactionRequest{
+		Transactions: []handler.BatchTransaction{up, unknown, right, up},
+	})
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+	var result handler.PostBatchTransactionResponse
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &result))
+	s.Require().Len(result.Results, 4)
+	s.Require().Equal(fiber.StatusOK, result.Results[0].Status)
+	s.Require().NotEmpty(result.Results[0].TxHash)
+	s.Require().Equal(fiber.StatusNotFound, result.Results[1].Status)
+	s.Require().Equal(fiber.StatusOK, result.Results[2].Status)
+	// The same transaction can not be submitted twice in a batch.
+	s.Require().Equal(fiber.StatusForbidden, result.Results[3].Status)
+	s.Require().NotEmpty(result.Results[3].Error)
+
+	// Both valid transactions are handled in the same tick.
+	s.fixture.DoTick()
+	res = s.fixture.Post("query/game/location", QueryLocationRequest{Persona: personaTag})
+	var loc LocationComponent
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &loc))
+	s.Require().Equal(LocationComponent{1, 1}, loc)
+}
+
 // Creates a transaction with the given message, and runs it in a tick.
 func (s *ServerTestSuite) runTx(personaTag string, msg types.Message, payload any) {
 	tx, err := sign.NewTransaction(s.privateKey, personaTag, s.world.Namespace(), payload)
@@ -493,3 +606,64 @@ func (s *ServerTestSuite) TestCQL_NonExistentComponent() {
 	err = json.Unmarshal([]byte(s.readBody(res.Body)), &result)
 	s.Require().Error(err)
 }
+
+func (s *ServerTestSuite) TestCQLAtTick() {
+	s.setupWorld(cardinal.WithStateHistory(10))
+	s.fixture.DoTick()
+
+	wCtx := cardinal.NewWorldContext(s.world)
+	_, err := cardinal.CreateMany(wCtx, 10, LocationComponent{})
+	assert.NilError(s.T(), err)
+	s.fixture.DoTick()
+
+	_, err = cardinal.CreateMany(wCtx, 5, LocationComponent{})
+	assert.NilError(s.T(), err)
+	s.fixture.DoTick()
+
+	testCases := []struct {
+		path      string
+		wantCount int
+	}{
+		{path: "/cql", wantCount: 15},
+		{path: "/cql?atTick=2", wantCount: 15},
+		{path: "/cql?atTick=1", wantCount: 10},
+		{path: "/cql?atTick=0", wantCount: 0},
+	}
+	for _, tc := range testCases {
+		res := s.fixture.Post(tc.path, handler.CQLQueryRequest{CQL: "CONTAINS(location)"})
+		s.Require().Equal(fiber.StatusOK, res.StatusCode, tc.path)
+		var result handler.CQLQueryResponse
+		err = json.Unmarshal([]byte(s.readBody(res.Body)), &result)
+		s.Require().NoError(err)
+		s.Require().Len(result.Results, tc.wantCount, tc.path)
+	}
+
+	// Ticks that have not happened yet can not be queried
+	res := s.fixture.Post("/cql?atTick=100", handler.CQLQueryRequest{CQL: "CONTAINS(location)"})
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode)
+	res = s.fixture.Post("/cql?atTick=foo", handler.CQLQueryRequest{CQL: "CONTAINS(location)"})
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode)
+}
+
+func (s *ServerTestSuite) TestQueryAtTick() {
+	s.setupWorld(cardinal.WithStateHistory(10))
+	s.fixture.DoTick()
+	personaTag := s.CreateRandomPersona()
+	moveMessage, ok := s.world.GetMessageByFullName("game." + moveMsgName)
+	s.Require().True(ok)
+	s.runTx(personaTag, moveMessage, MoveMsgInput{Direction: "up"})
+	moveTick := s.world.CurrentTick() - 1
+	s.runTx(personaTag, moveMessage, MoveMsgInput{Direction: "up"})
+
+	res := s.fixture.Post(utils.GetQueryURL("game", "location"), QueryLocationRequest{personaTag})
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+	var loc LocationComponent
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &loc))
+	s.Require().Equal(LocationComponent{0, 2}, loc)
+
+	res = s.fixture.Post(fmt.Sprintf("%s?atTick=%d", utils.GetQueryURL("game", "location"), moveTick),
+		QueryLocationRequest{personaTag})
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &loc))
+	s.Require().Equal(LocationComponent{0, 1}, loc)
+}
@@ -0,0 +1,82 @@
+package server_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+	"pkg.world.dev/world-engine/sign"
+)
+
+func (s *ServerTestSuite) getTxStatus(path string) handler.GetTxStatusResponse {
+	res := s.fixture.Get(path)
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	var status handler.GetTxStatusResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&status))
+	return status
+}
+
+func (s *ServerTestSuite) TestTxStatus() {
+	s.setupWorld()
+	world := s.world
+	type fooIn struct{ Fail bool }
+	type fooOut struct{ Y int }
+	type barIn struct{}
+	err := cardinal.RegisterMessage[fooIn, fooOut](world, "foo")
+	s.Require().NoError(err)
+	// No system handles bar messages.
+	err = cardinal.RegisterMessage[barIn, fooOut](world, "bar")
+	s.Require().NoError(err)
+	err = cardinal.RegisterSystems(world, func(ctx cardinal.WorldContext) error {
+		return cardinal.EachMessage[fooIn, fooOut](ctx, func(tx cardinal.TxData[fooIn]) (fooOut, error) {
+			if tx.Msg.Fail {
+				return fooOut{}, errors.New("foo failed")
+			}
+			return fooOut{Y: 4}, nil
+		})
+	})
+	s.Require().NoError(err)
+	s.fixture.DoTick()
+
+	fooMsg, ok := world.GetMessageByFullName("game.foo")
+	s.Require().True(ok)
+	_, okHash := world.AddTransaction(fooMsg.ID(), fooIn{}, &sign.Transaction{PersonaTag: "alpha"})
+	_, failHash := world.AddTransaction(fooMsg.ID(), fooIn{Fail: true}, &sign.Transaction{PersonaTag: "beta"})
+	barMsg, ok := world.GetMessageByFullName("game.bar")
+	s.Require().True(ok)
+	_, barHash := world.AddTransaction(barMsg.ID(), barIn{}, &sign.Transaction{PersonaTag: "gamma"})
+
+	status := s.getTxStatus("tx/" + string(okHash) + "/status")
+	s.Require().Equal("pending", status.Status)
+	s.Require().Nil(status.Receipt)
+
+	// The request waits for the tick that handles the transaction.
+	waited := make(chan handler.GetTxStatusResponse)
+	go func() {
+		waited <- s.getTxStatus("tx/" + string(okHash) + "/status?wait=5s")
+	}()
+	s.fixture.DoTick()
+	status = <-waited
+	s.Require().Equal("processed", status.Status)
+	s.Require().NotNil(status.Receipt)
+	s.Require().Equal(uint64(1), status.Receipt.Tick)
+	s.Require().Equal(map[string]any{"Y": float64(4)}, status.Receipt.Result)
+
+	status = s.getTxStatus("tx/" + string(failHash) + "/status?wait=5s")
+	s.Require().Equal("failed", status.Status)
+	s.Require().Equal([]string{"foo failed"}, status.Receipt.Errors)
+
+	// A transaction that no system set a result or an error for was processed as well.
+	status = s.getTxStatus("tx/" + string(barHash) + "/status")
+	s.Require().Equal("processed", status.Status)
+	s.Require().NotNil(status.Receipt)
+	s.Require().Nil(status.Receipt.Result)
+
+	status = s.getTxStatus("tx/unknown/status")
+	s.Require().Equal("unknown", status.Status)
+
+	res := s.fixture.Get("tx/" + string(okHash) + "/status?wait=soon")
+	s.Require().Equal(http.StatusBadRequest, res.StatusCode)
+}
@@ -0,0 +1,18 @@
+package types
+
+import (
+	"pkg.world.dev/world-engine/cardinal/receipt"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// TopicReceipt is a transaction receipt along with the topics it was published to.
+type TopicReceipt struct {
+	Receipt receipt.Receipt
+	Topics  []types.Topic
+}
+
+// TopicEvent is an event emitted by a system along with the topics it was published to.
+type TopicEvent struct {
+	Data   []byte
+	Topics []types.Topic
+}
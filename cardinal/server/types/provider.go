@@ -1,26 +1,43 @@
 package types
 
 import (
+	"context"
+	"encoding/json"
+
 	"pkg.world.dev/world-engine/cardinal/gamestate"
 	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/server/validator"
+	"pkg.world.dev/world-engine/cardinal/txpool"
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/sign"
 )
 
 type ProviderWorld interface {
 	validator.SignerAddressProvider
+	validator.NonceProvider
 	UseNonce(signerAddress string, nonce uint64) error
+	GetNextNonce(signerAddress string) (uint64, error)
 	GetSignerForPersonaTag(personaTag string, tick uint64) (addr string, err error)
-	AddTransaction(id types.MessageID, v any, sig *sign.Transaction) (uint64, types.TxHash)
+	SubmitTransaction(id types.MessageID, v any, sig *sign.Transaction) (uint64, types.TxHash, error)
+	SubmitTransactions(txs []txpool.TxData) (tick uint64, txHashes []types.TxHash, err error)
 	Namespace() string
 	GetComponentByName(name string) (types.ComponentMetadata, error)
+	GetResourceByName(name string) (types.ComponentMetadata, error)
+	GetResourceInRawJSON(name string) (json.RawMessage, error)
+	GetResourceInRawJSONAtTick(name string, tick uint64) (json.RawMessage, error)
+	GetComponentProof(componentName string, id types.EntityID) (*gamestate.ComponentProof, error)
+	GetComponentProofAtTick(componentName string, id types.EntityID, tick uint64) (*gamestate.ComponentProof, error)
 	StoreReader() gamestate.Reader
 	HandleQuery(group string, name string, bz []byte) ([]byte, error)
+	HandleQueryAtTick(group string, name string, bz []byte, tick uint64) ([]byte, error)
 	CurrentTick() uint64
-	ReceiptHistorySize() uint64
 	GetTransactionReceiptsForTick(tick uint64) ([]receipt.Receipt, error)
+	GetTransactionReceiptsForTicks(startTick, endTick uint64) (uint64, []receipt.TickReceipts, error)
+	GetTransactionReceipt(txHash types.TxHash) (receipt.Receipt, uint64, error)
+	GetTransactionStatus(txHash types.TxHash) (types.TxStatus, *receipt.Receipt, uint64, error)
+	WaitForTransaction(ctx context.Context, txHash types.TxHash) (types.TxStatus, *receipt.Receipt, uint64, error)
 	EvaluateCQL(cql string) ([]types.EntityStateElement, error)
+	EvaluateCQLAtTick(cql string, tick uint64) ([]types.EntityStateElement, error)
 	GetDebugState() ([]types.DebugStateElement, error)
 	BuildQueryFields() []types.FieldDetail
 }
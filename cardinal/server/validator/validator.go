@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/common" // for hash
 	"github.com/rotisserie/eris"
 
+	"pkg.world.dev/world-engine/cardinal/storage"
 	"pkg.world.dev/world-engine/sign"
 )
 
@@ -20,6 +21,15 @@ type SignerAddressProvider interface {
 	GetSignerForPersonaTag(personaTag string, tick uint64) (addr string, err error)
 }
 
+// NonceProvider durably stores the nonces of the signers, see SignatureValidator.EnableNonces.
+type NonceProvider interface {
+	// UseIncreasingNonce marks the nonce as used, or returns storage.ErrNonceTooLow if the signer already used a
+	// nonce at least as high.
+	UseIncreasingNonce(signerAddress string, nonce uint64) error
+	// ReleaseIncreasingNonces gives back the nonces first to last of the signer, if they are the last nonces it used.
+	ReleaseIncreasingNonces(signerAddress string, first, last uint64) error
+}
+
 const cacheRetentionExtraSeconds = 10 // this is how many seconds past normal expiration a hash is left in the cache.
 // we want to ensure it's long enough that any message that's not expired but
 // still has its hash in the cache for replay protection. Setting it too long
@@ -42,6 +52,8 @@ var (
 	ErrCacheWriteFailed = eris.New("cache store failed")
 	ErrDuplicateMessage = eris.New("duplicate message")
 	ErrInvalidSignature = eris.New("invalid signature")
+	ErrNonceTooLow      = eris.New("nonce too low")
+	ErrNonceStoreFailed = eris.New("nonce store failed")
 )
 
 type SignatureValidator struct {
@@ -51,6 +63,9 @@ type SignatureValidator struct {
 	namespace                string
 	cache                    *freecache.Cache
 	signerAddressProvider    SignerAddressProvider
+	// nonceProvider is only set when nonces are used for replay protection instead of message expiration and the
+	// hash cache.
+	nonceProvider NonceProvider
 }
 
 func NewSignatureValidator(disabled bool, msgExpirationSec uint, hashCacheSizeKB uint, namespace string,
@@ -71,11 +86,21 @@ func NewSignatureValidator(disabled bool, msgExpirationSec uint, hashCacheSizeKB
 	return &validator
 }
 
+// EnableNonces makes the validator use per signer nonces for replay protection, instead of the hash cache: the nonce of
+// each transaction must be higher than the nonce of the previous transaction of the same signer, and the transaction
+// must be signed with sign.HashVersion1 or later so its nonce is part of its hash. The nonces are stored by the given
+// provider, so replay protection is kept across restarts. Transactions still expire in this mode, which bounds how
+// long a signed transaction that was never submitted can be used.
+func (validator *SignatureValidator) EnableNonces(provider NonceProvider) {
+	validator.nonceProvider = provider
+}
+
 // ValidateTransactionTTL checks that the timestamp on the message is valid, the message has not expired,
 // and that the message is not previously handled as indicated by it being in the hash cache.
 // returns an error (ErrMessageExpired, ErrBadTimestamp, ErrDuplicateMessage, or ErrCacheReadFailed) if
 // there was a problem, and nil if everything was ok
-// if signature validation is disabled, no checks are done and nil is always returned
+// if signature validation is disabled, no checks are done and nil is always returned. if nonces are enabled, the hash
+// cache is not checked, since the nonce of the transaction prevents replays.
 func (validator *SignatureValidator) ValidateTransactionTTL(tx *sign.Transaction) error {
 	if !validator.IsDisabled {
 		now := time.Now()
@@ -93,6 +118,9 @@ func (validator *SignatureValidator) ValidateTransactionTTL(tx *sign.Transaction
 					"message timestamp more than %d seconds in the future. Got timestamp: %d, current timestamp: %d ",
 					ttlMaxFutureSeconds, tx.Timestamp, sign.TimestampAt(now)))
 		}
+		if validator.nonceProvider != nil {
+			return nil
+		}
 		// check for duplicate message via hash cache
 		if found, err := validator.isHashInCache(tx.Hash); err != nil {
 			return eris.Wrap(ErrCacheReadFailed,
@@ -110,7 +138,28 @@ func (validator *SignatureValidator) ValidateTransactionTTL(tx *sign.Transaction
 // has the correct namespace, and has not been altered. If all checks pass, it is added to the hash cache as a
 // known message, and nil is returned. Other possible returns are ErrNoPersonaTag, ErrInvalidSignature, and
 // ErrCacheWriteFailed. If signature validation is disabled, we only check for the presence of a persona tag.
+// If nonces are enabled, the nonce of the transaction is used instead of adding it to the hash cache, which can
+// return ErrNonceTooLow and ErrNonceStoreFailed.
 func (validator *SignatureValidator) ValidateTransactionSignature(tx *sign.Transaction, signerAddress string,
+) error {
+	return validator.validateTransactionSignature(tx, signerAddress, false)
+}
+
+// ValidateRestoredTransaction checks a transaction that was accepted before a restart and put back in the transaction
+// pool from its write-ahead log. Its TTL and signature are checked again like the ones of a new transaction, and its
+// hash is added to the hash cache, which does not survive restarts, so it can not be replayed. If nonces are enabled,
+// the nonce of the transaction was already used when it was accepted, so a nonce that is too low is not an error; the
+// nonce is only used again in case the nonce store lost it. Like any other transaction, a restored transaction that
+// expired while the shard was down is rejected.
+func (validator *SignatureValidator) ValidateRestoredTransaction(tx *sign.Transaction, signerAddress string) error {
+	if err := validator.ValidateTransactionTTL(tx); err != nil {
+		return err
+	}
+	return validator.validateTransactionSignature(tx, signerAddress, true)
+}
+
+func (validator *SignatureValidator) validateTransactionSignature(tx *sign.Transaction, signerAddress string,
+	restored bool,
 ) error {
 	// this is the only validation we do when signature validation is disabled
 	if tx.PersonaTag == "" {
@@ -137,6 +186,22 @@ func (validator *SignatureValidator) ValidateTransactionSignature(tx *sign.Trans
 			fmt.Sprintf("signature validation failed for message %s: %v", tx.Hash.String(), err))
 	}
 
+	if validator.nonceProvider != nil && tx.Version < sign.HashVersion1 {
+		return eris.Wrap(ErrInvalidSignature,
+			fmt.Sprintf("message %s has hash version %d, nonces require hash version %d or later",
+				tx.Hash.String(), tx.Version, sign.HashVersion1))
+	}
+
+	// the message was valid, so use its nonce. like the hash cache below, this is only done for valid signatures so
+	// nobody else can use up the nonces of a signer.
+	if validator.nonceProvider != nil {
+		err = validator.useNonce(tx, signerAddress)
+		if restored && eris.Is(err, ErrNonceTooLow) {
+			return nil
+		}
+		return err
+	}
+
 	// the message was valid, so add its hash to the cache
 	// we don't do this until we have verified the signature to prevent an attack where someone sends
 	// large numbers of hashes with unsigned/invalid messages and thus blocks legit messages from
@@ -152,6 +217,57 @@ func (validator *SignatureValidator) ValidateTransactionSignature(tx *sign.Trans
 	return nil
 }
 
+// ReleaseNonces gives back the nonces of transactions that passed validation but were not accepted, for instance
+// because the transaction pool was full, so the signer can submit them again. signerAddresses holds the signer of each
+// transaction, or "" to look it up from its persona tag. The nonces of a signer are only released if they are the
+// last nonces the signer used. Nothing is done if signature validation is disabled or nonces are not enabled.
+func (validator *SignatureValidator) ReleaseNonces(txs []*sign.Transaction, signerAddresses []string) error {
+	if validator.IsDisabled || validator.nonceProvider == nil {
+		return nil
+	}
+	type nonceRange struct {
+		first, last uint64
+	}
+	ranges := map[string]*nonceRange{}
+	var signers []string
+	for i, tx := range txs {
+		signerAddress := signerAddresses[i]
+		if signerAddress == "" {
+			var err error
+			signerAddress, err = validator.signerAddressProvider.GetSignerForPersonaTag(tx.PersonaTag, 0)
+			if err != nil {
+				return eris.Wrapf(err, "could not get signer for persona %s", tx.PersonaTag)
+			}
+		}
+		r, ok := ranges[signerAddress]
+		if !ok {
+			ranges[signerAddress] = &nonceRange{first: tx.Nonce, last: tx.Nonce}
+			signers = append(signers, signerAddress)
+			continue
+		}
+		r.first = min(r.first, tx.Nonce)
+		r.last = max(r.last, tx.Nonce)
+	}
+	for _, signerAddress := range signers {
+		r := ranges[signerAddress]
+		if err := validator.nonceProvider.ReleaseIncreasingNonces(signerAddress, r.first, r.last); err != nil {
+			return eris.Wrapf(err, "could not release the nonces of signer %s", signerAddress)
+		}
+	}
+	return nil
+}
+
+func (validator *SignatureValidator) useNonce(tx *sign.Transaction, signerAddress string) error {
+	err := validator.nonceProvider.UseIncreasingNonce(signerAddress, tx.Nonce)
+	if eris.Is(err, storage.ErrNonceTooLow) {
+		return eris.Wrap(ErrNonceTooLow, fmt.Sprintf("message %s rejected: %v", tx.Hash.String(), err))
+	} else if err != nil {
+		return eris.Wrap(ErrNonceStoreFailed,
+			fmt.Sprintf("unexpected nonce store error %v. message %s ignored", err, tx.Hash.String()))
+	}
+	return nil
+}
+
 func (validator *SignatureValidator) isHashInCache(hash common.Hash) (bool, error) {
 	_, err := validator.cache.Get(hash.Bytes())
 	if err == nil {
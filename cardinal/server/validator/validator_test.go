@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"pkg.world.dev/world-engine/cardinal/persona"
+	"pkg.world.dev/world-engine/cardinal/storage"
 	"pkg.world.dev/world-engine/sign"
 )
 
@@ -57,6 +58,26 @@ func (pf *ProviderFixture) GetSignerForPersonaTag(personaTag string, _ uint64) (
 	return pf.vts.signerAddr, nil
 }
 
+// NonceFixture is a NonceProvider that keeps the next nonce of each signer in memory.
+type NonceFixture struct {
+	next map[string]uint64
+}
+
+func (nf *NonceFixture) UseIncreasingNonce(signerAddress string, nonce uint64) error {
+	if nonce < nf.next[signerAddress] {
+		return eris.Wrap(storage.ErrNonceTooLow, "")
+	}
+	nf.next[signerAddress] = nonce + 1
+	return nil
+}
+
+func (nf *NonceFixture) ReleaseIncreasingNonces(signerAddress string, first, last uint64) error {
+	if nf.next[signerAddress] == last+1 {
+		nf.next[signerAddress] = first
+	}
+	return nil
+}
+
 func TestServerValidator(t *testing.T) {
 	suite.Run(t, new(ValidatorTestSuite))
 }
@@ -379,3 +400,117 @@ func (s *ValidatorTestSuite) TestRejectsDuplicateTx() {
 	s.Require().True(eris.Is(err, ErrDuplicateMessage))
 	s.Require().Contains(err.Error(), fmt.Sprintf("message %s already handled", tx.Hash))
 }
+
+// TestRejectsReusedNonceTx tests that a validator that uses nonces for replay protection only accepts increasing
+// nonces from each signer.
+func (s *ValidatorTestSuite) TestRejectsReusedNonceTx() {
+	validator := s.createValidatorWithTTL(10)
+	validator.EnableNonces(&NonceFixture{next: map[string]uint64{}})
+
+	tx, err := sign.NewTransactionWithNonce(s.privateKey, goodPersona, goodNamespace, 5, goodRequestBody)
+	s.Require().NoError(err)
+	s.Require().NoError(validator.ValidateTransactionTTL(tx))
+	s.Require().NoError(validator.ValidateTransactionSignature(tx, lookupSignerAddress))
+
+	// the same transaction, or any other transaction with a nonce that is not higher, is rejected
+	err = validator.ValidateTransactionSignature(tx, lookupSignerAddress)
+	s.Require().True(eris.Is(err, ErrNonceTooLow))
+	lowTx, err := sign.NewTransactionWithNonce(s.privateKey, goodPersona, goodNamespace, 4, goodRequestBody)
+	s.Require().NoError(err)
+	err = validator.ValidateTransactionSignature(lowTx, lookupSignerAddress)
+	s.Require().True(eris.Is(err, ErrNonceTooLow))
+
+	// an invalid signature does not use up the nonce
+	badTx, err := sign.NewTransactionWithNonce(s.privateKey, goodPersona, badNamespace, 6, goodRequestBody)
+	s.Require().NoError(err)
+	err = validator.ValidateTransactionSignature(badTx, lookupSignerAddress)
+	s.Require().True(eris.Is(err, ErrInvalidSignature))
+
+	nextTx, err := sign.NewTransactionWithNonce(s.privateKey, goodPersona, goodNamespace, 6, goodRequestBody)
+	s.Require().NoError(err)
+	s.Require().NoError(validator.ValidateTransactionSignature(nextTx, lookupSignerAddress))
+
+	// a transaction whose hash does not include its nonce is rejected
+	legacyTx, err := s.simulateReceivedTransaction(goodPersona, goodNamespace, goodRequestBody)
+	s.Require().NoError(err)
+	err = validator.ValidateTransactionSignature(legacyTx, lookupSignerAddress)
+	s.Require().True(eris.Is(err, ErrInvalidSignature))
+}
+
+// TestReleasesNonces tests that the nonces of transactions that were not accepted can be used again, unless the signer
+// used a higher nonce since.
+func (s *ValidatorTestSuite) TestReleasesNonces() {
+	nonces := &NonceFixture{next: map[string]uint64{}}
+	validator := s.createValidatorWithTTL(10)
+	validator.EnableNonces(nonces)
+
+	var txs []*sign.Transaction
+	for _, nonce := range []uint64{1, 3} {
+		tx, err := sign.NewTransactionWithNonce(s.privateKey, goodPersona, goodNamespace, nonce, goodRequestBody)
+		s.Require().NoError(err)
+		s.Require().NoError(validator.ValidateTransactionSignature(tx, lookupSignerAddress))
+		txs = append(txs, tx)
+	}
+	s.Require().Equal(uint64(4), nonces.next[s.signerAddr])
+	// releasing both transactions, e.g. when the transaction pool rejected them, makes their nonces usable again
+	s.Require().NoError(validator.ReleaseNonces(txs[:2], []string{lookupSignerAddress, lookupSignerAddress}))
+	s.Require().Equal(uint64(1), nonces.next[s.signerAddr])
+	s.Require().NoError(validator.ValidateTransactionSignature(txs[0], lookupSignerAddress))
+
+	// the nonce is not released once a higher nonce was used
+	tx, err := sign.NewTransactionWithNonce(s.privateKey, goodPersona, goodNamespace, 5, goodRequestBody)
+	s.Require().NoError(err)
+	s.Require().NoError(validator.ValidateTransactionSignature(tx, lookupSignerAddress))
+	s.Require().NoError(validator.ReleaseNonces(txs[:1], []string{lookupSignerAddress}))
+	s.Require().Equal(uint64(6), nonces.next[s.signerAddr])
+}
+
+// TestRejectsExpiredTxWithNonces tests that transactions still expire when nonces are used for replay protection,
+// but that their hash is not checked against the hash cache.
+func (s *ValidatorTestSuite) TestRejectsExpiredTxWithNonces() {
+	validator := s.createValidatorWithTTL(10)
+	validator.EnableNonces(&NonceFixture{next: map[string]uint64{}})
+	tx, err := sign.NewTransactionWithNonce(s.privateKey, goodPersona, goodNamespace, 1, goodRequestBody)
+	s.Require().NoError(err)
+	s.Require().NoError(validator.ValidateTransactionTTL(tx))
+	s.Require().NoError(validator.ValidateTransactionSignature(tx, lookupSignerAddress))
+	s.Require().NoError(validator.ValidateTransactionTTL(tx))
+
+	tx.Timestamp = veryOldTimestamp
+	err = validator.ValidateTransactionTTL(tx)
+	s.Require().True(eris.Is(err, ErrMessageExpired))
+}
+
+// TestValidatesRestoredTx tests that the transactions restored after a restart are validated again, and that their
+// nonce, which was used when they were accepted, does not get them rejected.
+func (s *ValidatorTestSuite) TestValidatesRestoredTx() {
+	validator := s.createValidatorWithTTL(10)
+	tx, err := s.simulateReceivedTransaction(goodPersona, goodNamespace, goodRequestBody)
+	s.Require().NoError(err)
+	s.Require().NoError(validator.ValidateRestoredTransaction(tx, lookupSignerAddress))
+	// the restored transaction can not be replayed
+	err = validator.ValidateTransactionTTL(tx)
+	s.Require().True(eris.Is(err, ErrDuplicateMessage))
+
+	expiredTx, err := s.simulateReceivedTransaction(goodPersona, goodNamespace, goodRequestBody)
+	s.Require().NoError(err)
+	expiredTx.Timestamp = veryOldTimestamp
+	err = validator.ValidateRestoredTransaction(expiredTx, lookupSignerAddress)
+	s.Require().True(eris.Is(err, ErrMessageExpired))
+	badTx, err := s.simulateReceivedTransaction(goodPersona, badNamespace, goodRequestBody)
+	s.Require().NoError(err)
+	err = validator.ValidateRestoredTransaction(badTx, lookupSignerAddress)
+	s.Require().True(eris.Is(err, ErrInvalidSignature))
+
+	nonces := &NonceFixture{next: map[string]uint64{}}
+	validator = s.createValidatorWithTTL(10)
+	validator.EnableNonces(nonces)
+	nonceTx, err := sign.NewTransactionWithNonce(s.privateKey, goodPersona, goodNamespace, 5, goodRequestBody)
+	s.Require().NoError(err)
+	s.Require().NoError(validator.ValidateTransactionSignature(nonceTx, lookupSignerAddress))
+	s.Require().NoError(validator.ValidateRestoredTransaction(nonceTx, lookupSignerAddress))
+	// the nonce of a restored transaction is used if the nonce store lost it
+	delete(nonces.next, s.signerAddr)
+	s.Require().NoError(validator.ValidateRestoredTransaction(nonceTx, lookupSignerAddress))
+	s.Require().Equal(uint64(6), nonces.next[s.signerAddr])
+}
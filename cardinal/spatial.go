@@ -0,0 +1,139 @@
+package cardinal
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// RegisterSpatialIndex adds a spatial index on the 2D position stored in the component T, which is returned by
+// position. The index buckets entities in a grid of cellSize by cellSize cells, so searches using InRect, WithinRadius
+// or Nearest only need to look at the entities near the searched area. A good cell size is close to the radius that is
+// usually searched. Like other indexes, it is kept up to date when the component changes and rebuilt when the game
+// starts.
+//
+// Usage:
+//
+// cardinal.RegisterSpatialIndex[Location](world, 10, func(l Location) (float64, float64) { return l.X, l.Y })
+func RegisterSpatialIndex[T types.Component](w *World, cellSize float64, position func(T) (x, y float64)) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"world state is %s, expected %s to register spatial index",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+
+	var t T
+	c, err := w.GetComponentByName(t.Name())
+	if err != nil {
+		return eris.Wrap(err, "component must be registered before its indexes")
+	}
+
+	indexer, ok := w.entityStore.(gamestate.Indexer)
+	if !ok {
+		return eris.New("indexes are not supported by the configured game state manager")
+	}
+
+	return indexer.RegisterSpatialIndex(gamestate.SpatialIndexDefinition{
+		Component: c,
+		CellSize:  cellSize,
+		Position: func(value any) (gamestate.Point, error) {
+			var x, y float64
+			switch comp := value.(type) {
+			case T:
+				x, y = position(comp)
+			case *T:
+				x, y = position(*comp)
+			default:
+				return gamestate.Point{}, eris.Errorf("unable to index %T as component %s", value, t.Name())
+			}
+			return gamestate.Point{X: x, Y: y}, nil
+		},
+	})
+}
+
+// SpatialFilter restricts a search to the entities whose position, stored in a component with a spatial index, is in
+// an area. It is created with InRect, WithinRadius or Nearest, and used with EntitySearch.WhereSpatial.
+type SpatialFilter struct {
+	component string
+	// lookup returns the sorted IDs of the matching entities. It is nil for Nearest filters.
+	lookup func(reader gamestate.IndexReader) ([]types.EntityID, error)
+	// nearest is only set for Nearest filters.
+	nearest *nearestFilter
+}
+
+type nearestFilter struct {
+	center gamestate.Point
+	k      int
+}
+
+// InRect matches the entities whose position in component T is in the rectangle from (minX, minY) to (maxX, maxY),
+// edges included.
+func InRect[T types.Component](minX, minY, maxX, maxY float64) SpatialFilter {
+	var t T
+	rect := gamestate.Rect{Min: gamestate.Point{X: minX, Y: minY}, Max: gamestate.Point{X: maxX, Y: maxY}}
+	return SpatialFilter{
+		component: t.Name(),
+		lookup: func(reader gamestate.IndexReader) ([]types.EntityID, error) {
+			return reader.LookupRect(t.Name(), rect)
+		},
+		nearest: nil,
+	}
+}
+
+// WithinRadius matches the entities whose position in component T is at most radius away from (x, y).
+func WithinRadius[T types.Component](x, y, radius float64) SpatialFilter {
+	var t T
+	center := gamestate.Point{X: x, Y: y}
+	return SpatialFilter{
+		component: t.Name(),
+		lookup: func(reader gamestate.IndexReader) ([]types.EntityID, error) {
+			return reader.LookupRadius(t.Name(), center, radius)
+		},
+		nearest: nil,
+	}
+}
+
+// Nearest matches the k entities whose position in component T is nearest to (x, y), among the entities that match
+// the rest of the search. Each visits them from nearest to farthest, and entities at the same distance in the order of
+// their IDs. A search can have at most one Nearest filter.
+func Nearest[T types.Component](x, y float64, k int) SpatialFilter {
+	var t T
+	return SpatialFilter{
+		component: t.Name(),
+		lookup:    nil,
+		nearest:   &nearestFilter{center: gamestate.Point{X: x, Y: y}, k: k},
+	}
+}
+
+func (f SpatialFilter) indexReader(wCtx WorldContext) (gamestate.IndexReader, error) {
+	if err := wCtx.componentAccess().checkRead(f.component); err != nil {
+		return nil, err
+	}
+	reader, ok := wCtx.storeReader().(gamestate.IndexReader)
+	if !ok {
+		return nil, eris.Wrap(gamestate.ErrIndexNotAvailable, "")
+	}
+	return reader, nil
+}
+
+// lookupNearest returns the IDs of the k nearest entities for which accept returns true, from nearest to farthest.
+func (f SpatialFilter) lookupNearest(wCtx WorldContext, accept func(types.EntityID) bool) ([]types.EntityID, error) {
+	reader, err := f.indexReader(wCtx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.LookupNearest(f.component, f.nearest.center, f.nearest.k, accept)
+}
+
+// lookupIDs returns the sorted IDs of the entities that match an InRect or WithinRadius filter.
+func (f SpatialFilter) lookupIDs(wCtx WorldContext) ([]types.EntityID, error) {
+	reader, err := f.indexReader(wCtx)
+	if err != nil {
+		return nil, err
+	}
+	return f.lookup(reader)
+}
@@ -0,0 +1,76 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+type Position struct {
+	X, Y float64
+}
+
+func (Position) Name() string { return "position" }
+
+func TestSpatialSearch(t *testing.T) {
+	tf := cardinal.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Position](world))
+	assert.NilError(t, cardinal.RegisterComponent[AlphaTest](world))
+	assert.NilError(t, cardinal.RegisterSpatialIndex[Position](world, 10,
+		func(p Position) (float64, float64) { return p.X, p.Y }))
+	tf.StartWorld()
+
+	wCtx := cardinal.NewWorldContext(world)
+	positions := []Position{{0, 0}, {3, 4}, {-6, 8}, {20, 0}, {15, 15}, {1000, -1000}}
+	ids := make([]types.EntityID, 0, len(positions))
+	for _, p := range positions {
+		id, err := cardinal.Create(wCtx, p)
+		assert.NilError(t, err)
+		ids = append(ids, id)
+	}
+	alphaID, err := cardinal.Create(wCtx, Position{X: 1, Y: 1}, AlphaTest{})
+	assert.NilError(t, err)
+
+	search := func() cardinal.EntitySearch {
+		return cardinal.NewSearch().Entity(filter.Contains(filter.Component[Position]()))
+	}
+	collect := func(search cardinal.EntitySearch) []types.EntityID {
+		got := make([]types.EntityID, 0)
+		assert.NilError(t, search.Each(wCtx, func(id types.EntityID) bool {
+			got = append(got, id)
+			return true
+		}))
+		return got
+	}
+
+	assert.DeepEqual(t, []types.EntityID{ids[0], ids[1], alphaID},
+		collect(search().WhereSpatial(cardinal.InRect[Position](0, 0, 10, 10))))
+	assert.DeepEqual(t, []types.EntityID{ids[0], ids[1], ids[2], alphaID},
+		collect(search().WhereSpatial(cardinal.WithinRadius[Position](0, 0, 10))))
+
+	// Nearest visits the entities from nearest to farthest, among the entities that match the rest of the search.
+	assert.DeepEqual(t, []types.EntityID{ids[3], ids[4], ids[1]},
+		collect(search().WhereSpatial(cardinal.Nearest[Position](20, 5, 3))))
+	exact := cardinal.NewSearch().Entity(filter.Exact(filter.Component[Position]()))
+	assert.DeepEqual(t, []types.EntityID{ids[0], ids[1]},
+		collect(exact.WhereSpatial(cardinal.Nearest[Position](0, 0, 2))))
+	assert.DeepEqual(t, []types.EntityID{ids[5], ids[0]},
+		collect(search().
+			WhereSpatial(cardinal.Nearest[Position](900, -900, 2)).
+			Where(func(_ cardinal.WorldContext, id types.EntityID) (bool, error) { return id != ids[3], nil })))
+	assert.Equal(t, len(ids)+1, len(collect(search().WhereSpatial(cardinal.Nearest[Position](0, 0, 100)))))
+
+	tf.DoTick()
+
+	// Moved and removed entities are found at their new position.
+	assert.NilError(t, cardinal.SetComponent[Position](wCtx, ids[5], &Position{X: 2, Y: 2}))
+	assert.NilError(t, cardinal.Remove(wCtx, alphaID))
+	assert.DeepEqual(t, []types.EntityID{ids[0], ids[1], ids[5]},
+		collect(search().WhereSpatial(cardinal.InRect[Position](0, 0, 10, 10))))
+	assert.DeepEqual(t, []types.EntityID{ids[5], ids[1]},
+		collect(search().WhereSpatial(cardinal.Nearest[Position](2, 3, 2))))
+}
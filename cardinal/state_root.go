@@ -0,0 +1,55 @@
+package cardinal
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// StateRoot returns the Merkle root over the game state at the end of the given tick. The world must be created with
+// the WithStateRoots option.
+func (w *World) StateRoot(tick uint64) ([]byte, error) {
+	committer, ok := w.entityStore.(gamestate.StateCommitter)
+	if !ok {
+		return nil, eris.New("state roots are not supported by the configured game state manager")
+	}
+	return committer.StateRoot(tick)
+}
+
+// GetComponentProof returns a Merkle proof of the value of the named component of an entity at the end of the last
+// finalized tick. The proof can be checked against the state root of that tick with ComponentProof.Verify, or with
+// the same algorithm on another chain (see package merkle).
+func (w *World) GetComponentProof(componentName string, id types.EntityID) (*gamestate.ComponentProof, error) {
+	committer, comp, err := w.componentProver(componentName)
+	if err != nil {
+		return nil, err
+	}
+	return committer.ProveComponent(comp, id)
+}
+
+// GetComponentProofAtTick returns a Merkle proof of the value of the named component of an entity at the end of the
+// given tick. Ticks before the last finalized one are only available when they are retained by WithStateHistory.
+func (w *World) GetComponentProofAtTick(componentName string, id types.EntityID, tick uint64) (
+	*gamestate.ComponentProof, error,
+) {
+	committer, comp, err := w.componentProver(componentName)
+	if err != nil {
+		return nil, err
+	}
+	return committer.ProveComponentAtTick(comp, id, tick)
+}
+
+func (w *World) componentProver(componentName string) (
+	gamestate.StateCommitter, types.ComponentMetadata, error,
+) {
+	committer, ok := w.entityStore.(gamestate.StateCommitter)
+	if !ok {
+		return nil, nil, eris.New("state roots are not supported by the configured game state manager")
+	}
+	comp, err := w.GetComponentByName(componentName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return committer, comp, nil
+}
@@ -0,0 +1,171 @@
+package cardinal_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/merkle"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+func TestStateRoot(t *testing.T) {
+	tf := cardinal.NewTestFixture(t, nil, cardinal.WithStateRoots(10))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterResource[Season](world))
+	var id types.EntityID
+	created := false
+	value, season := 10, 1
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx cardinal.WorldContext) error {
+		if err := cardinal.SetResource(wCtx, Season{Number: season}); err != nil {
+			return err
+		}
+		if !created {
+			var err error
+			id, err = cardinal.Create(wCtx, Health{Value: value})
+			created = true
+			return err
+		}
+		return cardinal.SetComponent(wCtx, id, &Health{Value: value})
+	}))
+
+	// The state tree has a leaf for every key of the game state, not only for the component values.
+	expectedRoot := func() []byte {
+		tree := merkle.NewTree()
+		for _, key := range tf.Redis.Keys() {
+			if !strings.HasPrefix(key, "ECB:") || key == "ECB:LAST-FINALIZED-TICK" ||
+				strings.HasPrefix(key, "ECB:COMPONENT-SCHEMA:") || strings.HasPrefix(key, "ECB:STATE-ROOT:") {
+				continue
+			}
+			value, err := tf.Redis.Get(key)
+			assert.NilError(t, err)
+			if strings.HasPrefix(key, "ECB:COMPONENT-VALUE:") {
+				key = gamestate.StateRootLeafKey(Health{}.Name(), id)
+			}
+			tree.Set(key, []byte(value))
+		}
+		return tree.Root()
+	}
+
+	tf.DoTick()
+	root0, err := world.StateRoot(0)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, root0, expectedRoot())
+
+	// Writing the same values again does not change the state root.
+	tf.DoTick()
+	root1, err := world.StateRoot(1)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, root1, root0)
+
+	value = 20
+	tf.DoTick()
+	root2, err := world.StateRoot(2)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, root2, expectedRoot())
+	assert.Check(t, string(root2) != string(root1))
+
+	// Changing a resource changes the state root.
+	season = 2
+	tf.DoTick()
+	root3, err := world.StateRoot(3)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, root3, expectedRoot())
+	assert.Check(t, string(root3) != string(root2))
+
+	_, err = world.StateRoot(4)
+	assert.ErrorIs(t, err, gamestate.ErrStateRootNotFound)
+
+	// The state tree is loaded from the saved game state after a restart.
+	tf2 := cardinal.NewTestFixture(t, tf.Redis, cardinal.WithStateRoots(10))
+	assert.NilError(t, cardinal.RegisterComponent[Health](tf2.World))
+	tf2.DoTick()
+	root4, err := tf2.World.StateRoot(4)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, root4, root3)
+}
+
+func TestComponentProof(t *testing.T) {
+	tf := cardinal.NewTestFixture(t, nil, cardinal.WithStateRoots(10), cardinal.WithStateHistory(10))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterComponent[Foo](world))
+	var ids []types.EntityID
+	value := 10
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx cardinal.WorldContext) error {
+		if ids == nil {
+			var err error
+			ids, err = cardinal.CreateMany(wCtx, 3, Health{Value: value})
+			return err
+		}
+		return cardinal.SetComponent(wCtx, ids[1], &Health{Value: value})
+	}))
+
+	encode := func(health Health) []byte {
+		bz, err := json.Marshal(health)
+		assert.NilError(t, err)
+		return bz
+	}
+
+	tf.DoTick()
+	value = 20
+	tf.DoTick()
+
+	// The proof of the last finalized tick holds for the state root of that tick.
+	proof, err := world.GetComponentProof(Health{}.Name(), ids[1])
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(1), proof.Tick)
+	assert.DeepEqual(t, proof.Value, encode(Health{Value: 20}))
+	root1, err := world.StateRoot(1)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, proof.StateRoot, root1)
+	assert.Check(t, proof.Verify(root1))
+	root0, err := world.StateRoot(0)
+	assert.NilError(t, err)
+	assert.Check(t, !proof.Verify(root0))
+
+	// The proof of an older tick is built from the state history.
+	proof, err = world.GetComponentProofAtTick(Health{}.Name(), ids[1], 0)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, proof.Value, encode(Health{Value: 10}))
+	assert.Check(t, proof.Verify(root0))
+
+	_, err = world.GetComponentProof(Foo{}.Name(), ids[1])
+	assert.ErrorIs(t, err, merkle.ErrKeyNotFound)
+	_, err = world.GetComponentProofAtTick(Health{}.Name(), ids[1], 5)
+	assert.ErrorIs(t, err, gamestate.ErrTickNotInHistory)
+
+	// The proof served over HTTP is hex encoded.
+	res := tf.Post(fmt.Sprintf("query/proofs/%s/%d?atTick=1", Health{}.Name(), ids[2]), nil)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	var body handler.ComponentProofResponse
+	assert.NilError(t, json.NewDecoder(res.Body).Decode(&body))
+	decode := func(s string) []byte {
+		bz, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		assert.NilError(t, err)
+		return bz
+	}
+	depths := make([]uint8, 0, len(body.Depths))
+	for _, depth := range body.Depths {
+		depths = append(depths, uint8(depth))
+	}
+	siblings := make([][]byte, 0, len(body.Siblings))
+	for _, sibling := range body.Siblings {
+		siblings = append(siblings, decode(sibling))
+	}
+	assert.DeepEqual(t, decode(body.StateRoot), root1)
+	assert.Check(t, merkle.Verify(decode(body.StateRoot), body.Key, decode(body.Value), &merkle.Proof{
+		Depths:   depths,
+		Siblings: siblings,
+	}))
+	res = tf.Post(fmt.Sprintf("query/proofs/unknown/%d", ids[2]), nil)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/alicebob/miniredis/v2"
 
 	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/storage"
 	"pkg.world.dev/world-engine/cardinal/storage/redis"
 )
 
@@ -136,3 +137,63 @@ func TestUsedNoncesAreRememberedAcrossRestart(t *testing.T) {
 		assert.ErrorIs(t, redis.ErrNonceHasAlreadyBeenUsed, err)
 	}
 }
+
+func TestIncreasingNonces(t *testing.T) {
+	rs := GetRedisStorage(t)
+	addr := "some-address"
+
+	next, err := rs.GetNextNonce(addr)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(0), next)
+
+	assert.NilError(t, rs.UseIncreasingNonce(addr, 0))
+	// Nonces may skip values.
+	assert.NilError(t, rs.UseIncreasingNonce(addr, 5))
+	next, err = rs.GetNextNonce(addr)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(6), next)
+
+	assert.ErrorIs(t, rs.UseIncreasingNonce(addr, 5), storage.ErrNonceTooLow)
+	assert.ErrorIs(t, rs.UseIncreasingNonce(addr, 3), storage.ErrNonceTooLow)
+
+	// The nonces of other signers are independent.
+	assert.NilError(t, rs.UseIncreasingNonce("other-address", 1))
+}
+
+func TestReleaseIncreasingNonces(t *testing.T) {
+	rs := GetRedisStorage(t)
+	addr := "some-address"
+
+	assert.NilError(t, rs.UseIncreasingNonce(addr, 2))
+	assert.NilError(t, rs.UseIncreasingNonce(addr, 4))
+	assert.NilError(t, rs.UseIncreasingNonce(addr, 6))
+	// Releasing the last nonces makes them usable again.
+	assert.NilError(t, rs.ReleaseIncreasingNonces(addr, 4, 6))
+	next, err := rs.GetNextNonce(addr)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(4), next)
+	assert.NilError(t, rs.UseIncreasingNonce(addr, 4))
+
+	// Nonces are not released once the signer used a higher nonce.
+	assert.NilError(t, rs.ReleaseIncreasingNonces(addr, 2, 2))
+	next, err = rs.GetNextNonce(addr)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(5), next)
+}
+
+func TestIncreasingNoncesAreDurable(t *testing.T) {
+	s := miniredis.RunT(t)
+	options := redis.Options{Addr: s.Addr()}
+	addr := "some-address"
+
+	rs := redis.NewRedisStorage(options, Namespace)
+	assert.NilError(t, rs.UseIncreasingNonce(addr, 10))
+	assert.NilError(t, rs.Close())
+
+	// A new storage, e.g. after a restart, still rejects the used nonces.
+	rs = redis.NewRedisStorage(options, Namespace)
+	assert.ErrorIs(t, rs.UseIncreasingNonce(addr, 10), storage.ErrNonceTooLow)
+	next, err := rs.GetNextNonce(addr)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(11), next)
+}
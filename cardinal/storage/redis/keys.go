@@ -5,12 +5,18 @@ import "fmt"
 /*
 	NONCE STORAGE:      ADDRESS_TO_NONCE -> Nonce used for verifying signatures.
 	Hash set of signature address to uint64 nonce
+
+	NEXT NONCE:         ADDRESS_TO_NEXT_NONCE -> Next nonce expected from a signer when nonces must increase.
 */
 
 func (r *NonceStorage) nonceSetKey(str string) string {
 	return fmt.Sprintf("USED_NONCES_%s", str)
 }
 
+func (r *NonceStorage) nextNonceKey(str string) string {
+	return fmt.Sprintf("NEXT_NONCE_%s", str)
+}
+
 func (r *SchemaStorage) schemaStorageKey() string {
 	return "COMPONENT_NAME_TO_SCHEMA_DATA"
 }
@@ -3,12 +3,15 @@ package redis
 import (
 	"context"
 	"errors"
+	"math"
 	"strconv"
 	"sync"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/rotisserie/eris"
 	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/storage"
 )
 
 const (
@@ -104,6 +107,74 @@ func (r *NonceStorage) UseNonce(signerAddress string, nonce uint64) error {
 	return nil
 }
 
+// useIncreasingNonceScript sets the next nonce of a signer (KEYS[1]) to ARGV[2] if the nonce ARGV[1] is not lower than
+// it, and returns the empty string. Otherwise it returns the next nonce. The nonces are compared as decimal strings,
+// because Lua numbers can not hold every uint64.
+var useIncreasingNonceScript = redis.NewScript(`
+local next = redis.call('GET', KEYS[1])
+if next and (#ARGV[1] < #next or (#ARGV[1] == #next and ARGV[1] < next)) then
+	return next
+end
+redis.call('SET', KEYS[1], ARGV[2])
+return ''
+`)
+
+// releaseIncreasingNoncesScript sets the next nonce of a signer (KEYS[1]) to ARGV[2] if it is ARGV[1].
+var releaseIncreasingNoncesScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('SET', KEYS[1], ARGV[2])
+end
+return ''
+`)
+
+// UseIncreasingNonce atomically marks the given nonce as used if it is not lower than the next expected nonce of the
+// signer, see GetNextNonce. The next expected nonce then becomes nonce+1: the nonces of a signer must strictly
+// increase, but they may skip values. storage.ErrNonceTooLow is returned if the nonce is too low. The nonce is checked
+// and updated by a script that Redis runs atomically, so it is safe for concurrent use by several processes.
+func (r *NonceStorage) UseIncreasingNonce(signerAddress string, nonce uint64) error {
+	if nonce == math.MaxUint64 {
+		return eris.New("nonce is too large")
+	}
+	next, err := useIncreasingNonceScript.Run(context.Background(), r.Client, []string{r.nextNonceKey(signerAddress)},
+		strconv.FormatUint(nonce, 10), strconv.FormatUint(nonce+1, 10)).Text()
+	if err != nil {
+		return eris.Wrap(err, "failed to use nonce")
+	}
+	if next != "" {
+		return eris.Wrapf(storage.ErrNonceTooLow, "signer %q used nonce %d, expected at least %s",
+			signerAddress, nonce, next)
+	}
+	return nil
+}
+
+// ReleaseIncreasingNonces atomically undoes the use of the nonces from first to last of the signer with
+// UseIncreasingNonce, if the signer did not use a higher nonce since: the next expected nonce becomes first again.
+func (r *NonceStorage) ReleaseIncreasingNonces(signerAddress string, first, last uint64) error {
+	err := releaseIncreasingNoncesScript.Run(context.Background(), r.Client, []string{r.nextNonceKey(signerAddress)},
+		strconv.FormatUint(last+1, 10), strconv.FormatUint(first, 10)).Err()
+	return eris.Wrap(err, "failed to release nonces")
+}
+
+// GetNextNonce returns the lowest nonce the given signer can use next with UseIncreasingNonce. It is 0 for signers
+// that never used a nonce.
+func (r *NonceStorage) GetNextNonce(signerAddress string) (uint64, error) {
+	return r.getNextNonce(context.Background(), signerAddress)
+}
+
+func (r *NonceStorage) getNextNonce(ctx context.Context, signerAddress string) (uint64, error) {
+	value, err := r.Client.Get(ctx, r.nextNonceKey(signerAddress)).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	} else if err != nil {
+		return 0, eris.Wrap(err, "failed to get next nonce")
+	}
+	next, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, eris.Wrapf(err, "failed to convert %q to uint64", value)
+	}
+	return next, nil
+}
+
 // cleanupOldNonces removes the record of all nonces that are older than NonceSlidingWindowSize. Nonces in that range
 // can be rejected without checking storage. ZRemRangeByScore has a performance of O(log(N)+M) where N is the number
 // of items in the set and M is the number of items to remove.
@@ -1,7 +1,15 @@
 package storage
 
+import "errors"
+
+// ErrNonceTooLow is returned when a signer uses a nonce that is lower than its next expected nonce.
+var ErrNonceTooLow = errors.New("nonce is lower than the next expected nonce")
+
 type NonceStorage interface {
 	UseNonce(signerAddress string, nonce uint64) error
+	UseIncreasingNonce(signerAddress string, nonce uint64) error
+	ReleaseIncreasingNonces(signerAddress string, first, last uint64) error
+	GetNextNonce(signerAddress string) (uint64, error)
 }
 
 type SchemaStorage interface {
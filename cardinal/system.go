@@ -2,12 +2,12 @@ package cardinal
 
 import (
 	"context"
-	"path/filepath"
-	"reflect"
-	"runtime"
 	"slices"
+	"strings"
+	"sync"
 
 	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
@@ -28,6 +28,16 @@ type System func(ctx WorldContext) error
 type systemType struct {
 	Name string
 	Fn   System
+	// Access is the set of components the system declared it uses. It is nil for systems that did not declare their
+	// access, which never run concurrently with other systems.
+	Access *systemAccess
+	// Phase is the stage of the tick the system runs in.
+	Phase SystemPhase
+	// Before and After hold the names of the systems this system must run before and after.
+	Before []string
+	After  []string
+	// Conditions must all be true for the system to run in a tick.
+	Conditions []RunCondition
 }
 
 type SystemManager interface {
@@ -40,8 +50,9 @@ type SystemManager interface {
 
 	// These methods are intentionally made private to avoid other
 	// packages from trying to modify the system manager in the middle of a tick.
-	registerSystems(isInit bool, systems ...System) error
+	registerSystems(isInit bool, opts []SystemOption, systems ...System) error
 	registerSystem(isInit bool, systemName string, systemFunc System) error
+	sortSystems() error
 	runSystems(ctx context.Context, wCtx WorldContext) error
 }
 
@@ -70,8 +81,9 @@ func newSystemManager() SystemManager {
 // RegisterSystems registers multiple systems with the system manager.
 // There can only be one system with a given name, which is derived from the function name.
 // If isInit is true, the system will only be executed once at tick 0.
+// The given options (e.g. the phase of the systems) are applied to every system.
 // If there is a duplicate system name, an error will be returned and none of the systems will be registered.
-func (m *systemManager) registerSystems(isInit bool, systemFuncs ...System) error {
+func (m *systemManager) registerSystems(isInit bool, opts []SystemOption, systemFuncs ...System) error {
 	// We create a list of systemType structs to register, and then register them in one go to ensure all or nothing.
 	systemsToRegister := make([]systemType, 0, len(systemFuncs))
 
@@ -80,7 +92,7 @@ func (m *systemManager) registerSystems(isInit bool, systemFuncs ...System) erro
 	// 2) Create a new system entry for each one.
 	for _, systemFunc := range systemFuncs {
 		// Obtain the name of the system function using reflection.
-		systemName := filepath.Base(runtime.FuncForPC(reflect.ValueOf(systemFunc).Pointer()).Name())
+		systemName := SystemName(systemFunc)
 
 		// Check for duplicate system names within the list of systems to be registered
 		if slices.ContainsFunc(
@@ -99,12 +111,16 @@ func (m *systemManager) registerSystems(isInit bool, systemFuncs ...System) erro
 			return eris.Errorf("System %q is already registered", systemName)
 		}
 
-		systemsToRegister = append(systemsToRegister, systemType{Name: systemName, Fn: systemFunc})
+		sys := systemType{Name: systemName, Fn: systemFunc, Phase: Update}
+		for _, opt := range opts {
+			opt(&sys)
+		}
+		systemsToRegister = append(systemsToRegister, sys)
 	}
 
 	// We only register if the system if we know for sure all of them is not already registsred.
 	for _, sys := range systemsToRegister {
-		if err := m.registerSystem(isInit, sys.Name, sys.Fn); err != nil {
+		if err := m.addSystem(isInit, sys); err != nil {
 			return eris.Wrap(err, "failed to register system")
 		}
 	}
@@ -114,18 +130,21 @@ func (m *systemManager) registerSystems(isInit bool, systemFuncs ...System) erro
 
 // registerSystem is an internal function that allows us to register a system with a custom system name.
 func (m *systemManager) registerSystem(isInit bool, systemName string, systemFunc System) error {
+	return m.addSystem(isInit, systemType{Name: systemName, Fn: systemFunc, Phase: Update})
+}
+
+func (m *systemManager) addSystem(isInit bool, systemToRegister systemType) error {
 	// TODO: there is duplication in check in registerSystems and this function.
 	//  We should refactor this, but we are doing it this way to err on the side of safety.
 
 	// Checks if the system is already previously registered.
 	if slices.ContainsFunc(
 		slices.Concat(m.registeredSystems, m.registeredInitSystems),
-		func(s systemType) bool { return s.Name == systemName },
+		func(s systemType) bool { return s.Name == systemToRegister.Name },
 	) {
-		return eris.Errorf("System %q is already registered", systemName)
+		return eris.Errorf("System %q is already registered", systemToRegister.Name)
 	}
 
-	systemToRegister := systemType{Name: systemName, Fn: systemFunc}
 	if isInit {
 		m.registeredInitSystems = append(m.registeredInitSystems, systemToRegister)
 	} else {
@@ -135,7 +154,25 @@ func (m *systemManager) registerSystem(isInit bool, systemName string, systemFun
 	return nil
 }
 
-// RunSystems runs all the registered system in the order that they were registered.
+// sortSystems orders the registered systems by their phase and ordering constraints. It must be called once all the
+// systems have been registered.
+func (m *systemManager) sortSystems() error {
+	sorted, err := sortSystems(m.registeredInitSystems)
+	if err != nil {
+		return eris.Wrap(err, "failed to order init systems")
+	}
+	m.registeredInitSystems = sorted
+
+	sorted, err = sortSystems(m.registeredSystems)
+	if err != nil {
+		return eris.Wrap(err, "failed to order systems")
+	}
+	m.registeredSystems = sorted
+	return nil
+}
+
+// RunSystems runs all the registered system in the order that they were registered. Consecutive systems that declared
+// non-conflicting component access are run concurrently.
 func (m *systemManager) runSystems(ctx context.Context, wCtx WorldContext) error {
 	ctx, span := m.tracer.Start(ddotel.ContextWithStartOptions(ctx, ddtracer.Measured()), "system.run")
 	defer span.End()
@@ -150,26 +187,30 @@ func (m *systemManager) runSystems(ctx context.Context, wCtx WorldContext) error
 	// Store the original logger so that it can be reset to its original value
 	logger := wCtx.Logger()
 
-	for _, sys := range systemsToRun {
-		// Explicit memory aliasing
-		m.currentSystem = sys.Name
+	for _, batch := range scheduleSystems(systemsToRun) {
+		batch = m.filterSystemsToRun(wCtx, batch)
+		if len(batch) == 0 {
+			continue
+		}
 
-		// Inject the system name into the logger
-		wCtx.setLogger(logger.With().Str("system", sys.Name).Logger())
+		var err error
+		if len(batch) == 1 && batch[0].Access == nil {
+			sys := batch[0]
+			m.currentSystem = sys.Name
 
-		// Executes the system function that the user registered
-		_, systemFnSpan := m.tracer.Start(ddotel.ContextWithStartOptions(ctx, //nolint:spancheck // false positive
-			ddtracer.Measured()),
-			"system.run."+sys.Name)
-		if err := sys.Fn(wCtx); err != nil {
+			// Inject the system name into the logger
+			wCtx.setLogger(logger.With().Str("system", sys.Name).Logger())
+
+			err = m.runSystem(ctx, wCtx, sys)
+		} else {
+			err = m.runSystemsConcurrently(ctx, wCtx, logger, batch)
+		}
+		if err != nil {
 			m.currentSystem = ""
 			span.SetStatus(codes.Error, eris.ToString(err, true))
 			span.RecordError(err)
-			systemFnSpan.SetStatus(codes.Error, eris.ToString(err, true))
-			systemFnSpan.RecordError(err)
-			return eris.Wrapf(err, "System %s generated an error", sys.Name) //nolint:spancheck // false positive
+			return err
 		}
-		systemFnSpan.End()
 	}
 
 	// Reset the logger to the original logger
@@ -181,6 +222,106 @@ func (m *systemManager) runSystems(ctx context.Context, wCtx WorldContext) error
 	return nil
 }
 
+// filterSystemsToRun returns the systems in the batch whose run conditions are true.
+func (m *systemManager) filterSystemsToRun(wCtx WorldContext, batch []systemType) []systemType {
+	toRun := make([]systemType, 0, len(batch))
+	for _, sys := range batch {
+		// A panic in a run condition is reported as a panic in the system.
+		m.currentSystem = sys.Name
+		if sys.shouldRun(wCtx) {
+			toRun = append(toRun, sys)
+		}
+	}
+	return toRun
+}
+
+// runSystemsConcurrently runs each system in the batch in its own goroutine with its own copy of the world context.
+// Once all the systems are done, the events they emitted are added to the tick results in registration order, and the
+// error of the first failing system (in registration order) is returned.
+func (m *systemManager) runSystemsConcurrently(
+	ctx context.Context, wCtx WorldContext, logger *zerolog.Logger, batch []systemType,
+) error {
+	names := make([]string, 0, len(batch))
+	for _, sys := range batch {
+		names = append(names, sys.Name)
+	}
+	m.currentSystem = strings.Join(names, ",")
+
+	sysCtxs := make([]WorldContext, len(batch))
+	errs := make([]error, len(batch))
+	panics := make([]any, len(batch))
+
+	var wg sync.WaitGroup
+	for i, sys := range batch {
+		sysCtxs[i] = wCtx.newSystemContext(sys.Name, sys.Access)
+		sysCtxs[i].setLogger(logger.With().Str("system", sys.Name).Logger())
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Panics can only be recovered by the goroutine they happen in, so they are passed on to the caller.
+			defer func() {
+				if r := recover(); r != nil {
+					panics[i] = r
+				}
+			}()
+			errs[i] = m.runSystem(ctx, sysCtxs[i], sys)
+		}()
+	}
+	wg.Wait()
+
+	for i, r := range panics {
+		if r != nil {
+			m.currentSystem = batch[i].Name
+			panic(r)
+		}
+	}
+	for i, err := range errs {
+		if err != nil {
+			return err
+		}
+		if err := sysCtxs[i].commitEvents(); err != nil {
+			return eris.Wrapf(err, "failed to add the events of system %s", batch[i].Name)
+		}
+	}
+	return nil
+}
+
+// runSystem executes the system function that the user registered.
+func (m *systemManager) runSystem(ctx context.Context, wCtx WorldContext, sys systemType) error {
+	_, systemFnSpan := m.tracer.Start(ddotel.ContextWithStartOptions(ctx, ddtracer.Measured()), "system.run."+sys.Name)
+	defer systemFnSpan.End()
+
+	if err := sys.Fn(wCtx); err != nil {
+		systemFnSpan.SetStatus(codes.Error, eris.ToString(err, true))
+		systemFnSpan.RecordError(err)
+		return eris.Wrapf(err, "System %s generated an error", sys.Name)
+	}
+	return nil
+}
+
+// scheduleSystems splits the systems into batches of systems that can run concurrently. A system is only added to the
+// batch of the system right before it, so the batches preserve the order of the systems and are the same on every tick.
+func scheduleSystems(systems []systemType) [][]systemType {
+	batches := make([][]systemType, 0, len(systems))
+	for _, sys := range systems {
+		if n := len(batches); n > 0 && !conflictsWithBatch(sys, batches[n-1]) {
+			batches[n-1] = append(batches[n-1], sys)
+			continue
+		}
+		batches = append(batches, []systemType{sys})
+	}
+	return batches
+}
+
+func conflictsWithBatch(sys systemType, batch []systemType) bool {
+	for _, other := range batch {
+		if sys.Phase != other.Phase || sys.isOrderedWith(other) || sys.Access.conflictsWith(other.Access) {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *systemManager) GetRegisteredSystems() []string {
 	sys := slices.Concat(m.registeredInitSystems, m.registeredSystems)
 	sysNames := make([]string, len(sys))
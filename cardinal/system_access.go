@@ -0,0 +1,93 @@
+package cardinal
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// SystemAccess declares the components and resources a system reads and writes. Systems registered with
+// RegisterSystemsWithAccess whose component access does not conflict are run concurrently within a tick. Two systems
+// conflict if one of them writes a component that the other one reads or writes.
+//
+// A system registered with an access declaration may only read the components in Reads or Writes, and only write the
+// components in Writes. It may not create or remove entities, or add or remove components from entities, because
+// that would change entity IDs and archetypes in a way that depends on which system ran first.
+type SystemAccess struct {
+	Reads  []types.Component
+	Writes []types.Component
+}
+
+// systemAccess is the set of component names a system declared access to.
+type systemAccess struct {
+	reads  map[string]struct{}
+	writes map[string]struct{}
+}
+
+func newSystemAccess(access SystemAccess) *systemAccess {
+	a := &systemAccess{
+		reads:  make(map[string]struct{}, len(access.Reads)),
+		writes: make(map[string]struct{}, len(access.Writes)),
+	}
+	for _, comp := range access.Reads {
+		a.reads[comp.Name()] = struct{}{}
+	}
+	for _, comp := range access.Writes {
+		a.writes[comp.Name()] = struct{}{}
+	}
+	return a
+}
+
+func (a *systemAccess) canRead(componentName string) bool {
+	_, read := a.reads[componentName]
+	_, write := a.writes[componentName]
+	return read || write
+}
+
+func (a *systemAccess) canWrite(componentName string) bool {
+	_, ok := a.writes[componentName]
+	return ok
+}
+
+// conflictsWith returns true if the two systems can not safely run at the same time. Systems that did not declare
+// their access conflict with every other system.
+func (a *systemAccess) conflictsWith(other *systemAccess) bool {
+	if a == nil || other == nil {
+		return true
+	}
+	for name := range a.writes {
+		if other.canRead(name) {
+			return true
+		}
+	}
+	for name := range other.writes {
+		if a.canRead(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRead returns an error if the system is not allowed to read the given component.
+func (a *systemAccess) checkRead(componentName string) error {
+	if a == nil || a.canRead(componentName) {
+		return nil
+	}
+	return eris.Wrapf(ErrComponentAccessNotDeclared, "read of component %q", componentName)
+}
+
+// checkWrite returns an error if the system is not allowed to write the given component.
+func (a *systemAccess) checkWrite(componentName string) error {
+	if a == nil || a.canWrite(componentName) {
+		return nil
+	}
+	return eris.Wrapf(ErrComponentAccessNotDeclared, "write of component %q", componentName)
+}
+
+// checkStructuralChange returns an error if the system is not allowed to create or remove entities or components.
+func (a *systemAccess) checkStructuralChange() error {
+	if a == nil {
+		return nil
+	}
+	return eris.Wrap(ErrStructuralChangeNotAllowed, "")
+}
@@ -0,0 +1,51 @@
+package cardinal
+
+import (
+	"reflect"
+)
+
+// RunCondition decides whether a system runs in the current tick. Run conditions are evaluated right before the system
+// would run, so they see the changes made by the systems that ran before it. They should only read the world state.
+type RunCondition func(wCtx WorldContext) bool
+
+// RunIf makes the system only run in ticks where all the given conditions are true.
+//
+// Usage:
+//
+// cardinal.RegisterSystem(world, EconomySystem, cardinal.RunIf(cardinal.EveryNTicks(20)))
+func RunIf(conditions ...RunCondition) SystemOption {
+	return func(sys *systemType) {
+		sys.Conditions = append(sys.Conditions, conditions...)
+	}
+}
+
+// EveryNTicks is true once every n ticks, starting with tick 0.
+func EveryNTicks(n uint64) RunCondition {
+	return func(wCtx WorldContext) bool {
+		return n <= 1 || wCtx.CurrentTick()%n == 0
+	}
+}
+
+// WhenMessagePending is true in ticks that have at least one transaction for the given message. It is always false if
+// the message has not been registered.
+func WhenMessagePending[In any, Out any]() RunCondition {
+	return func(wCtx WorldContext) bool {
+		var msg MessageType[In, Out]
+		registered, ok := wCtx.getMessageByType(reflect.TypeOf(msg))
+		if !ok {
+			return false
+		}
+		txPool := wCtx.getTxPool()
+		return txPool != nil && len(txPool.ForID(registered.ID())) > 0
+	}
+}
+
+// shouldRun returns true if all the run conditions of the system are true.
+func (s systemType) shouldRun(wCtx WorldContext) bool {
+	for _, condition := range s.Conditions {
+		if !condition(wCtx) {
+			return false
+		}
+	}
+	return true
+}
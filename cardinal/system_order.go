@@ -0,0 +1,178 @@
+package cardinal
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/rotisserie/eris"
+)
+
+// SystemPhase groups systems that run at the same stage of a tick. All the systems in a phase run before any system of
+// the next phase.
+type SystemPhase int
+
+const (
+	// PreUpdate systems run before all other systems, e.g. to process persona messages.
+	PreUpdate SystemPhase = iota
+	// Update is the default phase of systems.
+	Update
+	// PostUpdate systems run after all other systems.
+	PostUpdate
+)
+
+func (p SystemPhase) String() string {
+	switch p {
+	case PreUpdate:
+		return "PreUpdate"
+	case Update:
+		return "Update"
+	case PostUpdate:
+		return "PostUpdate"
+	default:
+		return "Unknown"
+	}
+}
+
+// SystemOption configures a system registered with RegisterSystem.
+type SystemOption func(sys *systemType)
+
+// WithPhase sets the phase the system runs in. Systems run in the Update phase by default.
+func WithPhase(phase SystemPhase) SystemOption {
+	return func(sys *systemType) {
+		sys.Phase = phase
+	}
+}
+
+// Before makes the system run before the systems with the given names, see SystemName.
+func Before(systemNames ...string) SystemOption {
+	return func(sys *systemType) {
+		sys.Before = append(sys.Before, systemNames...)
+	}
+}
+
+// After makes the system run after the systems with the given names, see SystemName.
+func After(systemNames ...string) SystemOption {
+	return func(sys *systemType) {
+		sys.After = append(sys.After, systemNames...)
+	}
+}
+
+// WithComponentAccess declares the components the system reads and writes, see SystemAccess.
+func WithComponentAccess(access SystemAccess) SystemOption {
+	return func(sys *systemType) {
+		sys.Access = newSystemAccess(access)
+	}
+}
+
+// SystemName returns the name a system is registered under, which is derived from the name of the function.
+func SystemName(sys System) string {
+	return filepath.Base(runtime.FuncForPC(reflect.ValueOf(sys).Pointer()).Name())
+}
+
+// isOrderedWith returns true if one of the systems has an ordering constraint on the other.
+func (s systemType) isOrderedWith(other systemType) bool {
+	return slices.Contains(s.Before, other.Name) || slices.Contains(s.After, other.Name) ||
+		slices.Contains(other.Before, s.Name) || slices.Contains(other.After, s.Name)
+}
+
+// sortSystems returns the systems sorted by phase, such that every Before and After constraint is satisfied. Systems
+// that are not ordered by a phase or constraint keep their registration order. An error is returned if a constraint
+// refers to an unknown system, contradicts the phases of the systems, or if the constraints contain a cycle.
+func sortSystems(systems []systemType) ([]systemType, error) {
+	index := make(map[string]int, len(systems))
+	for i, sys := range systems {
+		index[sys.Name] = i
+	}
+
+	// predecessors[i] holds the systems that must run before system i.
+	predecessors := make([][]int, len(systems))
+	addConstraint := func(first, then int) error {
+		if systems[first].Phase > systems[then].Phase {
+			return eris.Errorf("system %q in phase %s cannot run before system %q in phase %s",
+				systems[first].Name, systems[first].Phase, systems[then].Name, systems[then].Phase)
+		}
+		predecessors[then] = append(predecessors[then], first)
+		return nil
+	}
+	for i, sys := range systems {
+		for _, name := range sys.Before {
+			j, ok := index[name]
+			if !ok {
+				return nil, eris.Errorf("system %q must run before unknown system %q", sys.Name, name)
+			}
+			if err := addConstraint(i, j); err != nil {
+				return nil, err
+			}
+		}
+		for _, name := range sys.After {
+			j, ok := index[name]
+			if !ok {
+				return nil, eris.Errorf("system %q must run after unknown system %q", sys.Name, name)
+			}
+			if err := addConstraint(j, i); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Repeatedly pick the system in the earliest phase, then the earliest registered system, whose predecessors have
+	// all been picked. Since the constraints agree with the phases, this never picks a system before a system in an
+	// earlier phase.
+	sorted := make([]systemType, 0, len(systems))
+	done := make([]bool, len(systems))
+	isReady := func(i int) bool {
+		for _, p := range predecessors[i] {
+			if !done[p] {
+				return false
+			}
+		}
+		return true
+	}
+	for len(sorted) < len(systems) {
+		next := -1
+		for i, sys := range systems {
+			if done[i] || !isReady(i) {
+				continue
+			}
+			if next == -1 || sys.Phase < systems[next].Phase {
+				next = i
+			}
+		}
+		if next == -1 {
+			return nil, eris.Wrap(ErrSystemOrderCycle, formatCycle(systems, predecessors, done))
+		}
+		done[next] = true
+		sorted = append(sorted, systems[next])
+	}
+	return sorted, nil
+}
+
+// formatCycle returns a cycle among the systems that have not been sorted, e.g. "a -> b -> a".
+func formatCycle(systems []systemType, predecessors [][]int, done []bool) string {
+	start := slices.Index(done, false)
+	// Walk backwards through unsorted predecessors until a system is visited twice.
+	visited := map[int]int{}
+	path := make([]int, 0)
+	for curr := start; ; {
+		if pos, ok := visited[curr]; ok {
+			path = append(path[pos:], curr)
+			break
+		}
+		visited[curr] = len(path)
+		path = append(path, curr)
+		for _, p := range predecessors[curr] {
+			if !done[p] {
+				curr = p
+				break
+			}
+		}
+	}
+	names := make([]string, 0, len(path))
+	for i := len(path) - 1; i >= 0; i-- {
+		names = append(names, systems[path[i]].Name)
+	}
+	return strings.Join(names, " -> ")
+}
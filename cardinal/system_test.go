@@ -2,7 +2,12 @@ package cardinal_test
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
@@ -102,3 +107,272 @@ func TestInitSystemRunsOnce(t *testing.T) {
 	assert.Equal(t, count, 1)
 	assert.Equal(t, count2, 2)
 }
+
+func TestSystemsWithDisjointAccessRunConcurrently(t *testing.T) {
+	tf := cardinal.NewTestFixture(t, nil)
+	world, doTick := tf.World, tf.DoTick
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterComponent[HealthComponent](world))
+
+	var id types.EntityID
+	assert.NilError(t, cardinal.RegisterInitSystems(world, func(wCtx cardinal.WorldContext) error {
+		var err error
+		id, err = cardinal.Create(wCtx, Health{}, HealthComponent{})
+		return err
+	}))
+
+	// Each system waits for the other one to start, which only succeeds if they run at the same time.
+	var started sync.WaitGroup
+	started.Add(2)
+	waitForOtherSystem := func() error {
+		started.Done()
+		done := make(chan struct{})
+		go func() {
+			started.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return nil
+		case <-time.After(5 * time.Second):
+			return errors.New("systems did not run concurrently")
+		}
+	}
+	assert.NilError(t, cardinal.RegisterSystemsWithAccess(world,
+		cardinal.SystemAccess{Writes: []types.Component{Health{}}},
+		func(wCtx cardinal.WorldContext) error {
+			if err := waitForOtherSystem(); err != nil {
+				return err
+			}
+			return cardinal.SetComponent[Health](wCtx, id, &Health{Value: 10})
+		},
+	))
+	assert.NilError(t, cardinal.RegisterSystemsWithAccess(world,
+		cardinal.SystemAccess{Writes: []types.Component{HealthComponent{}}},
+		func(wCtx cardinal.WorldContext) error {
+			if err := waitForOtherSystem(); err != nil {
+				return err
+			}
+			return cardinal.SetComponent[HealthComponent](wCtx, id, &HealthComponent{HP: 20})
+		},
+	))
+
+	doTick()
+
+	wCtx := cardinal.NewReadOnlyWorldContext(world)
+	health, err := cardinal.GetComponent[Health](wCtx, id)
+	assert.NilError(t, err)
+	assert.Equal(t, 10, health.Value)
+	hp, err := cardinal.GetComponent[HealthComponent](wCtx, id)
+	assert.NilError(t, err)
+	assert.Equal(t, 20, hp.HP)
+}
+
+func TestSystemsWithConflictingAccessRunInRegistrationOrder(t *testing.T) {
+	tf := cardinal.NewTestFixture(t, nil)
+	world, doTick := tf.World, tf.DoTick
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+
+	var id types.EntityID
+	assert.NilError(t, cardinal.RegisterInitSystems(world, func(wCtx cardinal.WorldContext) error {
+		var err error
+		id, err = cardinal.Create(wCtx, Health{})
+		return err
+	}))
+
+	var running atomic.Int32
+	var seen []int
+	assert.NilError(t, cardinal.RegisterSystemsWithAccess(world,
+		cardinal.SystemAccess{Writes: []types.Component{Health{}}},
+		func(wCtx cardinal.WorldContext) error {
+			assert.Equal(t, int32(1), running.Add(1))
+			defer running.Add(-1)
+			return cardinal.UpdateComponent[Health](wCtx, id, func(h *Health) *Health {
+				h.Value++
+				return h
+			})
+		},
+	))
+	assert.NilError(t, cardinal.RegisterSystemsWithAccess(world,
+		cardinal.SystemAccess{Reads: []types.Component{Health{}}},
+		func(wCtx cardinal.WorldContext) error {
+			assert.Equal(t, int32(1), running.Add(1))
+			defer running.Add(-1)
+			health, err := cardinal.GetComponent[Health](wCtx, id)
+			if err != nil {
+				return err
+			}
+			seen = append(seen, health.Value)
+			return nil
+		},
+	))
+
+	for i := 0; i < 3; i++ {
+		doTick()
+	}
+	assert.DeepEqual(t, []int{1, 2, 3}, seen)
+}
+
+func TestSystemPanicsOnUndeclaredComponentAccess(t *testing.T) {
+	tf := cardinal.NewTestFixture(t, nil)
+	world, doTick := tf.World, tf.DoTick
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterComponent[HealthComponent](world))
+
+	var id types.EntityID
+	assert.NilError(t, cardinal.RegisterInitSystems(world, func(wCtx cardinal.WorldContext) error {
+		var err error
+		id, err = cardinal.Create(wCtx, Health{})
+		return err
+	}))
+
+	expectPanic := func(t *testing.T, wantErr error, fn func() error) {
+		defer func() {
+			r := recover()
+			assert.Check(t, r != nil, "expected a panic")
+			assert.Check(t, strings.Contains(fmt.Sprint(r), wantErr.Error()), "expected %v but got %v", wantErr, r)
+		}()
+		_ = fn()
+	}
+	assert.NilError(t, cardinal.RegisterSystemsWithAccess(world,
+		cardinal.SystemAccess{Reads: []types.Component{Health{}}},
+		func(wCtx cardinal.WorldContext) error {
+			_, err := cardinal.GetComponent[Health](wCtx, id)
+			assert.NilError(t, err)
+
+			expectPanic(t, cardinal.ErrComponentAccessNotDeclared, func() error {
+				return cardinal.SetComponent[Health](wCtx, id, &Health{Value: 1})
+			})
+			expectPanic(t, cardinal.ErrComponentAccessNotDeclared, func() error {
+				_, err := cardinal.GetComponent[HealthComponent](wCtx, id)
+				return err
+			})
+			expectPanic(t, cardinal.ErrStructuralChangeNotAllowed, func() error {
+				_, err := cardinal.Create(wCtx, Health{})
+				return err
+			})
+			return nil
+		},
+	))
+
+	doTick()
+}
+
+func TestSystemsRunInPhaseAndConstraintOrder(t *testing.T) {
+	tf := cardinal.NewTestFixture(t, nil)
+	world, doTick := tf.World, tf.DoTick
+
+	// System names are derived from the function name, so each system must be a separate function.
+	var order []string
+	cleanup := func(cardinal.WorldContext) error {
+		order = append(order, "cleanup")
+		return nil
+	}
+	update := func(cardinal.WorldContext) error {
+		order = append(order, "update")
+		return nil
+	}
+	input := func(cardinal.WorldContext) error {
+		order = append(order, "input")
+		return nil
+	}
+	move := func(cardinal.WorldContext) error {
+		order = append(order, "move")
+		return nil
+	}
+
+	assert.NilError(t, cardinal.RegisterSystem(world, cleanup, cardinal.WithPhase(cardinal.PostUpdate)))
+	assert.NilError(t, cardinal.RegisterSystem(world, move, cardinal.After(cardinal.SystemName(input))))
+	assert.NilError(t, cardinal.RegisterSystem(world, update))
+	assert.NilError(t, cardinal.RegisterSystem(world, input, cardinal.Before(cardinal.SystemName(update))))
+
+	doTick()
+	assert.DeepEqual(t, []string{"input", "move", "update", "cleanup"}, order)
+}
+
+func TestStartGameFailsOnInvalidSystemOrder(t *testing.T) {
+	first := func(cardinal.WorldContext) error { return nil }
+	second := func(cardinal.WorldContext) error { return nil }
+	third := func(cardinal.WorldContext) error { return nil }
+
+	testCases := []struct {
+		name     string
+		register func(world *cardinal.World) error
+		wantErr  string
+	}{
+		{
+			name: "cycle",
+			register: func(world *cardinal.World) error {
+				return errors.Join(
+					cardinal.RegisterSystem(world, first, cardinal.Before(cardinal.SystemName(second))),
+					cardinal.RegisterSystem(world, second, cardinal.Before(cardinal.SystemName(third))),
+					cardinal.RegisterSystem(world, third, cardinal.Before(cardinal.SystemName(first))),
+				)
+			},
+			wantErr: cardinal.ErrSystemOrderCycle.Error(),
+		},
+		{
+			name: "unknown system",
+			register: func(world *cardinal.World) error {
+				return cardinal.RegisterSystem(world, first, cardinal.After("does_not_exist"))
+			},
+			wantErr: "unknown system",
+		},
+		{
+			name: "constraint contradicts phases",
+			register: func(world *cardinal.World) error {
+				return errors.Join(
+					cardinal.RegisterSystem(world, first, cardinal.WithPhase(cardinal.PostUpdate)),
+					cardinal.RegisterSystem(world, second, cardinal.After(cardinal.SystemName(first))),
+				)
+			},
+			wantErr: "cannot run before",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tf := cardinal.NewTestFixture(t, nil)
+			assert.NilError(t, tc.register(tf.World))
+			err := tf.World.StartGame()
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestSystemRunConditions(t *testing.T) {
+	tf := cardinal.NewTestFixture(t, nil)
+	world, doTick := tf.World, tf.DoTick
+
+	type fooMsg struct{}
+	type fooMsgRes struct{}
+	assert.NilError(t, cardinal.RegisterMessage[fooMsg, fooMsgRes](world, "foo"))
+
+	var everyThirdTick, onMessage, whileEnabled []uint64
+	enabled := true
+	assert.NilError(t, cardinal.RegisterSystem(world, func(wCtx cardinal.WorldContext) error {
+		everyThirdTick = append(everyThirdTick, wCtx.CurrentTick())
+		return nil
+	}, cardinal.RunIf(cardinal.EveryNTicks(3))))
+	assert.NilError(t, cardinal.RegisterSystem(world, func(wCtx cardinal.WorldContext) error {
+		onMessage = append(onMessage, wCtx.CurrentTick())
+		return nil
+	}, cardinal.RunIf(cardinal.WhenMessagePending[fooMsg, fooMsgRes]())))
+	assert.NilError(t, cardinal.RegisterSystem(world, func(wCtx cardinal.WorldContext) error {
+		whileEnabled = append(whileEnabled, wCtx.CurrentTick())
+		return nil
+	}, cardinal.RunIf(func(cardinal.WorldContext) bool { return enabled })))
+
+	fooMessage, ok := world.GetMessageByFullName("game.foo")
+	assert.True(t, ok)
+	for tick := 0; tick < 7; tick++ {
+		if tick == 2 || tick == 5 {
+			tf.AddTransaction(fooMessage.ID(), fooMsg{})
+		}
+		enabled = tick < 4
+		doTick()
+	}
+
+	assert.DeepEqual(t, []uint64{0, 3, 6}, everyThirdTick)
+	assert.DeepEqual(t, []uint64{2, 5}, onMessage)
+	assert.DeepEqual(t, []uint64{0, 1, 2, 3}, whileEnabled)
+}
@@ -6,36 +6,51 @@ import (
 	"github.com/rotisserie/eris"
 
 	"pkg.world.dev/world-engine/cardinal/receipt"
+	"pkg.world.dev/world-engine/cardinal/types"
 )
 
 type TickResults struct {
 	Tick     uint64
 	Receipts []receipt.Receipt
 	Events   [][]byte
+
+	// eventTopics holds the topics each event in Events was published to.
+	eventTopics [][]types.Topic
 }
 
 func NewTickResults(initialTick uint64) *TickResults {
 	return &TickResults{
-		Tick:     initialTick,
-		Receipts: []receipt.Receipt{},
-		Events:   [][]byte{},
+		Tick:        initialTick,
+		Receipts:    []receipt.Receipt{},
+		Events:      [][]byte{},
+		eventTopics: [][]types.Topic{},
 	}
 }
 
-func (tr *TickResults) AddEvent(event any) error {
+func (tr *TickResults) AddEvent(event any, topics ...types.Topic) error {
 	data, err := json.Marshal(event)
 	if err != nil {
 		return eris.Wrap(err, "must use a json serializable type for emitting events")
 	}
 	tr.Events = append(tr.Events, data)
+	tr.eventTopics = append(tr.eventTopics, topics)
 	return nil
 }
 
-func (tr *TickResults) AddStringEvent(e string) error {
+func (tr *TickResults) AddStringEvent(e string, topics ...types.Topic) error {
 	tr.Events = append(tr.Events, []byte(e))
+	tr.eventTopics = append(tr.eventTopics, topics)
 	return nil
 }
 
+// EventTopics returns the topics the event at the given index of Events was published to.
+func (tr *TickResults) EventTopics(i int) []types.Topic {
+	if i >= len(tr.eventTopics) {
+		return nil
+	}
+	return tr.eventTopics[i]
+}
+
 func (tr *TickResults) SetReceipts(newReceipts []receipt.Receipt) {
 	tr.Receipts = newReceipts
 }
@@ -48,4 +63,5 @@ func (tr *TickResults) Clear() {
 	tr.Tick = 0
 	tr.Receipts = nil
 	tr.Events = nil
+	tr.eventTopics = nil
 }
@@ -0,0 +1,60 @@
+package txpool
+
+type Option func(*TxPool)
+
+// WithMaxTxsPerTick limits the number of transactions handled in a tick. The other transactions are deferred to the
+// next ticks. A limit of 0 means no limit.
+func WithMaxTxsPerTick(limit int) Option {
+	return func(t *TxPool) {
+		t.maxTxsPerTick = limit
+	}
+}
+
+// WithMaxBytesPerTick limits the total size of the message bodies of the transactions handled in a tick. The other
+// transactions are deferred to the next ticks. A limit of 0 means no limit.
+func WithMaxBytesPerTick(limit int) Option {
+	return func(t *TxPool) {
+		t.maxBytesPerTick = limit
+	}
+}
+
+// WithMaxPoolSize limits the number of transactions waiting in the pool, including deferred transactions. Submitting
+// a transaction to a full pool fails with ErrPoolFull. A limit of 0 means no limit.
+func WithMaxPoolSize(limit int) Option {
+	return func(t *TxPool) {
+		t.maxPoolSize = limit
+	}
+}
+
+// WithPersonaRateLimit limits the number of transactions each persona can submit in a tick. Submitting more fails
+// with ErrRateLimited. A limit of 0 means no limit.
+func WithPersonaRateLimit(txsPerTick int) Option {
+	return func(t *TxPool) {
+		t.personaRate = txsPerTick
+	}
+}
+
+// WithPriority sets the function that orders the transactions of the pool when they do not all fit in a tick. By
+// default, transactions are handled in the order they were added.
+func WithPriority(priority PriorityFunc) Option {
+	return func(t *TxPool) {
+		t.priority = priority
+	}
+}
+
+// WithMaxDeferrals sets the number of ticks a transaction can be deferred to because of its priority. Once a
+// transaction was deferred that many times, it is handled before the transactions that were deferred fewer times,
+// whatever their priority. The default is 10. A limit of 0 means no limit, which lets a steady flow of transactions
+// with a high priority starve the transactions with a lower priority.
+func WithMaxDeferrals(ticks int) Option {
+	return func(t *TxPool) {
+		t.maxDeferrals = ticks
+	}
+}
+
+// WithWAL logs the transactions submitted to the pool in the given write-ahead log, see TxPool.Restore.
+func WithWAL(wal WAL) Option {
+	return func(t *TxPool) {
+		t.wal = wal
+	}
+}
@@ -2,8 +2,11 @@ package txpool
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"sync"
 
+	"github.com/rotisserie/eris"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	ddotel "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentelemetry"
@@ -13,6 +16,14 @@ import (
 	"pkg.world.dev/world-engine/sign"
 )
 
+var (
+	// ErrPoolFull is returned when a transaction is submitted while the pool already holds the maximum number of
+	// transactions.
+	ErrPoolFull = errors.New("transaction pool is full")
+	// ErrRateLimited is returned when a persona submits more transactions in a tick than its rate limit allows.
+	ErrRateLimited = errors.New("persona submitted too many transactions in this tick")
+)
+
 type TxMap map[types.MessageID][]TxData
 
 type TxData struct {
@@ -22,21 +33,63 @@ type TxData struct {
 	Tx     *sign.Transaction
 	// EVMSourceTxHash is the tx hash of the EVM tx that triggered this tx.
 	EVMSourceTxHash string
+	// batch is the hash of the first transaction of the batch this transaction was submitted with, see
+	// SubmitTransactions. It is empty for transactions that were submitted on their own.
+	batch types.TxHash
+	// deferrals is the number of ticks the transaction was deferred to, see SelectTransactions.
+	deferrals int
 }
 
+// defaultMaxDeferrals is the default number of ticks a transaction can be deferred to before it is handled ahead of
+// the transactions with a higher priority, see WithMaxDeferrals.
+const defaultMaxDeferrals = 10
+
+// PriorityFunc returns the priority of a transaction. Transactions with a higher priority are handled first when not
+// all the transactions in the pool fit in a tick.
+type PriorityFunc func(tx TxData) int64
+
 type TxPool struct {
-	m         TxMap
+	m TxMap
+	// txs holds the same transactions as m, in the order they were added.
+	txs       []TxData
 	txsInPool int
-	mux       *sync.Mutex
-	tracer    trace.Tracer
+	// deferred holds the transactions that did not fit in the tick, see SelectTransactions.
+	deferred []TxData
+	// personaTxs is the number of transactions submitted by each persona since the last tick.
+	personaTxs map[string]int
+	// submitted holds the hashes of the transactions in the pool that were submitted by clients.
+	submitted map[types.TxHash]struct{}
+	// logging holds the hashes of the submitted transactions that are being logged to the WAL, which is done without
+	// holding mux. They count towards the size of the pool until they are added to it.
+	logging map[types.TxHash]struct{}
+	// inTick holds the hashes of the transactions taken from the pool by the running tick, see CompleteTick.
+	inTick map[types.TxHash]struct{}
+	wal    WAL
+	mux    *sync.Mutex
+	tracer trace.Tracer
+
+	maxTxsPerTick   int
+	maxBytesPerTick int
+	maxPoolSize     int
+	personaRate     int
+	priority        PriorityFunc
+	maxDeferrals    int
 }
 
-func New() *TxPool {
-	return &TxPool{
-		m:      TxMap{},
-		mux:    &sync.Mutex{},
-		tracer: otel.Tracer("txpool"),
+func New(opts ...Option) *TxPool {
+	t := &TxPool{
+		m:            TxMap{},
+		personaTxs:   map[string]int{},
+		submitted:    map[types.TxHash]struct{}{},
+		logging:      map[types.TxHash]struct{}{},
+		mux:          &sync.Mutex{},
+		tracer:       otel.Tracer("txpool"),
+		maxDeferrals: defaultMaxDeferrals,
+	}
+	for _, opt := range opts {
+		opt(t)
 	}
+	return t
 }
 
 func (t *TxPool) GetAmountOfTxs() int {
@@ -61,34 +114,198 @@ func (t *TxPool) GetEVMTxs() []TxData {
 	return transactions
 }
 
+// AddTransaction adds a transaction to the pool. The pool size and rate limits do not apply, use SubmitTransaction
+// for transactions that come from clients.
 func (t *TxPool) AddTransaction(id types.MessageID, v any, sig *sign.Transaction) types.TxHash {
-	return t.addTransaction(id, v, sig, "")
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.addTransaction(TxData{MsgID: id, Msg: v, Tx: sig})
 }
 
 func (t *TxPool) AddEVMTransaction(id types.MessageID, v any, sig *sign.Transaction, evmTxHash string) types.TxHash {
-	return t.addTransaction(id, v, sig, evmTxHash)
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.addTransaction(TxData{MsgID: id, Msg: v, Tx: sig, EVMSourceTxHash: evmTxHash})
+}
+
+// SubmitTransaction adds a transaction that comes from a client to the pool. ErrPoolFull is returned if the pool
+// is full, and ErrRateLimited if the persona that signed the transaction exceeded its rate limit. Submitting a
+// transaction that is already waiting in the pool has no effect. If the pool has a WAL, the transaction is logged
+// before it is added.
+func (t *TxPool) SubmitTransaction(id types.MessageID, v any, sig *sign.Transaction) (types.TxHash, error) {
+	txHashes, err := t.SubmitTransactions([]TxData{{MsgID: id, Msg: v, Tx: sig}})
+	if err != nil {
+		return "", err
+	}
+	return txHashes[0], nil
 }
 
-func (t *TxPool) addTransaction(id types.MessageID, v any, sig *sign.Transaction, evmTxHash string) types.TxHash {
+// SubmitTransactions adds a batch of transactions that come from clients to the pool. The MsgID, Msg and Tx of each
+// TxData must be set. Either all or none of the transactions are added: an error is returned if the pool does not
+// have room for all of them, if any persona would exceed its rate limit, or if they can not be logged. The WAL is
+// written without blocking the other calls to the pool, so the transactions of concurrent calls share its syncs. The
+// transactions of a batch are always handled in the same tick, see SelectTransactions. It returns the hashes of the
+// transactions, in order.
+func (t *TxPool) SubmitTransactions(txs []TxData) ([]types.TxHash, error) {
+	t.mux.Lock()
+	txHashes := make([]types.TxHash, 0, len(txs))
+	newTxs := make([]TxData, 0, len(txs))
+	sigs := make([]*sign.Transaction, 0, len(txs))
+	seen := map[types.TxHash]struct{}{}
+	for _, tx := range txs {
+		txHash := types.TxHash(tx.Tx.HashHex())
+		txHashes = append(txHashes, txHash)
+		if _, ok := t.submitted[txHash]; ok {
+			continue
+		}
+		if _, ok := t.logging[txHash]; ok {
+			continue
+		}
+		if _, ok := seen[txHash]; ok {
+			continue
+		}
+		seen[txHash] = struct{}{}
+		newTxs = append(newTxs, TxData{MsgID: tx.MsgID, Msg: tx.Msg, Tx: tx.Tx})
+		sigs = append(sigs, tx.Tx)
+	}
+	if err := t.checkLimits(sigs); err != nil {
+		t.mux.Unlock()
+		return nil, err
+	}
+	if len(newTxs) > 1 {
+		for i := range newTxs {
+			newTxs[i].batch = types.TxHash(newTxs[0].Tx.HashHex())
+		}
+	}
+	if t.wal == nil || len(newTxs) == 0 {
+		t.addSubmitted(newTxs)
+		t.mux.Unlock()
+		return txHashes, nil
+	}
+
+	// The room for the transactions is reserved while they are logged, so the limits still hold.
+	for _, tx := range newTxs {
+		t.countPersonaTx(tx.Tx)
+		t.logging[types.TxHash(tx.Tx.HashHex())] = struct{}{}
+	}
+	t.mux.Unlock()
+
+	err := t.wal.Append(newTxs)
+
 	t.mux.Lock()
 	defer t.mux.Unlock()
-	txHash := types.TxHash(sig.HashHex())
-	t.m[id] = append(t.m[id], TxData{
-		MsgID:           id,
-		TxHash:          txHash,
-		Msg:             v,
-		Tx:              sig,
-		EVMSourceTxHash: evmTxHash,
-	})
+	for _, tx := range newTxs {
+		delete(t.logging, types.TxHash(tx.Tx.HashHex()))
+		t.uncountPersonaTx(tx.Tx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.addSubmitted(newTxs)
+	return txHashes, nil
+}
+
+// addSubmitted adds transactions submitted by clients to the pool.
+func (t *TxPool) addSubmitted(txs []TxData) {
+	for _, tx := range txs {
+		t.countPersonaTx(tx.Tx)
+		t.submitted[t.addTransaction(tx)] = struct{}{}
+	}
+}
+
+// Restore adds the transactions that were logged by the WAL of the pool but not handled by a finalized tick back to
+// the pool. currentTick is the first tick that is not finalized. The decode function validates a logged transaction
+// and returns its message; the transactions it rejects are dropped. It returns the number of restored transactions.
+func (t *TxPool) Restore(
+	currentTick uint64, decode func(id types.MessageID, tx *sign.Transaction) (any, error),
+) (int, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.wal == nil {
+		return 0, nil
+	}
+	txs, err := t.wal.Pending(currentTick)
+	if err != nil {
+		return 0, err
+	}
+	restored := 0
+	for _, tx := range txs {
+		if _, ok := t.submitted[tx.TxHash]; ok {
+			continue
+		}
+		msg, err := decode(tx.MsgID, tx.Tx)
+		if err != nil {
+			continue
+		}
+		tx.Msg = msg
+		t.submitted[t.addTransaction(tx)] = struct{}{}
+		restored++
+	}
+	return restored, nil
+}
+
+// Close closes the WAL of the pool, if any.
+func (t *TxPool) Close() error {
+	if t.wal == nil {
+		return nil
+	}
+	return t.wal.Close()
+}
+
+// checkLimits checks that transactions signed with the given signatures can be added to the pool.
+func (t *TxPool) checkLimits(sigs []*sign.Transaction) error {
+	if t.maxPoolSize > 0 && t.txsInPool+len(t.logging)+len(sigs) > t.maxPoolSize {
+		return eris.Wrapf(ErrPoolFull, "the pool holds %d transactions", t.txsInPool+len(t.logging))
+	}
+	if t.personaRate == 0 {
+		return nil
+	}
+	counts := map[string]int{}
+	for _, sig := range sigs {
+		if sig == nil {
+			continue
+		}
+		counts[sig.PersonaTag]++
+		if t.personaTxs[sig.PersonaTag]+counts[sig.PersonaTag] > t.personaRate {
+			return eris.Wrapf(ErrRateLimited, "persona %q", sig.PersonaTag)
+		}
+	}
+	return nil
+}
+
+func (t *TxPool) countPersonaTx(sig *sign.Transaction) {
+	if t.personaRate > 0 && sig != nil {
+		t.personaTxs[sig.PersonaTag]++
+	}
+}
+
+func (t *TxPool) uncountPersonaTx(sig *sign.Transaction) {
+	if t.personaRate > 0 && sig != nil && t.personaTxs[sig.PersonaTag] > 0 {
+		t.personaTxs[sig.PersonaTag]--
+	}
+}
+
+func (t *TxPool) addTransaction(tx TxData) types.TxHash {
+	tx.TxHash = types.TxHash(tx.Tx.HashHex())
+	t.m[tx.MsgID] = append(t.m[tx.MsgID], tx)
+	t.txs = append(t.txs, tx)
 	t.txsInPool++
-	return txHash
+	return tx.TxHash
 }
 
 func (t *TxPool) Transactions() TxMap {
 	return t.m
 }
 
-// CopyTransactions returns a copy of the TxPool, and resets the state to 0 values.
+// Deferred returns the transactions that were left in the pool for a later tick when this copy of the pool was made
+// by SelectTransactions.
+func (t *TxPool) Deferred() []TxData {
+	return t.deferred
+}
+
+// CopyTransactions returns a copy of the TxPool, and resets the state to 0 values. All the transactions are moved to
+// the copy, regardless of the per-tick limits.
 func (t *TxPool) CopyTransactions(ctx context.Context) *TxPool {
 	_, span := t.tracer.Start(ddotel.ContextWithStartOptions(ctx, ddtracer.Measured()), "txpool.copy-transactions")
 	defer span.End()
@@ -97,14 +314,190 @@ func (t *TxPool) CopyTransactions(ctx context.Context) *TxPool {
 	defer t.mux.Unlock()
 
 	cpy := *t
-	t.reset()
+	t.reset(nil)
+	t.setInTick(cpy.txs)
 
 	return &cpy
 }
 
-func (t *TxPool) reset() {
+// SelectTransactions moves the transactions to handle in the given tick to a copy of the TxPool and returns it. The
+// transactions are taken by priority, and in the order they were added for equal priorities, until the maximum
+// number of transactions or bytes per tick is reached. The size of a transaction is the size of its message body.
+// Transactions that were already deferred to the maximum number of ticks (see WithMaxDeferrals) are taken first, in
+// the order they were added, so low priorities are not starved by a steady flow of higher priorities. The
+// transactions of a batch submitted with SubmitTransactions are taken as a whole, with the highest priority of
+// its transactions, or not at all. At least one transaction or batch is taken, even if it is over the limits. The
+// other transactions stay in the pool for the next tick, and are listed by the Deferred method of the copy. If the
+// pool has a WAL, the transactions taken are logged before they are removed from the pool.
+func (t *TxPool) SelectTransactions(ctx context.Context, tick uint64) (*TxPool, error) {
+	_, span := t.tracer.Start(ddotel.ContextWithStartOptions(ctx, ddtracer.Measured()), "txpool.select-transactions")
+	defer span.End()
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.maxTxsPerTick == 0 && t.maxBytesPerTick == 0 {
+		if err := t.logTick(tick, t.txs); err != nil {
+			return nil, err
+		}
+		cpy := *t
+		t.reset(nil)
+		t.setInTick(cpy.txs)
+		return &cpy, nil
+	}
+
+	units := t.selectionUnits()
+	slices.SortStableFunc(units, func(a, b selectionUnit) int {
+		switch {
+		case a.overdue != b.overdue:
+			if a.overdue {
+				return -1
+			}
+			return 1
+		case a.overdue:
+			// Overdue transactions are handled in the order they were added.
+			return 0
+		case a.priority > b.priority:
+			return -1
+		case a.priority < b.priority:
+			return 1
+		}
+		return 0
+	})
+
+	selected := make([]bool, len(t.txs))
+	count, size := 0, 0
+	for _, unit := range units {
+		if t.maxTxsPerTick > 0 && count > 0 && count+len(unit.txs) > t.maxTxsPerTick {
+			break
+		}
+		if t.maxBytesPerTick > 0 && count > 0 && size+unit.size > t.maxBytesPerTick {
+			break
+		}
+		for _, i := range unit.txs {
+			selected[i] = true
+		}
+		count += len(unit.txs)
+		size += unit.size
+	}
+
+	cpy := New()
+	var deferred []TxData
+	for i, tx := range t.txs {
+		if selected[i] {
+			cpy.addTransaction(tx)
+		} else {
+			tx.deferrals++
+			deferred = append(deferred, tx)
+		}
+	}
+	if err := t.logTick(tick, cpy.txs); err != nil {
+		return nil, err
+	}
+	cpy.deferred = deferred
+	t.reset(deferred)
+	t.setInTick(cpy.txs)
+	return cpy, nil
+}
+
+// selectionUnit is a transaction of the pool, or all the transactions of a batch, which are selected together.
+type selectionUnit struct {
+	// txs are the indexes of the transactions in TxPool.txs.
+	txs      []int
+	priority int64
+	size     int
+	// overdue is true if the unit was deferred to the maximum number of ticks.
+	overdue bool
+}
+
+// selectionUnits returns the units of the transactions in the pool, in the order their first transaction was added.
+func (t *TxPool) selectionUnits() []selectionUnit {
+	units := make([]selectionUnit, 0, len(t.txs))
+	batches := map[types.TxHash]int{}
+	for i, tx := range t.txs {
+		var priority int64
+		if t.priority != nil {
+			priority = t.priority(tx)
+		}
+		size := 0
+		if tx.Tx != nil {
+			size = len(tx.Tx.Body)
+		}
+		overdue := t.maxDeferrals > 0 && tx.deferrals >= t.maxDeferrals
+		if j, ok := batches[tx.batch]; ok && tx.batch != "" {
+			units[j].txs = append(units[j].txs, i)
+			units[j].priority = max(units[j].priority, priority)
+			units[j].size += size
+			units[j].overdue = units[j].overdue || overdue
+			continue
+		}
+		if tx.batch != "" {
+			batches[tx.batch] = len(units)
+		}
+		units = append(units, selectionUnit{txs: []int{i}, priority: priority, size: size, overdue: overdue})
+	}
+	return units
+}
+
+// CompleteTick marks the transactions taken by the last call to SelectTransactions or CopyTransactions as handled,
+// once their receipts are available.
+func (t *TxPool) CompleteTick() {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.inTick = nil
+}
+
+// IsPending reports whether the given transaction is waiting in the pool, or is being handled by a tick that is not
+// completed yet.
+func (t *TxPool) IsPending(txHash types.TxHash) bool {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if _, ok := t.inTick[txHash]; ok {
+		return true
+	}
+	for _, tx := range t.txs {
+		if tx.TxHash == txHash {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *TxPool) setInTick(txs []TxData) {
+	t.inTick = make(map[types.TxHash]struct{}, len(txs))
+	for _, tx := range txs {
+		t.inTick[tx.TxHash] = struct{}{}
+	}
+}
+
+func (t *TxPool) logTick(tick uint64, txs []TxData) error {
+	if t.wal == nil {
+		return nil
+	}
+	txHashes := make([]types.TxHash, 0, len(txs))
+	for _, tx := range txs {
+		if _, ok := t.submitted[tx.TxHash]; ok {
+			txHashes = append(txHashes, tx.TxHash)
+		}
+	}
+	return t.wal.StartTick(tick, txHashes)
+}
+
+// reset empties the pool, except for the given transactions, and resets the rate limits.
+func (t *TxPool) reset(keep []TxData) {
+	submitted := t.submitted
 	t.m = TxMap{}
+	t.txs = nil
 	t.txsInPool = 0
+	t.deferred = nil
+	t.personaTxs = map[string]int{}
+	t.submitted = map[types.TxHash]struct{}{}
+	for _, tx := range keep {
+		t.addTransaction(tx)
+		if _, ok := submitted[tx.TxHash]; ok {
+			t.submitted[tx.TxHash] = struct{}{}
+		}
+	}
 }
 
 func (t *TxPool) ForID(id types.MessageID) []TxData {
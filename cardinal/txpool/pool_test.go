@@ -0,0 +1,207 @@
+package txpool_test
+
+import (
+	"context"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/txpool"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/sign"
+)
+
+type fooMsg struct {
+	Fee int64
+}
+
+func newTx(personaTag string, timestamp int64, body string) *sign.Transaction {
+	return &sign.Transaction{PersonaTag: personaTag, Timestamp: timestamp, Body: []byte(body)}
+}
+
+func selectTxs(t *testing.T, pool *txpool.TxPool) *txpool.TxPool {
+	txs, err := pool.SelectTransactions(context.Background(), 0)
+	assert.NilError(t, err)
+	return txs
+}
+
+func hashes(txs []txpool.TxData) []types.TxHash {
+	res := make([]types.TxHash, 0, len(txs))
+	for _, tx := range txs {
+		res = append(res, tx.TxHash)
+	}
+	return res
+}
+
+func TestSelectTransactionsByPriority(t *testing.T) {
+	pool := txpool.New(
+		txpool.WithMaxTxsPerTick(2),
+		txpool.WithPriority(func(tx txpool.TxData) int64 {
+			return tx.Msg.(fooMsg).Fee
+		}),
+	)
+	low := pool.AddTransaction(1, fooMsg{Fee: 1}, newTx("a", 1, "{}"))
+	high := pool.AddTransaction(1, fooMsg{Fee: 5}, newTx("a", 2, "{}"))
+	first := pool.AddTransaction(1, fooMsg{Fee: 2}, newTx("a", 3, "{}"))
+	second := pool.AddTransaction(1, fooMsg{Fee: 2}, newTx("a", 4, "{}"))
+
+	// The transactions with the highest fees are handled first, and equal fees are handled in order.
+	txs := selectTxs(t, pool)
+	assert.DeepEqual(t, hashes(txs.ForID(1)), []types.TxHash{high, first})
+	assert.DeepEqual(t, hashes(txs.Deferred()), []types.TxHash{low, second})
+	assert.Equal(t, 2, pool.GetAmountOfTxs())
+
+	// A deferred transaction still goes after newer transactions with a higher priority.
+	higher := pool.AddTransaction(1, fooMsg{Fee: 3}, newTx("a", 5, "{}"))
+	txs = selectTxs(t, pool)
+	assert.DeepEqual(t, hashes(txs.ForID(1)), []types.TxHash{second, higher})
+	txs = selectTxs(t, pool)
+	assert.DeepEqual(t, hashes(txs.ForID(1)), []types.TxHash{low})
+	assert.Equal(t, 0, len(txs.Deferred()))
+	assert.Equal(t, 0, pool.GetAmountOfTxs())
+}
+
+func TestSelectTransactionsDoesNotStarveLowPriorities(t *testing.T) {
+	pool := txpool.New(
+		txpool.WithMaxTxsPerTick(1),
+		txpool.WithMaxDeferrals(2),
+		txpool.WithPriority(func(tx txpool.TxData) int64 {
+			return tx.Msg.(fooMsg).Fee
+		}),
+	)
+	low := pool.AddTransaction(1, fooMsg{Fee: 1}, newTx("a", 0, "{}"))
+
+	// A new transaction with a higher priority arrives every tick, but the low priority transaction is handled once
+	// it was deferred twice.
+	var got []types.TxHash
+	for i := 1; i <= 4; i++ {
+		pool.AddTransaction(1, fooMsg{Fee: 5}, newTx("a", int64(i), "{}"))
+		got = append(got, hashes(selectTxs(t, pool).ForID(1))...)
+	}
+	assert.Equal(t, 4, len(got))
+	assert.Equal(t, low, got[2])
+
+	// Without a limit, the low priority transaction waits as long as higher priorities keep arriving.
+	pool = txpool.New(
+		txpool.WithMaxTxsPerTick(1),
+		txpool.WithMaxDeferrals(0),
+		txpool.WithPriority(func(tx txpool.TxData) int64 {
+			return tx.Msg.(fooMsg).Fee
+		}),
+	)
+	low = pool.AddTransaction(1, fooMsg{Fee: 1}, newTx("a", 0, "{}"))
+	for i := 1; i <= 20; i++ {
+		pool.AddTransaction(1, fooMsg{Fee: 5}, newTx("a", int64(i), "{}"))
+		assert.Check(t, hashes(selectTxs(t, pool).ForID(1))[0] != low)
+	}
+}
+
+func TestSelectTransactionsByBytes(t *testing.T) {
+	pool := txpool.New(txpool.WithMaxBytesPerTick(10))
+	big := pool.AddTransaction(1, fooMsg{}, newTx("a", 1, `{"x":"0123456789"}`))
+	small1 := pool.AddTransaction(1, fooMsg{}, newTx("a", 2, `{"x":1}`))
+	small2 := pool.AddTransaction(1, fooMsg{}, newTx("a", 3, `{"x":2}`))
+
+	// A transaction larger than the limit is still handled when it is the first of the tick.
+	txs := selectTxs(t, pool)
+	assert.DeepEqual(t, hashes(txs.ForID(1)), []types.TxHash{big})
+	txs = selectTxs(t, pool)
+	assert.DeepEqual(t, hashes(txs.ForID(1)), []types.TxHash{small1})
+	txs = selectTxs(t, pool)
+	assert.DeepEqual(t, hashes(txs.ForID(1)), []types.TxHash{small2})
+
+	// CopyTransactions ignores the limits.
+	pool.AddTransaction(1, fooMsg{}, newTx("a", 4, `{"x":"0123456789"}`))
+	pool.AddTransaction(1, fooMsg{}, newTx("a", 5, `{"x":"0123456789"}`))
+	assert.Equal(t, 2, pool.CopyTransactions(context.Background()).GetAmountOfTxs())
+}
+
+func TestSelectTransactionsKeepsBatchesTogether(t *testing.T) {
+	pool := txpool.New(
+		txpool.WithMaxTxsPerTick(3),
+		txpool.WithPriority(func(tx txpool.TxData) int64 {
+			return tx.Msg.(fooMsg).Fee
+		}),
+	)
+	single := pool.AddTransaction(1, fooMsg{Fee: 2}, newTx("a", 1, "{}"))
+	batch, err := pool.SubmitTransactions([]txpool.TxData{
+		{MsgID: 1, Msg: fooMsg{Fee: 1}, Tx: newTx("b", 2, "{}")},
+		{MsgID: 1, Msg: fooMsg{Fee: 1}, Tx: newTx("b", 3, "{}")},
+		{MsgID: 1, Msg: fooMsg{Fee: 1}, Tx: newTx("b", 4, "{}")},
+	})
+	assert.NilError(t, err)
+
+	// The batch does not fit in the tick after the single transaction, so it is deferred as a whole.
+	txs := selectTxs(t, pool)
+	assert.DeepEqual(t, hashes(txs.ForID(1)), []types.TxHash{single})
+	assert.DeepEqual(t, hashes(txs.Deferred()), batch)
+
+	// A batch takes the highest priority of its transactions.
+	other := pool.AddTransaction(1, fooMsg{Fee: 2}, newTx("a", 5, "{}"))
+	first, err := pool.SubmitTransactions([]txpool.TxData{
+		{MsgID: 1, Msg: fooMsg{Fee: 3}, Tx: newTx("c", 6, "{}")},
+		{MsgID: 1, Msg: fooMsg{Fee: 0}, Tx: newTx("c", 7, "{}")},
+	})
+	assert.NilError(t, err)
+	txs = selectTxs(t, pool)
+	assert.DeepEqual(t, hashes(txs.ForID(1)), []types.TxHash{other, first[0], first[1]})
+
+	// A batch larger than the limit is handled as a whole when it is the first of the tick.
+	txs = selectTxs(t, pool)
+	assert.DeepEqual(t, hashes(txs.ForID(1)), batch)
+	assert.Equal(t, 0, pool.GetAmountOfTxs())
+}
+
+func TestSubmitTransactionLimits(t *testing.T) {
+	pool := txpool.New(txpool.WithMaxPoolSize(3), txpool.WithPersonaRateLimit(2))
+
+	_, err := pool.SubmitTransaction(1, fooMsg{}, newTx("a", 1, "{}"))
+	assert.NilError(t, err)
+	_, err = pool.SubmitTransaction(1, fooMsg{}, newTx("a", 2, "{}"))
+	assert.NilError(t, err)
+	_, err = pool.SubmitTransaction(1, fooMsg{}, newTx("a", 3, "{}"))
+	assert.ErrorIs(t, err, txpool.ErrRateLimited)
+
+	// A batch is rejected as a whole.
+	_, err = pool.SubmitTransactions([]txpool.TxData{
+		{MsgID: 1, Msg: fooMsg{}, Tx: newTx("b", 4, "{}")},
+		{MsgID: 1, Msg: fooMsg{}, Tx: newTx("c", 5, "{}")},
+	})
+	assert.ErrorIs(t, err, txpool.ErrPoolFull)
+	assert.Equal(t, 2, pool.GetAmountOfTxs())
+	_, err = pool.SubmitTransactions([]txpool.TxData{
+		{MsgID: 1, Msg: fooMsg{}, Tx: newTx("b", 4, "{}")},
+	})
+	assert.NilError(t, err)
+
+	// Submitting a transaction that is already in the pool has no effect.
+	txHash, err := pool.SubmitTransaction(1, fooMsg{}, newTx("b", 4, "{}"))
+	assert.NilError(t, err)
+	assert.Equal(t, types.TxHash(newTx("b", 4, "{}").HashHex()), txHash)
+	assert.Equal(t, 3, pool.GetAmountOfTxs())
+
+	// Transactions added by the engine itself are not limited.
+	pool.AddTransaction(1, fooMsg{}, newTx("a", 6, "{}"))
+	assert.Equal(t, 4, pool.GetAmountOfTxs())
+
+	// The rate limits are reset every tick.
+	selectTxs(t, pool)
+	_, err = pool.SubmitTransaction(1, fooMsg{}, newTx("a", 7, "{}"))
+	assert.NilError(t, err)
+}
+
+func TestIsPending(t *testing.T) {
+	pool := txpool.New(txpool.WithMaxTxsPerTick(1))
+	first := pool.AddTransaction(1, fooMsg{}, newTx("a", 1, "{}"))
+	second := pool.AddTransaction(1, fooMsg{}, newTx("a", 2, "{}"))
+	assert.Check(t, pool.IsPending(first))
+	assert.Check(t, pool.IsPending(second))
+
+	// The transactions of a tick are pending until the tick is completed.
+	selectTxs(t, pool)
+	assert.Check(t, pool.IsPending(first))
+	assert.Check(t, pool.IsPending(second))
+	pool.CompleteTick()
+	assert.Check(t, !pool.IsPending(first))
+	assert.Check(t, pool.IsPending(second))
+	assert.Check(t, !pool.IsPending("unknown"))
+}
@@ -0,0 +1,335 @@
+package txpool
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/sign"
+)
+
+const (
+	walRecordTx   = "tx"
+	walRecordTick = "tick"
+
+	// walCompactionThreshold is the number of handled transactions after which the log file is rewritten without
+	// them.
+	walCompactionThreshold = 1024
+)
+
+// WAL is a write-ahead log of the transactions submitted to a TxPool. Transactions are logged before they are
+// accepted, and the transactions handled by each tick are logged before the tick runs, so the transactions that were
+// accepted but not handled by a finalized tick can be put back in the pool after a restart.
+type WAL interface {
+	// Append logs transactions that are about to be accepted by the pool. Only their MsgID, Tx, EVMSourceTxHash and
+	// batch are logged.
+	Append(txs []TxData) error
+	// StartTick logs the hashes of the transactions handled by the given tick. It is called before the tick runs,
+	// which means all the previous ticks are finalized.
+	StartTick(tick uint64, txHashes []types.TxHash) error
+	// Pending returns the logged transactions that were not handled by a finalized tick, in the order they were
+	// logged. currentTick is the first tick that is not finalized. The Msg of the returned transactions is not set.
+	Pending(currentTick uint64) ([]TxData, error)
+	Close() error
+}
+
+var _ WAL = &FileWAL{}
+
+// FileWAL is a WAL that appends JSON records to a file, and syncs the file before each write is acknowledged. The
+// records appended by concurrent calls to Append are synced together.
+type FileWAL struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	// written is the number of writes to the file, and synced is the number of writes that are known to be synced.
+	written, synced uint64
+	// syncMu is held while the file is synced by Append, without holding mu.
+	syncMu sync.Mutex
+	// txs holds the records of the logged transactions that were not handled by a finalized tick, and order holds
+	// their hashes in the order they were logged. order may still hold the hashes of handled transactions.
+	txs   map[types.TxHash]walRecord
+	order []types.TxHash
+	// lastTick is the record of the last started tick, which may not be finalized yet.
+	lastTick *walRecord
+	// handled is the number of handled transactions since the log file was last rewritten.
+	handled int
+}
+
+type walRecord struct {
+	Type            string            `json:"type"`
+	MsgID           types.MessageID   `json:"msgId,omitempty"`
+	Tx              *sign.Transaction `json:"tx,omitempty"`
+	EVMSourceTxHash string            `json:"evmSourceTxHash,omitempty"`
+	Batch           types.TxHash      `json:"batch,omitempty"`
+	Tick            uint64            `json:"tick,omitempty"`
+	TxHashes        []types.TxHash    `json:"txHashes,omitempty"`
+}
+
+// NewFileWAL opens the write-ahead log in the given file, creating the file and its directory if needed.
+func NewFileWAL(path string) (*FileWAL, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:gomnd // standard directory permissions
+		return nil, eris.Wrapf(err, "failed to create txpool log directory for %q", path)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644) //nolint:gomnd // standard file permissions
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to open txpool log %q", path)
+	}
+	return &FileWAL{
+		path: path,
+		file: file,
+		txs:  map[types.TxHash]walRecord{},
+	}, nil
+}
+
+func (f *FileWAL) Append(txs []TxData) error {
+	f.mu.Lock()
+	records := make([]walRecord, 0, len(txs))
+	for _, tx := range txs {
+		records = append(records, walRecord{
+			Type:            walRecordTx,
+			MsgID:           tx.MsgID,
+			Tx:              tx.Tx,
+			EVMSourceTxHash: tx.EVMSourceTxHash,
+			Batch:           tx.batch,
+		})
+	}
+	if err := f.write(records...); err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	for _, record := range records {
+		f.addTx(record)
+	}
+	written := f.written
+	f.mu.Unlock()
+	return f.syncWrites(written)
+}
+
+// syncWrites syncs the file until at least the given number of writes are synced. A single sync covers the writes of
+// all the calls that are waiting for it.
+func (f *FileWAL) syncWrites(written uint64) error {
+	f.syncMu.Lock()
+	defer f.syncMu.Unlock()
+
+	f.mu.Lock()
+	if f.synced >= written {
+		f.mu.Unlock()
+		return nil
+	}
+	file, target := f.file, f.written
+	f.mu.Unlock()
+
+	err := file.Sync()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err != nil {
+		// The file may have been replaced by a compaction, which syncs all the writes.
+		if f.synced >= written {
+			return nil
+		}
+		return eris.Wrapf(err, "failed to sync txpool log %q", f.path)
+	}
+	f.synced = max(f.synced, target)
+	return nil
+}
+
+func (f *FileWAL) StartTick(tick uint64, txHashes []types.TxHash) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// The last started tick is finalized now, so its transactions are no longer needed.
+	if f.lastTick != nil {
+		for _, txHash := range f.lastTick.TxHashes {
+			delete(f.txs, txHash)
+			f.handled++
+		}
+		f.lastTick = nil
+	}
+
+	record := walRecord{Type: walRecordTick, Tick: tick}
+	for _, txHash := range txHashes {
+		if _, ok := f.txs[txHash]; ok {
+			record.TxHashes = append(record.TxHashes, txHash)
+		}
+	}
+	if len(record.TxHashes) == 0 {
+		return f.compactIfNeeded()
+	}
+	if err := f.write(record); err != nil {
+		return err
+	}
+	if err := f.file.Sync(); err != nil {
+		return eris.Wrapf(err, "failed to sync txpool log %q", f.path)
+	}
+	f.synced = f.written
+	f.lastTick = &record
+	return f.compactIfNeeded()
+}
+
+func (f *FileWAL) Pending(currentTick uint64) ([]TxData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bz, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to read txpool log %q", f.path)
+	}
+	f.txs = map[types.TxHash]walRecord{}
+	f.order = nil
+	f.lastTick = nil
+	lines := bytes.Split(bz, []byte("\n"))
+	for i, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+		var record walRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			if i == len(lines)-1 {
+				// The last record was not fully written before a crash, so it was never acknowledged.
+				break
+			}
+			return nil, eris.Wrapf(err, "failed to decode record %d of txpool log %q", i, f.path)
+		}
+		switch record.Type {
+		case walRecordTx:
+			f.addTx(record)
+		case walRecordTick:
+			// The transactions of a tick that was not finalized must be handled again.
+			if record.Tick < currentTick {
+				for _, txHash := range record.TxHashes {
+					delete(f.txs, txHash)
+				}
+			}
+		default:
+			return nil, eris.Errorf("unknown record type %q in txpool log %q", record.Type, f.path)
+		}
+	}
+	if err := f.compact(); err != nil {
+		return nil, err
+	}
+
+	txs := make([]TxData, 0, len(f.order))
+	for _, txHash := range f.order {
+		record := f.txs[txHash]
+		txs = append(txs, TxData{
+			MsgID:           record.MsgID,
+			TxHash:          txHash,
+			Tx:              record.Tx,
+			EVMSourceTxHash: record.EVMSourceTxHash,
+			batch:           record.Batch,
+		})
+	}
+	return txs, nil
+}
+
+func (f *FileWAL) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return eris.Wrap(f.file.Close(), "")
+}
+
+// addTx keeps track of a logged transaction. Transactions that were already logged are ignored.
+func (f *FileWAL) addTx(record walRecord) {
+	if record.Tx == nil {
+		return
+	}
+	txHash := types.TxHash(record.Tx.HashHex())
+	if _, ok := f.txs[txHash]; ok {
+		return
+	}
+	f.txs[txHash] = record
+	f.order = append(f.order, txHash)
+}
+
+// write appends records to the file. The file is not synced.
+func (f *FileWAL) write(records ...walRecord) error {
+	var buf bytes.Buffer
+	for _, record := range records {
+		bz, err := json.Marshal(record)
+		if err != nil {
+			return eris.Wrap(err, "failed to encode txpool log record")
+		}
+		buf.Write(bz)
+		buf.WriteByte('\n')
+	}
+	if _, err := f.file.Write(buf.Bytes()); err != nil {
+		return eris.Wrapf(err, "failed to write to txpool log %q", f.path)
+	}
+	f.written++
+	return nil
+}
+
+func (f *FileWAL) compactIfNeeded() error {
+	if f.handled < walCompactionThreshold {
+		return nil
+	}
+	return f.compact()
+}
+
+// compact rewrites the log file with only the transactions that were not handled by a finalized tick.
+func (f *FileWAL) compact() error {
+	order := make([]types.TxHash, 0, len(f.txs))
+	for _, txHash := range f.order {
+		if _, ok := f.txs[txHash]; ok {
+			order = append(order, txHash)
+		}
+	}
+	f.order = order
+
+	// Write to a temporary file first so a crash in the middle of a write never loses the log.
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+"*.tmp")
+	if err != nil {
+		return eris.Wrap(err, "failed to create txpool log file")
+	}
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, txHash := range f.order {
+		if err := enc.Encode(f.txs[txHash]); err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+			return eris.Wrap(err, "failed to write txpool log file")
+		}
+	}
+	if f.lastTick != nil {
+		if err := enc.Encode(f.lastTick); err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+			return eris.Wrap(err, "failed to write txpool log file")
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return eris.Wrap(err, "failed to write txpool log file")
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return eris.Wrap(err, "failed to sync txpool log file")
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return eris.Wrap(err, "failed to close txpool log file")
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return eris.Wrap(err, "failed to rename txpool log file")
+	}
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_WRONLY, 0o644) //nolint:gomnd // standard file permissions
+	if err != nil {
+		return eris.Wrapf(err, "failed to open txpool log %q", f.path)
+	}
+	_ = f.file.Close()
+	f.file = file
+	f.handled = 0
+	f.synced = f.written
+	return nil
+}
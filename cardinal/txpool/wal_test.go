@@ -0,0 +1,133 @@
+package txpool_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/txpool"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/sign"
+)
+
+func decodeFoo(_ types.MessageID, _ *sign.Transaction) (any, error) {
+	return fooMsg{}, nil
+}
+
+func TestWALRestoresPendingTransactions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "txpool.log")
+	wal, err := txpool.NewFileWAL(path)
+	assert.NilError(t, err)
+	pool := txpool.New(txpool.WithWAL(wal), txpool.WithMaxTxsPerTick(1))
+	restored, err := pool.Restore(0, decodeFoo)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, restored)
+
+	_, err = pool.SubmitTransaction(1, fooMsg{}, newTx("a", 1, "{}"))
+	assert.NilError(t, err)
+	second, err := pool.SubmitTransaction(2, fooMsg{}, newTx("a", 2, "{}"))
+	assert.NilError(t, err)
+	third, err := pool.SubmitTransaction(1, fooMsg{}, newTx("a", 3, "{}"))
+	assert.NilError(t, err)
+	// Transactions that were not submitted by clients are not logged.
+	pool.AddTransaction(1, fooMsg{}, newTx("a", 4, "{}"))
+
+	// Tick 0 is finalized, but the crash happens while tick 1 is running.
+	_, err = pool.SelectTransactions(context.Background(), 0)
+	assert.NilError(t, err)
+	_, err = pool.SelectTransactions(context.Background(), 1)
+	assert.NilError(t, err)
+	assert.NilError(t, pool.Close())
+
+	wal, err = txpool.NewFileWAL(path)
+	assert.NilError(t, err)
+	pool = txpool.New(txpool.WithWAL(wal))
+	restored, err = pool.Restore(1, decodeFoo)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, restored)
+	txs, err := pool.SelectTransactions(context.Background(), 1)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, hashes(txs.ForID(1)), []types.TxHash{third})
+	assert.DeepEqual(t, hashes(txs.ForID(2)), []types.TxHash{second})
+
+	// Once tick 1 is finalized, nothing is left to restore.
+	_, err = pool.SelectTransactions(context.Background(), 2)
+	assert.NilError(t, err)
+	assert.NilError(t, pool.Close())
+	wal, err = txpool.NewFileWAL(path)
+	assert.NilError(t, err)
+	pool = txpool.New(txpool.WithWAL(wal))
+	restored, err = pool.Restore(3, decodeFoo)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, restored)
+	assert.NilError(t, pool.Close())
+}
+
+func TestWALIgnoresPartialRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "txpool.log")
+	wal, err := txpool.NewFileWAL(path)
+	assert.NilError(t, err)
+	pool := txpool.New(txpool.WithWAL(wal))
+	txHash, err := pool.SubmitTransaction(1, fooMsg{}, newTx("a", 1, "{}"))
+	assert.NilError(t, err)
+	assert.NilError(t, pool.Close())
+
+	// The process crashed in the middle of writing a record.
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	assert.NilError(t, err)
+	_, err = file.WriteString(`{"type":"tx","msgId":1,"tx":{"perso`)
+	assert.NilError(t, err)
+	assert.NilError(t, file.Close())
+
+	wal, err = txpool.NewFileWAL(path)
+	assert.NilError(t, err)
+	pool = txpool.New(txpool.WithWAL(wal))
+	restored, err := pool.Restore(0, decodeFoo)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, restored)
+	assert.DeepEqual(t, hashes(pool.ForID(1)), []types.TxHash{txHash})
+	assert.NilError(t, pool.Close())
+}
+
+func TestWALLogsConcurrentSubmissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "txpool.log")
+	wal, err := txpool.NewFileWAL(path)
+	assert.NilError(t, err)
+	pool := txpool.New(txpool.WithWAL(wal), txpool.WithMaxPoolSize(50))
+
+	// The transactions are logged without holding the pool lock, but the pool limits still hold.
+	wg := &sync.WaitGroup{}
+	errs := make(chan error, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := pool.SubmitTransaction(1, fooMsg{}, newTx("a", int64(i), "{}"))
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	accepted := 0
+	for err := range errs {
+		if err == nil {
+			accepted++
+		} else {
+			assert.ErrorIs(t, err, txpool.ErrPoolFull)
+		}
+	}
+	assert.Equal(t, 50, accepted)
+	assert.Equal(t, 50, pool.GetAmountOfTxs())
+	assert.NilError(t, pool.Close())
+
+	wal, err = txpool.NewFileWAL(path)
+	assert.NilError(t, err)
+	pool = txpool.New(txpool.WithWAL(wal))
+	restored, err := pool.Restore(0, decodeFoo)
+	assert.NilError(t, err)
+	assert.Equal(t, 50, restored)
+	assert.NilError(t, pool.Close())
+}
@@ -6,9 +6,14 @@ import (
 	"github.com/invopop/jsonschema"
 	"github.com/rotisserie/eris"
 	"github.com/wI2L/jsondiff"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
 )
 
-var ErrComponentSchemaMismatch = errors.New("component schema does not match target schema")
+var (
+	ErrComponentSchemaMismatch = errors.New("component schema does not match target schema")
+	ErrComponentCodecMismatch  = errors.New("component codec does not match the codec of the stored values")
+)
 
 type ComponentID int
 
@@ -29,6 +34,8 @@ type ComponentMetadata interface { //revive:disable-line:exported
 	New() ([]byte, error)
 	Encode(any) ([]byte, error)
 	Decode([]byte) (Component, error)
+	// Codec returns the codec used by Encode and Decode.
+	Codec() codec.Codec
 	GetSchema() []byte
 	ValidateAgainstSchema(targetSchema []byte) error
 
@@ -3,3 +3,5 @@ package types
 import "github.com/rotisserie/eris"
 
 var ErrQueryNotFound = eris.New("query not found")
+
+var ErrResourceNotRegistered = eris.New("resource not registered")
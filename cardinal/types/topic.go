@@ -0,0 +1,36 @@
+package types
+
+import "strconv"
+
+// Topic identifies a stream of events on the /events websocket. Clients that subscribe to one or more topics only
+// receive the receipts and events that were published to those topics.
+type Topic string
+
+const (
+	topicPrefixEventType = "event:"
+	topicPrefixPersona   = "persona:"
+	topicPrefixTx        = "tx:"
+	topicPrefixEntity    = "entity:"
+)
+
+// EventTypeTopic is the topic for events of the given type.
+func EventTypeTopic(eventType string) Topic {
+	return Topic(topicPrefixEventType + eventType)
+}
+
+// PersonaTopic is the topic for events relevant to the given persona. Receipts of transactions signed by a persona are
+// published to its topic.
+func PersonaTopic(personaTag string) Topic {
+	return Topic(topicPrefixPersona + personaTag)
+}
+
+// TxTopic is the topic for events relevant to the given transaction. The receipt of a transaction is published to its
+// topic.
+func TxTopic(hash TxHash) Topic {
+	return Topic(topicPrefixTx + string(hash))
+}
+
+// EntityTopic is the topic for events relevant to the given entity.
+func EntityTopic(id EntityID) Topic {
+	return Topic(topicPrefixEntity + strconv.FormatUint(uint64(id), 10))
+}
@@ -1,3 +1,18 @@
 package types
 
 type TxHash string
+
+// TxStatus is the status of a transaction submitted to a world.
+type TxStatus string
+
+const (
+	// TxStatusPending is the status of a transaction that is waiting in the pool, or is being handled by a tick that
+	// is not finalized yet.
+	TxStatusPending TxStatus = "pending"
+	// TxStatusProcessed is the status of a transaction that was handled without errors.
+	TxStatusProcessed TxStatus = "processed"
+	// TxStatusFailed is the status of a transaction that was handled with errors.
+	TxStatusFailed TxStatus = "failed"
+	// TxStatusUnknown is the status of a transaction that was never submitted, or whose receipt is no longer kept.
+	TxStatusUnknown TxStatus = "unknown"
+)
@@ -12,6 +12,7 @@ var NonFatalError = []error{
 	ErrComponentNotOnEntity,
 	ErrComponentAlreadyOnEntity,
 	ErrEntityMustHaveAtLeastOneComponent,
+	ErrRelationshipCycle,
 }
 
 // separateOptions separates the given options into ecs options, server options, and cardinal (this package) options.
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"os"
 	"os/signal"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
@@ -29,6 +30,7 @@ import (
 	"pkg.world.dev/world-engine/cardinal/server"
 	"pkg.world.dev/world-engine/cardinal/server/handler/cql"
 	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/storage"
 	"pkg.world.dev/world-engine/cardinal/storage/redis"
 	"pkg.world.dev/world-engine/cardinal/telemetry"
 	"pkg.world.dev/world-engine/cardinal/txpool"
@@ -55,9 +57,26 @@ type World struct {
 	rollupEnabled bool
 	cancel        context.CancelFunc
 
+	// World resources, keyed by name
+	resources map[string]types.ComponentMetadata
+
 	// Storage
-	redisStorage *redis.Storage
-	entityStore  gamestate.Manager
+	entityStore gamestate.Manager
+	// metaStorage keeps the nonces and the component schemas, in Redis or in the storage backend.
+	metaStorage storage.Storage
+	// storageBackend is only set when the game state is kept in a non-Redis storage via WithStorageBackend.
+	storageBackend gamestate.PrimitiveStorage[string]
+
+	// Snapshots
+	snapshotStore      gamestate.SnapshotStore
+	snapshotInterval   uint64
+	snapshotInProgress *atomic.Bool
+	snapshotWaitGroup  *sync.WaitGroup
+
+	// State history
+	stateHistorySize uint64
+	stateHashSize    uint64
+	stateRootSize    uint64
 
 	// Networking
 	server        *server.Server
@@ -67,10 +86,16 @@ type World struct {
 	worldStage *worldstage.Manager
 	router     router.Router
 	txPool     *txpool.TxPool
+	// txPoolWALPath is the file of the write-ahead log of the transaction pool, if it is set with WithTxPoolWAL.
+	txPoolWALPath string
 
 	// Receipt
 	receiptHistory *receipt.History
 	evmTxReceipts  map[string]EVMTxReceipt
+	// receiptStore is only set when receipts are persisted via WithReceiptStore or WithReceiptStoreTTL.
+	receiptStore     *receipt.Store
+	receiptStoreSize uint64
+	receiptStoreTTL  time.Duration
 
 	// Telemetry
 	telemetry *telemetry.Manager
@@ -112,19 +137,6 @@ func NewWorld(opts ...WorldOption) (*World, error) {
 		}
 	}
 
-	redisMetaStore := redis.NewRedisStorage(redis.Options{
-		Addr:        cfg.RedisAddress,
-		Password:    cfg.RedisPassword,
-		DB:          0,                              // use default DB
-		DialTimeout: RedisDialTimeOut * time.Second, // Increase startup dial timeout
-	}, cfg.CardinalNamespace)
-
-	redisStore := gamestate.NewRedisPrimitiveStorage(redisMetaStore.Client)
-	entityCommandBuffer, err := gamestate.NewEntityCommandBuffer(&redisStore)
-	if err != nil {
-		return nil, err
-	}
-
 	tick := new(atomic.Uint64)
 	world := &World{
 		namespace:     Namespace(cfg.CardinalNamespace),
@@ -132,8 +144,18 @@ func NewWorld(opts ...WorldOption) (*World, error) {
 		cancel:        nil,
 
 		// Storage
-		redisStorage: &redisMetaStore,
-		entityStore:  entityCommandBuffer,
+		entityStore:    nil, // Will be set by initStorage unless it is injected via options
+		metaStorage:    nil, // Will be set by initStorage
+		storageBackend: nil, // Will be set if a storage backend is injected via options
+
+		// Snapshots
+		snapshotStore:      nil, // Will be set if snapshots are enabled via options
+		snapshotInterval:   0,
+		snapshotInProgress: new(atomic.Bool),
+		snapshotWaitGroup:  new(sync.WaitGroup),
+
+		// State history
+		stateHistorySize: 0, // Will be set if state history is enabled via options
 
 		// Networking
 		server:        nil, // Will be initialized in StartGame
@@ -143,8 +165,9 @@ func NewWorld(opts ...WorldOption) (*World, error) {
 		worldStage:       worldstage.NewManager(),
 		MessageManager:   newMessageManager(),
 		SystemManager:    newSystemManager(),
-		ComponentManager: component.NewManager(&redisMetaStore),
+		ComponentManager: nil, // Will be set by initStorage
 		QueryManager:     nil,
+		resources:        make(map[string]types.ComponentMetadata),
 		router:           nil, // Will be set if run mode is production or its injected via options
 		txPool:           txpool.New(),
 
@@ -186,6 +209,18 @@ func NewWorld(opts ...WorldOption) (*World, error) {
 		opt(world)
 	}
 
+	if err := world.initStorage(cfg); err != nil {
+		return nil, err
+	}
+
+	if world.txPoolWALPath != "" {
+		wal, err := txpool.NewFileWAL(world.txPoolWALPath)
+		if err != nil {
+			return nil, eris.Wrap(err, "failed to open the transaction pool log")
+		}
+		txpool.WithWAL(wal)(world.txPool)
+	}
+
 	// Register internal plugins
 	world.RegisterPlugin(newPersonaPlugin())
 	world.RegisterPlugin(newFutureTaskPlugin())
@@ -193,6 +228,40 @@ func NewWorld(opts ...WorldOption) (*World, error) {
 	return world, nil
 }
 
+// initStorage sets up the storage of the game state, the nonces, the component schemas and the receipts. Everything is
+// kept in Redis, unless a storage backend is set with WithStorageBackend, in which case Redis is not used at all.
+func (w *World) initStorage(cfg *WorldConfig) error {
+	var primitiveStorage gamestate.PrimitiveStorage[string]
+	if w.storageBackend != nil {
+		primitiveStorage = w.storageBackend
+		w.metaStorage = gamestate.NewMetaStorage(w.storageBackend)
+	} else {
+		redisMetaStore := redis.NewRedisStorage(redis.Options{
+			Addr:        cfg.RedisAddress,
+			Password:    cfg.RedisPassword,
+			DB:          0,                              // use default DB
+			DialTimeout: RedisDialTimeOut * time.Second, // Increase startup dial timeout
+		}, cfg.CardinalNamespace)
+		redisStore := gamestate.NewRedisPrimitiveStorage(redisMetaStore.Client)
+		primitiveStorage = &redisStore
+		w.metaStorage = &redisMetaStore
+	}
+
+	if w.entityStore == nil {
+		entityCommandBuffer, err := gamestate.NewEntityCommandBuffer(primitiveStorage)
+		if err != nil {
+			return eris.Wrap(err, "failed to create entity command buffer")
+		}
+		w.entityStore = entityCommandBuffer
+	}
+	w.ComponentManager = component.NewManager(w.metaStorage)
+
+	if w.receiptStoreSize > 0 || w.receiptStoreTTL > 0 {
+		w.receiptStore = receipt.NewStore(primitiveStorage, w.receiptStoreSize, w.receiptStoreTTL)
+	}
+	return nil
+}
+
 func (w *World) CurrentTick() uint64 {
 	return w.tick.Load()
 }
@@ -223,7 +292,23 @@ func (w *World) doTick(ctx context.Context, timestamp uint64) (err error) {
 	defer w.handleTickPanic()
 
 	// Copy the transactions from the pool so that we can safely modify the pool while the tick is running.
-	txPool := w.txPool.CopyTransactions(ctx)
+	var txPool *txpool.TxPool
+	if w.worldStage.Current() == worldstage.Recovering {
+		// The recovered transactions were all handled in the same tick, so the per-tick limits do not apply.
+		txPool = w.txPool.CopyTransactions(ctx)
+	} else {
+		txPool, err = w.txPool.SelectTransactions(ctx, w.CurrentTick())
+		if err != nil {
+			span.SetStatus(codes.Error, eris.ToString(err, true))
+			span.RecordError(err)
+			return eris.Wrap(err, "failed to select the transactions of the tick")
+		}
+		for _, tx := range txPool.Deferred() {
+			w.receiptHistory.SetDeferred(tx.TxHash)
+		}
+	}
+	// The transactions are no longer pending once the tick completes, or if it fails.
+	defer w.txPool.CompleteTick()
 
 	// Store the timestamp for this tick
 	w.timestamp.Store(timestamp)
@@ -239,20 +324,42 @@ func (w *World) doTick(ctx context.Context, timestamp uint64) (err error) {
 		return err
 	}
 
+	// The state changes must be collected before they are committed by FinalizeTick.
+	stateChanges := w.pendingStateChanges(ctx)
+
 	if err := w.entityStore.FinalizeTick(ctx); err != nil {
 		span.SetStatus(codes.Error, eris.ToString(err, true))
 		span.RecordError(err)
 		return err
 	}
 
+	w.takeSnapshot(ctx, w.CurrentTick()+1)
+
 	w.setEvmResults(txPool.GetEVMTxs())
 
+	// The transactions that no system set a result or an error for were handled as well.
+	for _, txs := range txPool.Transactions() {
+		for _, tx := range txs {
+			w.receiptHistory.SetProcessed(tx.TxHash)
+		}
+	}
+
 	// Handle tx data blob submission
 	// Only submit transactions when the following criteria is satisfied:
 	// 1. The shard router is set
 	// 2. The world is not in the recovering stage (we don't want to resubmit past transactions)
 	if w.router != nil && w.worldStage.Current() != worldstage.Recovering {
-		err := w.router.SubmitTxBlob(ctx, txPool.Transactions(), w.tick.Load(), w.timestamp.Load())
+		// The state root is only submitted if state roots are enabled with WithStateRoots.
+		var stateRoot []byte
+		if w.stateRootSize > 0 {
+			var err error
+			if stateRoot, err = w.StateRoot(w.tick.Load()); err != nil {
+				span.SetStatus(codes.Error, eris.ToString(err, true))
+				span.RecordError(err)
+				return eris.Wrap(err, "failed to get state root")
+			}
+		}
+		err := w.router.SubmitTxBlob(ctx, txPool.Transactions(), w.tick.Load(), w.timestamp.Load(), stateRoot)
 		if err != nil {
 			span.SetStatus(codes.Error, eris.ToString(err, true))
 			span.RecordError(err)
@@ -263,16 +370,19 @@ func (w *World) doTick(ctx context.Context, timestamp uint64) (err error) {
 	// Increment the tick
 	w.tick.Add(1)
 	w.receiptHistory.NextTick() // todo(scott): use channels
+	w.storeReceipts(ctx, w.CurrentTick()-1)
 
 	if w.worldStage.Current() != worldstage.Recovering {
 		// Populate world.TickResults for the current tick and emit it as an Event
-		w.broadcastTickResults(ctx)
+		w.broadcastTickResults(ctx, txPool)
+		w.broadcastStateChanges(stateChanges)
 	}
 
 	log.Info().
 		Int("tick", int(w.CurrentTick()-1)).
 		Str("duration", time.Since(startTime).String()).
 		Int("tx_count", txPool.GetAmountOfTxs()).
+		Int("deferred_tx_count", len(txPool.Deferred())).
 		Msg("Tick completed")
 
 	return nil
@@ -303,6 +413,44 @@ func (w *World) StartGame() error {
 		return errors.New("game has already been started")
 	}
 
+	if err := w.SystemManager.sortSystems(); err != nil {
+		return err
+	}
+
+	if w.stateHistorySize > 0 {
+		historical, ok := w.entityStore.(gamestate.HistoricalManager)
+		if !ok {
+			return eris.New("state history is not supported by the configured game state manager")
+		}
+		historical.SetHistorySize(w.stateHistorySize)
+	}
+
+	if w.stateHashSize > 0 {
+		hasher, ok := w.entityStore.(gamestate.StateHasher)
+		if !ok {
+			return eris.New("state hashes are not supported by the configured game state manager")
+		}
+		hasher.SetStateHashSize(w.stateHashSize)
+	}
+
+	if w.stateRootSize > 0 {
+		committer, ok := w.entityStore.(gamestate.StateCommitter)
+		if !ok {
+			return eris.New("state roots are not supported by the configured game state manager")
+		}
+		committer.SetStateRootSize(w.stateRootSize)
+	}
+
+	// Restore the game state from the latest snapshot (if any) before the saved state is loaded.
+	if err := w.restoreFromSnapshot(ctx); err != nil {
+		return eris.Wrap(err, "failed to restore from snapshot")
+	}
+
+	// Migrate the stored values of components whose schema changed before the saved state is loaded.
+	if _, err := w.migrateComponents(ctx, false); err != nil {
+		return eris.Wrap(err, "failed to migrate components")
+	}
+
 	// TODO(scott): entityStore.RegisterComponents is ambiguous with cardinal.RegisterComponent.
 	//  We should probably rename this to LoadComponents or something.
 	if err := w.entityStore.RegisterComponents(w.GetComponents()); err != nil {
@@ -341,6 +489,17 @@ func (w *World) StartGame() error {
 	//  receiptHistory tick separately.
 	w.receiptHistory.SetTick(w.CurrentTick())
 
+	// The server is created before the transaction pool is restored, which validates the restored transactions.
+	w.server, err = server.New(w, w.GetRegisteredComponents(), w.GetRegisteredMessages(), w.serverOptions...)
+	if err != nil {
+		return err
+	}
+
+	// Put the transactions that were accepted but not handled before the last shutdown back in the pool.
+	if err := w.restoreTxPool(); err != nil {
+		return eris.Wrap(err, "failed to restore the transaction pool")
+	}
+
 	// World stage: Ready -> Running
 	w.worldStage.Store(worldstage.Running)
 
@@ -349,10 +508,6 @@ func (w *World) StartGame() error {
 		return w.startGameLoop(ctx, w.tickChannel, w.tickDoneChannel)
 	})
 	g.Go(func() error {
-		w.server, err = server.New(w, w.GetRegisteredComponents(), w.GetRegisteredMessages(), w.serverOptions...)
-		if err != nil {
-			return err
-		}
 		return w.server.Serve(ctx)
 	})
 	if err := g.Wait(); err != nil {
@@ -437,9 +592,19 @@ func (w *World) Shutdown() {
 
 // cleanup is called after StartGame terminates. It does the housekeeping required to cleanly shutdown World.
 func (w *World) cleanup() {
-	if err := w.redisStorage.Close(); err != nil {
+	// The snapshot that is being saved in the background reads from the storage.
+	w.snapshotWaitGroup.Wait()
+	if err := w.metaStorage.Close(); err != nil {
 		log.Error().Err(err).Msg("Failed to close storage connection")
 	}
+	if err := w.txPool.Close(); err != nil {
+		log.Error().Err(err).Msg("Failed to close transaction pool log")
+	}
+	if w.storageBackend != nil {
+		if err := w.storageBackend.Close(context.Background()); err != nil {
+			log.Error().Err(err).Msg("Failed to close storage backend")
+		}
+	}
 	if w.telemetry != nil {
 		if err := w.telemetry.Shutdown(); err != nil {
 			log.Error().Err(err).Msg("Failed to shut down telemetry")
@@ -448,6 +613,32 @@ func (w *World) cleanup() {
 	w.worldStage.Store(worldstage.ShutDown)
 }
 
+// restoreTxPool adds the transactions logged by the write-ahead log of the transaction pool (see WithTxPoolWAL) that
+// were not handled by a finalized tick back to the pool. The transactions are validated again by the server, so the
+// ones that expired or are no longer correctly signed are dropped.
+func (w *World) restoreTxPool() error {
+	restored, err := w.txPool.Restore(w.CurrentTick(), func(id types.MessageID, tx *sign.Transaction) (any, error) {
+		msgType, ok := w.GetMessageByID(id)
+		if !ok {
+			log.Warn().Msgf("Dropping logged transaction %s of unknown message %d", tx.HashHex(), id)
+			return nil, eris.Errorf("message %d is not registered", id)
+		}
+		msg, err := w.server.ValidateRestoredTransaction(msgType, tx)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Dropping logged transaction %s that is no longer valid", tx.HashHex())
+			return nil, err
+		}
+		return msg, nil
+	})
+	if err != nil {
+		return err
+	}
+	if restored > 0 {
+		log.Info().Msgf("Restored %d transactions to the transaction pool", restored)
+	}
+	return nil
+}
+
 func (w *World) handleTickPanic() {
 	if r := recover(); r != nil {
 		log.Error().Msgf(
@@ -495,6 +686,25 @@ func (w *World) AddTransaction(id types.MessageID, v any, sig *sign.Transaction)
 	return tick, txHash
 }
 
+// SubmitTransaction adds a transaction submitted by a client to the transaction pool. Unlike AddTransaction, it fails
+// with txpool.ErrPoolFull or txpool.ErrRateLimited when the limits set by WithTxPoolSize or WithPersonaTxRateLimit
+// are reached. Returns the tick this transaction will be executed in, unless it is deferred to a later tick.
+func (w *World) SubmitTransaction(id types.MessageID, v any, sig *sign.Transaction) (
+	tick uint64, txHash types.TxHash, err error,
+) {
+	tick = w.CurrentTick()
+	txHash, err = w.txPool.SubmitTransaction(id, v, sig)
+	return tick, txHash, err
+}
+
+// SubmitTransactions adds a batch of transactions submitted by clients to the transaction pool. Either all or none
+// of the transactions are added, see SubmitTransaction. The MsgID, Msg and Tx of each TxData must be set.
+func (w *World) SubmitTransactions(txs []txpool.TxData) (tick uint64, txHashes []types.TxHash, err error) {
+	tick = w.CurrentTick()
+	txHashes, err = w.txPool.SubmitTransactions(txs)
+	return tick, txHashes, err
+}
+
 func (w *World) AddEVMTransaction(
 	id types.MessageID,
 	v any,
@@ -509,7 +719,24 @@ func (w *World) AddEVMTransaction(
 }
 
 func (w *World) UseNonce(signerAddress string, nonce uint64) error {
-	return w.redisStorage.UseNonce(signerAddress, nonce)
+	return w.metaStorage.UseNonce(signerAddress, nonce)
+}
+
+// UseIncreasingNonce marks the given nonce of the signer as used, see WithNonceReplayProtection.
+func (w *World) UseIncreasingNonce(signerAddress string, nonce uint64) error {
+	return w.metaStorage.UseIncreasingNonce(signerAddress, nonce)
+}
+
+// ReleaseIncreasingNonces undoes the use of the nonces from first to last of the signer, if it did not use a higher
+// nonce since, see WithNonceReplayProtection.
+func (w *World) ReleaseIncreasingNonces(signerAddress string, first, last uint64) error {
+	return w.metaStorage.ReleaseIncreasingNonces(signerAddress, first, last)
+}
+
+// GetNextNonce returns the lowest nonce the given signer can use in its next transaction, see
+// WithNonceReplayProtection.
+func (w *World) GetNextNonce(signerAddress string) (uint64, error) {
+	return w.metaStorage.GetNextNonce(signerAddress)
 }
 
 func (w *World) GetDebugState() ([]types.DebugStateElement, error) {
@@ -575,6 +802,16 @@ func (w *World) StoreReader() gamestate.Reader {
 	return w.entityStore.ToReadOnly()
 }
 
+// StoreReaderAtTick returns a Reader of the game state as it was right after the given tick was finalized. Only the
+// ticks retained by WithStateHistory are available.
+func (w *World) StoreReaderAtTick(tick uint64) (gamestate.Reader, error) {
+	historical, ok := w.entityStore.(gamestate.HistoricalManager)
+	if !ok {
+		return nil, eris.Wrap(gamestate.ErrTickNotInHistory, "state history is not supported by the game state manager")
+	}
+	return historical.ToReadOnlyAtTick(tick)
+}
+
 func (w *World) GetRegisteredComponents() []types.ComponentMetadata {
 	return w.GetComponents()
 }
@@ -588,7 +825,7 @@ func (w *World) GetMessageByID(id types.MessageID) (types.Message, bool) {
 	return msg, msg != nil
 }
 
-func (w *World) broadcastTickResults(ctx context.Context) {
+func (w *World) broadcastTickResults(ctx context.Context, txPool *txpool.TxPool) {
 	_, span := w.tracer.Start(ddotel.ContextWithStartOptions(ctx, ddtracer.Measured()),
 		"world.tick.broadcast_tick_results")
 	defer span.End()
@@ -602,8 +839,35 @@ func (w *World) broadcastTickResults(ctx context.Context) {
 	w.tickResults.SetReceipts(receipts)
 	w.tickResults.SetTick(w.CurrentTick() - 1)
 
-	// Broadcast the tick results to all clients
-	if err := w.server.BroadcastEvent(w.tickResults); err != nil {
+	// Receipts are published to the topics of their transaction and of the persona that signed it.
+	personaTags := make(map[types.TxHash]string)
+	for _, txs := range txPool.Transactions() {
+		for _, tx := range txs {
+			if tx.Tx != nil {
+				personaTags[tx.TxHash] = tx.Tx.PersonaTag
+			}
+		}
+	}
+	for _, tx := range txPool.Deferred() {
+		if tx.Tx != nil {
+			personaTags[tx.TxHash] = tx.Tx.PersonaTag
+		}
+	}
+	topicReceipts := make([]servertypes.TopicReceipt, 0, len(w.tickResults.Receipts))
+	for _, rec := range w.tickResults.Receipts {
+		topics := []types.Topic{types.TxTopic(rec.TxHash)}
+		if personaTag, ok := personaTags[rec.TxHash]; ok && personaTag != "" {
+			topics = append(topics, types.PersonaTopic(personaTag))
+		}
+		topicReceipts = append(topicReceipts, servertypes.TopicReceipt{Receipt: rec, Topics: topics})
+	}
+	topicEvents := make([]servertypes.TopicEvent, 0, len(w.tickResults.Events))
+	for i, event := range w.tickResults.Events {
+		topicEvents = append(topicEvents, servertypes.TopicEvent{Data: event, Topics: w.tickResults.EventTopics(i)})
+	}
+
+	// Broadcast the tick results to all clients subscribed to them
+	if err := w.server.BroadcastTickResults(w.tickResults.Tick, topicReceipts, topicEvents); err != nil {
 		span.SetStatus(codes.Error, eris.ToString(err, true))
 		span.RecordError(err)
 		log.Err(err).Msgf("failed to broadcast tick results")
@@ -613,11 +877,51 @@ func (w *World) broadcastTickResults(ctx context.Context) {
 	w.tickResults.Clear()
 }
 
+// pendingStateChanges returns the state changes made in the current tick if there are clients subscribed to the state
+// diff stream. Otherwise, nil is returned.
+func (w *World) pendingStateChanges(ctx context.Context) *gamestate.StateChanges {
+	if w.server == nil || !w.server.HasStateDiffSubscribers() {
+		return nil
+	}
+	tracker, ok := w.entityStore.(gamestate.ChangeTracker)
+	if !ok {
+		return nil
+	}
+	changes, err := tracker.PendingChanges(ctx)
+	if err != nil {
+		log.Err(err).Msgf("failed to get state changes for tick %d", w.CurrentTick())
+		return nil
+	}
+	changes.Tick = w.CurrentTick()
+	return changes
+}
+
+func (w *World) broadcastStateChanges(changes *gamestate.StateChanges) {
+	if changes == nil {
+		return
+	}
+	w.server.BroadcastStateDiff(changes)
+}
+
 func (w *World) ReceiptHistorySize() uint64 {
 	return w.receiptHistory.Size()
 }
 
 func (w *World) EvaluateCQL(cqlString string) ([]types.EntityStateElement, error) {
+	return w.evaluateCQL(NewReadOnlyWorldContext(w), cqlString)
+}
+
+// EvaluateCQLAtTick evaluates the CQL query against the game state as it was right after the given tick was
+// finalized.
+func (w *World) EvaluateCQLAtTick(cqlString string, tick uint64) ([]types.EntityStateElement, error) {
+	wCtx, err := NewReadOnlyWorldContextAtTick(w, tick)
+	if err != nil {
+		return nil, err
+	}
+	return w.evaluateCQL(wCtx, cqlString)
+}
+
+func (w *World) evaluateCQL(wCtx WorldContext, cqlString string) ([]types.EntityStateElement, error) {
 	// getComponentByName is a wrapper function that casts component.ComponentMetadata from ctx.getComponentByName
 	// to types.Component
 	getComponentByName := func(name string) (types.Component, error) {
@@ -635,10 +939,9 @@ func (w *World) EvaluateCQL(cqlString string) ([]types.EntityStateElement, error
 	}
 	result := make([]types.EntityStateElement, 0)
 	var eachError error
-	wCtx := NewReadOnlyWorldContext(w)
 	searchErr := w.Search(cqlFilter).Each(wCtx,
 		func(id types.EntityID) bool {
-			components, err := w.StoreReader().GetComponentTypesForEntity(id)
+			components, err := wCtx.storeReader().GetComponentTypesForEntity(id)
 			if err != nil {
 				eachError = err
 				return false
@@ -649,7 +952,7 @@ func (w *World) EvaluateCQL(cqlString string) ([]types.EntityStateElement, error
 			}
 
 			for _, c := range components {
-				data, err := w.StoreReader().GetComponentForEntityInRawJSON(c, id)
+				data, err := wCtx.storeReader().GetComponentForEntityInRawJSON(c, id)
 				if err != nil {
 					eachError = err
 					return false
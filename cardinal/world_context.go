@@ -1,6 +1,7 @@
 package cardinal
 
 import (
+	"hash/fnv"
 	"math/rand"
 	"reflect"
 	"time"
@@ -32,12 +33,14 @@ type WorldContext interface {
 	// Logger returns the logger that can be used to log messages from within system or query.
 	Logger() *zerolog.Logger
 
-	// EmitEvent emits an event that will be broadcast to all websocket subscribers.
-	EmitEvent(map[string]any) error
+	// EmitEvent emits an event that will be broadcast to websocket subscribers. If topics are given (e.g.
+	// types.PersonaTopic), the event is only sent to subscribers of those topics and to subscribers that did not
+	// subscribe to any topic. Events without topics are only sent to subscribers that did not subscribe to any topic.
+	EmitEvent(event map[string]any, topics ...types.Topic) error
 
-	// EmitStringEvent emits a string event that will be broadcast to all websocket subscribers.
+	// EmitStringEvent emits a string event that will be broadcast to websocket subscribers, see EmitEvent.
 	// This method is provided for backwards compatability. EmitEvent should be used for most cases.
-	EmitStringEvent(string) error
+	EmitStringEvent(event string, topics ...types.Topic) error
 
 	// Namespace returns the namespace of the world.
 	Namespace() string
@@ -58,6 +61,7 @@ type WorldContext interface {
 	addMessageError(id types.TxHash, err error)
 	setMessageResult(id types.TxHash, a any)
 	getComponentByName(name string) (types.ComponentMetadata, error)
+	getResourceByName(name string) (types.ComponentMetadata, error)
 	getMessageByType(mType reflect.Type) (types.Message, bool)
 	getTransactionReceipt(id types.TxHash) (any, []error, bool)
 	getSignerForPersonaTag(personaTag string, tick uint64) (addr string, err error)
@@ -69,6 +73,9 @@ type WorldContext interface {
 	storeManager() gamestate.Manager
 	getTxPool() *txpool.TxPool
 	isReadOnly() bool
+	componentAccess() *systemAccess
+	newSystemContext(systemName string, access *systemAccess) WorldContext
+	commitEvents() error
 }
 
 type worldContext struct {
@@ -77,6 +84,17 @@ type worldContext struct {
 	logger   *zerolog.Logger
 	readOnly bool
 	rand     *rand.Rand
+
+	// historicalReader is only set on contexts created by NewReadOnlyWorldContextAtTick. It reads the game state as it
+	// was right after historicalTick was finalized.
+	historicalReader gamestate.Reader
+	historicalTick   uint64
+
+	// access is only set on contexts created by newSystemContext. It restricts the components the system can use.
+	access *systemAccess
+	// events buffers the events emitted by a system that runs concurrently with other systems, so they can be added to
+	// the tick results in a deterministic order once all the systems are done.
+	events *TickResults
 }
 
 func newWorldContextForTick(world *World, txPool *txpool.TxPool) WorldContext {
@@ -110,6 +128,24 @@ func NewReadOnlyWorldContext(world *World) WorldContext {
 	}
 }
 
+// NewReadOnlyWorldContextAtTick returns a read only WorldContext that sees the game state as it was right after the
+// given tick was finalized.
+func NewReadOnlyWorldContextAtTick(world *World, tick uint64) (WorldContext, error) {
+	reader, err := world.StoreReaderAtTick(tick)
+	if err != nil {
+		return nil, err
+	}
+	return &worldContext{
+		world:            world,
+		txPool:           nil,
+		logger:           &log.Logger,
+		readOnly:         true,
+		rand:             nil,
+		historicalReader: reader,
+		historicalTick:   tick,
+	}, nil
+}
+
 // -----------------------------------------------------------------------------
 // Public methods
 // -----------------------------------------------------------------------------
@@ -128,12 +164,18 @@ func (ctx *worldContext) ScheduleTimeTask(duration time.Duration, task Task) err
 	return createTimestampTask(ctx, triggerAtTimestamp, task)
 }
 
-func (ctx *worldContext) EmitEvent(event map[string]any) error {
-	return ctx.world.tickResults.AddEvent(event)
+func (ctx *worldContext) EmitEvent(event map[string]any, topics ...types.Topic) error {
+	if ctx.events != nil {
+		return ctx.events.AddEvent(event, topics...)
+	}
+	return ctx.world.tickResults.AddEvent(event, topics...)
 }
 
-func (ctx *worldContext) EmitStringEvent(e string) error {
-	return ctx.world.tickResults.AddStringEvent(e)
+func (ctx *worldContext) EmitStringEvent(e string, topics ...types.Topic) error {
+	if ctx.events != nil {
+		return ctx.events.AddStringEvent(e, topics...)
+	}
+	return ctx.world.tickResults.AddStringEvent(e, topics...)
 }
 
 func (ctx *worldContext) Timestamp() uint64 {
@@ -141,6 +183,10 @@ func (ctx *worldContext) Timestamp() uint64 {
 }
 
 func (ctx *worldContext) CurrentTick() uint64 {
+	if ctx.historicalReader != nil {
+		// The world's current tick is the tick right after the last finalized one.
+		return ctx.historicalTick + 1
+	}
 	return ctx.world.CurrentTick()
 }
 
@@ -177,6 +223,10 @@ func (ctx *worldContext) getComponentByName(name string) (types.ComponentMetadat
 	return ctx.world.GetComponentByName(name)
 }
 
+func (ctx *worldContext) getResourceByName(name string) (types.ComponentMetadata, error) {
+	return ctx.world.GetResourceByName(name)
+}
+
 func (ctx *worldContext) addMessageError(id types.TxHash, err error) {
 	// TODO(scott): i dont trust exposing this to the users. this should be fully abstracted away.
 	ctx.world.receiptHistory.AddError(id, err)
@@ -219,11 +269,53 @@ func (ctx *worldContext) isReadOnly() bool {
 	return ctx.readOnly
 }
 
+func (ctx *worldContext) componentAccess() *systemAccess {
+	return ctx.access
+}
+
+// newSystemContext returns a copy of the context for a system registered with RegisterSystemsWithAccess. The copy has
+// its own logger, a random number generator seeded from the tick timestamp and the system name, and buffers emitted
+// events until commitEvents is called, so systems running concurrently do not share any mutable state.
+func (ctx *worldContext) newSystemContext(systemName string, access *systemAccess) WorldContext {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(systemName))
+	seed := ctx.world.timestamp.Load() ^ h.Sum64()
+	return &worldContext{
+		world:    ctx.world,
+		txPool:   ctx.txPool,
+		logger:   ctx.logger,
+		readOnly: ctx.readOnly,
+		//nolint:gosec // we require manual in the rng which crypto/rand doesn't have, but math/rand does.
+		rand:             rand.New(rand.NewSource(int64(seed))),
+		historicalReader: ctx.historicalReader,
+		historicalTick:   ctx.historicalTick,
+		access:           access,
+		events:           NewTickResults(0),
+	}
+}
+
+// commitEvents adds the events buffered by a context created by newSystemContext to the tick results.
+func (ctx *worldContext) commitEvents() error {
+	if ctx.events == nil {
+		return nil
+	}
+	for i, e := range ctx.events.Events {
+		if err := ctx.world.tickResults.AddStringEvent(string(e), ctx.events.EventTopics(i)...); err != nil {
+			return err
+		}
+	}
+	ctx.events.Clear()
+	return nil
+}
+
 func (ctx *worldContext) storeManager() gamestate.Manager {
 	return ctx.world.entityStore
 }
 
 func (ctx *worldContext) storeReader() gamestate.Reader {
+	if ctx.historicalReader != nil {
+		return ctx.historicalReader
+	}
 	sm := ctx.storeManager()
 	if ctx.isReadOnly() {
 		return sm.ToReadOnly()
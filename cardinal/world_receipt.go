@@ -1,10 +1,14 @@
 package cardinal
 
 import (
+	"context"
+
 	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
 
 	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/txpool"
+	"pkg.world.dev/world-engine/cardinal/types"
 )
 
 type EVMTxReceipt struct {
@@ -13,8 +17,148 @@ type EVMTxReceipt struct {
 	EVMTxHash string
 }
 
+// GetTransactionReceiptsForTick returns the receipts of the given tick. When receipts are persisted (see
+// WithReceiptStore), the receipts of ticks that are no longer kept in memory are read from the receipt store.
 func (w *World) GetTransactionReceiptsForTick(tick uint64) ([]receipt.Receipt, error) {
-	return w.receiptHistory.GetReceiptsForTick(tick)
+	recs, err := w.receiptHistory.GetReceiptsForTick(tick)
+	if w.receiptStore == nil || tick >= w.CurrentTick() || (err == nil && len(recs) > 0) {
+		return recs, err
+	}
+	// The receipts were discarded from memory, or the world restarted since the tick.
+	return w.receiptStore.GetReceiptsForTick(context.Background(), tick)
+}
+
+// GetTransactionReceiptsForTicks returns the receipts of the ticks from startTick up to but not including endTick that
+// are still retained, in tick order, and the first of these ticks. Ticks without receipts are left out. When receipts
+// are persisted (see WithReceiptStore), the receipts of the ticks that are no longer kept in memory are read from the
+// receipt store in a few round trips.
+func (w *World) GetTransactionReceiptsForTicks(
+	startTick, endTick uint64,
+) (uint64, []receipt.TickReceipts, error) {
+	ctx := context.Background()
+	current := w.CurrentTick()
+	endTick = min(endTick, current)
+	size := w.receiptHistory.Size()
+	// The ticks from memStart are kept in memory.
+	memStart := max(current+1, size) - size
+	firstTick := max(startTick, memStart)
+
+	var ticks []receipt.TickReceipts
+	if w.receiptStore != nil {
+		oldest, err := w.receiptStore.OldestTick(ctx)
+		if err == nil {
+			firstTick = max(startTick, min(oldest, memStart))
+			// Only the receipts of the last tick may not be saved yet, and the store also has the receipts of the
+			// ticks before a restart.
+			memStart = max(current, 1) - 1
+			ticks, err = w.receiptStore.GetReceiptsForTicks(ctx, firstTick, min(memStart, endTick))
+		}
+		if err != nil && !eris.Is(err, receipt.ErrReceiptNotFound) {
+			return 0, nil, err
+		}
+	}
+	for tick := max(firstTick, memStart); tick < endTick; tick++ {
+		recs, err := w.receiptHistory.GetReceiptsForTick(tick)
+		// The tick was discarded while the receipts were read.
+		if err != nil || len(recs) == 0 {
+			continue
+		}
+		ticks = append(ticks, receipt.TickReceipts{Tick: tick, Receipts: recs})
+	}
+	return min(firstTick, endTick), ticks, nil
+}
+
+// GetTransactionReceipt returns the receipt of the given transaction, and the tick in which it was handled. Only the
+// ticks kept in memory and, when receipts are persisted, the ticks kept in the receipt store are searched.
+// receipt.ErrReceiptNotFound is returned if the receipt can not be found.
+func (w *World) GetTransactionReceipt(txHash types.TxHash) (receipt.Receipt, uint64, error) {
+	// The receipts of the most recent ticks are searched first, so a deferred transaction gets the receipt of the
+	// tick that handled it.
+	for i := uint64(1); i < w.receiptHistory.Size() && i <= w.CurrentTick(); i++ {
+		tick := w.CurrentTick() - i
+		recs, err := w.receiptHistory.GetReceiptsForTick(tick)
+		if err != nil {
+			break
+		}
+		for _, rec := range recs {
+			if rec.TxHash == txHash {
+				return rec, tick, nil
+			}
+		}
+	}
+	if w.receiptStore == nil {
+		return receipt.Receipt{}, 0, eris.Wrapf(receipt.ErrReceiptNotFound, "tx %s", txHash)
+	}
+	return w.receiptStore.GetReceipt(context.Background(), txHash)
+}
+
+// GetTransactionStatus returns the status of the given transaction. The receipt of the transaction and the tick in
+// which it was handled are returned as well when the transaction was handled.
+func (w *World) GetTransactionStatus(txHash types.TxHash) (types.TxStatus, *receipt.Receipt, uint64, error) {
+	// The pool is checked first: a transaction leaves the pool only once its receipt is available.
+	if w.txPool.IsPending(txHash) {
+		return types.TxStatusPending, nil, 0, nil
+	}
+	rec, tick, err := w.GetTransactionReceipt(txHash)
+	if eris.Is(err, receipt.ErrReceiptNotFound) {
+		return types.TxStatusUnknown, nil, 0, nil
+	} else if err != nil {
+		return "", nil, 0, err
+	}
+	if rec.Deferred {
+		// The transaction was deferred, but it is no longer in the pool (e.g. it was lost in a restart).
+		return types.TxStatusUnknown, nil, 0, nil
+	}
+	if len(rec.Errs) > 0 {
+		return types.TxStatusFailed, &rec, tick, nil
+	}
+	return types.TxStatusProcessed, &rec, tick, nil
+}
+
+// WaitForTransaction returns the status of the given transaction like GetTransactionStatus, but while the transaction
+// is pending it first waits for the ticks to complete, until the transaction is handled or the context is done.
+func (w *World) WaitForTransaction(
+	ctx context.Context, txHash types.TxHash,
+) (types.TxStatus, *receipt.Receipt, uint64, error) {
+	for {
+		// The channel is added before the status is checked, so a tick that completes in between is not missed.
+		startTick := w.CurrentTick()
+		ch := make(chan struct{})
+		select {
+		case w.addChannelWaitingForNextTick <- ch:
+		case <-ctx.Done():
+			return w.GetTransactionStatus(txHash)
+		}
+		status, rec, tick, err := w.GetTransactionStatus(txHash)
+		if err != nil || status != types.TxStatusPending {
+			return status, rec, tick, err
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return w.GetTransactionStatus(txHash)
+		}
+		// The world was shut down.
+		if w.CurrentTick() == startTick {
+			return w.GetTransactionStatus(txHash)
+		}
+	}
+}
+
+// storeReceipts saves the receipts of the given tick to the receipt store, if receipts are persisted. Receipts are
+// saved in the recovering stage as well, so the receipts of the recovered ticks are generated again.
+func (w *World) storeReceipts(ctx context.Context, tick uint64) {
+	if w.receiptStore == nil {
+		return
+	}
+	recs, err := w.receiptHistory.GetReceiptsForTick(tick)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to get receipts for tick %d", tick)
+		return
+	}
+	if err := w.receiptStore.SaveReceipts(ctx, tick, recs); err != nil {
+		log.Error().Err(err).Msgf("failed to store receipts for tick %d", tick)
+	}
 }
 
 // ConsumeEVMMsgResult consumes a tx result from an EVM originated Cardinal message.
@@ -1,12 +1,16 @@
 package cardinal_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/filter"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
 	"pkg.world.dev/world-engine/cardinal/router/iterator"
 	iteratormocks "pkg.world.dev/world-engine/cardinal/router/iterator/mocks"
 	"pkg.world.dev/world-engine/cardinal/router/mocks"
@@ -81,7 +85,7 @@ func TestWorldRecovery(t *testing.T) {
 	router.EXPECT().Start().Times(1)
 	router.EXPECT().RegisterGameShard(gomock.Any()).Times(1)
 	router.EXPECT().
-		SubmitTxBlob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		SubmitTxBlob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(nil).AnyTimes()
 
 	tf.StartWorld()
@@ -91,3 +95,63 @@ func TestWorldRecovery(t *testing.T) {
 
 	controller.Finish()
 }
+
+func TestWorldRecoveryStartsFromLatestSnapshot(t *testing.T) {
+	store, err := gamestate.NewFileSnapshotStore(t.TempDir(), gamestate.DefaultSnapshotsToRetain)
+	assert.NilError(t, err)
+	incrementCounter := func(wCtx cardinal.WorldContext) error {
+		id := cardinal.NewSearch().Entity(filter.Exact(filter.Component[CounterComponent]())).MustFirst(wCtx)
+		return cardinal.UpdateComponent[CounterComponent](wCtx, id, func(c *CounterComponent) *CounterComponent {
+			c.Count++
+			return c
+		})
+	}
+
+	// Run a world that saves a snapshot every 2 ticks.
+	tf1 := cardinal.NewTestFixture(t, nil, cardinal.WithSnapshots(store, 2))
+	assert.NilError(t, cardinal.RegisterComponent[CounterComponent](tf1.World))
+	assert.NilError(t, cardinal.RegisterSystems(tf1.World, incrementCounter))
+	tf1.StartWorld()
+	id, err := cardinal.Create(cardinal.NewWorldContext(tf1.World), CounterComponent{})
+	assert.NilError(t, err)
+	for i := 0; i < 5; i++ {
+		tf1.DoTick()
+		// Snapshots are saved in the background, and a snapshot is skipped while the previous one is being saved.
+		if i%2 == 1 {
+			waitForSnapshot(t, store, uint64(i+1))
+		}
+	}
+
+	// Start a new world with empty storage. The state should be restored from the snapshot taken after tick 3, and
+	// only the ticks after the snapshot should be replayed from the base shard.
+	setEnvToCardinalRollupMode(t)
+	controller := gomock.NewController(t)
+	router := mocks.NewMockRouter(controller)
+	iter := iteratormocks.NewMockIterator(controller)
+	iter.EXPECT().Each(gomock.Any(), uint64(4)).Return(nil).Times(1)
+	router.EXPECT().TransactionIterator().Return(iter).Times(1)
+	router.EXPECT().Start().Times(1)
+	router.EXPECT().RegisterGameShard(gomock.Any()).Times(1)
+
+	tf2 := cardinal.NewTestFixture(t, nil, cardinal.WithSnapshots(store, 2), cardinal.WithCustomRouter(router))
+	assert.NilError(t, cardinal.RegisterComponent[CounterComponent](tf2.World))
+	assert.NilError(t, cardinal.RegisterSystems(tf2.World, incrementCounter))
+	tf2.StartWorld()
+
+	assert.Equal(t, uint64(4), tf2.World.CurrentTick())
+	counter, err := cardinal.GetComponent[CounterComponent](cardinal.NewReadOnlyWorldContext(tf2.World), id)
+	assert.NilError(t, err)
+	assert.Equal(t, 4, counter.Count)
+
+	controller.Finish()
+}
+
+func waitForSnapshot(t *testing.T, store gamestate.SnapshotStore, tick uint64) {
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		snapshot, err := store.Latest(context.Background())
+		if err == nil && snapshot.Tick == tick {
+			return
+		}
+		assert.Assert(t, time.Since(start) < 5*time.Second, "snapshot of tick %d was not saved", tick)
+	}
+}
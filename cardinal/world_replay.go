@@ -0,0 +1,153 @@
+package cardinal
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/router/iterator"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// errStopReplay is returned from the transaction iterator callback to stop the replay early.
+var errStopReplay = eris.New("stop replay")
+
+// DivergenceReport is the result of a replay.
+type DivergenceReport struct {
+	FromTick uint64
+	ToTick   uint64
+	// Diverged is true if the state hash of a replayed tick differs from the recorded hash of that tick.
+	Diverged bool
+	// Tick is the first tick whose hashes differ. It is only set if Diverged is true.
+	Tick         uint64
+	ExpectedHash []byte
+	ActualHash   []byte
+}
+
+// StateHashSource returns the state hash that was recorded for the given tick, e.g. World.StateHash of the live world
+// or gamestate.LoadStateHash on a copy of its storage.
+type StateHashSource func(tick uint64) ([]byte, error)
+
+// StateHash returns the hash of the game state at the end of the given tick. The world must be created with the
+// WithStateHashes option.
+func (w *World) StateHash(tick uint64) ([]byte, error) {
+	hasher, ok := w.entityStore.(gamestate.StateHasher)
+	if !ok {
+		return nil, eris.New("state hashes are not supported by the configured game state manager")
+	}
+	return hasher.StateHash(tick)
+}
+
+// ReplayAndCheck replays the transactions of ticks 0 to toTick returned by txs against w, and compares the state hash
+// of every replayed tick from fromTick onwards with the hash returned by recorded. The replay stops at the first tick
+// whose hashes differ, which is returned in the report.
+//
+// w must be a fresh world with an empty state, e.g. a world created with
+// WithStorageBackend(gamestate.NewBadgerPrimitiveStorage("")), that has the same components, messages and systems as
+// the game that recorded the hashes. It must not be started; it is shut down once the replay is done.
+//
+// Usage:
+//
+// txs := iterator.New(world.GetMessageByID, namespace, shard.NewTransactionHandlerClient(conn))
+// report, err := cardinal.ReplayAndCheck(ctx, world, txs, recordedHashes, 100, 200)
+func ReplayAndCheck(
+	ctx context.Context, w *World, txs iterator.Iterator, recorded StateHashSource, fromTick, toTick uint64,
+) (*DivergenceReport, error) {
+	if fromTick > toTick {
+		return nil, eris.Errorf("invalid tick range: %d to %d", fromTick, toTick)
+	}
+	if !w.worldStage.CompareAndSwap(worldstage.Init, worldstage.Starting) {
+		return nil, eris.Errorf("world state is %s, expected %s to replay", w.worldStage.Current(), worldstage.Init)
+	}
+	defer w.cleanup()
+
+	if err := w.SystemManager.sortSystems(); err != nil {
+		return nil, err
+	}
+	hasher, ok := w.entityStore.(gamestate.StateHasher)
+	if !ok {
+		return nil, eris.New("state hashes are not supported by the configured game state manager")
+	}
+	hasher.SetStateHashSize(toTick + 1)
+	if err := w.entityStore.RegisterComponents(w.GetComponents()); err != nil {
+		return nil, eris.Wrap(err, "failed to register components")
+	}
+	lastTick, err := w.entityStore.GetLastFinalizedTick()
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to get latest finalized tick")
+	}
+	if lastTick != 0 {
+		return nil, eris.Errorf("replay world must have an empty state, but tick %d is already finalized", lastTick)
+	}
+	w.worldStage.Store(worldstage.Recovering)
+
+	report := &DivergenceReport{FromTick: fromTick, ToTick: toTick}
+	// runTick runs the next tick and compares its state hash with the recorded hash.
+	runTick := func(timestamp uint64) error {
+		tick := w.CurrentTick()
+		if err := w.doTick(ctx, timestamp); err != nil {
+			return eris.Wrap(err, "failed to tick world")
+		}
+		if tick < fromTick {
+			return nil
+		}
+		expected, err := recorded(tick)
+		if err != nil {
+			return eris.Wrapf(err, "failed to get recorded state hash of tick %d", tick)
+		}
+		actual, err := hasher.StateHash(tick)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(expected, actual) {
+			report.Diverged = true
+			report.Tick = tick
+			report.ExpectedHash = expected
+			report.ActualHash = actual
+			return errStopReplay
+		}
+		if tick == toTick {
+			return errStopReplay
+		}
+		return nil
+	}
+
+	// Ticks without transactions are not stored by the base shard, so they are replayed with the timestamp of the
+	// next tick that has transactions.
+	var lastTimestamp uint64
+	err = txs.Each(func(batches []*iterator.TxBatch, tick, timestamp uint64) error {
+		if err := ctx.Err(); err != nil {
+			return eris.Wrap(err, "context cancelled, terminating replay")
+		}
+		lastTimestamp = timestamp
+		for w.CurrentTick() < tick {
+			if err := runTick(timestamp); err != nil {
+				return err
+			}
+		}
+		for _, batch := range batches {
+			w.AddTransaction(batch.MsgID, batch.MsgValue, batch.Tx)
+		}
+		log.Debug().Msgf("Replaying tick %d", tick)
+		return runTick(timestamp)
+	}, 0, toTick)
+	if eris.Is(err, errStopReplay) {
+		return report, nil
+	} else if err != nil {
+		return nil, eris.Wrap(err, "encountered an error while replaying transactions")
+	}
+
+	// Replay the empty ticks after the last tick with transactions.
+	for w.CurrentTick() <= toTick {
+		if err := runTick(lastTimestamp); err != nil {
+			if eris.Is(err, errStopReplay) {
+				break
+			}
+			return nil, err
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,96 @@
+package cardinal_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/router/iterator"
+	iteratormocks "pkg.world.dev/world-engine/cardinal/router/iterator/mocks"
+	"pkg.world.dev/world-engine/sign"
+)
+
+type ReplayCounter struct {
+	Value int
+}
+
+func (ReplayCounter) Name() string { return "replay_counter" }
+
+type replayMsg struct {
+	Amount int
+}
+
+type replayResult struct{}
+
+// newReplayWorld creates a world that creates a counter for each replay message. The value of the counter is the
+// amount of the message passed through valueOf.
+func newReplayWorld(t *testing.T, valueOf func(int) int, opts ...cardinal.WorldOption) *cardinal.TestFixture {
+	tf := cardinal.NewTestFixture(t, nil, opts...)
+	assert.NilError(t, cardinal.RegisterComponent[ReplayCounter](tf.World))
+	assert.NilError(t, cardinal.RegisterMessage[replayMsg, replayResult](tf.World, "replay"))
+	assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx cardinal.WorldContext) error {
+		return cardinal.EachMessage[replayMsg, replayResult](wCtx,
+			func(tx cardinal.TxData[replayMsg]) (replayResult, error) {
+				_, err := cardinal.Create(wCtx, ReplayCounter{Value: valueOf(tx.Msg.Amount)})
+				return replayResult{}, err
+			})
+	}))
+	return tf
+}
+
+func TestReplayAndCheck(t *testing.T) {
+	identity := func(amount int) int { return amount }
+	live := newReplayWorld(t, identity, cardinal.WithStateHashes(10))
+	msg, ok := live.World.GetMessageByFullName("game.replay")
+	assert.Assert(t, ok)
+
+	// Run 4 ticks in the live world, with transactions in ticks 0 and 2.
+	txs := map[uint64][]replayMsg{0: {{Amount: 1}}, 2: {{Amount: 2}, {Amount: 3}}}
+	for tick := uint64(0); tick < 4; tick++ {
+		for _, m := range txs[tick] {
+			live.AddTransaction(msg.ID(), m, &sign.Transaction{PersonaTag: "ty"})
+		}
+		live.DoTick()
+	}
+
+	newIterator := func() iterator.Iterator {
+		iter := iteratormocks.NewMockIterator(gomock.NewController(t))
+		iter.EXPECT().Each(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(fn func(batch []*iterator.TxBatch, tick, timestamp uint64) error, _ ...uint64) error {
+				for _, tick := range []uint64{0, 2} {
+					var batches []*iterator.TxBatch
+					for _, m := range txs[tick] {
+						batches = append(batches, &iterator.TxBatch{
+							Tx:       &sign.Transaction{PersonaTag: "ty"},
+							MsgID:    msg.ID(),
+							MsgValue: m,
+						})
+					}
+					if err := fn(batches, tick, 1577883100+tick); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		return iter
+	}
+
+	// Replaying the same game produces the same state hashes.
+	replay := newReplayWorld(t, identity)
+	report, err := cardinal.ReplayAndCheck(context.Background(), replay.World, newIterator(), live.World.StateHash, 0, 3)
+	assert.NilError(t, err)
+	assert.Check(t, !report.Diverged)
+	assert.Equal(t, uint64(4), replay.World.CurrentTick())
+
+	// A game that handles the message of tick 2 differently diverges at tick 2.
+	capped := newReplayWorld(t, func(amount int) int { return min(amount, 2) })
+	report, err = cardinal.ReplayAndCheck(context.Background(), capped.World, newIterator(), live.World.StateHash, 1, 3)
+	assert.NilError(t, err)
+	assert.Check(t, report.Diverged)
+	assert.Equal(t, uint64(2), report.Tick)
+	assert.Check(t, len(report.ExpectedHash) > 0)
+	assert.NotEqual(t, report.ExpectedHash, report.ActualHash)
+}
@@ -0,0 +1,105 @@
+package cardinal
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/codes"
+	ddotel "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentelemetry"
+	ddtracer "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+)
+
+// takeSnapshot starts a snapshot of the finalized game state if snapshots are enabled and the given tick (the next
+// tick that will be executed) falls on the snapshot interval. The snapshot is of the state at the end of the tick that
+// was just finalized, but it is read and saved in the background so the game loop does not wait for it. A snapshot is
+// skipped if the previous one is still being saved. A failed snapshot is logged but does not halt the game.
+func (w *World) takeSnapshot(ctx context.Context, tick uint64) {
+	if w.snapshotStore == nil || w.snapshotInterval == 0 || tick%w.snapshotInterval != 0 {
+		return
+	}
+
+	snapshotter, ok := w.entityStore.(gamestate.Snapshotter)
+	if !ok {
+		log.Error().Msg("game state manager does not support snapshots")
+		return
+	}
+	if !w.snapshotInProgress.CompareAndSwap(false, true) {
+		log.Warn().Msgf("skipping snapshot at tick %d, the previous snapshot is still being saved", tick)
+		return
+	}
+	read, err := snapshotter.StartSnapshot(ctx)
+	if err != nil {
+		w.snapshotInProgress.Store(false)
+		log.Error().Err(err).Msgf("failed to start snapshot at tick %d", tick)
+		return
+	}
+
+	w.snapshotWaitGroup.Add(1)
+	go func() {
+		defer w.snapshotWaitGroup.Done()
+		defer w.snapshotInProgress.Store(false)
+
+		ctx, span := w.tracer.Start(ddotel.ContextWithStartOptions(context.WithoutCancel(ctx), ddtracer.Measured()),
+			"world.snapshot")
+		defer span.End()
+
+		if err := w.saveSnapshot(ctx, read); err != nil {
+			span.SetStatus(codes.Error, eris.ToString(err, true))
+			span.RecordError(err)
+			log.Error().Err(err).Msgf("failed to save snapshot at tick %d", tick)
+		}
+	}()
+}
+
+func (w *World) saveSnapshot(ctx context.Context, read func(ctx context.Context) (*gamestate.Snapshot, error)) error {
+	snapshot, err := read(ctx)
+	if err != nil {
+		return eris.Wrap(err, "failed to take snapshot")
+	}
+	if err := w.snapshotStore.Save(ctx, snapshot); err != nil {
+		return eris.Wrap(err, "failed to save snapshot")
+	}
+	log.Debug().Msgf("Saved snapshot at tick %d", snapshot.Tick)
+	return nil
+}
+
+// restoreFromSnapshot restores the game state from the latest snapshot if it is newer than the state that is already
+// in storage. Recovery from the base shard can then resume from the snapshot's tick instead of replaying every epoch.
+func (w *World) restoreFromSnapshot(ctx context.Context) error {
+	if w.snapshotStore == nil {
+		return nil
+	}
+
+	snapshot, err := w.snapshotStore.Latest(ctx)
+	if errors.Is(err, gamestate.ErrSnapshotNotFound) {
+		log.Info().Msg("No snapshot found, skipping restore from snapshot")
+		return nil
+	} else if err != nil {
+		return eris.Wrap(err, "failed to load latest snapshot")
+	}
+
+	tick, err := w.entityStore.GetLastFinalizedTick()
+	if err != nil {
+		return eris.Wrap(err, "failed to get latest finalized tick")
+	}
+	if snapshot.Tick <= tick {
+		log.Info().Msgf("Stored state at tick %d is not older than the latest snapshot at tick %d, "+
+			"skipping restore from snapshot", tick, snapshot.Tick)
+		return nil
+	}
+
+	snapshotter, ok := w.entityStore.(gamestate.Snapshotter)
+	if !ok {
+		return eris.New("game state manager does not support snapshots")
+	}
+	if err := snapshotter.RestoreSnapshot(ctx, snapshot); err != nil {
+		return eris.Wrap(err, "failed to restore snapshot")
+	}
+
+	log.Info().Msgf("Restored state from snapshot at tick %d", snapshot.Tick)
+	return nil
+}
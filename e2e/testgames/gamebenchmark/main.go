@@ -11,6 +11,8 @@ import (
 	"github.com/rotisserie/eris"
 
 	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/component"
 )
 
 func initializeSystems(
@@ -52,9 +54,10 @@ func main() {
 		panic(eris.ToString(err, true))
 	}
 	err = errors.Join(
-		cardinal.RegisterComponent[comp.ArrayComp](world),
+		// The large components are stored with a binary codec, which is faster to encode and smaller than JSON.
+		cardinal.RegisterComponent[comp.ArrayComp](world, component.WithCodec[comp.ArrayComp](codec.MsgPack)),
 		cardinal.RegisterComponent[comp.SingleNumber](world),
-		cardinal.RegisterComponent[comp.Tree](world),
+		cardinal.RegisterComponent[comp.Tree](world, component.WithCodec[comp.Tree](codec.MsgPack)),
 	)
 	if err != nil {
 		panic(eris.ToString(err, true))
@@ -72,6 +72,7 @@ var (
 	fd_SubmitShardTxRequest_epoch          protoreflect.FieldDescriptor
 	fd_SubmitShardTxRequest_unix_timestamp protoreflect.FieldDescriptor
 	fd_SubmitShardTxRequest_txs            protoreflect.FieldDescriptor
+	fd_SubmitShardTxRequest_state_root     protoreflect.FieldDescriptor
 )
 
 func init() {
@@ -82,6 +83,7 @@ func init() {
 	fd_SubmitShardTxRequest_epoch = md_SubmitShardTxRequest.Fields().ByName("epoch")
 	fd_SubmitShardTxRequest_unix_timestamp = md_SubmitShardTxRequest.Fields().ByName("unix_timestamp")
 	fd_SubmitShardTxRequest_txs = md_SubmitShardTxRequest.Fields().ByName("txs")
+	fd_SubmitShardTxRequest_state_root = md_SubmitShardTxRequest.Fields().ByName("state_root")
 }
 
 var _ protoreflect.Message = (*fastReflection_SubmitShardTxRequest)(nil)
@@ -179,6 +181,12 @@ func (x *fastReflection_SubmitShardTxRequest) Range(f func(protoreflect.FieldDes
 			return
 		}
 	}
+	if len(x.StateRoot) != 0 {
+		value := protoreflect.ValueOfBytes(x.StateRoot)
+		if !f(fd_SubmitShardTxRequest_state_root, value) {
+			return
+		}
+	}
 }
 
 // Has reports whether a field is populated.
@@ -204,6 +212,8 @@ func (x *fastReflection_SubmitShardTxRequest) Has(fd protoreflect.FieldDescripto
 		return x.UnixTimestamp != uint64(0)
 	case "shard.v1.SubmitShardTxRequest.txs":
 		return len(x.Txs) != 0
+	case "shard.v1.SubmitShardTxRequest.state_root":
+		return len(x.StateRoot) != 0
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.SubmitShardTxRequest"))
@@ -230,6 +240,8 @@ func (x *fastReflection_SubmitShardTxRequest) Clear(fd protoreflect.FieldDescrip
 		x.UnixTimestamp = uint64(0)
 	case "shard.v1.SubmitShardTxRequest.txs":
 		x.Txs = nil
+	case "shard.v1.SubmitShardTxRequest.state_root":
+		x.StateRoot = nil
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.SubmitShardTxRequest"))
@@ -264,6 +276,9 @@ func (x *fastReflection_SubmitShardTxRequest) Get(descriptor protoreflect.FieldD
 		}
 		listValue := &_SubmitShardTxRequest_5_list{list: &x.Txs}
 		return protoreflect.ValueOfList(listValue)
+	case "shard.v1.SubmitShardTxRequest.state_root":
+		value := x.StateRoot
+		return protoreflect.ValueOfBytes(value)
 	default:
 		if descriptor.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.SubmitShardTxRequest"))
@@ -296,6 +311,8 @@ func (x *fastReflection_SubmitShardTxRequest) Set(fd protoreflect.FieldDescripto
 		lv := value.List()
 		clv := lv.(*_SubmitShardTxRequest_5_list)
 		x.Txs = *clv.list
+	case "shard.v1.SubmitShardTxRequest.state_root":
+		x.StateRoot = value.Bytes()
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.SubmitShardTxRequest"))
@@ -330,6 +347,8 @@ func (x *fastReflection_SubmitShardTxRequest) Mutable(fd protoreflect.FieldDescr
 		panic(fmt.Errorf("field epoch of message shard.v1.SubmitShardTxRequest is not mutable"))
 	case "shard.v1.SubmitShardTxRequest.unix_timestamp":
 		panic(fmt.Errorf("field unix_timestamp of message shard.v1.SubmitShardTxRequest is not mutable"))
+	case "shard.v1.SubmitShardTxRequest.state_root":
+		panic(fmt.Errorf("field state_root of message shard.v1.SubmitShardTxRequest is not mutable"))
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.SubmitShardTxRequest"))
@@ -354,6 +373,8 @@ func (x *fastReflection_SubmitShardTxRequest) NewField(fd protoreflect.FieldDesc
 	case "shard.v1.SubmitShardTxRequest.txs":
 		list := []*Transaction{}
 		return protoreflect.ValueOfList(&_SubmitShardTxRequest_5_list{list: &list})
+	case "shard.v1.SubmitShardTxRequest.state_root":
+		return protoreflect.ValueOfBytes(nil)
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.SubmitShardTxRequest"))
@@ -443,6 +464,10 @@ func (x *fastReflection_SubmitShardTxRequest) ProtoMethods() *protoiface.Methods
 				n += 1 + l + runtime.Sov(uint64(l))
 			}
 		}
+		l = len(x.StateRoot)
+		if l > 0 {
+			n += 1 + l + runtime.Sov(uint64(l))
+		}
 		if x.unknownFields != nil {
 			n += len(x.unknownFields)
 		}
@@ -472,6 +497,13 @@ func (x *fastReflection_SubmitShardTxRequest) ProtoMethods() *protoiface.Methods
 			i -= len(x.unknownFields)
 			copy(dAtA[i:], x.unknownFields)
 		}
+		if len(x.StateRoot) > 0 {
+			i -= len(x.StateRoot)
+			copy(dAtA[i:], x.StateRoot)
+			i = runtime.EncodeVarint(dAtA, i, uint64(len(x.StateRoot)))
+			i--
+			dAtA[i] = 0x32
+		}
 		if len(x.Txs) > 0 {
 			for iNdEx := len(x.Txs) - 1; iNdEx >= 0; iNdEx-- {
 				encoded, err := options.Marshal(x.Txs[iNdEx])
@@ -697,6 +729,40 @@ func (x *fastReflection_SubmitShardTxRequest) ProtoMethods() *protoiface.Methods
 					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, err
 				}
 				iNdEx = postIndex
+			case 6:
+				if wireType != 2 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: wrong wireType = %d for field StateRoot", wireType)
+				}
+				var byteLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrIntOverflow
+					}
+					if iNdEx >= l {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					byteLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if byteLen < 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrInvalidLength
+				}
+				postIndex := iNdEx + byteLen
+				if postIndex < 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrInvalidLength
+				}
+				if postIndex > l {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+				}
+				x.StateRoot = append(x.StateRoot[:0], dAtA[iNdEx:postIndex]...)
+				if x.StateRoot == nil {
+					x.StateRoot = []byte{}
+				}
+				iNdEx = postIndex
 			default:
 				iNdEx = preIndex
 				skippy, err := runtime.Skip(dAtA[iNdEx:])
@@ -1117,6 +1183,8 @@ type SubmitShardTxRequest struct {
 	UnixTimestamp uint64 `protobuf:"varint,4,opt,name=unix_timestamp,json=unixTimestamp,proto3" json:"unix_timestamp,omitempty"`
 	// txs are the transactions that occurred in this tick.
 	Txs []*Transaction `protobuf:"bytes,5,rep,name=txs,proto3" json:"txs,omitempty"`
+	// state_root is the Merkle root of the game shard's state at the end of the epoch.
+	StateRoot []byte `protobuf:"bytes,6,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
 }
 
 func (x *SubmitShardTxRequest) Reset() {
@@ -1174,6 +1242,13 @@ func (x *SubmitShardTxRequest) GetTxs() []*Transaction {
 	return nil
 }
 
+func (x *SubmitShardTxRequest) GetStateRoot() []byte {
+	if x != nil {
+		return x.StateRoot
+	}
+	return nil
+}
+
 type SubmitShardTxResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1209,7 +1284,7 @@ var file_shard_v1_tx_proto_rawDesc = []byte{
 	0x6f, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73,
 	0x2f, 0x6d, 0x73, 0x67, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x73, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x1a, 0x14, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x79, 0x70, 0x65,
-	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd9, 0x01, 0x0a, 0x14, 0x53, 0x75, 0x62, 0x6d,
+	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf8, 0x01, 0x0a, 0x14, 0x53, 0x75, 0x62, 0x6d,
 	0x69, 0x74, 0x53, 0x68, 0x61, 0x72, 0x64, 0x54, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
 	0x12, 0x30, 0x0a, 0x06, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
 	0x42, 0x18, 0xd2, 0xb4, 0x2d, 0x14, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2e, 0x41, 0x64, 0x64,
@@ -1222,24 +1297,25 @@ var file_shard_v1_tx_proto_rawDesc = []byte{
 	0x75, 0x6e, 0x69, 0x78, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x27, 0x0a,
 	0x03, 0x74, 0x78, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x73, 0x68, 0x61,
 	0x72, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x52, 0x03, 0x74, 0x78, 0x73, 0x3a, 0x0b, 0x82, 0xe7, 0xb0, 0x2a, 0x06, 0x73, 0x65, 0x6e,
-	0x64, 0x65, 0x72, 0x22, 0x17, 0x0a, 0x15, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61,
-	0x72, 0x64, 0x54, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x5e, 0x0a, 0x03,
-	0x4d, 0x73, 0x67, 0x12, 0x50, 0x0a, 0x0d, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61,
-	0x72, 0x64, 0x54, 0x78, 0x12, 0x1e, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76, 0x31, 0x2e,
-	0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61, 0x72, 0x64, 0x54, 0x78, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76, 0x31, 0x2e,
-	0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61, 0x72, 0x64, 0x54, 0x78, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x1a, 0x05, 0x80, 0xe7, 0xb0, 0x2a, 0x01, 0x42, 0x7b, 0x0a, 0x0c,
-	0x63, 0x6f, 0x6d, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76, 0x31, 0x42, 0x07, 0x54, 0x78,
-	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x21, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x73,
-	0x64, 0x6b, 0x2e, 0x69, 0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2f,
-	0x76, 0x31, 0x3b, 0x73, 0x68, 0x61, 0x72, 0x64, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x53, 0x58, 0x58,
-	0xaa, 0x02, 0x08, 0x53, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x08, 0x53, 0x68,
-	0x61, 0x72, 0x64, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x14, 0x53, 0x68, 0x61, 0x72, 0x64, 0x5c, 0x56,
-	0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x09,
-	0x53, 0x68, 0x61, 0x72, 0x64, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x6e, 0x52, 0x03, 0x74, 0x78, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f,
+	0x72, 0x6f, 0x6f, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x6f, 0x6f, 0x74, 0x3a, 0x0b, 0x82, 0xe7, 0xb0, 0x2a, 0x06, 0x73, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x22, 0x17, 0x0a, 0x15, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61, 0x72,
+	0x64, 0x54, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x5e, 0x0a, 0x03, 0x4d,
+	0x73, 0x67, 0x12, 0x50, 0x0a, 0x0d, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61, 0x72,
+	0x64, 0x54, 0x78, 0x12, 0x1e, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61, 0x72, 0x64, 0x54, 0x78, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61, 0x72, 0x64, 0x54, 0x78, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x1a, 0x05, 0x80, 0xe7, 0xb0, 0x2a, 0x01, 0x42, 0x7b, 0x0a, 0x0c, 0x63,
+	0x6f, 0x6d, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76, 0x31, 0x42, 0x07, 0x54, 0x78, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x21, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x73, 0x64,
+	0x6b, 0x2e, 0x69, 0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2f, 0x76,
+	0x31, 0x3b, 0x73, 0x68, 0x61, 0x72, 0x64, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x53, 0x58, 0x58, 0xaa,
+	0x02, 0x08, 0x53, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x08, 0x53, 0x68, 0x61,
+	0x72, 0x64, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x14, 0x53, 0x68, 0x61, 0x72, 0x64, 0x5c, 0x56, 0x31,
+	0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x09, 0x53,
+	0x68, 0x61, 0x72, 0x64, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -538,6 +538,7 @@ var (
 	fd_Epoch_epoch          protoreflect.FieldDescriptor
 	fd_Epoch_unix_timestamp protoreflect.FieldDescriptor
 	fd_Epoch_txs            protoreflect.FieldDescriptor
+	fd_Epoch_state_root     protoreflect.FieldDescriptor
 )
 
 func init() {
@@ -546,6 +547,7 @@ func init() {
 	fd_Epoch_epoch = md_Epoch.Fields().ByName("epoch")
 	fd_Epoch_unix_timestamp = md_Epoch.Fields().ByName("unix_timestamp")
 	fd_Epoch_txs = md_Epoch.Fields().ByName("txs")
+	fd_Epoch_state_root = md_Epoch.Fields().ByName("state_root")
 }
 
 var _ protoreflect.Message = (*fastReflection_Epoch)(nil)
@@ -631,6 +633,12 @@ func (x *fastReflection_Epoch) Range(f func(protoreflect.FieldDescriptor, protor
 			return
 		}
 	}
+	if len(x.StateRoot) != 0 {
+		value := protoreflect.ValueOfBytes(x.StateRoot)
+		if !f(fd_Epoch_state_root, value) {
+			return
+		}
+	}
 }
 
 // Has reports whether a field is populated.
@@ -652,6 +660,8 @@ func (x *fastReflection_Epoch) Has(fd protoreflect.FieldDescriptor) bool {
 		return x.UnixTimestamp != uint64(0)
 	case "shard.v1.Epoch.txs":
 		return len(x.Txs) != 0
+	case "shard.v1.Epoch.state_root":
+		return len(x.StateRoot) != 0
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.Epoch"))
@@ -674,6 +684,8 @@ func (x *fastReflection_Epoch) Clear(fd protoreflect.FieldDescriptor) {
 		x.UnixTimestamp = uint64(0)
 	case "shard.v1.Epoch.txs":
 		x.Txs = nil
+	case "shard.v1.Epoch.state_root":
+		x.StateRoot = nil
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.Epoch"))
@@ -702,6 +714,9 @@ func (x *fastReflection_Epoch) Get(descriptor protoreflect.FieldDescriptor) prot
 		}
 		listValue := &_Epoch_3_list{list: &x.Txs}
 		return protoreflect.ValueOfList(listValue)
+	case "shard.v1.Epoch.state_root":
+		value := x.StateRoot
+		return protoreflect.ValueOfBytes(value)
 	default:
 		if descriptor.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.Epoch"))
@@ -730,6 +745,8 @@ func (x *fastReflection_Epoch) Set(fd protoreflect.FieldDescriptor, value protor
 		lv := value.List()
 		clv := lv.(*_Epoch_3_list)
 		x.Txs = *clv.list
+	case "shard.v1.Epoch.state_root":
+		x.StateRoot = value.Bytes()
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.Epoch"))
@@ -760,6 +777,8 @@ func (x *fastReflection_Epoch) Mutable(fd protoreflect.FieldDescriptor) protoref
 		panic(fmt.Errorf("field epoch of message shard.v1.Epoch is not mutable"))
 	case "shard.v1.Epoch.unix_timestamp":
 		panic(fmt.Errorf("field unix_timestamp of message shard.v1.Epoch is not mutable"))
+	case "shard.v1.Epoch.state_root":
+		panic(fmt.Errorf("field state_root of message shard.v1.Epoch is not mutable"))
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.Epoch"))
@@ -780,6 +799,8 @@ func (x *fastReflection_Epoch) NewField(fd protoreflect.FieldDescriptor) protore
 	case "shard.v1.Epoch.txs":
 		list := []*Transaction{}
 		return protoreflect.ValueOfList(&_Epoch_3_list{list: &list})
+	case "shard.v1.Epoch.state_root":
+		return protoreflect.ValueOfBytes(nil)
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: shard.v1.Epoch"))
@@ -861,6 +882,10 @@ func (x *fastReflection_Epoch) ProtoMethods() *protoiface.Methods {
 				n += 1 + l + runtime.Sov(uint64(l))
 			}
 		}
+		l = len(x.StateRoot)
+		if l > 0 {
+			n += 1 + l + runtime.Sov(uint64(l))
+		}
 		if x.unknownFields != nil {
 			n += len(x.unknownFields)
 		}
@@ -890,6 +915,13 @@ func (x *fastReflection_Epoch) ProtoMethods() *protoiface.Methods {
 			i -= len(x.unknownFields)
 			copy(dAtA[i:], x.unknownFields)
 		}
+		if len(x.StateRoot) > 0 {
+			i -= len(x.StateRoot)
+			copy(dAtA[i:], x.StateRoot)
+			i = runtime.EncodeVarint(dAtA, i, uint64(len(x.StateRoot)))
+			i--
+			dAtA[i] = 0x22
+		}
 		if len(x.Txs) > 0 {
 			for iNdEx := len(x.Txs) - 1; iNdEx >= 0; iNdEx-- {
 				encoded, err := options.Marshal(x.Txs[iNdEx])
@@ -1037,6 +1069,40 @@ func (x *fastReflection_Epoch) ProtoMethods() *protoiface.Methods {
 					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, err
 				}
 				iNdEx = postIndex
+			case 4:
+				if wireType != 2 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: wrong wireType = %d for field StateRoot", wireType)
+				}
+				var byteLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrIntOverflow
+					}
+					if iNdEx >= l {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					byteLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if byteLen < 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrInvalidLength
+				}
+				postIndex := iNdEx + byteLen
+				if postIndex < 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrInvalidLength
+				}
+				if postIndex > l {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+				}
+				x.StateRoot = append(x.StateRoot[:0], dAtA[iNdEx:postIndex]...)
+				if x.StateRoot == nil {
+					x.StateRoot = []byte{}
+				}
+				iNdEx = postIndex
 			default:
 				iNdEx = preIndex
 				skippy, err := runtime.Skip(dAtA[iNdEx:])
@@ -1140,6 +1206,8 @@ type Epoch struct {
 	Epoch         uint64         `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	UnixTimestamp uint64         `protobuf:"varint,2,opt,name=unix_timestamp,json=unixTimestamp,proto3" json:"unix_timestamp,omitempty"`
 	Txs           []*Transaction `protobuf:"bytes,3,rep,name=txs,proto3" json:"txs,omitempty"`
+	// state_root is the Merkle root of the game shard's state at the end of the epoch.
+	StateRoot []byte `protobuf:"bytes,4,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
 }
 
 func (x *Epoch) Reset() {
@@ -1183,6 +1251,13 @@ func (x *Epoch) GetTxs() []*Transaction {
 	return nil
 }
 
+func (x *Epoch) GetStateRoot() []byte {
+	if x != nil {
+		return x.StateRoot
+	}
+	return nil
+}
+
 var File_shard_v1_types_proto protoreflect.FileDescriptor
 
 var file_shard_v1_types_proto_rawDesc = []byte{
@@ -1193,14 +1268,16 @@ var file_shard_v1_types_proto_rawDesc = []byte{
 	0x74, 0x78, 0x49, 0x64, 0x12, 0x34, 0x0a, 0x16, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x73, 0x68, 0x61,
 	0x72, 0x64, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
 	0x20, 0x01, 0x28, 0x0c, 0x52, 0x14, 0x67, 0x61, 0x6d, 0x65, 0x53, 0x68, 0x61, 0x72, 0x64, 0x54,
-	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x6d, 0x0a, 0x05, 0x45, 0x70,
-	0x6f, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x6e, 0x69,
-	0x78, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x0d, 0x75, 0x6e, 0x69, 0x78, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x12, 0x27, 0x0a, 0x03, 0x74, 0x78, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e,
-	0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x74, 0x78, 0x73, 0x42, 0x7e, 0x0a, 0x0c, 0x63, 0x6f, 0x6d,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x8c, 0x01, 0x0a, 0x05, 0x45,
+	0x70, 0x6f, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x6e,
+	0x69, 0x78, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0d, 0x75, 0x6e, 0x69, 0x78, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x12, 0x27, 0x0a, 0x03, 0x74, 0x78, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x74, 0x78, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x42, 0x7e, 0x0a, 0x0c, 0x63, 0x6f, 0x6d,
 	0x2e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2e, 0x76, 0x31, 0x42, 0x0a, 0x54, 0x79, 0x70, 0x65, 0x73,
 	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x21, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x73,
 	0x64, 0x6b, 0x2e, 0x69, 0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x73, 0x68, 0x61, 0x72, 0x64, 0x2f,
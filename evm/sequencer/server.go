@@ -95,6 +95,14 @@ func (s *Sequencer) FlushMessages() ([]*types.SubmitShardTxRequest, []*namespace
 func (s *Sequencer) Submit(_ context.Context, req *shard.SubmitTransactionsRequest) (
 	*shard.SubmitTransactionsResponse, error,
 ) {
+	// The state root of every epoch is committed, including the epochs in which the game shard processed no
+	// transactions.
+	if len(req.GetStateRoot()) > 0 {
+		err := s.tq.AddEpoch(req.GetNamespace(), req.GetEpoch(), req.GetUnixTimestamp(), req.GetStateRoot())
+		if err != nil {
+			return nil, eris.Wrap(err, "failed to add game shard epoch to queue")
+		}
+	}
 	txIDs := sortMapKeys(req.GetTransactions())
 	for _, txID := range txIDs {
 		txs := req.GetTransactions()[txID].GetTxs()
@@ -103,7 +111,7 @@ func (s *Sequencer) Submit(_ context.Context, req *shard.SubmitTransactionsReque
 			if err != nil {
 				return nil, eris.Wrap(err, "failed to marshal transaction")
 			}
-			err = s.tq.AddTx(req.GetNamespace(), req.GetEpoch(), req.GetUnixTimestamp(), txID, bz)
+			err = s.tq.AddTx(req.GetNamespace(), req.GetEpoch(), req.GetUnixTimestamp(), req.GetStateRoot(), txID, bz)
 			if err != nil {
 				return nil, eris.Wrap(err, "failed to add game shard tx submission to queue")
 			}
@@ -22,6 +22,7 @@ func TestMessagesAreOrderedAndProtoMarshalled(t *testing.T) {
 		Epoch:         10,
 		Namespace:     namespace,
 		UnixTimestamp: 400,
+		StateRoot:     []byte("state-root"),
 		Transactions: map[uint64]*shardv2.Transactions{
 			44: {
 				Txs: []*shardv2.Transaction{
@@ -54,6 +55,7 @@ func TestMessagesAreOrderedAndProtoMarshalled(t *testing.T) {
 	assert.Len(t, flushedMessages, 1)
 	messages := flushedMessages[0]
 	assert.Len(t, messages.Txs, 2)
+	assert.DeepEqual(t, messages.StateRoot, req.GetStateRoot())
 	assert.Equal(t, messages.Txs[0].TxId, uint64(30))
 	assert.Equal(t, messages.Txs[1].TxId, uint64(44))
 
@@ -67,6 +69,31 @@ func TestMessagesAreOrderedAndProtoMarshalled(t *testing.T) {
 	assert.Check(t, proto.Equal(pbMsg, req.GetTransactions()[44].GetTxs()[0]))
 }
 
+func TestStateRootOfEpochWithoutTransactionsIsSubmitted(t *testing.T) {
+	t.Parallel()
+	seq := New(keeper.NewKeeper(nil, "foo"), nil)
+	_, err := seq.Submit(context.Background(), &shardv2.SubmitTransactionsRequest{
+		Epoch:         7,
+		UnixTimestamp: 400,
+		Namespace:     "foo",
+		StateRoot:     []byte("state-root"),
+	})
+	assert.NilError(t, err)
+	// Without a state root, there is nothing to commit for an epoch without transactions.
+	_, err = seq.Submit(context.Background(), &shardv2.SubmitTransactionsRequest{
+		Epoch:         8,
+		UnixTimestamp: 401,
+		Namespace:     "foo",
+	})
+	assert.NilError(t, err)
+
+	flushedMessages, _ := seq.FlushMessages()
+	assert.Len(t, flushedMessages, 1)
+	assert.Equal(t, flushedMessages[0].Epoch, uint64(7))
+	assert.Len(t, flushedMessages[0].Txs, 0)
+	assert.DeepEqual(t, flushedMessages[0].StateRoot, []byte("state-root"))
+}
+
 func TestGetBothSlices(t *testing.T) {
 	t.Parallel()
 	seq := New(keeper.NewKeeper(nil, "foo"), nil)
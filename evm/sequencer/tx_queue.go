@@ -53,11 +53,41 @@ func (tc *TxQueue) AddInitMsg(namespace, routerAddr string) error {
 	return nil
 }
 
-// AddTx adds a transaction to the queue.
-func (tc *TxQueue) AddTx(namespace string, epoch, unixTimestamp, txID uint64, payload []byte) error {
+// AddTx adds a transaction to the queue. stateRoot is the state root of the game shard at the end of the epoch.
+func (tc *TxQueue) AddTx(
+	namespace string, epoch, unixTimestamp uint64, stateRoot []byte, txID uint64, payload []byte,
+) error {
 	tc.lock.Lock()
 	defer tc.lock.Unlock()
 
+	req, err := tc.epochRequest(namespace, epoch, unixTimestamp, stateRoot)
+	if err != nil {
+		return err
+	}
+
+	// append the transaction data for this epoch.
+	req.Txs = append(req.Txs, &types.Transaction{
+		TxId:                 txID,
+		GameShardTransaction: payload,
+	})
+
+	return nil
+}
+
+// AddEpoch adds an epoch to the queue, even if no transaction is added to it, so the state root of an epoch without
+// transactions is committed as well.
+func (tc *TxQueue) AddEpoch(namespace string, epoch, unixTimestamp uint64, stateRoot []byte) error {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+
+	_, err := tc.epochRequest(namespace, epoch, unixTimestamp, stateRoot)
+	return err
+}
+
+// epochRequest returns the queued request of the given epoch, and creates it if there is none yet.
+func (tc *TxQueue) epochRequest(
+	namespace string, epoch, unixTimestamp uint64, stateRoot []byte,
+) (*types.SubmitShardTxRequest, error) {
 	if tc.txQueue[namespace] == nil {
 		tc.txQueue[namespace] = make(map[uint64]*types.SubmitShardTxRequest)
 	}
@@ -71,21 +101,15 @@ func (tc *TxQueue) AddTx(namespace string, epoch, unixTimestamp, txID uint64, pa
 			Epoch:         epoch,
 			UnixTimestamp: unixTimestamp,
 			Txs:           make([]*types.Transaction, 0),
+			StateRoot:     stateRoot,
 		}
 		if err := req.ValidateBasic(); err != nil {
-			return err
+			return nil, err
 		}
 
 		tc.txQueue[namespace][epoch] = req
 	}
-
-	// append the transaction data for this epoch.
-	tc.txQueue[namespace][epoch].Txs = append(tc.txQueue[namespace][epoch].Txs, &types.Transaction{
-		TxId:                 txID,
-		GameShardTransaction: payload,
-	})
-
-	return nil
+	return tc.txQueue[namespace][epoch], nil
 }
 
 // FlushTxQueue gets all currently queued transactions sorted by namespace and by transaction ID, and then clears the
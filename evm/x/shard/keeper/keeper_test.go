@@ -65,6 +65,7 @@ func (s *TestSuite) TestSubmitTransactions() {
 			Namespace: tx.GetNamespace(),
 			Epoch:     epoch,
 			Txs:       txs,
+			StateRoot: []byte("state-root"),
 		},
 	)
 	s.Require().NoError(err)
@@ -90,6 +91,8 @@ func (s *TestSuite) TestSubmitTransactions() {
 	s.Require().Len(res.Epochs, 1)
 	// should have equal amount of txs within the epoch.
 	s.Require().Len(res.Epochs[0].Txs, len(txs))
+	// the state root is stored alongside the epoch.
+	s.Require().Equal([]byte("state-root"), res.Epochs[0].StateRoot)
 }
 
 func (s *TestSuite) TestPagedQueryTransactions() {
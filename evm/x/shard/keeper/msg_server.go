@@ -24,6 +24,7 @@ func (k *Keeper) SubmitShardTx(
 		Epoch:         msg.Epoch,
 		UnixTimestamp: msg.UnixTimestamp,
 		Txs:           msg.Txs,
+		StateRoot:     msg.StateRoot,
 	})
 	if err != nil {
 		return nil, err
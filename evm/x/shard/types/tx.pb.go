@@ -41,6 +41,8 @@ type SubmitShardTxRequest struct {
 	UnixTimestamp uint64 `protobuf:"varint,4,opt,name=unix_timestamp,json=unixTimestamp,proto3" json:"unix_timestamp,omitempty"`
 	// txs are the transactions that occurred in this tick.
 	Txs []*Transaction `protobuf:"bytes,5,rep,name=txs,proto3" json:"txs,omitempty"`
+	// state_root is the Merkle root of the game shard's state at the end of the epoch.
+	StateRoot []byte `protobuf:"bytes,6,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
 }
 
 func (m *SubmitShardTxRequest) Reset()         { *m = SubmitShardTxRequest{} }
@@ -111,6 +113,13 @@ func (m *SubmitShardTxRequest) GetTxs() []*Transaction {
 	return nil
 }
 
+func (m *SubmitShardTxRequest) GetStateRoot() []byte {
+	if m != nil {
+		return m.StateRoot
+	}
+	return nil
+}
+
 type SubmitShardTxResponse struct {
 }
 
@@ -155,31 +164,32 @@ func init() {
 func init() { proto.RegisterFile("shard/v1/tx.proto", fileDescriptor_2ea9067d7c94eab8) }
 
 var fileDescriptor_2ea9067d7c94eab8 = []byte{
-	// 370 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x51, 0xcd, 0x4e, 0xea, 0x40,
-	0x14, 0x66, 0x6e, 0x81, 0x5c, 0x86, 0x70, 0x93, 0x3b, 0x29, 0xa1, 0xb7, 0xb9, 0xa9, 0x84, 0xc4,
-	0x48, 0x48, 0xe8, 0x08, 0xee, 0xdc, 0xc9, 0xca, 0x8d, 0x89, 0x29, 0xac, 0x5c, 0x48, 0x4a, 0x3b,
-	0x29, 0x8d, 0x76, 0xa6, 0xf6, 0x0c, 0x58, 0x77, 0xc6, 0x27, 0xf0, 0x51, 0x58, 0xf8, 0x10, 0x2e,
-	0x89, 0x2b, 0xdd, 0x19, 0x58, 0xf0, 0x1a, 0xa6, 0x3f, 0x84, 0x68, 0x74, 0x37, 0xdf, 0xcf, 0xf9,
-	0x66, 0xe6, 0x7c, 0xf8, 0x2f, 0x4c, 0xed, 0xc8, 0xa5, 0xf3, 0x1e, 0x95, 0xb1, 0x19, 0x46, 0x42,
-	0x0a, 0xf2, 0x3b, 0xa5, 0xcc, 0x79, 0x4f, 0xff, 0xe7, 0x08, 0x08, 0x04, 0x8c, 0x53, 0x9e, 0x66,
-	0x20, 0x33, 0xe9, 0x8d, 0x0c, 0xd1, 0x00, 0xbc, 0x64, 0x38, 0x00, 0x2f, 0x17, 0xd4, 0x5d, 0xe0,
-	0x5d, 0xc8, 0x72, 0x7b, 0xeb, 0x0d, 0x61, 0x75, 0x38, 0x9b, 0x04, 0xbe, 0x1c, 0x26, 0xf2, 0x28,
-	0xb6, 0xd8, 0xcd, 0x8c, 0x81, 0x24, 0x87, 0xb8, 0x0c, 0x8c, 0xbb, 0x2c, 0xd2, 0x50, 0x13, 0xb5,
-	0x2b, 0x03, 0xed, 0xe5, 0xa9, 0xab, 0xe6, 0x37, 0x9d, 0xb8, 0x6e, 0xc4, 0x00, 0x86, 0x32, 0xf2,
-	0xb9, 0x67, 0xe5, 0x3e, 0xf2, 0x1f, 0x57, 0xb8, 0x1d, 0x30, 0x08, 0x6d, 0x87, 0x69, 0xbf, 0x92,
-	0x21, 0x6b, 0x47, 0x10, 0x15, 0x97, 0x58, 0x28, 0x9c, 0xa9, 0xa6, 0x34, 0x51, 0xbb, 0x68, 0x65,
-	0x80, 0xec, 0xe3, 0x3f, 0x33, 0xee, 0xc7, 0x63, 0xe9, 0x07, 0x0c, 0xa4, 0x1d, 0x84, 0x5a, 0x31,
-	0x95, 0x6b, 0x09, 0x3b, 0xda, 0x92, 0xe4, 0x00, 0x2b, 0x32, 0x06, 0xad, 0xd4, 0x54, 0xda, 0xd5,
-	0x7e, 0xdd, 0xdc, 0xee, 0xc1, 0x1c, 0x45, 0x36, 0x07, 0xdb, 0x91, 0xbe, 0xe0, 0x56, 0xe2, 0x38,
-	0xae, 0x3e, 0x6c, 0x16, 0x9d, 0xfc, 0x41, 0xad, 0x06, 0xae, 0x7f, 0xf9, 0x1a, 0x84, 0x82, 0x03,
-	0xeb, 0x5f, 0x62, 0xe5, 0x0c, 0x3c, 0x72, 0x8e, 0x6b, 0x9f, 0x74, 0x62, 0xec, 0x92, 0xbf, 0xdb,
-	0x89, 0xbe, 0xf7, 0xa3, 0x9e, 0x05, 0xeb, 0xa5, 0xfb, 0xcd, 0xa2, 0x83, 0x06, 0xa7, 0xcf, 0x2b,
-	0x03, 0x2d, 0x57, 0x06, 0x7a, 0x5f, 0x19, 0xe8, 0x71, 0x6d, 0x14, 0x96, 0x6b, 0xa3, 0xf0, 0xba,
-	0x36, 0x0a, 0x17, 0x66, 0x78, 0xe5, 0x99, 0xb7, 0x22, 0xba, 0x76, 0x4d, 0x97, 0xcd, 0x69, 0x7a,
-	0xea, 0x32, 0xee, 0xf9, 0x9c, 0x51, 0x67, 0x6a, 0xfb, 0x9c, 0xc6, 0x34, 0xeb, 0x29, 0x2d, 0x69,
-	0x52, 0x4e, 0x5b, 0x3a, 0xfa, 0x08, 0x00, 0x00, 0xff, 0xff, 0x70, 0x9c, 0x49, 0x6d, 0x0e, 0x02,
-	0x00, 0x00,
+	// 391 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0xcf, 0xae, 0xd2, 0x40,
+	0x14, 0xc6, 0x19, 0x0b, 0x44, 0x06, 0x31, 0x71, 0x52, 0x42, 0x6d, 0xb4, 0x36, 0x24, 0xc6, 0x86,
+	0x84, 0x8e, 0xe0, 0xce, 0x9d, 0xac, 0xdc, 0x98, 0x98, 0xc2, 0xca, 0x85, 0x64, 0x68, 0x27, 0xa5,
+	0xd1, 0xce, 0xd4, 0x39, 0x03, 0xd6, 0x9d, 0xf1, 0x09, 0x7c, 0x14, 0x16, 0x3e, 0x84, 0x4b, 0xe2,
+	0xca, 0xa5, 0x81, 0x05, 0xaf, 0xe0, 0xf2, 0xa6, 0x7f, 0x08, 0xb9, 0x37, 0xf7, 0xee, 0x7a, 0x7e,
+	0xdf, 0x77, 0xce, 0xe9, 0x9c, 0x0f, 0x3f, 0x82, 0x35, 0x53, 0x11, 0xdd, 0x4e, 0xa8, 0xce, 0xfd,
+	0x4c, 0x49, 0x2d, 0xc9, 0xfd, 0x12, 0xf9, 0xdb, 0x89, 0xfd, 0x38, 0x94, 0x90, 0x4a, 0x58, 0x96,
+	0x9c, 0x56, 0x45, 0x65, 0xb2, 0x07, 0x55, 0x45, 0x53, 0x88, 0x8b, 0xe6, 0x14, 0xe2, 0x5a, 0x30,
+	0x2f, 0x03, 0xbf, 0x65, 0xbc, 0xb6, 0x0f, 0xff, 0x23, 0x6c, 0xce, 0x37, 0xab, 0x34, 0xd1, 0xf3,
+	0x42, 0x5e, 0xe4, 0x01, 0xff, 0xb2, 0xe1, 0xa0, 0xc9, 0x4b, 0xdc, 0x06, 0x2e, 0x22, 0xae, 0x2c,
+	0xe4, 0x22, 0xaf, 0x33, 0xb3, 0xfe, 0xfc, 0x1a, 0x9b, 0xf5, 0xa6, 0x37, 0x51, 0xa4, 0x38, 0xc0,
+	0x5c, 0xab, 0x44, 0xc4, 0x41, 0xed, 0x23, 0x4f, 0x70, 0x47, 0xb0, 0x94, 0x43, 0xc6, 0x42, 0x6e,
+	0xdd, 0x2b, 0x9a, 0x82, 0x0b, 0x20, 0x26, 0x6e, 0xf1, 0x4c, 0x86, 0x6b, 0xcb, 0x70, 0x91, 0xd7,
+	0x0c, 0xaa, 0x82, 0x3c, 0xc7, 0x0f, 0x37, 0x22, 0xc9, 0x97, 0x3a, 0x49, 0x39, 0x68, 0x96, 0x66,
+	0x56, 0xb3, 0x94, 0x7b, 0x05, 0x5d, 0x9c, 0x21, 0x79, 0x81, 0x0d, 0x9d, 0x83, 0xd5, 0x72, 0x0d,
+	0xaf, 0x3b, 0xed, 0xfb, 0xe7, 0x3b, 0xf8, 0x0b, 0xc5, 0x04, 0xb0, 0x50, 0x27, 0x52, 0x04, 0x85,
+	0x83, 0x3c, 0xc5, 0x18, 0x34, 0xd3, 0x7c, 0xa9, 0xa4, 0xd4, 0x56, 0xdb, 0x45, 0xde, 0x83, 0xa0,
+	0x53, 0x92, 0x40, 0x4a, 0xfd, 0xba, 0xfb, 0xe3, 0xb4, 0x1b, 0xd5, 0xff, 0x3b, 0x1c, 0xe0, 0xfe,
+	0x8d, 0x97, 0x43, 0x26, 0x05, 0xf0, 0xe9, 0x47, 0x6c, 0xbc, 0x83, 0x98, 0xbc, 0xc7, 0xbd, 0x6b,
+	0x3a, 0x71, 0x2e, 0x8b, 0x6f, 0x3b, 0x99, 0xfd, 0xec, 0x4e, 0xbd, 0x1a, 0x6c, 0xb7, 0xbe, 0x9f,
+	0x76, 0x23, 0x34, 0x7b, 0xfb, 0xc1, 0xcf, 0x3e, 0xc5, 0xfe, 0x57, 0xa9, 0x3e, 0x47, 0x7e, 0xc4,
+	0xb7, 0xb4, 0xfc, 0x1a, 0x73, 0x11, 0x27, 0x82, 0xd3, 0x70, 0xcd, 0x12, 0x41, 0x73, 0x5a, 0xc5,
+	0x55, 0x66, 0xf5, 0xfb, 0xe0, 0xa0, 0xfd, 0xc1, 0x41, 0xff, 0x0e, 0x0e, 0xfa, 0x79, 0x74, 0x1a,
+	0xfb, 0xa3, 0xd3, 0xf8, 0x7b, 0x74, 0x1a, 0xab, 0x76, 0x19, 0xe2, 0xab, 0xab, 0x01, 0x00, 0x15,
+	0x13, 0x83, 0xdd, 0x2d, 0x02, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -282,6 +292,13 @@ func (m *SubmitShardTxRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.StateRoot) > 0 {
+		i -= len(m.StateRoot)
+		copy(dAtA[i:], m.StateRoot)
+		i = encodeVarintTx(dAtA, i, uint64(len(m.StateRoot)))
+		i--
+		dAtA[i] = 0x32
+	}
 	if len(m.Txs) > 0 {
 		for iNdEx := len(m.Txs) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -383,6 +400,10 @@ func (m *SubmitShardTxRequest) Size() (n int) {
 			n += 1 + l + sovTx(uint64(l))
 		}
 	}
+	l = len(m.StateRoot)
+	if l > 0 {
+		n += 1 + l + sovTx(uint64(l))
+	}
 	return n
 }
 
@@ -566,6 +587,40 @@ func (m *SubmitShardTxRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateRoot", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTx
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTx
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTx
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateRoot = append(m.StateRoot[:0], dAtA[iNdEx:postIndex]...)
+			if m.StateRoot == nil {
+				m.StateRoot = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTx(dAtA[iNdEx:])
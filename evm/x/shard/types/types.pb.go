@@ -82,6 +82,8 @@ type Epoch struct {
 	Epoch         uint64         `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	UnixTimestamp uint64         `protobuf:"varint,2,opt,name=unix_timestamp,json=unixTimestamp,proto3" json:"unix_timestamp,omitempty"`
 	Txs           []*Transaction `protobuf:"bytes,3,rep,name=txs,proto3" json:"txs,omitempty"`
+	// state_root is the Merkle root of the game shard's state at the end of the epoch.
+	StateRoot []byte `protobuf:"bytes,4,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
 }
 
 func (m *Epoch) Reset()         { *m = Epoch{} }
@@ -138,6 +140,13 @@ func (m *Epoch) GetTxs() []*Transaction {
 	return nil
 }
 
+func (m *Epoch) GetStateRoot() []byte {
+	if m != nil {
+		return m.StateRoot
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Transaction)(nil), "shard.v1.Transaction")
 	proto.RegisterType((*Epoch)(nil), "shard.v1.Epoch")
@@ -146,24 +155,25 @@ func init() {
 func init() { proto.RegisterFile("shard/v1/types.proto", fileDescriptor_0a60f84bb846c47b) }
 
 var fileDescriptor_0a60f84bb846c47b = []byte{
-	// 262 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x29, 0xce, 0x48, 0x2c,
-	0x4a, 0xd1, 0x2f, 0x33, 0xd4, 0x2f, 0xa9, 0x2c, 0x48, 0x2d, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9,
-	0x17, 0xe2, 0x00, 0x8b, 0xea, 0x95, 0x19, 0x2a, 0x45, 0x70, 0x71, 0x87, 0x14, 0x25, 0xe6, 0x15,
-	0x27, 0x26, 0x97, 0x64, 0xe6, 0xe7, 0x09, 0x09, 0x73, 0xb1, 0x96, 0x54, 0xc4, 0x67, 0xa6, 0x48,
-	0x30, 0x2a, 0x30, 0x6a, 0xb0, 0x04, 0xb1, 0x94, 0x54, 0x78, 0xa6, 0x08, 0x99, 0x70, 0x89, 0xa5,
-	0x27, 0xe6, 0xa6, 0xc6, 0x83, 0x35, 0xc5, 0x97, 0x20, 0x94, 0x4b, 0x30, 0x29, 0x30, 0x6a, 0xf0,
-	0x04, 0x89, 0x80, 0x64, 0x83, 0x41, 0x92, 0x48, 0x46, 0x29, 0xe5, 0x72, 0xb1, 0xba, 0x16, 0xe4,
-	0x27, 0x67, 0x08, 0x89, 0x70, 0xb1, 0xa6, 0x82, 0x18, 0x50, 0x33, 0x21, 0x1c, 0x21, 0x55, 0x2e,
-	0xbe, 0xd2, 0xbc, 0xcc, 0x8a, 0xf8, 0x92, 0xcc, 0xdc, 0xd4, 0xe2, 0x92, 0xc4, 0xdc, 0x02, 0xb0,
-	0x61, 0x2c, 0x41, 0xbc, 0x20, 0xd1, 0x10, 0x98, 0xa0, 0x90, 0x3a, 0x17, 0x73, 0x49, 0x45, 0xb1,
-	0x04, 0xb3, 0x02, 0xb3, 0x06, 0xb7, 0x91, 0xa8, 0x1e, 0xcc, 0xdd, 0x7a, 0x48, 0x36, 0x05, 0x81,
-	0x54, 0x38, 0x79, 0x9c, 0x78, 0x24, 0xc7, 0x78, 0xe1, 0x91, 0x1c, 0xe3, 0x83, 0x47, 0x72, 0x8c,
-	0x13, 0x1e, 0xcb, 0x31, 0x5c, 0x78, 0x2c, 0xc7, 0x70, 0xe3, 0xb1, 0x1c, 0x43, 0x94, 0x5e, 0x41,
-	0x76, 0xba, 0x5e, 0x79, 0x7e, 0x51, 0x4e, 0x8a, 0x5e, 0x4a, 0x6a, 0x99, 0x3e, 0x98, 0xa5, 0x9b,
-	0x9a, 0x97, 0x9e, 0x99, 0x97, 0xaa, 0x9f, 0x9c, 0x91, 0x98, 0x99, 0xa7, 0x5f, 0xa1, 0x0f, 0x09,
-	0x25, 0x70, 0x10, 0x25, 0xb1, 0x81, 0xc3, 0xc8, 0x18, 0x10, 0x00, 0x00, 0xff, 0xff, 0x39, 0xd2,
-	0x46, 0x65, 0x3b, 0x01, 0x00, 0x00,
+	// 281 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x90, 0xb1, 0x4e, 0xc3, 0x30,
+	0x14, 0x45, 0x6b, 0x9a, 0x22, 0x70, 0x81, 0xc1, 0x04, 0x94, 0x05, 0x2b, 0xaa, 0x84, 0xc8, 0x82,
+	0xad, 0x02, 0x5f, 0x80, 0x84, 0x04, 0x6b, 0xe8, 0x80, 0x58, 0x22, 0x93, 0x58, 0x89, 0x05, 0xb1,
+	0xa3, 0xf8, 0x11, 0xc2, 0x3f, 0x30, 0xf0, 0x59, 0x8c, 0x1d, 0x19, 0x51, 0xf2, 0x23, 0x28, 0x2e,
+	0x15, 0xdd, 0x9e, 0xcf, 0xbb, 0xbe, 0xef, 0xea, 0x62, 0xdf, 0x16, 0xa2, 0xce, 0x78, 0x33, 0xe7,
+	0xf0, 0x5e, 0x49, 0xcb, 0xaa, 0xda, 0x80, 0x21, 0x3b, 0x8e, 0xb2, 0x66, 0x3e, 0x7b, 0xc0, 0xd3,
+	0x45, 0x2d, 0xb4, 0x15, 0x29, 0x28, 0xa3, 0xc9, 0x21, 0x9e, 0x40, 0x9b, 0xa8, 0x2c, 0x40, 0x21,
+	0x8a, 0xbc, 0xd8, 0x83, 0xf6, 0x2e, 0x23, 0x57, 0xf8, 0x38, 0x17, 0xa5, 0x4c, 0xdc, 0xa7, 0x04,
+	0xfe, 0xe5, 0xc1, 0x56, 0x88, 0xa2, 0xbd, 0xd8, 0x1f, 0xb6, 0xf7, 0xc3, 0x72, 0xc3, 0x6a, 0xf6,
+	0x81, 0xf0, 0xe4, 0xa6, 0x32, 0x69, 0x41, 0x7c, 0x3c, 0x91, 0xc3, 0xf0, 0x67, 0xba, 0x7a, 0x90,
+	0x53, 0x7c, 0xf0, 0xaa, 0x55, 0x9b, 0x80, 0x2a, 0xa5, 0x05, 0x51, 0x56, 0xce, 0xcd, 0x8b, 0xf7,
+	0x07, 0xba, 0x58, 0x43, 0x72, 0x86, 0xc7, 0xd0, 0xda, 0x60, 0x1c, 0x8e, 0xa3, 0xe9, 0xc5, 0x11,
+	0x5b, 0x07, 0x67, 0x1b, 0xa7, 0xe2, 0x41, 0x41, 0x4e, 0x30, 0xb6, 0x20, 0x40, 0x26, 0xb5, 0x31,
+	0x10, 0x78, 0x2e, 0xd9, 0xae, 0x23, 0xb1, 0x31, 0x70, 0x7d, 0xfb, 0xc8, 0xaa, 0xe7, 0x9c, 0xbd,
+	0x99, 0xfa, 0x25, 0x63, 0x99, 0x6c, 0xb8, 0x9b, 0xce, 0xa5, 0xce, 0x95, 0x96, 0x3c, 0x2d, 0x84,
+	0xd2, 0xbc, 0xe5, 0xab, 0xb6, 0x5c, 0x55, 0x5f, 0x1d, 0x45, 0xcb, 0x8e, 0xa2, 0x9f, 0x8e, 0xa2,
+	0xcf, 0x9e, 0x8e, 0x96, 0x3d, 0x1d, 0x7d, 0xf7, 0x74, 0xf4, 0xb4, 0xed, 0x3a, 0xbc, 0xfc, 0x1d,
+	0x00, 0x3b, 0xf6, 0x83, 0x7d, 0x5b, 0x01, 0x00, 0x00,
 }
 
 func (m *Transaction) Marshal() (dAtA []byte, err error) {
@@ -221,6 +231,13 @@ func (m *Epoch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.StateRoot) > 0 {
+		i -= len(m.StateRoot)
+		copy(dAtA[i:], m.StateRoot)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.StateRoot)))
+		i--
+		dAtA[i] = 0x22
+	}
 	if len(m.Txs) > 0 {
 		for iNdEx := len(m.Txs) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -293,6 +310,10 @@ func (m *Epoch) Size() (n int) {
 			n += 1 + l + sovTypes(uint64(l))
 		}
 	}
+	l = len(m.StateRoot)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
 	return n
 }
 
@@ -506,6 +527,40 @@ func (m *Epoch) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateRoot", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateRoot = append(m.StateRoot[:0], dAtA[iNdEx:postIndex]...)
+			if m.StateRoot == nil {
+				m.StateRoot = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
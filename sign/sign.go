@@ -4,6 +4,7 @@ package sign
 import (
 	"bytes"
 	"crypto/ecdsa"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -23,6 +24,21 @@ import (
 // does not actually exist (e.g. during the PersonaTag creation process).
 const SystemPersonaTag = "SystemPersonaTag"
 
+// The versions of the format of the hash of a Transaction, which is the data that is signed.
+const (
+	// HashVersionLegacy hashes the persona tag, namespace, timestamp, salt (if not 0) and body of a transaction, one
+	// after the other. The nonce is not signed, so a transaction with this version can not have a nonce.
+	HashVersionLegacy = 0
+	// HashVersion1 hashes a prefix that identifies the version, followed by the persona tag, namespace, timestamp,
+	// salt, nonce and body of a transaction, each preceded by its length. All the fields are always hashed, and the
+	// numbers are hashed as big endian integers.
+	HashVersion1 = 1
+)
+
+// hashVersionPrefix is followed by the version in the first data hashed by HashVersion1 and later versions, e.g.
+// "world-engine/transaction/v1".
+const hashVersionPrefix = "world-engine/transaction/v"
+
 var (
 	// ErrSignatureValidationFailed is returned when a signature is not valid.
 	ErrSignatureValidationFailed = errors.New("signature validation failed")
@@ -35,6 +51,9 @@ var (
 	ErrNoSignatureField  = errors.New("transaction must contain signature field")
 	ErrNoBodyField       = errors.New("transaction must contain body field")
 	ErrNoTimestampField  = errors.New("transaction must contain timestamp field")
+
+	ErrUnsupportedVersion = errors.New("unsupported transaction version")
+	ErrUnsignedNonce      = errors.New("the nonce of a transaction is only signed from version 1")
 )
 
 type Transaction struct {
@@ -42,6 +61,8 @@ type Transaction struct {
 	Namespace  string          `json:"namespace"`
 	Timestamp  int64           `json:"timestamp"`                 // unix millisecond timestamp
 	Salt       uint16          `json:"salt,omitempty"`            // an optional field for additional hash uniqueness
+	Nonce      uint64          `json:"nonce,omitempty"`           // per signer nonce, required when nonces are enforced
+	Version    uint32          `json:"version,omitempty"`         // format of the hash, see HashVersion1
 	Signature  string          `json:"signature"`                 // hex encoded string
 	Hash       common.Hash     `json:"-"`                         // don't marshal or unmarshal for json
 	Body       json.RawMessage `json:"body" swaggertype:"object"` // json string
@@ -99,6 +120,17 @@ func (s *Transaction) checkRequiredFields() error {
 	if len(s.Body) == 0 {
 		return eris.Wrap(ErrNoBodyField, "")
 	}
+	return s.checkVersion()
+}
+
+// checkVersion ensures that the hash version of the transaction is supported, and that its nonce is signed.
+func (s *Transaction) checkVersion() error {
+	if s.Version > HashVersion1 {
+		return eris.Wrapf(ErrUnsupportedVersion, "version %d", s.Version)
+	}
+	if s.Version == HashVersionLegacy && s.Nonce != 0 {
+		return eris.Wrap(ErrUnsignedNonce, "")
+	}
 	return nil
 }
 
@@ -111,6 +143,8 @@ func MappedTransaction(tx map[string]interface{}) (*Transaction, error) {
 		"signature":  true,
 		"timestamp":  true,
 		"salt":       true,
+		"nonce":      true,
+		"version":    true,
 		"body":       true,
 		"hash":       true,
 	}
@@ -174,9 +208,11 @@ func normalizeJSON(data any) ([]byte, error) {
 	return normalizedBz, nil
 }
 
-// sign uses the given private key to sign the personaTag, namespace, timestamp, and data. The timestamp is set
-// automatically to the wall time by the sign function just before signing.
-func sign(pk *ecdsa.PrivateKey, personaTag, namespace string, data any) (*Transaction, error) {
+// sign uses the given private key to sign the personaTag, namespace, timestamp, nonce and data with the given hash
+// version. The timestamp is set automatically to the wall time by the sign function just before signing.
+func sign(
+	pk *ecdsa.PrivateKey, personaTag, namespace string, nonce uint64, version uint32, data any,
+) (*Transaction, error) {
 	if data == nil || reflect.ValueOf(data).IsZero() {
 		return nil, ErrCannotSignEmptyBody
 	}
@@ -195,6 +231,8 @@ func sign(pk *ecdsa.PrivateKey, personaTag, namespace string, data any) (*Transa
 		Namespace:  namespace,
 		Timestamp:  TimestampNow(),
 		Salt:       uint16(rand.Intn(math.MaxUint16)), //nolint: gosec // additional uniqueness for each hash and sign
+		Nonce:      nonce,
+		Version:    version,
 		Body:       bz,
 	}
 	sp.populateHash()
@@ -208,10 +246,21 @@ func sign(pk *ecdsa.PrivateKey, personaTag, namespace string, data any) (*Transa
 
 // NewSystemTransaction signs a given body with the given private key using the SystemPersonaTag.
 func NewSystemTransaction(pk *ecdsa.PrivateKey, namespace string, data any) (*Transaction, error) {
-	return sign(pk, SystemPersonaTag, namespace, data)
+	return sign(pk, SystemPersonaTag, namespace, 0, HashVersionLegacy, data)
+}
+
+// NewSystemTransactionWithNonce signs a given body and nonce with the given private key using the SystemPersonaTag.
+// The transaction uses HashVersion1, so its nonce is signed.
+func NewSystemTransactionWithNonce(
+	pk *ecdsa.PrivateKey,
+	namespace string,
+	nonce uint64,
+	data any,
+) (*Transaction, error) {
+	return sign(pk, SystemPersonaTag, namespace, nonce, HashVersion1, data)
 }
 
-// NewTransaction signs a given body, tag, and nonce with the given private key.
+// NewTransaction signs a given body and tag with the given private key.
 func NewTransaction(
 	pk *ecdsa.PrivateKey,
 	personaTag,
@@ -221,7 +270,22 @@ func NewTransaction(
 	if len(personaTag) == 0 || personaTag == SystemPersonaTag {
 		return nil, ErrInvalidPersonaTag
 	}
-	return sign(pk, personaTag, namespace, data)
+	return sign(pk, personaTag, namespace, 0, HashVersionLegacy, data)
+}
+
+// NewTransactionWithNonce signs a given body, tag, and nonce with the given private key. The transaction uses
+// HashVersion1, so its nonce is signed.
+func NewTransactionWithNonce(
+	pk *ecdsa.PrivateKey,
+	personaTag,
+	namespace string,
+	nonce uint64,
+	data any,
+) (*Transaction, error) {
+	if len(personaTag) == 0 || personaTag == SystemPersonaTag {
+		return nil, ErrInvalidPersonaTag
+	}
+	return sign(pk, personaTag, namespace, nonce, HashVersion1, data)
 }
 
 func (s *Transaction) IsSystemTransaction() bool {
@@ -254,6 +318,9 @@ func (s *Transaction) HashHex() string {
 // TODO: Review this signature verification, and compare it to geth's sig verification
 func (s *Transaction) Verify(hexAddress string) error {
 	addr := common.HexToAddress(hexAddress)
+	if err := s.checkVersion(); err != nil {
+		return err
+	}
 
 	if IsZeroHash(s.Hash) {
 		s.populateHash()
@@ -280,6 +347,10 @@ func (s *Transaction) Verify(hexAddress string) error {
 }
 
 func (s *Transaction) populateHash() {
+	if s.Version != HashVersionLegacy {
+		s.populateHashV1()
+		return
+	}
 	if s.Salt != 0 {
 		s.Hash = crypto.Keccak256Hash(
 			[]byte(s.PersonaTag),
@@ -299,3 +370,22 @@ func (s *Transaction) populateHash() {
 		)
 	}
 }
+
+// populateHashV1 sets the hash of the transaction with HashVersion1. A version above HashVersion1 is hashed with its
+// own prefix, so the signature of a transaction does not hold for another version; checkVersion rejects it anyway.
+func (s *Transaction) populateHashV1() {
+	fields := [][]byte{
+		[]byte(s.PersonaTag),
+		[]byte(s.Namespace),
+		binary.BigEndian.AppendUint64(nil, uint64(s.Timestamp)),
+		binary.BigEndian.AppendUint16(nil, s.Salt),
+		binary.BigEndian.AppendUint64(nil, s.Nonce),
+		s.Body,
+	}
+	parts := make([][]byte, 0, 1+2*len(fields))
+	parts = append(parts, []byte(hashVersionPrefix+strconv.FormatUint(uint64(s.Version), 10)))
+	for _, field := range fields {
+		parts = append(parts, binary.BigEndian.AppendUint64(nil, uint64(len(field))), field)
+	}
+	s.Hash = crypto.Keccak256Hash(parts...)
+}
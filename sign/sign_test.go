@@ -69,6 +69,55 @@ func TestCanParseAMappedTransaction(t *testing.T) {
 	assert.DeepEqual(t, sp, gotSP)
 }
 
+func TestNonceIsSigned(t *testing.T) {
+	goodKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	body := `{"msg": "this is a request body"}`
+
+	sp, err := NewTransactionWithNonce(goodKey, "my-tag", "my-namespace", 7, body)
+	assert.NilError(t, err)
+	buf, err := sp.Marshal()
+	assert.NilError(t, err)
+	toBeVerified, err := UnmarshalTransaction(buf)
+	assert.NilError(t, err)
+	assert.Equal(t, uint64(7), toBeVerified.Nonce)
+	addressHex := crypto.PubkeyToAddress(goodKey.PublicKey).Hex()
+	assert.NilError(t, toBeVerified.Verify(addressHex))
+
+	// Changing the nonce invalidates the signature.
+	toBeVerified.Nonce = 8
+	toBeVerified.Hash = common.Hash{}
+	assert.ErrorIs(t, eris.Unwrap(toBeVerified.Verify(addressHex)), ErrSignatureValidationFailed)
+
+	// The nonce is signed even if it is 0, and the hash depends on the version.
+	zero, err := NewTransactionWithNonce(goodKey, "my-tag", "my-namespace", 0, body)
+	assert.NilError(t, err)
+	assert.Equal(t, uint32(HashVersion1), zero.Version)
+	legacy := *zero
+	legacy.Version = HashVersionLegacy
+	legacy.Hash = common.Hash{}
+	assert.Assert(t, legacy.HashHex() != zero.HashHex())
+	assert.ErrorIs(t, eris.Unwrap(legacy.Verify(addressHex)), ErrSignatureValidationFailed)
+
+	// A nonce can not be set on a transaction whose hash does not include it.
+	legacy.Nonce = 7
+	buf, err = legacy.Marshal()
+	assert.NilError(t, err)
+	_, err = UnmarshalTransaction(buf)
+	assert.ErrorIs(t, eris.Unwrap(err), ErrUnsignedNonce)
+	assert.ErrorIs(t, eris.Unwrap(legacy.Verify(addressHex)), ErrUnsignedNonce)
+
+	unknown := *zero
+	unknown.Version = HashVersion1 + 1
+	assert.ErrorIs(t, eris.Unwrap(unknown.Verify(addressHex)), ErrUnsupportedVersion)
+
+	system, err := NewSystemTransactionWithNonce(goodKey, "my-namespace", 3, body)
+	assert.NilError(t, err)
+	assert.Check(t, system.IsSystemTransaction())
+	assert.Equal(t, uint32(HashVersion1), system.Version)
+	assert.NilError(t, system.Verify(addressHex))
+}
+
 func TestCanGetHashHex(t *testing.T) {
 	goodKey, err := crypto.GenerateKey()
 	assert.NilError(t, err)